@@ -26,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/nodes"
@@ -131,6 +133,9 @@ func (c *GatewayController) InitController(informers k8s.K8sinformers, registere
 
 	c.SetupEventHandlers(informers)
 	c.SetupGatewayApiEventHandlers(numWorkers)
+	c.SetupAviInfraSettingEventHandlers(numWorkers)
+	c.SetupHostRuleEventHandlers(numWorkers)
+	c.SetupHTTPRuleEventHandlers(numWorkers)
 
 	if lib.DisableSync {
 		akogatewayapilib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKODeleteConfigSet, "AKO is in disable sync state")
@@ -202,6 +207,48 @@ func (c *GatewayController) addIndexers() {
 			},
 		},
 	)
+	gwinformer.ReferenceGrantInformer.Informer().AddIndexers(
+		cache.Indexers{
+			akogatewayapilib.ReferenceGrantFromIndex: func(obj interface{}) ([]string, error) {
+				referenceGrant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+				if !ok {
+					return []string{}, nil
+				}
+				var keys []string
+				for _, from := range referenceGrant.Spec.From {
+					if string(from.Group) != gatewayv1.GroupName {
+						continue
+					}
+					keys = append(keys, akogatewayapilib.GetReferenceGrantFromIndexKey(referenceGrant.Namespace, string(from.Kind), string(from.Namespace)))
+				}
+				return keys, nil
+			},
+		},
+	)
+	gwinformer.BackendTLSPolicyInformer.Informer().AddIndexers(
+		cache.Indexers{
+			akogatewayapilib.BackendTLSPolicyTargetIndex: func(obj interface{}) ([]string, error) {
+				policy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+				if !ok {
+					return []string{}, nil
+				}
+				return []string{akogatewayapilib.GetBackendTLSPolicyIndexKey(policy.Namespace, string(policy.Spec.TargetRef.Name))}, nil
+			},
+			akogatewayapilib.BackendTLSPolicyCACertIndex: func(obj interface{}) ([]string, error) {
+				policy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+				if !ok {
+					return []string{}, nil
+				}
+				var keys []string
+				for _, caCertRef := range policy.Spec.TLS.CACertRefs {
+					if caCertRef.Group == "" && string(caCertRef.Kind) == utils.Secret {
+						keys = append(keys, akogatewayapilib.GetBackendTLSPolicyIndexKey(policy.Namespace, string(caCertRef.Name)))
+					}
+				}
+				return keys, nil
+			},
+		},
+	)
 }
 
 func (c *GatewayController) FullSyncK8s(sync bool) error {
@@ -211,6 +258,45 @@ func (c *GatewayController) FullSyncK8s(sync bool) error {
 		return nil
 	}
 
+	// AviInfraSetting Section
+	// the networks of the AviInfraSettings are populated before the gateways which refer to these are built
+	if crdInformers := lib.AKOControlConfig().CRDInformers(); crdInformers != nil && crdInformers.AviInfraSettingInformer != nil {
+		infraSettingObjs, err := crdInformers.AviInfraSettingInformer.Lister().List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the aviinfrasettings during full sync: %s", err)
+			return err
+		}
+		for _, infraSettingObj := range infraSettingObjs {
+			key := lib.AviInfraSetting + "/" + utils.ObjKey(infraSettingObj)
+			k8s.PopulateAviInfraSettingNetworks(key, infraSettingObj)
+		}
+	}
+
+	// HostRule and HTTPRule Section
+	// the FQDNs of the HostRules and HTTPRules are mapped before the routes on these FQDNs are built
+	if crdInformers := lib.AKOControlConfig().CRDInformers(); crdInformers != nil && crdInformers.HostRuleInformer != nil {
+		hostRuleObjs, err := crdInformers.HostRuleInformer.Lister().List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the hostrules during full sync: %s", err)
+			return err
+		}
+		for _, hostRuleObj := range hostRuleObjs {
+			key := lib.HostRule + "/" + utils.ObjKey(hostRuleObj)
+			UpdateHostRuleMapping(key, hostRuleObj.Namespace, hostRuleObj.Name)
+		}
+	}
+	if crdInformers := lib.AKOControlConfig().CRDInformers(); crdInformers != nil && crdInformers.HTTPRuleInformer != nil {
+		httpRuleObjs, err := crdInformers.HTTPRuleInformer.Lister().List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the httprules during full sync: %s", err)
+			return err
+		}
+		for _, httpRuleObj := range httpRuleObjs {
+			key := lib.HTTPRule + "/" + utils.ObjKey(httpRuleObj)
+			UpdateHTTPRuleMapping(key, httpRuleObj.Namespace, httpRuleObj.Name)
+		}
+	}
+
 	// GatewayClass Section
 	gwClassObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayClassInformer.Lister().List(labels.Set(nil).AsSelector())
 	if err != nil {
@@ -271,6 +357,95 @@ func (c *GatewayController) FullSyncK8s(sync bool) error {
 		}
 	}
 
+	// GRPCRoute Section
+	grpcRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the grpcroutes during full sync: %s", err)
+		return err
+	}
+
+	for _, grpcRouteObj := range grpcRouteObjs {
+		key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRouteObj)
+		meta, err := meta.Accessor(grpcRouteObj)
+		if err == nil {
+			resVer := meta.GetResourceVersion()
+			objects.SharedResourceVerInstanceLister().Save(key, resVer)
+		}
+		if IsGRPCRouteValid(key, grpcRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// TLSRoute Section
+	tlsRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the tlsroutes during full sync: %s", err)
+		return err
+	}
+
+	for _, tlsRouteObj := range tlsRouteObjs {
+		key := lib.TLSRoute + "/" + utils.ObjKey(tlsRouteObj)
+		meta, err := meta.Accessor(tlsRouteObj)
+		if err == nil {
+			resVer := meta.GetResourceVersion()
+			objects.SharedResourceVerInstanceLister().Save(key, resVer)
+		}
+		if IsTLSRouteValid(key, tlsRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// TCPRoute Section
+	tcpRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the tcproutes during full sync: %s", err)
+		return err
+	}
+
+	for _, tcpRouteObj := range tcpRouteObjs {
+		key := lib.TCPRoute + "/" + utils.ObjKey(tcpRouteObj)
+		meta, err := meta.Accessor(tcpRouteObj)
+		if err == nil {
+			resVer := meta.GetResourceVersion()
+			objects.SharedResourceVerInstanceLister().Save(key, resVer)
+		}
+		if IsTCPRouteValid(key, tcpRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// UDPRoute Section
+	udpRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the udproutes during full sync: %s", err)
+		return err
+	}
+
+	for _, udpRouteObj := range udpRouteObjs {
+		key := lib.UDPRoute + "/" + utils.ObjKey(udpRouteObj)
+		meta, err := meta.Accessor(udpRouteObj)
+		if err == nil {
+			resVer := meta.GetResourceVersion()
+			objects.SharedResourceVerInstanceLister().Save(key, resVer)
+		}
+		if IsUDPRouteValid(key, udpRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// BackendTLSPolicy Section
+	// the policies are applied while building the pools of the routes, only their status is published here
+	policyObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the backendtlspolicies during full sync: %s", err)
+		return err
+	}
+
+	for _, policyObj := range policyObjs {
+		key := lib.BackendTLSPolicy + "/" + utils.ObjKey(policyObj)
+		IsBackendTLSPolicyValid(key, policyObj)
+	}
+
 	// Service Section
 	svcObjs, err := utils.GetInformers().ServiceInformer.Lister().Services(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
 	if err != nil {
@@ -314,9 +489,9 @@ func (c *GatewayController) publishAllParentVSKeysToRestLayer() {
 		modelName := vsCacheKey.Namespace + "/" + vsCacheKey.Name
 		delete(allModels, modelName)
 		utils.AviLog.Infof("Model published in full sync %s", modelName)
-		nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
-
 	}
+	// The models deleting virtualservices are published only if allowed by the mass deletion guard.
+	k8s.PublishVSModelsToRestLayer(vsKeys, allModelsMap.(map[string]interface{}))
 	// Now also publish the newly generated models (if any)
 	// Publish all the models to REST layer.
 	utils.AviLog.Debugf("Newly generated models that do not exist in cache %s", utils.Stringify(allModels))
@@ -433,17 +608,7 @@ func (c *GatewayController) cleanupStaleVSes() {
 		utils.AviLog.Errorf("AKO cluster name is invalid.")
 		return
 	}
-	if aviRestClientPool != nil && len(aviRestClientPool.AviClient) > 0 {
-		utils.AviLog.Infof("Starting clean up of stale objects")
-		restlayer := rest.NewRestOperations(aviObjCache, aviRestClientPool)
-		staleVSKey := lib.GetTenant() + "/" + lib.DummyVSForStaleData
-		restlayer.CleanupVS(staleVSKey, true)
-		staleCacheKey := avicache.NamespaceName{
-			Name:      lib.DummyVSForStaleData,
-			Namespace: lib.GetTenant(),
-		}
-		aviObjCache.VsCacheMeta.AviCacheDelete(staleCacheKey)
-	}
+	k8s.DeleteStaleObjects(aviObjCache, aviRestClientPool)
 
 	vsKeysPending := aviObjCache.VsCacheMeta.AviGetAllKeys()
 
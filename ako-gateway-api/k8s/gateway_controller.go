@@ -17,21 +17,27 @@ package k8s
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayexternalversions "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -59,21 +65,34 @@ func SharedGatewayController() *GatewayController {
 func (c *GatewayController) InitGatewayAPIInformers(cs gatewayclientset.Interface) {
 	gatewayFactory := gatewayexternalversions.NewSharedInformerFactory(cs, time.Second*30)
 	akogatewayapilib.AKOControlConfig().SetGatewayApiInformers(&akogatewayapilib.GatewayAPIInformers{
-		GatewayInformer:      gatewayFactory.Gateway().V1().Gateways(),
-		GatewayClassInformer: gatewayFactory.Gateway().V1().GatewayClasses(),
-		HTTPRouteInformer:    gatewayFactory.Gateway().V1().HTTPRoutes(),
+		GatewayInformer:          gatewayFactory.Gateway().V1().Gateways(),
+		GatewayClassInformer:     gatewayFactory.Gateway().V1().GatewayClasses(),
+		HTTPRouteInformer:        gatewayFactory.Gateway().V1().HTTPRoutes(),
+		GRPCRouteInformer:        gatewayFactory.Gateway().V1alpha2().GRPCRoutes(),
+		TLSRouteInformer:         gatewayFactory.Gateway().V1alpha2().TLSRoutes(),
+		TCPRouteInformer:         gatewayFactory.Gateway().V1alpha2().TCPRoutes(),
+		UDPRouteInformer:         gatewayFactory.Gateway().V1alpha2().UDPRoutes(),
+		ReferenceGrantInformer:   gatewayFactory.Gateway().V1beta1().ReferenceGrants(),
+		BackendTLSPolicyInformer: gatewayFactory.Gateway().V1alpha2().BackendTLSPolicies(),
 	})
 }
 
 func (c *GatewayController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 	}
 
+	if c.informers.EpInformer != nil {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+	if c.informers.EpSliceInformer != nil {
+		go c.informers.EpSliceInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSliceInformer.Informer().HasSynced)
+	}
+
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
@@ -84,12 +103,49 @@ func (c *GatewayController) Start(stopCh <-chan struct{}) {
 		informersList = append(informersList, c.informers.PodInformer.Informer().HasSynced)
 	}
 
+	if c.informers.NSInformer != nil {
+		go c.informers.NSInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.NSInformer.Informer().HasSynced)
+	}
+
+	if c.informers.ConfigMapInformer != nil {
+		go c.informers.ConfigMapInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.ConfigMapInformer.Informer().HasSynced)
+	}
+
+	if crdInformers := lib.AKOControlConfig().CRDInformers(); crdInformers != nil {
+		if crdInformers.AviInfraSettingInformer != nil {
+			go crdInformers.AviInfraSettingInformer.Informer().Run(stopCh)
+			informersList = append(informersList, crdInformers.AviInfraSettingInformer.Informer().HasSynced)
+		}
+		if crdInformers.HostRuleInformer != nil {
+			go crdInformers.HostRuleInformer.Informer().Run(stopCh)
+			informersList = append(informersList, crdInformers.HostRuleInformer.Informer().HasSynced)
+		}
+		if crdInformers.HTTPRuleInformer != nil {
+			go crdInformers.HTTPRuleInformer.Informer().Run(stopCh)
+			informersList = append(informersList, crdInformers.HTTPRuleInformer.Informer().HasSynced)
+		}
+	}
+
 	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayClassInformer.Informer().Run(stopCh)
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayClassInformer.Informer().HasSynced)
 	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Informer().Run(stopCh)
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Informer().HasSynced)
 	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Informer().Run(stopCh)
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Informer().HasSynced)
 
 	if !cache.WaitForCacheSync(stopCh, informersList...) {
 		runtime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
@@ -166,7 +222,14 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			}
 		},
 	}
-	c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	if c.informers.EpInformer != nil {
+		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	}
+
+	epSliceEventHandler := k8s.NewEndpointSliceEventHandler(c.workqueue, numWorkers, func() bool { return c.DisableSync }, nil)
+	if c.informers.EpSliceInformer != nil {
+		c.informers.EpSliceInformer.Informer().AddEventHandler(epSliceEventHandler)
+	}
 
 	svcEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -184,6 +247,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			validateBackendTLSPolicies(key, namespace, svc.Name)
 		},
 		DeleteFunc: func(obj interface{}) {
 			if c.DisableSync {
@@ -209,6 +273,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			c.workqueue[bkt].AddRateLimited(key)
 			objects.SharedResourceVerInstanceLister().Delete(key)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			validateBackendTLSPolicies(key, namespace, svc.Name)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			if c.DisableSync {
@@ -243,6 +308,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			c.syncBackendTLSPolicyCACert(key, namespace, secret.Name, numWorkers)
 		},
 		DeleteFunc: func(obj interface{}) {
 			if c.DisableSync {
@@ -271,6 +337,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+				c.syncBackendTLSPolicyCACert(key, namespace, secret.Name, numWorkers)
 			}
 		},
 		UpdateFunc: func(old, cur interface{}) {
@@ -291,6 +358,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 					bkt := utils.Bkt(namespace, numWorkers)
 					c.workqueue[bkt].AddRateLimited(key)
 					utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+					c.syncBackendTLSPolicyCACert(key, namespace, secret.Name, numWorkers)
 				}
 			}
 		},
@@ -298,6 +366,52 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 	if c.informers.SecretInformer != nil {
 		c.informers.SecretInformer.Informer().AddEventHandler(secretEventHandler)
 	}
+
+	// the routes in a namespace are evaluated again when the labels of the namespace change,
+	// since the listeners of the Gateways may admit the routes by a namespace selector.
+	nsEventHandler := cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			nsOld := old.(*corev1.Namespace)
+			nsCur := cur.(*corev1.Namespace)
+			if !reflect.DeepEqual(nsOld.Labels, nsCur.Labels) {
+				key := lib.Namespace + "/" + nsCur.GetName()
+				if lib.IsNamespaceBlocked(nsCur.GetName()) {
+					utils.AviLog.Debugf("key: %s, msg: namespace update event. namespace: %s didn't qualify filter", key, nsCur.GetName())
+					return
+				}
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				c.enqueueNamespaceRoutes(key, nsCur.GetName(), numWorkers)
+			}
+		},
+	}
+	if c.informers.NSInformer != nil {
+		c.informers.NSInformer.Informer().AddEventHandler(nsEventHandler)
+	}
+
+	// the virtualservice deletions halted by the mass deletion guard are resumed once the override is set in the configmap.
+	configMapEventHandler := cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			oldcm, oldok := old.(*corev1.ConfigMap)
+			cm, ok := cur.(*corev1.ConfigMap)
+			if !ok || !oldok || cm.Namespace != utils.GetAKONamespace() || cm.Name != lib.AviConfigMap {
+				return
+			}
+			if oldcm.ResourceVersion == cm.ResourceVersion || oldcm.Data[lib.ALLOW_MASS_DELETION] == cm.Data[lib.ALLOW_MASS_DELETION] {
+				return
+			}
+			lib.SetAllowMassDeletion(cm.Data[lib.ALLOW_MASS_DELETION])
+			// The deletions halted while deleteConfig is set are resumed as well, as sync is disabled only for the updates.
+			if lib.IsMassDeletionAllowed() && (!c.DisableSync || lib.GetDeleteConfigMap()) {
+				k8s.SharedMassDeletionGuard().ResumeHaltedDeletions()
+			}
+		},
+	}
+	if c.informers.ConfigMapInformer != nil {
+		c.informers.ConfigMapInformer.Informer().AddEventHandler(configMapEventHandler)
+	}
 }
 
 func checkAviSecretUpdateAndShutdown(secret *corev1.Secret) bool {
@@ -443,6 +557,12 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
 				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				// the gateways of the class are built again with the AviInfraSetting referred by the class
+				if !reflect.DeepEqual(oldGwClass.Spec.ParametersRef, gwClass.Spec.ParametersRef) {
+					c.enqueueGateways(key, func(gateway *gatewayv1.Gateway) bool {
+						return string(gateway.Spec.GatewayClassName) == gwClass.Name
+					}, numWorkers)
+				}
 			}
 		},
 	}
@@ -460,6 +580,7 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
 				return
 			}
+			validateBackendRefsTLSPolicies(key, httpRoute.Namespace, getHTTPRouteBackendRefs(httpRoute))
 			if !IsHTTPRouteValid(key, httpRoute) {
 				return
 			}
@@ -492,6 +613,7 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			validateBackendRefsTLSPolicies(key, namespace, getHTTPRouteBackendRefs(httpRoute))
 		},
 		UpdateFunc: func(old, obj interface{}) {
 			if c.DisableSync {
@@ -501,6 +623,7 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 			newHTTPRoute := obj.(*gatewayv1.HTTPRoute)
 			if IsHTTPRouteUpdated(oldHTTPRoute, newHTTPRoute) {
 				key := lib.HTTPRoute + "/" + utils.ObjKey(newHTTPRoute)
+				validateBackendRefsTLSPolicies(key, newHTTPRoute.Namespace, append(getHTTPRouteBackendRefs(oldHTTPRoute), getHTTPRouteBackendRefs(newHTTPRoute)...))
 				if !IsHTTPRouteValid(key, newHTTPRoute) {
 					return
 				}
@@ -512,22 +635,964 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 		},
 	}
 	informer.HTTPRouteInformer.Informer().AddEventHandler(httpRouteEventHandler)
-}
 
-func IsGatewayUpdated(oldGateway, newGateway *gatewayv1.Gateway) bool {
-	if newGateway.GetDeletionTimestamp() != nil {
-		return true
+	grpcRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grpcRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == grpcRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			validateBackendRefsTLSPolicies(key, grpcRoute.Namespace, getGRPCRouteBackendRefs(grpcRoute))
+			if !IsGRPCRouteValid(key, grpcRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(grpcRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grpcRoute, ok := obj.(*gatewayv1alpha2.GRPCRoute)
+			if !ok {
+				// grpcRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				grpcRoute, ok = tombstone.Obj.(*gatewayv1alpha2.GRPCRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a GRPCRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(grpcRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			validateBackendRefsTLSPolicies(key, namespace, getGRPCRouteBackendRefs(grpcRoute))
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldGRPCRoute := old.(*gatewayv1alpha2.GRPCRoute)
+			newGRPCRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+			if IsGRPCRouteUpdated(oldGRPCRoute, newGRPCRoute) {
+				key := lib.GRPCRoute + "/" + utils.ObjKey(newGRPCRoute)
+				validateBackendRefsTLSPolicies(key, newGRPCRoute.Namespace, append(getGRPCRouteBackendRefs(oldGRPCRoute), getGRPCRouteBackendRefs(newGRPCRoute)...))
+				if !IsGRPCRouteValid(key, newGRPCRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newGRPCRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
 	}
-	oldHash := utils.Hash(utils.Stringify(oldGateway.Spec))
-	newHash := utils.Hash(utils.Stringify(newGateway.Spec))
-	return oldHash != newHash
-}
+	informer.GRPCRouteInformer.Informer().AddEventHandler(grpcRouteEventHandler)
 
-func IsHTTPRouteUpdated(oldHTTPRoute, newHTTPRoute *gatewayv1.HTTPRoute) bool {
-	if newHTTPRoute.GetDeletionTimestamp() != nil {
-		return true
+	tlsRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tlsRoute := obj.(*gatewayv1alpha2.TLSRoute)
+			key := lib.TLSRoute + "/" + utils.ObjKey(tlsRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == tlsRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsTLSRouteValid(key, tlsRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tlsRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tlsRoute, ok := obj.(*gatewayv1alpha2.TLSRoute)
+			if !ok {
+				// tlsRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				tlsRoute, ok = tombstone.Obj.(*gatewayv1alpha2.TLSRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a TLSRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.TLSRoute + "/" + utils.ObjKey(tlsRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tlsRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldTLSRoute := old.(*gatewayv1alpha2.TLSRoute)
+			newTLSRoute := obj.(*gatewayv1alpha2.TLSRoute)
+			if IsTLSRouteUpdated(oldTLSRoute, newTLSRoute) {
+				key := lib.TLSRoute + "/" + utils.ObjKey(newTLSRoute)
+				if !IsTLSRouteValid(key, newTLSRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newTLSRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
 	}
-	oldHash := utils.Hash(utils.Stringify(oldHTTPRoute.Spec))
-	newHash := utils.Hash(utils.Stringify(newHTTPRoute.Spec))
+	informer.TLSRouteInformer.Informer().AddEventHandler(tlsRouteEventHandler)
+
+	tcpRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tcpRoute := obj.(*gatewayv1alpha2.TCPRoute)
+			key := lib.TCPRoute + "/" + utils.ObjKey(tcpRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == tcpRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsTCPRouteValid(key, tcpRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tcpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			tcpRoute, ok := obj.(*gatewayv1alpha2.TCPRoute)
+			if !ok {
+				// tcpRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				tcpRoute, ok = tombstone.Obj.(*gatewayv1alpha2.TCPRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a TCPRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.TCPRoute + "/" + utils.ObjKey(tcpRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(tcpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldTCPRoute := old.(*gatewayv1alpha2.TCPRoute)
+			newTCPRoute := obj.(*gatewayv1alpha2.TCPRoute)
+			if IsTCPRouteUpdated(oldTCPRoute, newTCPRoute) {
+				key := lib.TCPRoute + "/" + utils.ObjKey(newTCPRoute)
+				if !IsTCPRouteValid(key, newTCPRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newTCPRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	informer.TCPRouteInformer.Informer().AddEventHandler(tcpRouteEventHandler)
+
+	udpRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			udpRoute := obj.(*gatewayv1alpha2.UDPRoute)
+			key := lib.UDPRoute + "/" + utils.ObjKey(udpRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == udpRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsUDPRouteValid(key, udpRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(udpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			udpRoute, ok := obj.(*gatewayv1alpha2.UDPRoute)
+			if !ok {
+				// udpRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				udpRoute, ok = tombstone.Obj.(*gatewayv1alpha2.UDPRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a UDPRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.UDPRoute + "/" + utils.ObjKey(udpRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(udpRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldUDPRoute := old.(*gatewayv1alpha2.UDPRoute)
+			newUDPRoute := obj.(*gatewayv1alpha2.UDPRoute)
+			if IsUDPRouteUpdated(oldUDPRoute, newUDPRoute) {
+				key := lib.UDPRoute + "/" + utils.ObjKey(newUDPRoute)
+				if !IsUDPRouteValid(key, newUDPRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newUDPRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	informer.UDPRouteInformer.Informer().AddEventHandler(udpRouteEventHandler)
+
+	referenceGrantEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			referenceGrant := obj.(*gatewayv1beta1.ReferenceGrant)
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(referenceGrant)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			c.enqueueReferenceGrantSources(key, referenceGrant.Spec.From, numWorkers)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			referenceGrant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+			if !ok {
+				// referenceGrant was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				referenceGrant, ok = tombstone.Obj.(*gatewayv1beta1.ReferenceGrant)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a ReferenceGrant: %#v", obj)
+					return
+				}
+			}
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(referenceGrant)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.enqueueReferenceGrantSources(key, referenceGrant.Spec.From, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldReferenceGrant := old.(*gatewayv1beta1.ReferenceGrant)
+			newReferenceGrant := obj.(*gatewayv1beta1.ReferenceGrant)
+			if !reflect.DeepEqual(oldReferenceGrant.Spec, newReferenceGrant.Spec) {
+				key := lib.ReferenceGrant + "/" + utils.ObjKey(newReferenceGrant)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				// the objects which lost the grant are evaluated along with the objects which got it
+				from := make([]gatewayv1beta1.ReferenceGrantFrom, 0, len(oldReferenceGrant.Spec.From)+len(newReferenceGrant.Spec.From))
+				from = append(from, oldReferenceGrant.Spec.From...)
+				from = append(from, newReferenceGrant.Spec.From...)
+				c.enqueueReferenceGrantSources(key, from, numWorkers)
+			}
+		},
+	}
+	informer.ReferenceGrantInformer.Informer().AddEventHandler(referenceGrantEventHandler)
+
+	backendTLSPolicyEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			policy := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+			key := lib.BackendTLSPolicy + "/" + utils.ObjKey(policy)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			c.syncBackendTLSPolicyTarget(key, policy, numWorkers)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			policy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				policy, ok = tombstone.Obj.(*gatewayv1alpha2.BackendTLSPolicy)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a BackendTLSPolicy: %#v", obj)
+					return
+				}
+			}
+			key := lib.BackendTLSPolicy + "/" + utils.ObjKey(policy)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.syncBackendTLSPolicyTarget(key, policy, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldPolicy := old.(*gatewayv1alpha2.BackendTLSPolicy)
+			policy := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+			if !reflect.DeepEqual(oldPolicy.Spec, policy.Spec) {
+				key := lib.BackendTLSPolicy + "/" + utils.ObjKey(policy)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				if oldPolicy.Spec.TargetRef.Name != policy.Spec.TargetRef.Name {
+					c.syncBackendTLSPolicyTarget(key, oldPolicy, numWorkers)
+				}
+				c.syncBackendTLSPolicyTarget(key, policy, numWorkers)
+			}
+		},
+	}
+	informer.BackendTLSPolicyInformer.Informer().AddEventHandler(backendTLSPolicyEventHandler)
+}
+
+func (c *GatewayController) SetupAviInfraSettingEventHandlers(numWorkers uint32) {
+	crdInformers := lib.AKOControlConfig().CRDInformers()
+	if crdInformers == nil || crdInformers.AviInfraSettingInformer == nil {
+		return
+	}
+
+	// The AviInfraSettings are validated by the AKO container, the gateways are built again
+	// once the status of the AviInfraSettings is updated.
+	aviInfraEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			aviInfra := obj.(*akov1beta1.AviInfraSetting)
+			key := lib.AviInfraSetting + "/" + utils.ObjKey(aviInfra)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			k8s.PopulateAviInfraSettingNetworks(key, aviInfra)
+			c.enqueueAviInfraSettingGateways(key, aviInfra.Name, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldAviInfra := old.(*akov1beta1.AviInfraSetting)
+			aviInfra := obj.(*akov1beta1.AviInfraSetting)
+			if oldAviInfra.ResourceVersion != aviInfra.ResourceVersion &&
+				(!reflect.DeepEqual(oldAviInfra.Spec, aviInfra.Spec) || oldAviInfra.Status.Status != aviInfra.Status.Status) {
+				key := lib.AviInfraSetting + "/" + utils.ObjKey(aviInfra)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				k8s.PopulateAviInfraSettingNetworks(key, aviInfra)
+				c.enqueueAviInfraSettingGateways(key, aviInfra.Name, numWorkers)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			aviInfra, ok := obj.(*akov1beta1.AviInfraSetting)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				aviInfra, ok = tombstone.Obj.(*akov1beta1.AviInfraSetting)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an AviInfraSetting: %#v", obj)
+					return
+				}
+			}
+			key := lib.AviInfraSetting + "/" + utils.ObjKey(aviInfra)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.enqueueAviInfraSettingGateways(key, aviInfra.Name, numWorkers)
+		},
+	}
+	crdInformers.AviInfraSettingInformer.Informer().AddEventHandler(aviInfraEventHandler)
+}
+
+// enqueueAviInfraSettingGateways validates and enqueues the Gateways which refer to an AviInfraSetting
+// either directly or via their GatewayClass.
+func (c *GatewayController) enqueueAviInfraSettingGateways(key, infraSettingName string, numWorkers uint32) {
+	c.enqueueGateways(key, func(gateway *gatewayv1.Gateway) bool {
+		return akogatewayapilib.GetAviInfraSettingName(gateway) == infraSettingName
+	}, numWorkers)
+}
+
+// enqueueGateways validates and enqueues the Gateways which match the filter.
+func (c *GatewayController) enqueueGateways(key string, filter func(*gatewayv1.Gateway) bool, numWorkers uint32) {
+	gateways, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the Gateway objects, err: %v", key, err)
+		return
+	}
+	for _, gateway := range gateways {
+		if !filter(gateway) {
+			continue
+		}
+		objKey := lib.Gateway + "/" + utils.ObjKey(gateway)
+		if !IsValidGateway(objKey, gateway) {
+			continue
+		}
+		bkt := utils.Bkt(gateway.Namespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(objKey)
+		utils.AviLog.Debugf("key: %s, msg: %s enqueued", key, objKey)
+	}
+}
+
+func (c *GatewayController) SetupHostRuleEventHandlers(numWorkers uint32) {
+	crdInformers := lib.AKOControlConfig().CRDInformers()
+	if crdInformers == nil || crdInformers.HostRuleInformer == nil {
+		return
+	}
+
+	// The HostRules are validated by the AKO container, the routes are built again
+	// once the status of the HostRules is updated.
+	hostRuleEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			hostRule := obj.(*akov1beta1.HostRule)
+			key := lib.HostRule + "/" + utils.ObjKey(hostRule)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			c.syncHostRule(key, hostRule.Namespace, hostRule.Name, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldHostRule := old.(*akov1beta1.HostRule)
+			hostRule := obj.(*akov1beta1.HostRule)
+			if oldHostRule.ResourceVersion != hostRule.ResourceVersion &&
+				(!reflect.DeepEqual(oldHostRule.Spec, hostRule.Spec) || oldHostRule.Status.Status != hostRule.Status.Status) {
+				key := lib.HostRule + "/" + utils.ObjKey(hostRule)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				c.syncHostRule(key, hostRule.Namespace, hostRule.Name, numWorkers)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			hostRule, ok := obj.(*akov1beta1.HostRule)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				hostRule, ok = tombstone.Obj.(*akov1beta1.HostRule)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a HostRule: %#v", obj)
+					return
+				}
+			}
+			key := lib.HostRule + "/" + utils.ObjKey(hostRule)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.syncHostRule(key, hostRule.Namespace, hostRule.Name, numWorkers)
+		},
+	}
+	crdInformers.HostRuleInformer.Informer().AddEventHandler(hostRuleEventHandler)
+}
+
+func (c *GatewayController) SetupHTTPRuleEventHandlers(numWorkers uint32) {
+	crdInformers := lib.AKOControlConfig().CRDInformers()
+	if crdInformers == nil || crdInformers.HTTPRuleInformer == nil {
+		return
+	}
+
+	// The HTTPRules are validated by the AKO container, the routes are built again
+	// once the status of the HTTPRules is updated.
+	httpRuleEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			httpRule := obj.(*akov1beta1.HTTPRule)
+			key := lib.HTTPRule + "/" + utils.ObjKey(httpRule)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			c.syncHTTPRule(key, httpRule.Namespace, httpRule.Name, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldHTTPRule := old.(*akov1beta1.HTTPRule)
+			httpRule := obj.(*akov1beta1.HTTPRule)
+			if oldHTTPRule.ResourceVersion != httpRule.ResourceVersion &&
+				(!reflect.DeepEqual(oldHTTPRule.Spec, httpRule.Spec) || oldHTTPRule.Status.Status != httpRule.Status.Status) {
+				key := lib.HTTPRule + "/" + utils.ObjKey(httpRule)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+				c.syncHTTPRule(key, httpRule.Namespace, httpRule.Name, numWorkers)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			httpRule, ok := obj.(*akov1beta1.HTTPRule)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				httpRule, ok = tombstone.Obj.(*akov1beta1.HTTPRule)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an HTTPRule: %#v", obj)
+					return
+				}
+			}
+			key := lib.HTTPRule + "/" + utils.ObjKey(httpRule)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.syncHTTPRule(key, httpRule.Namespace, httpRule.Name, numWorkers)
+		},
+	}
+	crdInformers.HTTPRuleInformer.Informer().AddEventHandler(httpRuleEventHandler)
+}
+
+// syncHostRule updates the FQDN mapping of the HostRule, and enqueues the routes whose hostnames
+// match either the previous or the current FQDN of the HostRule.
+func (c *GatewayController) syncHostRule(key, namespace, name string, numWorkers uint32) {
+	oldFound, oldFqdn := objects.SharedCRDLister().GetHostruleToFQDNMapping(namespace + "/" + name)
+	oldFqdnType := objects.SharedCRDLister().GetFQDNFQDNTypeMapping(oldFqdn)
+	found, fqdn, fqdnType := UpdateHostRuleMapping(key, namespace, name)
+	c.enqueueRoutes(key, func(hostname string) bool {
+		return (oldFound && isHostnameMatchingFQDN(hostname, oldFqdn, oldFqdnType)) ||
+			(found && isHostnameMatchingFQDN(hostname, fqdn, fqdnType))
+	}, numWorkers)
+}
+
+// syncHTTPRule updates the FQDN and path mappings of the HTTPRule, and enqueues the routes whose
+// hostnames are either the previous or the current FQDN of the HTTPRule.
+func (c *GatewayController) syncHTTPRule(key, namespace, name string, numWorkers uint32) {
+	oldFound, oldFqdn := objects.SharedCRDLister().GetHTTPRuleFqdnMapping(namespace + "/" + name)
+	found, fqdn := UpdateHTTPRuleMapping(key, namespace, name)
+	c.enqueueRoutes(key, func(hostname string) bool {
+		return (oldFound && hostname == oldFqdn) || (found && hostname == fqdn)
+	}, numWorkers)
+}
+
+// UpdateHostRuleMapping maps the FQDN of the HostRule to the HostRule, if the HostRule has been accepted
+// by the AKO container. The FQDN and the FQDN type of the accepted HostRule are returned.
+func UpdateHostRuleMapping(key, namespace, name string) (bool, string, string) {
+	hrNsName := namespace + "/" + name
+	if found, oldFqdn := objects.SharedCRDLister().GetHostruleToFQDNMapping(hrNsName); found {
+		objects.SharedCRDLister().DeleteHostruleFQDNMapping(hrNsName)
+		objects.SharedCRDLister().DeleteFQDNFQDNTypeMapping(oldFqdn)
+	}
+	hostRule, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: HostRule not found, err: %v", key, err)
+		return false, "", ""
+	}
+	if hostRule.Status.Status != lib.StatusAccepted {
+		utils.AviLog.Debugf("key: %s, msg: HostRule is not in accepted state", key)
+		return false, "", ""
+	}
+	fqdn := hostRule.Spec.VirtualHost.Fqdn
+	fqdnType := string(hostRule.Spec.VirtualHost.FqdnType)
+	if fqdnType == "" {
+		fqdnType = string(akov1beta1.Exact)
+	}
+	objects.SharedCRDLister().UpdateFQDNHostruleMapping(fqdn, hrNsName)
+	objects.SharedCRDLister().UpdateFQDNFQDNTypeMapping(fqdn, fqdnType)
+	return true, fqdn, fqdnType
+}
+
+// UpdateHTTPRuleMapping maps the FQDN and the paths of the HTTPRule to the HTTPRule, if the HTTPRule has
+// been accepted by the AKO container. The FQDN of the accepted HTTPRule is returned.
+func UpdateHTTPRuleMapping(key, namespace, name string) (bool, string) {
+	rrNsName := namespace + "/" + name
+	objects.SharedCRDLister().RemoveFqdnHTTPRulesMappings(rrNsName)
+	httpRule, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: HTTPRule not found, err: %v", key, err)
+		return false, ""
+	}
+	if httpRule.Status.Status != lib.StatusAccepted {
+		utils.AviLog.Debugf("key: %s, msg: HTTPRule is not in accepted state", key)
+		return false, ""
+	}
+	for _, path := range httpRule.Spec.Paths {
+		objects.SharedCRDLister().UpdateFqdnHTTPRulesMappings(httpRule.Spec.Fqdn, path.Target, rrNsName)
+	}
+	return true, httpRule.Spec.Fqdn
+}
+
+// isHostnameMatchingFQDN checks whether the hostname of a route matches the FQDN of a HostRule,
+// the same way as the HostRules are matched with the hosts of the Ingresses.
+func isHostnameMatchingFQDN(hostname, fqdn, fqdnType string) bool {
+	switch fqdnType {
+	case string(akov1beta1.Contains):
+		return strings.Contains(hostname, fqdn)
+	case string(akov1beta1.Wildcard):
+		return strings.HasPrefix(fqdn, "*") && strings.HasSuffix(hostname, strings.TrimPrefix(fqdn, "*"))
+	}
+	return hostname == fqdn
+}
+
+// enqueueRoutes validates and enqueues the HTTPRoutes and the GRPCRoutes which have a hostname
+// matching the filter.
+func (c *GatewayController) enqueueRoutes(key string, filter func(string) bool, numWorkers uint32) {
+	hasMatchingHostname := func(hostnames []gatewayv1.Hostname) bool {
+		for _, hostname := range hostnames {
+			if filter(string(hostname)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	httpRoutes, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the HTTPRoute objects, err: %v", key, err)
+		return
+	}
+	for _, httpRoute := range httpRoutes {
+		if !hasMatchingHostname(httpRoute.Spec.Hostnames) {
+			continue
+		}
+		objKey := lib.HTTPRoute + "/" + utils.ObjKey(httpRoute)
+		if !IsHTTPRouteValid(objKey, httpRoute) {
+			continue
+		}
+		bkt := utils.Bkt(httpRoute.Namespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(objKey)
+		utils.AviLog.Debugf("key: %s, msg: %s enqueued", key, objKey)
+	}
+
+	grpcRoutes, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the GRPCRoute objects, err: %v", key, err)
+		return
+	}
+	for _, grpcRoute := range grpcRoutes {
+		if !hasMatchingHostname(grpcRoute.Spec.Hostnames) {
+			continue
+		}
+		objKey := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+		if !IsGRPCRouteValid(objKey, grpcRoute) {
+			continue
+		}
+		bkt := utils.Bkt(grpcRoute.Namespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(objKey)
+		utils.AviLog.Debugf("key: %s, msg: %s enqueued", key, objKey)
+	}
+}
+
+// syncBackendTLSPolicyTarget validates the BackendTLSPolicies which target the same Service as the policy,
+// since the conflicts among these are resolved again, and enqueues the Service so that its pools are built
+// again with the policy applied to the Service.
+func (c *GatewayController) syncBackendTLSPolicyTarget(key string, policy *gatewayv1alpha2.BackendTLSPolicy, numWorkers uint32) {
+	if !akogatewayapilib.IsBackendTLSPolicyTargetService(policy) {
+		IsBackendTLSPolicyValid(key, policy)
+		return
+	}
+	svcName := string(policy.Spec.TargetRef.Name)
+	validateBackendTLSPolicies(key, policy.Namespace, svcName)
+	if lib.IsNamespaceBlocked(policy.Namespace) {
+		return
+	}
+	svcKey := utils.Service + "/" + policy.Namespace + "/" + svcName
+	bkt := utils.Bkt(policy.Namespace, numWorkers)
+	c.workqueue[bkt].AddRateLimited(svcKey)
+	utils.AviLog.Debugf("key: %s, msg: %s enqueued for the BackendTLSPolicy", key, svcKey)
+}
+
+// syncBackendTLSPolicyCACert syncs the targets of the BackendTLSPolicies which refer to the Secret
+// namespace/name as CA certificate.
+func (c *GatewayController) syncBackendTLSPolicyCACert(key, namespace, name string, numWorkers uint32) {
+	informer := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	objs, err := informer.BackendTLSPolicyInformer.Informer().GetIndexer().ByIndex(akogatewayapilib.BackendTLSPolicyCACertIndex, akogatewayapilib.GetBackendTLSPolicyIndexKey(namespace, name))
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendTLSPolicies for the Secret %s/%s, err: %v", key, namespace, name, err)
+		return
+	}
+	for _, obj := range objs {
+		if policy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy); ok {
+			c.syncBackendTLSPolicyTarget(key, policy, numWorkers)
+		}
+	}
+}
+
+// validateBackendTLSPolicies validates the BackendTLSPolicies which target the Service namespace/name.
+func validateBackendTLSPolicies(key, namespace, name string) {
+	informer := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	objs, err := informer.BackendTLSPolicyInformer.Informer().GetIndexer().ByIndex(akogatewayapilib.BackendTLSPolicyTargetIndex, akogatewayapilib.GetBackendTLSPolicyIndexKey(namespace, name))
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendTLSPolicies for the Service %s/%s, err: %v", key, namespace, name, err)
+		return
+	}
+	for _, obj := range objs {
+		if policy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy); ok {
+			IsBackendTLSPolicyValid(key, policy)
+		}
+	}
+}
+
+// validateBackendRefsTLSPolicies validates the BackendTLSPolicies which target the backends of a route,
+// so that the Gateways of the route are published as ancestors of the policies.
+func validateBackendRefsTLSPolicies(key, routeNamespace string, backendRefs []gatewayv1.BackendRef) {
+	for _, backendRef := range backendRefs {
+		namespace := routeNamespace
+		if backendRef.Namespace != nil {
+			namespace = string(*backendRef.Namespace)
+		}
+		if isServiceBackendRef(backendRef.BackendObjectReference, routeNamespace, namespace, string(backendRef.Name)) {
+			validateBackendTLSPolicies(key, namespace, string(backendRef.Name))
+		}
+	}
+}
+
+// enqueueReferenceGrantSources validates and enqueues the Gateways and the routes which are
+// allowed to refer to the objects in the namespace of a ReferenceGrant, so that the references
+// are evaluated again when the ReferenceGrant changes.
+func (c *GatewayController) enqueueReferenceGrantSources(key string, referenceGrantFrom []gatewayv1beta1.ReferenceGrantFrom, numWorkers uint32) {
+	informer := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	evaluated := make(map[string]struct{})
+	for _, from := range referenceGrantFrom {
+		if string(from.Group) != gatewayv1.GroupName {
+			continue
+		}
+		namespace := string(from.Namespace)
+		var objKeys []string
+		var err error
+		switch string(from.Kind) {
+		case lib.Gateway:
+			var gateways []*gatewayv1.Gateway
+			gateways, err = informer.GatewayInformer.Lister().Gateways(namespace).List(labels.Everything())
+			for _, gateway := range gateways {
+				if objKey := lib.Gateway + "/" + utils.ObjKey(gateway); notEvaluated(evaluated, objKey) && IsValidGateway(objKey, gateway) {
+					objKeys = append(objKeys, objKey)
+				}
+			}
+		case lib.HTTPRoute:
+			var httpRoutes []*gatewayv1.HTTPRoute
+			httpRoutes, err = informer.HTTPRouteInformer.Lister().HTTPRoutes(namespace).List(labels.Everything())
+			for _, httpRoute := range httpRoutes {
+				if objKey := lib.HTTPRoute + "/" + utils.ObjKey(httpRoute); notEvaluated(evaluated, objKey) && IsHTTPRouteValid(objKey, httpRoute) {
+					objKeys = append(objKeys, objKey)
+				}
+			}
+		case lib.GRPCRoute:
+			var grpcRoutes []*gatewayv1alpha2.GRPCRoute
+			grpcRoutes, err = informer.GRPCRouteInformer.Lister().GRPCRoutes(namespace).List(labels.Everything())
+			for _, grpcRoute := range grpcRoutes {
+				if objKey := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute); notEvaluated(evaluated, objKey) && IsGRPCRouteValid(objKey, grpcRoute) {
+					objKeys = append(objKeys, objKey)
+				}
+			}
+		case lib.TLSRoute:
+			var tlsRoutes []*gatewayv1alpha2.TLSRoute
+			tlsRoutes, err = informer.TLSRouteInformer.Lister().TLSRoutes(namespace).List(labels.Everything())
+			for _, tlsRoute := range tlsRoutes {
+				if objKey := lib.TLSRoute + "/" + utils.ObjKey(tlsRoute); notEvaluated(evaluated, objKey) && IsTLSRouteValid(objKey, tlsRoute) {
+					objKeys = append(objKeys, objKey)
+				}
+			}
+		case lib.TCPRoute:
+			var tcpRoutes []*gatewayv1alpha2.TCPRoute
+			tcpRoutes, err = informer.TCPRouteInformer.Lister().TCPRoutes(namespace).List(labels.Everything())
+			for _, tcpRoute := range tcpRoutes {
+				if objKey := lib.TCPRoute + "/" + utils.ObjKey(tcpRoute); notEvaluated(evaluated, objKey) && IsTCPRouteValid(objKey, tcpRoute) {
+					objKeys = append(objKeys, objKey)
+				}
+			}
+		case lib.UDPRoute:
+			var udpRoutes []*gatewayv1alpha2.UDPRoute
+			udpRoutes, err = informer.UDPRouteInformer.Lister().UDPRoutes(namespace).List(labels.Everything())
+			for _, udpRoute := range udpRoutes {
+				if objKey := lib.UDPRoute + "/" + utils.ObjKey(udpRoute); notEvaluated(evaluated, objKey) && IsUDPRouteValid(objKey, udpRoute) {
+					objKeys = append(objKeys, objKey)
+				}
+			}
+		}
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects in the namespace %s, err: %v", key, from.Kind, namespace, err)
+			continue
+		}
+		bkt := utils.Bkt(namespace, numWorkers)
+		for _, objKey := range objKeys {
+			c.workqueue[bkt].AddRateLimited(objKey)
+			utils.AviLog.Debugf("key: %s, msg: %s enqueued for the ReferenceGrant", key, objKey)
+		}
+	}
+}
+
+// enqueueNamespaceRoutes validates and enqueues the routes in a namespace. The routes which are no longer
+// valid are enqueued as well when these are attached to a Gateway, so that their configuration is removed.
+func (c *GatewayController) enqueueNamespaceRoutes(key, namespace string, numWorkers uint32) {
+	informer := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	var objKeys []string
+	httpRoutes, err := informer.HTTPRouteInformer.Lister().HTTPRoutes(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects in the namespace %s, err: %v", key, lib.HTTPRoute, namespace, err)
+	}
+	for _, httpRoute := range httpRoutes {
+		objKey := lib.HTTPRoute + "/" + utils.ObjKey(httpRoute)
+		if IsHTTPRouteValid(objKey, httpRoute) || isRouteAttached(objKey) {
+			objKeys = append(objKeys, objKey)
+		}
+	}
+	grpcRoutes, err := informer.GRPCRouteInformer.Lister().GRPCRoutes(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects in the namespace %s, err: %v", key, lib.GRPCRoute, namespace, err)
+	}
+	for _, grpcRoute := range grpcRoutes {
+		objKey := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+		if IsGRPCRouteValid(objKey, grpcRoute) || isRouteAttached(objKey) {
+			objKeys = append(objKeys, objKey)
+		}
+	}
+	tlsRoutes, err := informer.TLSRouteInformer.Lister().TLSRoutes(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects in the namespace %s, err: %v", key, lib.TLSRoute, namespace, err)
+	}
+	for _, tlsRoute := range tlsRoutes {
+		objKey := lib.TLSRoute + "/" + utils.ObjKey(tlsRoute)
+		if IsTLSRouteValid(objKey, tlsRoute) || isRouteAttached(objKey) {
+			objKeys = append(objKeys, objKey)
+		}
+	}
+	tcpRoutes, err := informer.TCPRouteInformer.Lister().TCPRoutes(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects in the namespace %s, err: %v", key, lib.TCPRoute, namespace, err)
+	}
+	for _, tcpRoute := range tcpRoutes {
+		objKey := lib.TCPRoute + "/" + utils.ObjKey(tcpRoute)
+		if IsTCPRouteValid(objKey, tcpRoute) || isRouteAttached(objKey) {
+			objKeys = append(objKeys, objKey)
+		}
+	}
+	udpRoutes, err := informer.UDPRouteInformer.Lister().UDPRoutes(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects in the namespace %s, err: %v", key, lib.UDPRoute, namespace, err)
+	}
+	for _, udpRoute := range udpRoutes {
+		objKey := lib.UDPRoute + "/" + utils.ObjKey(udpRoute)
+		if IsUDPRouteValid(objKey, udpRoute) || isRouteAttached(objKey) {
+			objKeys = append(objKeys, objKey)
+		}
+	}
+	bkt := utils.Bkt(namespace, numWorkers)
+	for _, objKey := range objKeys {
+		c.workqueue[bkt].AddRateLimited(objKey)
+		utils.AviLog.Debugf("key: %s, msg: %s enqueued for the Namespace", key, objKey)
+	}
+}
+
+func isRouteAttached(routeTypeNsName string) bool {
+	_, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+	return len(gwNsNameList) > 0
+}
+
+func notEvaluated(evaluated map[string]struct{}, objKey string) bool {
+	if _, ok := evaluated[objKey]; ok {
+		return false
+	}
+	evaluated[objKey] = struct{}{}
+	return true
+}
+
+func IsGatewayUpdated(oldGateway, newGateway *gatewayv1.Gateway) bool {
+	if newGateway.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldGateway.Spec))
+	newHash := utils.Hash(utils.Stringify(newGateway.Spec))
+	return oldHash != newHash
+}
+
+func IsHTTPRouteUpdated(oldHTTPRoute, newHTTPRoute *gatewayv1.HTTPRoute) bool {
+	if newHTTPRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldHTTPRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newHTTPRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsGRPCRouteUpdated(oldGRPCRoute, newGRPCRoute *gatewayv1alpha2.GRPCRoute) bool {
+	if newGRPCRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldGRPCRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newGRPCRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsTLSRouteUpdated(oldTLSRoute, newTLSRoute *gatewayv1alpha2.TLSRoute) bool {
+	if newTLSRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldTLSRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newTLSRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsTCPRouteUpdated(oldTCPRoute, newTCPRoute *gatewayv1alpha2.TCPRoute) bool {
+	if newTCPRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldTCPRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newTCPRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsUDPRouteUpdated(oldUDPRoute, newUDPRoute *gatewayv1alpha2.UDPRoute) bool {
+	if newUDPRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldUDPRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newUDPRoute.Spec))
 	return oldHash != newHash
 }
@@ -16,12 +16,19 @@ package k8s
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -39,6 +46,19 @@ func IsGatewayClassValid(key string, gatewayClass *gatewayv1.GatewayClass) bool
 	}
 
 	gatewayClassStatus := gatewayClass.Status.DeepCopy()
+	// AviInfraSetting is the only kind of parameters supported for the GatewayClass
+	if gatewayClass.Spec.ParametersRef != nil && !akogatewayapilib.IsAviInfraSettingParametersRef(gatewayClass.Spec.ParametersRef) {
+		utils.AviLog.Errorf("key: %s, msg: parametersRef of GatewayClass object %s refers to an unsupported kind", key, gatewayClass.Name)
+		akogatewayapistatus.NewCondition().
+			Type(string(gatewayv1.GatewayClassConditionStatusAccepted)).
+			Reason(string(gatewayv1.GatewayClassReasonInvalidParameters)).
+			Status(metav1.ConditionFalse).
+			ObservedGeneration(gatewayClass.ObjectMeta.Generation).
+			Message(fmt.Sprintf("parametersRef must refer to %s of group %s", lib.AviInfraSetting, lib.AkoGroup)).
+			SetIn(&gatewayClassStatus.Conditions)
+		akogatewayapistatus.Record(key, gatewayClass, &akogatewayapistatus.Status{GatewayClassStatus: gatewayClassStatus})
+		return false
+	}
 	akogatewayapistatus.NewCondition().
 		Type(string(gatewayv1.GatewayClassConditionStatusAccepted)).
 		Reason(string(gatewayv1.GatewayClassReasonAccepted)).
@@ -123,6 +143,18 @@ func IsValidGateway(key string, gateway *gatewayv1.Gateway) bool {
 	return true
 }
 
+// getListenerPortType returns how the port of the listener is served, by the L4 VS, or by the EVH parent
+// VS of the gateway with the HTTP application profile, or with the L4 application profile for passthrough.
+func getListenerPortType(listener gatewayv1.Listener) string {
+	if akogatewayapilib.IsPassthroughListener(listener) {
+		return "Passthrough"
+	}
+	if akogatewayapilib.IsL4Listener(listener) {
+		return "L4"
+	}
+	return "EVH"
+}
+
 func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gatewayv1.GatewayStatus, index int) bool {
 
 	listener := gateway.Spec.Listeners[index]
@@ -136,8 +168,8 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 		Status(metav1.ConditionFalse).
 		ObservedGeneration(gateway.ObjectMeta.Generation)
 
-	// hostname is not nil or wildcard
-	if listener.Hostname == nil || *listener.Hostname == "*" {
+	// hostname is not nil or wildcard, the hostname is not used by the TCP and UDP listeners
+	if !akogatewayapilib.IsL4Listener(listener) && (listener.Hostname == nil || *listener.Hostname == "*") {
 		utils.AviLog.Errorf("key: %s, msg: hostname with wildcard found in listener %s", key, listener.Name)
 		defaultCondition.
 			Message("Hostname not found or Hostname has invalid configuration").
@@ -147,7 +179,10 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 
 	// protocol validation
 	if listener.Protocol != gatewayv1.HTTPProtocolType &&
-		listener.Protocol != gatewayv1.HTTPSProtocolType {
+		listener.Protocol != gatewayv1.HTTPSProtocolType &&
+		listener.Protocol != gatewayv1.TLSProtocolType &&
+		listener.Protocol != gatewayv1.TCPProtocolType &&
+		listener.Protocol != gatewayv1.UDPProtocolType {
 		utils.AviLog.Errorf("key: %s, msg: protocol is not supported for listener %s", key, listener.Name)
 		defaultCondition.
 			Reason(string(gatewayv1.ListenerReasonUnsupportedProtocol)).
@@ -157,8 +192,18 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 		return false
 	}
 
-	// has valid TLS config
-	if listener.TLS != nil {
+	// TLS protocol is supported only for passthrough, the certificates are served by the backends
+	if listener.Protocol == gatewayv1.TLSProtocolType {
+		if !akogatewayapilib.IsPassthroughListener(listener) {
+			utils.AviLog.Errorf("key: %s, msg: tls mode not valid for TLS protocol %+v/%+v, must be Passthrough", key, gateway.Name, listener.Name)
+			defaultCondition.
+				Reason(string(gatewayv1.ListenerReasonInvalidCertificateRef)).
+				Message("TLS mode or reference not valid").
+				SetIn(&gatewayStatus.Listeners[index].Conditions)
+			return false
+		}
+	} else if listener.TLS != nil {
+		// has valid TLS config
 		if (listener.TLS.Mode != nil && *listener.TLS.Mode != gatewayv1.TLSModeTerminate) || len(listener.TLS.CertificateRefs) == 0 {
 			utils.AviLog.Errorf("key: %s, msg: tls mode/ref not valid %+v/%+v", key, gateway.Name, listener.Name)
 			defaultCondition.
@@ -178,8 +223,36 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 					SetIn(&gatewayStatus.Listeners[index].Conditions)
 				return false
 			}
+			certNamespace := gateway.Namespace
+			if certRef.Namespace != nil && *certRef.Namespace != "" {
+				certNamespace = string(*certRef.Namespace)
+			}
+			if !akogatewayapilib.IsReferencePermitted(lib.Gateway, gateway.Namespace, utils.Secret, certNamespace, string(certRef.Name)) {
+				utils.AviLog.Errorf("key: %s, msg: CertificateRef %s/%s of listener %+v/%+v is not permitted by any ReferenceGrant", key, certNamespace, certRef.Name, gateway.Name, listener.Name)
+				defaultCondition.
+					Type(string(gatewayv1.ListenerConditionResolvedRefs)).
+					Reason(string(gatewayv1.ListenerReasonRefNotPermitted)).
+					Message(fmt.Sprintf("CertificateRef to Secret %s/%s is not permitted", certNamespace, certRef.Name)).
+					SetIn(&gatewayStatus.Listeners[index].Conditions)
+				return false
+			}
+		}
+	}
 
+	// The EVH parent and L4 VSes of the gateway share the vsvip, and the passthrough ports of the parent VS
+	// use the L4 application profile, so a port can only be used by the listeners served in the same way
+	for _, other := range gateway.Spec.Listeners {
+		if other.Name == listener.Name || other.Port != listener.Port || getListenerPortType(other) == getListenerPortType(listener) {
+			continue
 		}
+		utils.AviLog.Errorf("key: %s, msg: port %d of listener %+v/%+v is used by the listener %+v with protocol %s", key, listener.Port, gateway.Name, listener.Name, other.Name, other.Protocol)
+		defaultCondition.
+			Type(string(gatewayv1.ListenerConditionConflicted)).
+			Reason(string(gatewayv1.ListenerReasonProtocolConflict)).
+			Status(metav1.ConditionTrue).
+			Message(fmt.Sprintf("Port %d is used by the listener %s with protocol %s", listener.Port, other.Name, other.Protocol)).
+			SetIn(&gatewayStatus.Listeners[index].Conditions)
+		return false
 	}
 
 	// Valid listener
@@ -213,13 +286,24 @@ func IsHTTPRouteValid(key string, obj *gatewayv1.HTTPRoute) bool {
 	httpRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(httpRoute.Spec.ParentRefs))
 	var invalidParentRefCount int
 	for index := range httpRoute.Spec.ParentRefs {
-		err := validateParentReference(key, httpRoute, httpRouteStatus, index)
+		err := validateParentReference(key, httpRoute, lib.HTTPRoute, httpRoute.Spec.ParentRefs[index], httpRoute.Spec.Hostnames, &httpRouteStatus.RouteStatus)
 		if err != nil {
 			invalidParentRefCount++
 			parentRefName := httpRoute.Spec.ParentRefs[index].Name
 			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of HTTPRoute object %s is not valid, err: %v", key, parentRefName, httpRoute.Name, err)
 		}
 	}
+
+	if err := validateHTTPRouteRules(httpRoute); err != nil {
+		utils.AviLog.Errorf("key: %s, msg: HTTPRoute object %s is not valid, err: %v", key, httpRoute.Name, err)
+		setUnsupportedValueCondition(httpRoute, &httpRouteStatus.RouteStatus, err)
+		akogatewayapistatus.Record(key, httpRoute, &akogatewayapistatus.Status{HTTPRouteStatus: httpRouteStatus})
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(httpRoute, corev1.EventTypeWarning,
+			lib.Detached, "HTTPRoute object %s is not valid, %s", httpRoute.Name, err.Error())
+		return false
+	}
+
+	setResolvedRefsCondition(key, httpRoute, lib.HTTPRoute, getHTTPRouteBackendRefs(httpRoute), &httpRouteStatus.RouteStatus)
 	akogatewayapistatus.Record(key, httpRoute, &akogatewayapistatus.Status{HTTPRouteStatus: httpRouteStatus})
 
 	// No valid attachment, we can't proceed with this HTTPRoute object.
@@ -233,12 +317,295 @@ func IsHTTPRouteValid(key string, obj *gatewayv1.HTTPRoute) bool {
 	return true
 }
 
-func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRouteStatus *gatewayv1.HTTPRouteStatus, index int) error {
+func IsGRPCRouteValid(key string, obj *gatewayv1alpha2.GRPCRoute) bool {
+
+	grpcRoute := obj.DeepCopy()
+	if len(grpcRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the GRPCRoute %s", key, grpcRoute.Name)
+		return false
+	}
+
+	for _, hostname := range grpcRoute.Spec.Hostnames {
+		if strings.Contains(string(hostname), "*") {
+			utils.AviLog.Errorf("key: %s, msg: Wildcard in hostname is not supported for the GRPCRoute %s", key, grpcRoute.Name)
+			akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(grpcRoute, corev1.EventTypeWarning,
+				lib.Detached, "Wildcard in hostname is not supported for the GRPCRoute %s", grpcRoute.Name)
+			return false
+		}
+	}
+
+	grpcRouteStatus := obj.Status.DeepCopy()
+	grpcRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(grpcRoute.Spec.ParentRefs))
+	var invalidParentRefCount int
+	for index := range grpcRoute.Spec.ParentRefs {
+		err := validateParentReference(key, grpcRoute, lib.GRPCRoute, grpcRoute.Spec.ParentRefs[index], grpcRoute.Spec.Hostnames, &grpcRouteStatus.RouteStatus)
+		if err != nil {
+			invalidParentRefCount++
+			parentRefName := grpcRoute.Spec.ParentRefs[index].Name
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of GRPCRoute object %s is not valid, err: %v", key, parentRefName, grpcRoute.Name, err)
+		}
+	}
+
+	// the matches are translated to the path and the header matches of the child VS
+	if err := validateGRPCRouteMatches(grpcRoute); err != nil {
+		utils.AviLog.Errorf("key: %s, msg: GRPCRoute object %s is not valid, err: %v", key, grpcRoute.Name, err)
+		setUnsupportedValueCondition(grpcRoute, &grpcRouteStatus.RouteStatus, err)
+		akogatewayapistatus.Record(key, grpcRoute, &akogatewayapistatus.Status{GRPCRouteStatus: grpcRouteStatus})
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(grpcRoute, corev1.EventTypeWarning,
+			lib.Detached, "GRPCRoute object %s is not valid, %s", grpcRoute.Name, err.Error())
+		return false
+	}
+
+	setResolvedRefsCondition(key, grpcRoute, lib.GRPCRoute, getGRPCRouteBackendRefs(grpcRoute), &grpcRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, grpcRoute, &akogatewayapistatus.Status{GRPCRouteStatus: grpcRouteStatus})
+
+	// No valid attachment, we can't proceed with this GRPCRoute object.
+	if invalidParentRefCount == len(grpcRoute.Spec.ParentRefs) {
+		utils.AviLog.Errorf("key: %s, msg: GRPCRoute object %s is not valid", key, grpcRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(grpcRoute, corev1.EventTypeWarning,
+			lib.Detached, "GRPCRoute object %s is not valid", grpcRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: GRPCRoute object %s is valid", key, grpcRoute.Name)
+	return true
+}
+
+// validateHTTPRouteRules returns an error for the matches, the filters and the timeouts which
+// can't be translated to the match criteria and the HTTP policies of the child VS or to the pools.
+func validateHTTPRouteRules(httpRoute *gatewayv1.HTTPRoute) error {
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, match := range rule.Matches {
+			if len(match.QueryParams) > 1 {
+				return fmt.Errorf("Only a single query param match is supported per match")
+			}
+			for _, queryParam := range match.QueryParams {
+				if queryParam.Type != nil && *queryParam.Type != gatewayv1.QueryParamMatchExact {
+					return fmt.Errorf("Query param match type %s is not supported", *queryParam.Type)
+				}
+			}
+		}
+		for _, filter := range rule.Filters {
+			if serviceType := lib.GetServiceType(); filter.Type == gatewayv1.HTTPRouteFilterRequestMirror &&
+				(serviceType == lib.NodePort || serviceType == lib.NodePortLocal) {
+				// The requests are mirrored by the sideband profile of the child VS to the endpoint
+				// addresses on the port of the request, so the node ports can't be used.
+				return fmt.Errorf("Filter type %s is not supported in the %s mode", filter.Type, serviceType)
+			}
+			if filter.URLRewrite == nil || filter.URLRewrite.Path == nil ||
+				filter.URLRewrite.Path.Type != gatewayv1.PrefixMatchHTTPPathModifier {
+				continue
+			}
+			for _, match := range rule.Matches {
+				if match.Path == nil || (match.Path.Type != nil && *match.Path.Type != gatewayv1.PathMatchPathPrefix) {
+					return fmt.Errorf("ReplacePrefixMatch is only supported with the PathPrefix match")
+				}
+			}
+		}
+		if rule.Timeouts != nil {
+			for _, timeout := range []*gatewayv1.Duration{rule.Timeouts.Request, rule.Timeouts.BackendRequest} {
+				if timeout == nil {
+					continue
+				}
+				duration, err := time.ParseDuration(string(*timeout))
+				if err != nil || duration < 0 {
+					return fmt.Errorf("Timeout %s is not a valid duration", *timeout)
+				}
+				if duration > akogatewayapilib.MaxPoolServerTimeout {
+					return fmt.Errorf("Timeout %s is more than the maximum supported timeout of %s", *timeout, akogatewayapilib.MaxPoolServerTimeout)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// setUnsupportedValueCondition sets the Accepted condition to False with the reason
+// UnsupportedValue for all the parents of the route.
+func setUnsupportedValueCondition(route metav1.Object, routeStatus *gatewayv1.RouteStatus, err error) {
+	unsupportedValueCondition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.RouteConditionAccepted)).
+		Reason(string(gatewayv1.RouteReasonUnsupportedValue)).
+		Status(metav1.ConditionFalse).
+		ObservedGeneration(route.GetGeneration()).
+		Message(err.Error())
+	for i := range routeStatus.Parents {
+		unsupportedValueCondition.SetIn(&routeStatus.Parents[i].Conditions)
+	}
+}
+
+// validateGRPCRouteMatches returns an error for the method and the header matches
+// which can't be translated to the match criteria of the child VS.
+func validateGRPCRouteMatches(grpcRoute *gatewayv1alpha2.GRPCRoute) error {
+	for _, rule := range grpcRoute.Spec.Rules {
+		for _, match := range rule.Matches {
+			if match.Method != nil {
+				if match.Method.Type != nil && *match.Method.Type != gatewayv1alpha2.GRPCMethodMatchExact {
+					return fmt.Errorf("Method match type %s is not supported", *match.Method.Type)
+				}
+				if match.Method.Service == nil && match.Method.Method == nil {
+					return fmt.Errorf("One of service or method must be specified in the method match")
+				}
+			}
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type != gatewayv1.HeaderMatchExact {
+					return fmt.Errorf("Header match type %s is not supported", *header.Type)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func IsTLSRouteValid(key string, obj *gatewayv1alpha2.TLSRoute) bool {
+
+	tlsRoute := obj.DeepCopy()
+	if len(tlsRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the TLSRoute %s", key, tlsRoute.Name)
+		return false
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range tlsRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	tlsRouteStatus := obj.Status.DeepCopy()
+	valid := validateL4Route(key, tlsRoute, lib.TLSRoute, tlsRoute.Spec.ParentRefs, tlsRoute.Spec.Hostnames, backendRefs, &tlsRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, tlsRoute, &akogatewayapistatus.Status{TLSRouteStatus: tlsRouteStatus})
+
+	// No valid attachment, we can't proceed with this TLSRoute object.
+	if !valid {
+		utils.AviLog.Errorf("key: %s, msg: TLSRoute object %s is not valid", key, tlsRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(tlsRoute, corev1.EventTypeWarning,
+			lib.Detached, "TLSRoute object %s is not valid", tlsRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: TLSRoute object %s is valid", key, tlsRoute.Name)
+	return true
+}
+
+func IsTCPRouteValid(key string, obj *gatewayv1alpha2.TCPRoute) bool {
+
+	tcpRoute := obj.DeepCopy()
+	if len(tcpRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the TCPRoute %s", key, tcpRoute.Name)
+		return false
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range tcpRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	tcpRouteStatus := obj.Status.DeepCopy()
+	valid := validateL4Route(key, tcpRoute, lib.TCPRoute, tcpRoute.Spec.ParentRefs, nil, backendRefs, &tcpRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, tcpRoute, &akogatewayapistatus.Status{TCPRouteStatus: tcpRouteStatus})
+
+	// No valid attachment, we can't proceed with this TCPRoute object.
+	if !valid {
+		utils.AviLog.Errorf("key: %s, msg: TCPRoute object %s is not valid", key, tcpRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(tcpRoute, corev1.EventTypeWarning,
+			lib.Detached, "TCPRoute object %s is not valid", tcpRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: TCPRoute object %s is valid", key, tcpRoute.Name)
+	return true
+}
+
+func IsUDPRouteValid(key string, obj *gatewayv1alpha2.UDPRoute) bool {
+
+	udpRoute := obj.DeepCopy()
+	if len(udpRoute.Spec.ParentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the UDPRoute %s", key, udpRoute.Name)
+		return false
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range udpRoute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	udpRouteStatus := obj.Status.DeepCopy()
+	valid := validateL4Route(key, udpRoute, lib.UDPRoute, udpRoute.Spec.ParentRefs, nil, backendRefs, &udpRouteStatus.RouteStatus)
+	akogatewayapistatus.Record(key, udpRoute, &akogatewayapistatus.Status{UDPRouteStatus: udpRouteStatus})
+
+	// No valid attachment, we can't proceed with this UDPRoute object.
+	if !valid {
+		utils.AviLog.Errorf("key: %s, msg: UDPRoute object %s is not valid", key, udpRoute.Name)
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(udpRoute, corev1.EventTypeWarning,
+			lib.Detached, "UDPRoute object %s is not valid", udpRoute.Name)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: UDPRoute object %s is valid", key, udpRoute.Name)
+	return true
+}
+
+// validateL4Route validates the parent references and the backend references of the routes
+// which are not served by the EVH parent VS, i.e. TLSRoute, TCPRoute and UDPRoute. The result
+// is set in the route status, and it returns false if the route is not attached to any parent.
+func validateL4Route(key string, route metav1.Object, routeKind string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, backendRefs []gatewayv1.BackendRef, routeStatus *gatewayv1.RouteStatus) bool {
+	routeStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(parentRefs))
+	var invalidParentRefCount int
+	for index := range parentRefs {
+		err := validateParentReference(key, route, routeKind, parentRefs[index], hostnames, routeStatus)
+		if err != nil {
+			invalidParentRefCount++
+			parentRefName := parentRefs[index].Name
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of %s object %s is not valid, err: %v", key, parentRefName, routeKind, route.GetName(), err)
+		}
+	}
+
+	setResolvedRefsCondition(key, route, routeKind, backendRefs, routeStatus)
+	return invalidParentRefCount != len(parentRefs)
+}
+
+// setResolvedRefsCondition sets the ResolvedRefs condition, which is common for all the
+// parents of the route, only Services are supported as backends.
+func setResolvedRefsCondition(key string, route metav1.Object, routeKind string, backendRefs []gatewayv1.BackendRef, routeStatus *gatewayv1.RouteStatus) {
+	resolvedRefsCondition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.RouteConditionResolvedRefs)).
+		Reason(string(gatewayv1.RouteReasonResolvedRefs)).
+		Status(metav1.ConditionTrue).
+		ObservedGeneration(route.GetGeneration()).
+		Message("All the backend references are resolved")
+	if reason, err := validateBackendRefs(key, route, routeKind, backendRefs); err != nil {
+		resolvedRefsCondition.
+			Reason(string(reason)).
+			Status(metav1.ConditionFalse).
+			Message(err.Error())
+	}
+	for i := range routeStatus.Parents {
+		resolvedRefsCondition.SetIn(&routeStatus.Parents[i].Conditions)
+	}
+}
+
+func validateBackendRefs(key string, route metav1.Object, routeKind string, backendRefs []gatewayv1.BackendRef) (gatewayv1.RouteConditionReason, error) {
+	for _, backendRef := range backendRefs {
+		if (backendRef.Group != nil && string(*backendRef.Group) != "") ||
+			(backendRef.Kind != nil && string(*backendRef.Kind) != utils.Service) {
+			utils.AviLog.Warnf("key: %s, msg: backend %s of %s %s is not a Service", key, backendRef.Name, routeKind, route.GetName())
+			return gatewayv1.RouteReasonInvalidKind, fmt.Errorf("Backend %s is not a Service", backendRef.Name)
+		}
+		namespace := route.GetNamespace()
+		if backendRef.Namespace != nil {
+			namespace = string(*backendRef.Namespace)
+		}
+		if !akogatewayapilib.IsReferencePermitted(routeKind, route.GetNamespace(), utils.Service, namespace, string(backendRef.Name)) {
+			utils.AviLog.Warnf("key: %s, msg: backend Service %s/%s of %s %s is not permitted by any ReferenceGrant", key, namespace, backendRef.Name, routeKind, route.GetName())
+			return gatewayv1.RouteReasonRefNotPermitted, fmt.Errorf("Backend Service %s/%s is not permitted", namespace, backendRef.Name)
+		}
+		_, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(string(backendRef.Name))
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: backend Service %s/%s of %s %s not found, err: %v", key, namespace, backendRef.Name, routeKind, route.GetName(), err)
+			return gatewayv1.RouteReasonBackendNotFound, fmt.Errorf("Backend Service %s/%s not found", namespace, backendRef.Name)
+		}
+	}
+	return "", nil
+}
+
+func validateParentReference(key string, route metav1.Object, routeKind string, parentRef gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, routeStatus *gatewayv1.RouteStatus) error {
 
-	name := string(httpRoute.Spec.ParentRefs[index].Name)
-	namespace := httpRoute.Namespace
-	if httpRoute.Spec.ParentRefs[index].Namespace != nil {
-		namespace = string(*httpRoute.Spec.ParentRefs[index].Namespace)
+	name := string(parentRef.Name)
+	namespace := route.GetNamespace()
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
 	}
 
 	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
@@ -251,26 +618,27 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 	gwClass := string(gateway.Spec.GatewayClassName)
 	_, isAKOCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(gwClass)
 	if !isAKOCtrl {
-		utils.AviLog.Warnf("key: %s, msg: controller for the parent reference %s of HTTPRoute object %s is not ako", key, name, httpRoute.Name)
-		return fmt.Errorf("controller for the parent reference %s of HTTPRoute object %s is not ako", name, httpRoute.Name)
+		utils.AviLog.Warnf("key: %s, msg: controller for the parent reference %s of %s object %s is not ako", key, name, routeKind, route.GetName())
+		return fmt.Errorf("controller for the parent reference %s of %s object %s is not ako", name, routeKind, route.GetName())
 	}
 	// creates the Parent status only when the AKO is the gateway controller
-	httpRouteStatus.Parents = append(httpRouteStatus.Parents, gatewayv1.RouteParentStatus{})
-	httpRouteStatus.Parents[index].ControllerName = akogatewayapilib.GatewayController
-	httpRouteStatus.Parents[index].ParentRef.Name = gatewayv1.ObjectName(name)
-	httpRouteStatus.Parents[index].ParentRef.Namespace = (*gatewayv1.Namespace)(&namespace)
+	routeStatus.Parents = append(routeStatus.Parents, gatewayv1.RouteParentStatus{})
+	parentStatus := &routeStatus.Parents[len(routeStatus.Parents)-1]
+	parentStatus.ControllerName = akogatewayapilib.GatewayController
+	parentStatus.ParentRef.Name = gatewayv1.ObjectName(name)
+	parentStatus.ParentRef.Namespace = (*gatewayv1.Namespace)(&namespace)
 
 	defaultCondition := akogatewayapistatus.NewCondition().
 		Type(string(gatewayv1.GatewayConditionAccepted)).
 		Reason(string(gatewayv1.GatewayReasonInvalid)).
 		Status(metav1.ConditionFalse).
-		ObservedGeneration(httpRoute.ObjectMeta.Generation)
+		ObservedGeneration(route.GetGeneration())
 
 	//section name is optional
 	var listenersForRoute []gatewayv1.Listener
-	if httpRoute.Spec.ParentRefs[index].SectionName != nil {
-		listenerName := *httpRoute.Spec.ParentRefs[index].SectionName
-		httpRouteStatus.Parents[index].ParentRef.SectionName = &listenerName
+	if parentRef.SectionName != nil {
+		listenerName := *parentRef.SectionName
+		parentStatus.ParentRef.SectionName = &listenerName
 		i := akogatewayapilib.FindListenerByName(string(listenerName), gateway.Spec.Listeners)
 		if i == -1 {
 			// listener is not present in gateway
@@ -278,7 +646,7 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 			err := fmt.Errorf("Invalid listener name provided")
 			defaultCondition.
 				Message(err.Error()).
-				SetIn(&httpRouteStatus.Parents[index].Conditions)
+				SetIn(&parentStatus.Conditions)
 			return err
 		}
 		listenersForRoute = append(listenersForRoute, gateway.Spec.Listeners[i])
@@ -286,8 +654,47 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 		listenersForRoute = append(listenersForRoute, gateway.Spec.Listeners...)
 	}
 
-	var listenersMatchedToRoute []gatewayv1.Listener
+	// a route can only attach to the listeners that support its kind
+	var listenersForKind []gatewayv1.Listener
 	for _, listenerObj := range listenersForRoute {
+		if akogatewayapilib.IsRouteKindSupported(listenerObj.Protocol, routeKind) {
+			listenersForKind = append(listenersForKind, listenerObj)
+		}
+	}
+	if len(listenersForKind) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Gateway object %s don't have any listeners that allows %s", key, gateway.Name, routeKind)
+		err := fmt.Errorf("No listener in the Gateway allows %s", routeKind)
+		defaultCondition.
+			Reason(string(gatewayv1.RouteReasonNotAllowedByListeners)).
+			Message(err.Error()).
+			SetIn(&parentStatus.Conditions)
+		return err
+	}
+
+	// a route can only attach to the listeners that allow its namespace
+	var listenersForNamespace []gatewayv1.Listener
+	for _, listenerObj := range listenersForKind {
+		if akogatewayapilib.IsRouteNamespaceAllowed(listenerObj, gateway.Namespace, route.GetNamespace()) {
+			listenersForNamespace = append(listenersForNamespace, listenerObj)
+		}
+	}
+	if len(listenersForNamespace) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Gateway object %s don't have any listeners that allows %s from namespace %s", key, gateway.Name, routeKind, route.GetNamespace())
+		err := fmt.Errorf("No listener in the Gateway allows %s from namespace %s", routeKind, route.GetNamespace())
+		defaultCondition.
+			Reason(string(gatewayv1.RouteReasonNotAllowedByListeners)).
+			Message(err.Error()).
+			SetIn(&parentStatus.Conditions)
+		return err
+	}
+
+	var listenersMatchedToRoute []gatewayv1.Listener
+	for _, listenerObj := range listenersForNamespace {
+		// TCPRoute and UDPRoute don't have hostnames, these attach to the listener by the port
+		if routeKind == lib.TCPRoute || routeKind == lib.UDPRoute {
+			listenersMatchedToRoute = append(listenersMatchedToRoute, listenerObj)
+			continue
+		}
 		// TODO: Don't attach to a invalid listener configuration
 		// check from store
 		hostInListener := listenerObj.Hostname
@@ -305,21 +712,28 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 			utils.AviLog.Warnf("key: %s, msg: unable to match the hostname with listener hostname. err: %s", key, err)
 			continue
 		}
-		var matched bool
-		for _, host := range httpRoute.Spec.Hostnames {
+		// TLSRoute without hostnames inherits the hostname of the listener
+		matched := routeKind == lib.TLSRoute && len(hostnames) == 0
+		for _, host := range hostnames {
+			if routeKind == lib.TLSRoute {
+				// the wildcard hostnames of the TLSRoutes are matched by the SNI of the connections
+				_, hostMatched := akogatewayapilib.GetMatchedHostname(string(*hostInListener), string(host))
+				matched = matched || hostMatched
+				continue
+			}
 			matched = matched || expr.MatchString(string(host))
 		}
 		if !matched {
-			utils.AviLog.Warnf("key: %s, msg: Gateway object %s don't have any listeners that matches the hostnames in HTTPRoute %s", key, gateway.Name, httpRoute.Name)
+			utils.AviLog.Warnf("key: %s, msg: Gateway object %s don't have any listeners that matches the hostnames in %s %s", key, gateway.Name, routeKind, route.GetName())
 			continue
 		}
 		listenersMatchedToRoute = append(listenersMatchedToRoute, listenerObj)
 	}
 	if len(listenersMatchedToRoute) == 0 {
-		err := fmt.Errorf("Hostname in Gateway Listener doesn't match with any of the hostnames in HTTPRoute")
+		err := fmt.Errorf("Hostname in Gateway Listener doesn't match with any of the hostnames in %s", routeKind)
 		defaultCondition.
 			Message(err.Error()).
-			SetIn(&httpRouteStatus.Parents[index].Conditions)
+			SetIn(&parentStatus.Conditions)
 		return err
 	}
 	gatewayStatus := gateway.Status.DeepCopy()
@@ -333,7 +747,7 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 			err := fmt.Errorf("Couldn't find the listener %s in the Gateway status", listenerName)
 			defaultCondition.
 				Message(err.Error()).
-				SetIn(&httpRouteStatus.Parents[index].Conditions)
+				SetIn(&parentStatus.Conditions)
 			return err
 		}
 
@@ -345,7 +759,190 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 		Reason(string(gatewayv1.GatewayReasonAccepted)).
 		Status(metav1.ConditionTrue).
 		Message("Parent reference is valid").
-		SetIn(&httpRouteStatus.Parents[index].Conditions)
-	utils.AviLog.Infof("key: %s, msg: Parent Reference %s of HTTPRoute object %s is valid", key, name, httpRoute.Name)
+		SetIn(&parentStatus.Conditions)
+	utils.AviLog.Infof("key: %s, msg: Parent Reference %s of %s object %s is valid", key, name, routeKind, route.GetName())
 	return nil
 }
+
+// IsBackendTLSPolicyValid validates the BackendTLSPolicy and publishes its Accepted condition for each
+// Gateway, controlled by AKO, of the HTTPRoutes and the GRPCRoutes which refer to the targeted Service.
+func IsBackendTLSPolicyValid(key string, obj *gatewayv1alpha2.BackendTLSPolicy) bool {
+
+	policy := obj.DeepCopy()
+	reason, message := validateBackendTLSPolicy(key, policy)
+	valid := reason == gatewayv1alpha2.PolicyReasonAccepted
+	if valid {
+		utils.AviLog.Infof("key: %s, msg: BackendTLSPolicy object %s is valid", key, policy.Name)
+	} else {
+		utils.AviLog.Warnf("key: %s, msg: BackendTLSPolicy object %s is not accepted, %s", key, policy.Name, message)
+	}
+
+	// the ancestors published by the other controllers are retained
+	policyStatus := &gatewayv1alpha2.PolicyStatus{}
+	for _, ancestor := range policy.Status.Ancestors {
+		if ancestor.ControllerName != akogatewayapilib.GatewayController {
+			policyStatus.Ancestors = append(policyStatus.Ancestors, ancestor)
+		}
+	}
+	conditionStatus := metav1.ConditionTrue
+	if !valid {
+		conditionStatus = metav1.ConditionFalse
+	}
+	for _, ancestorRef := range getBackendTLSPolicyAncestors(key, policy) {
+		ancestorStatus := gatewayv1alpha2.PolicyAncestorStatus{
+			AncestorRef:    ancestorRef,
+			ControllerName: akogatewayapilib.GatewayController,
+		}
+		for _, oldAncestor := range policy.Status.Ancestors {
+			if oldAncestor.ControllerName == akogatewayapilib.GatewayController && reflect.DeepEqual(oldAncestor.AncestorRef, ancestorRef) {
+				ancestorStatus.Conditions = append([]metav1.Condition{}, oldAncestor.Conditions...)
+				break
+			}
+		}
+		akogatewayapistatus.NewCondition().
+			Type(string(gatewayv1alpha2.PolicyConditionAccepted)).
+			Reason(string(reason)).
+			Status(conditionStatus).
+			ObservedGeneration(policy.ObjectMeta.Generation).
+			Message(message).
+			SetIn(&ancestorStatus.Conditions)
+		policyStatus.Ancestors = append(policyStatus.Ancestors, ancestorStatus)
+	}
+	akogatewayapistatus.Record(key, policy, &akogatewayapistatus.Status{PolicyStatus: policyStatus})
+	return valid
+}
+
+func validateBackendTLSPolicy(key string, policy *gatewayv1alpha2.BackendTLSPolicy) (gatewayv1alpha2.PolicyConditionReason, string) {
+	targetRef := policy.Spec.TargetRef
+	if !akogatewayapilib.IsBackendTLSPolicyTargetService(policy) ||
+		(targetRef.Namespace != nil && string(*targetRef.Namespace) != policy.Namespace) {
+		return gatewayv1alpha2.PolicyReasonInvalid, "targetRef must refer to a Service in the namespace of the BackendTLSPolicy"
+	}
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(policy.Namespace).Get(string(targetRef.Name))
+	if err != nil {
+		return gatewayv1alpha2.PolicyReasonTargetNotFound, fmt.Sprintf("Service %s not found", targetRef.Name)
+	}
+	portName := ""
+	if targetRef.SectionName != nil {
+		portName = string(*targetRef.SectionName)
+		found := false
+		for _, svcPort := range svcObj.Spec.Ports {
+			if svcPort.Name == portName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return gatewayv1alpha2.PolicyReasonTargetNotFound, fmt.Sprintf("Service %s has no port %s", targetRef.Name, portName)
+		}
+	}
+	if _, err := akogatewayapilib.GetBackendTLSPolicyCACert(policy); err != nil {
+		return gatewayv1alpha2.PolicyReasonInvalid, err.Error()
+	}
+	appliedPolicy, _ := akogatewayapilib.GetBackendTLSPolicyForPort(key, policy.Namespace, string(targetRef.Name), portName)
+	if appliedPolicy != nil && appliedPolicy.Name != policy.Name {
+		return gatewayv1alpha2.PolicyReasonConflicted, fmt.Sprintf("BackendTLSPolicy %s is applied to the Service %s", appliedPolicy.Name, targetRef.Name)
+	}
+	return gatewayv1alpha2.PolicyReasonAccepted, "BackendTLSPolicy is accepted"
+}
+
+// getBackendTLSPolicyAncestors returns the sorted references of the Gateways, controlled by AKO, which are
+// the parents of the HTTPRoutes and the GRPCRoutes referring to the Service targeted by the BackendTLSPolicy.
+func getBackendTLSPolicyAncestors(key string, policy *gatewayv1alpha2.BackendTLSPolicy) []gatewayv1.ParentReference {
+	informer := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	svcName := string(policy.Spec.TargetRef.Name)
+	gatewayNsNames := make(map[string]struct{})
+	addParents := func(routeNamespace string, parentRefs []gatewayv1.ParentReference, backendRefs []gatewayv1.BackendRef) {
+		for _, backendRef := range backendRefs {
+			if !isServiceBackendRef(backendRef.BackendObjectReference, routeNamespace, policy.Namespace, svcName) {
+				continue
+			}
+			for _, parentRef := range parentRefs {
+				if (parentRef.Kind != nil && string(*parentRef.Kind) != lib.Gateway) ||
+					(parentRef.Group != nil && string(*parentRef.Group) != gatewayv1.GroupName) {
+					continue
+				}
+				namespace := routeNamespace
+				if parentRef.Namespace != nil {
+					namespace = string(*parentRef.Namespace)
+				}
+				gatewayNsNames[namespace+"/"+string(parentRef.Name)] = struct{}{}
+			}
+			return
+		}
+	}
+
+	httpRoutes, err := informer.HTTPRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the HTTPRoute objects, err: %v", key, err)
+	}
+	for _, httpRoute := range httpRoutes {
+		addParents(httpRoute.Namespace, httpRoute.Spec.ParentRefs, getHTTPRouteBackendRefs(httpRoute))
+	}
+	grpcRoutes, err := informer.GRPCRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the GRPCRoute objects, err: %v", key, err)
+	}
+	for _, grpcRoute := range grpcRoutes {
+		addParents(grpcRoute.Namespace, grpcRoute.Spec.ParentRefs, getGRPCRouteBackendRefs(grpcRoute))
+	}
+
+	ancestors := make([]gatewayv1.ParentReference, 0, len(gatewayNsNames))
+	for gatewayNsName := range gatewayNsNames {
+		namespace, name, _ := cache.SplitMetaNamespaceKey(gatewayNsName)
+		gateway, err := informer.GatewayInformer.Lister().Gateways(namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		gatewayClass, err := informer.GatewayClassInformer.Lister().Get(string(gateway.Spec.GatewayClassName))
+		if err != nil || gatewayClass.Spec.ControllerName != akogatewayapilib.GatewayController {
+			continue
+		}
+		ancestors = append(ancestors, gatewayv1.ParentReference{
+			Group:     (*gatewayv1.Group)(proto.String(gatewayv1.GroupName)),
+			Kind:      (*gatewayv1.Kind)(proto.String(lib.Gateway)),
+			Namespace: (*gatewayv1.Namespace)(proto.String(namespace)),
+			Name:      gatewayv1.ObjectName(name),
+		})
+	}
+	sort.Slice(ancestors, func(i, j int) bool {
+		if *ancestors[i].Namespace != *ancestors[j].Namespace {
+			return *ancestors[i].Namespace < *ancestors[j].Namespace
+		}
+		return ancestors[i].Name < ancestors[j].Name
+	})
+	return ancestors
+}
+
+// isServiceBackendRef returns true if the backend of a route in the namespace routeNamespace is the Service namespace/name.
+func isServiceBackendRef(backendRef gatewayv1.BackendObjectReference, routeNamespace, namespace, name string) bool {
+	if (backendRef.Group != nil && *backendRef.Group != "") ||
+		(backendRef.Kind != nil && string(*backendRef.Kind) != utils.Service) {
+		return false
+	}
+	backendNamespace := routeNamespace
+	if backendRef.Namespace != nil {
+		backendNamespace = string(*backendRef.Namespace)
+	}
+	return backendNamespace == namespace && string(backendRef.Name) == name
+}
+
+func getHTTPRouteBackendRefs(httpRoute *gatewayv1.HTTPRoute) []gatewayv1.BackendRef {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	return backendRefs
+}
+
+func getGRPCRouteBackendRefs(grpcRoute *gatewayv1alpha2.GRPCRoute) []gatewayv1.BackendRef {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range grpcRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	return backendRefs
+}
@@ -14,6 +14,10 @@
 
 package lib
 
+import (
+	"time"
+)
+
 const (
 	Prefix            = "ako-gw-"
 	GatewayController = "ako.vmware.com/avi-lb"
@@ -25,4 +29,72 @@ const (
 
 const (
 	GatewayClassGatewayControllerIndex = "GatewayClassGatewayController"
+
+	// ReferenceGrantFromIndex maintains a map of <grant namespace>/<from kind>/<from namespace>
+	// to the ReferenceGrant objects, sample key: ns-backend/HTTPRoute/ns-frontend
+	ReferenceGrantFromIndex = "ReferenceGrantFrom"
+
+	// BackendTLSPolicyTargetIndex maintains a map of <namespace>/<service name> to the
+	// BackendTLSPolicy objects targeting the Service, sample key: default/svc-backend
+	BackendTLSPolicyTargetIndex = "BackendTLSPolicyTarget"
+
+	// BackendTLSPolicyCACertIndex maintains a map of <namespace>/<secret name> to the
+	// BackendTLSPolicy objects referring to the Secret as CA certificate, sample key: default/backend-ca
+	BackendTLSPolicyCACertIndex = "BackendTLSPolicyCACert"
+)
+
+const (
+	// BackendTLSPolicyCACertKey is the key of the PEM encoded CA certificates in the ConfigMaps
+	// and the Secrets referred by the BackendTLSPolicy objects.
+	BackendTLSPolicyCACertKey = "ca.crt"
+)
+
+const (
+	// MaxPoolServerTimeout is the largest server timeout which can be set on the Avi pools.
+	MaxPoolServerTimeout = 6 * time.Hour
+)
+
+const (
+	// PassthroughDatascript selects the poolgroup of the connections on the passthrough listeners of the
+	// gateway, by the SNI of the connection. The HOSTNAME_POOLGROUPS placeholder is replaced with the table of
+	// the poolgroups keyed by the SNI hostnames. An exact hostname is preferred over the wildcard hostnames,
+	// and a longer wildcard hostname is preferred over a shorter one.
+	PassthroughDatascript = `local avi_tls = require "Default-TLS"
+	local poolgroups = HOSTNAME_POOLGROUPS
+	buffered = avi.l4.collect(20)
+	payload = avi.l4.read()
+	len = avi_tls.get_req_buffer_size(payload)
+	if ( buffered < len ) then
+	  avi.l4.collect(len)
+	end
+	if ( avi_tls.sanity_check(payload) ) then
+	   local h = avi_tls.parse_record(payload)
+	   local sname = avi_tls.get_sni(h)
+	   if sname == nil then
+		  avi.vs.log('SNI not present')
+		  avi.vs.close_conn()
+	   else
+		  avi.vs.log("SNI=".. sname)
+		  local pg_name = poolgroups[sname]
+		  local suffix = sname
+		  while pg_name == nil do
+			 local dot = string.find(suffix, ".", 1, true)
+			 if dot == nil then
+				break
+			 end
+			 suffix = string.sub(suffix, dot + 1)
+			 pg_name = poolgroups["*." .. suffix]
+		  end
+		  if pg_name == nil then
+			 avi.vs.log("No poolgroup for SNI=".. sname)
+			 avi.vs.close_conn()
+		  else
+			 avi.poolgroup.select(pg_name)
+		  end
+	   end
+	else
+	   avi.vs.close_conn()
+	end
+	avi.l4.ds_done()
+	avi_tls = nil`
 )
@@ -15,21 +15,37 @@
 package lib
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
 func InformersToRegister(kclient *kubernetes.Clientset) ([]string, error) {
+	// Watch over EndpointSlices instead of Endpoints when the EndpointSlice based discovery is enabled.
+	endpointInformer := utils.EndpointInformer
+	if lib.IsEndpointSliceEnabled() {
+		endpointInformer = utils.EndpointSliceInformer
+	}
 	// Initialize the following informers in all AKO deployments. Provide AKO the ability to watch over
-	// Services, Endpoints, Secrets, ConfigMaps.
+	// Services, Endpoints, Secrets, ConfigMaps, Namespaces.
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
+		endpointInformer,
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
+		utils.NSInformer,
 	}
 
 	return allInformers, nil
@@ -42,6 +58,39 @@ func GetGatewayParentName(namespace, gwName string) string {
 	return lib.GetNamePrefix() + namespace + "-" + gwName + "-EVH"
 }
 
+// passthrough pg name format - ako-gw-clustername--encoded value of gatewayNs-gatewayName-hostname
+func GetPassthroughPGName(namespace, gwName, hostname string) string {
+	name := namespace + "-" + gwName + "-" + hostname
+	return lib.Encode(name, lib.PG)
+}
+
+func GetPassthroughPoolName(namespace, gwName, hostname, backendNs, backendName, backendPort string) string {
+	name := namespace + "-" + gwName + "-" + hostname + "-" + backendNs + "-" + backendName + "-" + backendPort
+	return lib.Encode(name, lib.Pool)
+}
+
+// passthrough datascript name format - ako-gw-clustername--encoded value of gatewayNs-gatewayName-Passthrough
+func GetPassthroughDSName(namespace, gwName string) string {
+	name := namespace + "-" + gwName + "-Passthrough"
+	return lib.Encode(name, lib.DataScript)
+}
+
+// l4 vs name format - ako-gw-clustername--gatewayNs-gatewayName-L4
+func GetGatewayL4Name(namespace, gwName string) string {
+	// The name must not contain -EVH, the L4 VS is processed by the non EVH rest layer
+	return lib.GetNamePrefix() + namespace + "-" + gwName + "-L4"
+}
+
+func GetL4PoolGroupName(namespace, gwName, listenerName string) string {
+	name := namespace + "-" + gwName + "-" + listenerName
+	return lib.Encode(name, lib.PG)
+}
+
+func GetL4PoolName(namespace, gwName, listenerName, backendNs, backendName, backendPort string) string {
+	name := namespace + "-" + gwName + "-" + listenerName + "-" + backendNs + "-" + backendName + "-" + backendPort
+	return lib.Encode(name, lib.Pool)
+}
+
 // child vs name format - ako-gw-clustername--encoded value of ako-gw-clustername--parentNs-parentName-routeNs-routeName-encodedMatch
 func GetChildName(parentNs, parentName, routeNs, routeName, matchName string) string {
 	name := parentNs + "-" + parentName + "-" + routeNs + "-" + routeName + "-" + utils.Stringify(utils.Hash(matchName))
@@ -62,6 +111,46 @@ func CheckGatewayClassController(controllerName string) bool {
 	return controllerName == lib.AviIngressController
 }
 
+// GetMatchedHostname returns the hostname of the connections, for which a route hostname is attached to
+// a listener hostname. A wildcard hostname matches the hostnames with one or more additional labels, in
+// which case the more specific hostname is returned.
+func GetMatchedHostname(listenerHostname, routeHostname string) (string, bool) {
+	if listenerHostname == routeHostname {
+		return routeHostname, true
+	}
+	if strings.HasPrefix(listenerHostname, "*.") && strings.HasSuffix(routeHostname, listenerHostname[1:]) {
+		return routeHostname, true
+	}
+	if strings.HasPrefix(routeHostname, "*.") && strings.HasSuffix(listenerHostname, routeHostname[1:]) {
+		return listenerHostname, true
+	}
+	return "", false
+}
+
+// IsPassthroughListener returns true for the TLS listeners that pass the
+// connection through to the backends without terminating it.
+func IsPassthroughListener(listener gatewayv1.Listener) bool {
+	return listener.Protocol == gatewayv1.TLSProtocolType &&
+		listener.TLS != nil && listener.TLS.Mode != nil &&
+		*listener.TLS.Mode == gatewayv1.TLSModePassthrough
+}
+
+// IsL4Listener returns true for the TCP and UDP listeners, which are
+// served by the L4 policies of the L4 VS of the gateway.
+func IsL4Listener(listener gatewayv1.Listener) bool {
+	return listener.Protocol == gatewayv1.TCPProtocolType ||
+		listener.Protocol == gatewayv1.UDPProtocolType
+}
+
+func IsRouteKindSupported(protocol gatewayv1.ProtocolType, kind string) bool {
+	for _, routeGroupKind := range SupportedKinds[protocol] {
+		if string(routeGroupKind.Kind) == kind {
+			return true
+		}
+	}
+	return false
+}
+
 func FindListenerByName(name string, listener []gatewayv1.Listener) int {
 	for i := range listener {
 		if string(listener[i].Name) == name {
@@ -79,3 +168,217 @@ func FindListenerStatusByName(name string, status []gatewayv1.ListenerStatus) in
 	}
 	return -1
 }
+
+func GetReferenceGrantFromIndexKey(grantNamespace, fromKind, fromNamespace string) string {
+	return grantNamespace + "/" + fromKind + "/" + fromNamespace
+}
+
+// IsReferencePermitted returns true if the object of kind fromKind in the namespace fromNamespace
+// is allowed to refer to the core object toKind toNamespace/toName. The references within the same
+// namespace are always allowed, the cross namespace references must be allowed by a ReferenceGrant
+// in the namespace of the referent.
+func IsReferencePermitted(fromKind, fromNamespace, toKind, toNamespace, toName string) bool {
+	if fromNamespace == toNamespace {
+		return true
+	}
+	informers := AKOControlConfig().GatewayApiInformers()
+	if informers == nil || informers.ReferenceGrantInformer == nil {
+		return false
+	}
+	indexKey := GetReferenceGrantFromIndexKey(toNamespace, fromKind, fromNamespace)
+	objs, err := informers.ReferenceGrantInformer.Informer().GetIndexer().ByIndex(ReferenceGrantFromIndex, indexKey)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to get the ReferenceGrants for %s, err: %v", indexKey, err)
+		return false
+	}
+	for _, obj := range objs {
+		referenceGrant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+		if !ok {
+			continue
+		}
+		for _, to := range referenceGrant.Spec.To {
+			if to.Group == "" && string(to.Kind) == toKind && (to.Name == nil || string(*to.Name) == toName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsRouteNamespaceAllowed returns true if the AllowedRoutes of the listener of the Gateway in the namespace
+// gwNamespace admit the routes from the namespace routeNamespace. The routes from the namespace of the Gateway
+// are admitted by default, the Selector is evaluated against the labels of the namespace of the route.
+func IsRouteNamespaceAllowed(listener gatewayv1.Listener, gwNamespace, routeNamespace string) bool {
+	from := gatewayv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes.Namespaces.From != nil {
+			from = *listener.AllowedRoutes.Namespaces.From
+		}
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true
+	case gatewayv1.NamespacesFromSelector:
+		if selector == nil {
+			return false
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			utils.AviLog.Warnf("Invalid namespace selector in the listener %s, err: %v", listener.Name, err)
+			return false
+		}
+		nsObj, err := utils.GetInformers().NSInformer.Lister().Get(routeNamespace)
+		if err != nil {
+			utils.AviLog.Warnf("Unable to get the namespace %s, err: %v", routeNamespace, err)
+			return false
+		}
+		return labelSelector.Matches(labels.Set(nsObj.GetLabels()))
+	default:
+		return gwNamespace == routeNamespace
+	}
+}
+
+// GetAviInfraSettingName returns the name of the AviInfraSetting which applies to the Gateway. The AviInfraSetting
+// annotation in the infrastructure of the Gateway takes precedence over the parametersRef of the GatewayClass.
+func GetAviInfraSettingName(gateway *gatewayv1.Gateway) string {
+	if gateway.Spec.Infrastructure != nil {
+		if name, ok := gateway.Spec.Infrastructure.Annotations[gatewayv1.AnnotationKey(lib.InfraSettingNameAnnotation)]; ok && name != "" {
+			return string(name)
+		}
+	}
+	informers := AKOControlConfig().GatewayApiInformers()
+	if informers == nil || informers.GatewayClassInformer == nil {
+		return ""
+	}
+	gwClass, err := informers.GatewayClassInformer.Lister().Get(string(gateway.Spec.GatewayClassName))
+	if err != nil {
+		return ""
+	}
+	if IsAviInfraSettingParametersRef(gwClass.Spec.ParametersRef) {
+		return gwClass.Spec.ParametersRef.Name
+	}
+	return ""
+}
+
+// IsAviInfraSettingParametersRef returns true if the parametersRef of a GatewayClass refers to an AviInfraSetting.
+func IsAviInfraSettingParametersRef(parametersRef *gatewayv1.ParametersReference) bool {
+	return parametersRef != nil && string(parametersRef.Group) == lib.AkoGroup && string(parametersRef.Kind) == lib.AviInfraSetting
+}
+
+// GetAviInfraSetting returns the accepted AviInfraSetting which applies to the Gateway, or nil if the Gateway
+// doesn't refer to any AviInfraSetting or the AviInfraSetting isn't accepted yet.
+func GetAviInfraSetting(key string, gateway *gatewayv1.Gateway) *akov1beta1.AviInfraSetting {
+	infraSettingName := GetAviInfraSettingName(gateway)
+	if infraSettingName == "" {
+		return nil
+	}
+	crdInformers := lib.AKOControlConfig().CRDInformers()
+	if crdInformers == nil || crdInformers.AviInfraSettingInformer == nil {
+		utils.AviLog.Warnf("key: %s, msg: AviInfraSetting informer is not initialized, ignoring AviInfraSetting %s", key, infraSettingName)
+		return nil
+	}
+	infraSetting, err := crdInformers.AviInfraSettingInformer.Lister().Get(infraSettingName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: Unable to get the AviInfraSetting %s, err: %v", key, infraSettingName, err)
+		return nil
+	}
+	if infraSetting.Status.Status != lib.StatusAccepted {
+		utils.AviLog.Warnf("key: %s, msg: Referred AviInfraSetting %s is not accepted", key, infraSettingName)
+		return nil
+	}
+	return infraSetting
+}
+
+func GetBackendTLSPolicyIndexKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// IsBackendTLSPolicyTargetService returns true if the BackendTLSPolicy targets a core Service,
+// which is the only kind of target supported by AKO.
+func IsBackendTLSPolicyTargetService(policy *gatewayv1alpha2.BackendTLSPolicy) bool {
+	targetRef := policy.Spec.TargetRef
+	return targetRef.Group == "" && string(targetRef.Kind) == utils.Service
+}
+
+// GetBackendTLSPolicyCACert returns the PEM encoded CA certificates of the BackendTLSPolicy, which
+// are read from the ca.crt key of the ConfigMaps and the Secrets in the namespace of the policy.
+func GetBackendTLSPolicyCACert(policy *gatewayv1alpha2.BackendTLSPolicy) (string, error) {
+	if policy.Spec.TLS.WellKnownCACerts != nil {
+		return "", fmt.Errorf("wellKnownCACerts %s is not supported", *policy.Spec.TLS.WellKnownCACerts)
+	}
+	if len(policy.Spec.TLS.CACertRefs) == 0 {
+		return "", fmt.Errorf("caCertRefs must be specified")
+	}
+	caCerts := make([]string, 0, len(policy.Spec.TLS.CACertRefs))
+	for _, caCertRef := range policy.Spec.TLS.CACertRefs {
+		if caCertRef.Group != "" {
+			return "", fmt.Errorf("group %s of caCertRef %s is not supported", caCertRef.Group, caCertRef.Name)
+		}
+		var data []byte
+		switch string(caCertRef.Kind) {
+		case "ConfigMap":
+			configMap, err := utils.GetInformers().ClientSet.CoreV1().ConfigMaps(policy.Namespace).Get(context.TODO(), string(caCertRef.Name), metav1.GetOptions{})
+			if err != nil {
+				return "", fmt.Errorf("unable to get the ConfigMap %s, err: %v", caCertRef.Name, err)
+			}
+			data = []byte(configMap.Data[BackendTLSPolicyCACertKey])
+		case utils.Secret:
+			secret, err := utils.GetInformers().SecretInformer.Lister().Secrets(policy.Namespace).Get(string(caCertRef.Name))
+			if err != nil {
+				return "", fmt.Errorf("unable to get the Secret %s, err: %v", caCertRef.Name, err)
+			}
+			data = secret.Data[BackendTLSPolicyCACertKey]
+		default:
+			return "", fmt.Errorf("kind %s of caCertRef %s is not supported", caCertRef.Kind, caCertRef.Name)
+		}
+		if len(data) == 0 {
+			return "", fmt.Errorf("%s %s has no %s key", caCertRef.Kind, caCertRef.Name, BackendTLSPolicyCACertKey)
+		}
+		caCerts = append(caCerts, strings.TrimSpace(string(data)))
+	}
+	return strings.Join(caCerts, "\n"), nil
+}
+
+// GetBackendTLSPolicyForPort returns the BackendTLSPolicy, and its CA certificates, applied to the
+// connections to the port portName of the Service namespace/name. Among the valid policies targeting
+// the Service, or the port, the oldest one is applied and the other ones are conflicted.
+func GetBackendTLSPolicyForPort(key, namespace, name, portName string) (*gatewayv1alpha2.BackendTLSPolicy, string) {
+	informers := AKOControlConfig().GatewayApiInformers()
+	if informers == nil || informers.BackendTLSPolicyInformer == nil {
+		return nil, ""
+	}
+	objs, err := informers.BackendTLSPolicyInformer.Informer().GetIndexer().ByIndex(BackendTLSPolicyTargetIndex, GetBackendTLSPolicyIndexKey(namespace, name))
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendTLSPolicies for the Service %s/%s, err: %v", key, namespace, name, err)
+		return nil, ""
+	}
+	policies := make([]*gatewayv1alpha2.BackendTLSPolicy, 0, len(objs))
+	for _, obj := range objs {
+		policy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+		if !ok || !IsBackendTLSPolicyTargetService(policy) {
+			continue
+		}
+		sectionName := policy.Spec.TargetRef.SectionName
+		if sectionName != nil && string(*sectionName) != portName {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if !policies[i].CreationTimestamp.Equal(&policies[j].CreationTimestamp) {
+			return policies[i].CreationTimestamp.Before(&policies[j].CreationTimestamp)
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	for _, policy := range policies {
+		caCert, err := GetBackendTLSPolicyCACert(policy)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: BackendTLSPolicy %s/%s is not valid, err: %v", key, policy.Namespace, policy.Name, err)
+			continue
+		}
+		return policy, caCert
+	}
+	return nil, ""
+}
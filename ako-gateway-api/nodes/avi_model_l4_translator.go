@@ -15,21 +15,255 @@
 package nodes
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
-func (o *AviObjectGraph) ProcessL4Routes(key string, routeModel RouteModel, parentNsName string) {
+// l4Route holds a TCPRoute or a UDPRoute attached to the gateway.
+type l4Route struct {
+	meta       metav1.ObjectMeta
+	parentRefs []gatewayv1.ParentReference
+	model      RouteModel
+}
+
+// BuildGatewayL4Vs builds the L4 VS for the TCP and UDP listeners of the gateway.
+// The connections are switched to a poolgroup per listener by the L4 policyset of the VS.
+func (o *AviObjectGraph) BuildGatewayL4Vs(gateway *gatewayv1.Gateway, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+
+	vsName := akogatewayapilib.GetGatewayL4Name(gateway.Namespace, gateway.Name)
+	vsNode := &nodes.AviVsNode{
+		Name:               vsName,
+		Tenant:             lib.GetTenant(),
+		ApplicationProfile: utils.DEFAULT_L4_APP_PROFILE,
+		VrfContext:         lib.GetVrf(),
+	}
+
+	isTCP, isUDP := false, false
+	for _, listener := range gateway.Spec.Listeners {
+		if !akogatewayapilib.IsL4Listener(listener) {
+			continue
+		}
+		pp := nodes.AviPortHostProtocol{Port: int32(listener.Port), Protocol: string(listener.Protocol)}
+		vsNode.PortProto = append(vsNode.PortProto, pp)
+		if listener.Protocol == gatewayv1.UDPProtocolType {
+			isUDP = true
+		} else {
+			isTCP = true
+		}
+	}
+	vsNode.NetworkProfile = nodes.GetNetworkProfile(false, isTCP, isUDP)
+
+	infraSetting := akogatewayapilib.GetAviInfraSetting(key, gateway)
+	vsNode.ServiceEngineGroup, vsNode.EnableRhi = getServiceEngineGroupAndRhi(infraSetting)
+	vsvipOwner := setGatewayVsRefs(gateway, vsName, &vsNode.ServiceMetadata)
+	vsNode.VSVIPRefs = []*nodes.AviVSVIPNode{BuildVsVipNodeForGateway(gateway, vsvipOwner, infraSetting)}
+
+	o.ProcessL4Routes(key, gateway, vsNode)
+
+	o.AddModelNode(vsNode)
+	utils.AviLog.Infof("key: %s, msg: checksum for AVI L4 VS object %v", key, vsNode.GetCheckSum())
+}
+
+// ProcessL4Routes attaches the TCPRoutes and UDPRoutes of the gateway to the L4 listeners.
+// A listener is served by a single route, the oldest route is given the listener when
+// more than one route is attached to it.
+func (o *AviObjectGraph) ProcessL4Routes(key string, gateway *gatewayv1.Gateway, vsNode *nodes.AviVsNode) {
+	l4Routes := getL4Routes(gateway, key)
+	l4PolicyNode := &nodes.AviL4PolicyNode{
+		Name:   vsNode.Name,
+		Tenant: lib.GetTenant(),
+	}
+	for _, listener := range gateway.Spec.Listeners {
+		if !akogatewayapilib.IsL4Listener(listener) {
+			continue
+		}
+		for _, route := range l4Routes {
+			if !akogatewayapilib.IsRouteKindSupported(listener.Protocol, route.model.GetType()) ||
+				!isRouteAttachedToListener(gateway, listener, route.meta.Namespace, route.parentRefs) {
+				continue
+			}
+			o.BuildL4PolicySet(key, gateway, vsNode, l4PolicyNode, listener, route.model)
+			break
+		}
+	}
+	if len(l4PolicyNode.PortPool) > 0 {
+		vsNode.L4PolicyRefs = []*nodes.AviL4PolicyNode{l4PolicyNode}
+	}
+}
+
+// BuildL4PolicySet adds the L4 policy rule for the listener, which selects the poolgroup
+// built with the backends of all the rules of the route.
+func (o *AviObjectGraph) BuildL4PolicySet(key string, gateway *gatewayv1.Gateway, vsNode *nodes.AviVsNode, l4PolicyNode *nodes.AviL4PolicyNode, listener gatewayv1.Listener, routeModel RouteModel) {
+	var backends []*Backend
 	for _, rule := range routeModel.ParseRouteRules().Rules {
-		parentNode := o.GetAviEvhVS()
+		backends = append(backends, rule.Backends...)
+	}
+	listenerName := string(listener.Name)
+	pgName := akogatewayapilib.GetL4PoolGroupName(gateway.Namespace, gateway.Name, listenerName)
+	pgNode, poolNodes := buildL4PoolGroup(key, pgName, string(listener.Protocol), nil, backends, func(backend *Backend) string {
+		return akogatewayapilib.GetL4PoolName(gateway.Namespace, gateway.Name, listenerName,
+			backend.Namespace, backend.Name, strconv.Itoa(int(backend.Port)))
+	})
+	vsNode.PoolRefs = append(vsNode.PoolRefs, poolNodes...)
+	vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, pgNode)
 
-		// create L4 policyset per rule
-		o.BuildL4PolicySet(key, parentNode[0], routeModel, rule)
+	l4PolicyNode.PortPool = append(l4PolicyNode.PortPool, nodes.AviHostPathPortPoolPG{
+		Port:      uint32(listener.Port),
+		Protocol:  string(listener.Protocol),
+		PoolGroup: fmt.Sprintf("/api/poolgroup?name=%s", pgNode.Name),
+	})
+	utils.AviLog.Infof("key: %s, msg: %s %s/%s attached to the listener %s of the gateway %s/%s", key,
+		routeModel.GetType(), routeModel.GetNamespace(), routeModel.GetName(), listenerName, gateway.Namespace, gateway.Name)
+}
+
+// getL4Routes returns the TCPRoutes and UDPRoutes attached to the gateway, sorted by
+// the creation timestamp.
+func getL4Routes(gateway *gatewayv1.Gateway, key string) []*l4Route {
+	gwNsName := gateway.Namespace + "/" + gateway.Name
+	_, routeTypeNsNameList := akogatewayapiobjects.GatewayApiLister().GetGatewayToRoute(gwNsName)
 
-		// Logic to override the app profile per port also must be taken care here.
+	var l4Routes []*l4Route
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		routeType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
+		route := &l4Route{}
+		switch routeType {
+		case lib.TCPRoute:
+			routeObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+			if err != nil {
+				utils.AviLog.Debugf("key: %s, msg: unable to get the TCPRoute %s, err: %v", key, routeTypeNsName, err)
+				continue
+			}
+			route.meta = routeObj.ObjectMeta
+			route.parentRefs = routeObj.Spec.ParentRefs
+			route.model = &tcpRoute{key: key, name: name, namespace: namespace, spec: routeObj.Spec.DeepCopy()}
+		case lib.UDPRoute:
+			routeObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+			if err != nil {
+				utils.AviLog.Debugf("key: %s, msg: unable to get the UDPRoute %s, err: %v", key, routeTypeNsName, err)
+				continue
+			}
+			route.meta = routeObj.ObjectMeta
+			route.parentRefs = routeObj.Spec.ParentRefs
+			route.model = &udpRoute{key: key, name: name, namespace: namespace, spec: routeObj.Spec.DeepCopy()}
+		default:
+			continue
+		}
+		l4Routes = append(l4Routes, route)
 	}
+	sort.Slice(l4Routes, func(i, j int) bool {
+		if !l4Routes[i].meta.CreationTimestamp.Equal(&l4Routes[j].meta.CreationTimestamp) {
+			return l4Routes[i].meta.CreationTimestamp.Before(&l4Routes[j].meta.CreationTimestamp)
+		}
+		return l4Routes[i].meta.Namespace+"/"+l4Routes[i].meta.Name < l4Routes[j].meta.Namespace+"/"+l4Routes[j].meta.Name
+	})
+	return l4Routes
 }
 
-func (o *AviObjectGraph) BuildL4PolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+// isRouteAttachedToListener returns true if any of the parent references of the route
+// refers to the listener of the gateway.
+func isRouteAttachedToListener(gateway *gatewayv1.Gateway, listener gatewayv1.Listener, routeNamespace string, parentRefs []gatewayv1.ParentReference) bool {
+	if !isRouteNamespaceAllowed(gateway, listener, routeNamespace) {
+		return false
+	}
+	for _, parentRef := range parentRefs {
+		ns := routeNamespace
+		if parentRef.Namespace != nil {
+			ns = string(*parentRef.Namespace)
+		}
+		if ns != gateway.Namespace || string(parentRef.Name) != gateway.Name {
+			continue
+		}
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+			continue
+		}
+		if parentRef.Port != nil && *parentRef.Port != listener.Port {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// buildL4PoolGroup builds a poolgroup with a pool per backend of the route, the backends
+// are resolved to the pool servers using the service port.
+func buildL4PoolGroup(key, pgName, protocol string, hostnames []string, backends []*Backend, getPoolName func(backend *Backend) string) (*nodes.AviPoolGroupNode, []*nodes.AviPoolNode) {
+	pgNode := &nodes.AviPoolGroupNode{
+		Name:   pgName,
+		Tenant: lib.GetTenant(),
+	}
+	var poolNodes []*nodes.AviPoolNode
+	for _, backend := range backends {
+		if backend.Weight == 0 {
+			continue
+		}
+		svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(backend.Namespace).Get(backend.Name)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: there was an error in retrieving the service %s/%s", key, backend.Namespace, backend.Name)
+			continue
+		}
+		poolNode := &nodes.AviPoolNode{
+			Name:     getPoolName(backend),
+			Tenant:   lib.GetTenant(),
+			Protocol: protocol,
+			ServiceMetadata: lib.ServiceMetadataObj{
+				NamespaceServiceName: []string{backend.Namespace + "/" + backend.Name},
+				HostNames:            hostnames,
+			},
+			VrfContext: lib.GetVrf(),
+		}
+		for _, port := range svcObj.Spec.Ports {
+			if port.Port == backend.Port {
+				poolNode.PortName = port.Name
+				poolNode.TargetPort = port.TargetPort
+				if poolNode.TargetPort == (intstr.IntOrString{}) {
+					poolNode.TargetPort = intstr.FromInt(int(port.Port))
+				}
+				break
+			}
+		}
+		poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
+		serviceType := lib.GetServiceType()
+		if serviceType == lib.NodePort {
+			servers := nodes.PopulateServersForNodePort(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
+			if servers != nil {
+				poolNode.Servers = servers
+			}
+		} else {
+			servers := nodes.PopulateServers(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
+			if servers != nil {
+				poolNode.Servers = servers
+			}
+		}
+		poolNode.CalculateCheckSum()
+		poolNodes = append(poolNodes, poolNode)
 
-	// TODO: add the l4policset code here
+		ratio := backend.Weight
+		poolRef := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+		pgNode.Members = append(pgNode.Members, &models.PoolGroupMember{PoolRef: &poolRef, Ratio: &ratio})
+	}
+	return pgNode, poolNodes
+}
+
+// HasL4Listeners returns true if the gateway has TCP or UDP listeners.
+func HasL4Listeners(gateway *gatewayv1.Gateway) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if akogatewayapilib.IsL4Listener(listener) {
+			return true
+		}
+	}
+	return false
 }
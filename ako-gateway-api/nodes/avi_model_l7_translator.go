@@ -16,16 +16,20 @@ package nodes
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/vmware/alb-sdk/go/models"
 	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -51,7 +55,7 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 		return
 	}
 
-	childVSName := akogatewayapilib.GetChildName(parentNs, parentName, routeModel.GetNamespace(), routeModel.GetName(), utils.Stringify(rule.Matches))
+	childVSName := akogatewayapilib.GetChildName(parentNs, parentName, routeModel.GetNamespace(), routeModel.GetName(), getRuleMatchName(routeModel, rule))
 	childVSes[childVSName] = struct{}{}
 
 	childNode := parentNode[0].GetEvhNodeForName(childVSName)
@@ -65,9 +69,11 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 
 	childNode.ServiceMetadata = lib.ServiceMetadataObj{
 		Gateway: parentName,
+		// retained so that the settings of a detached HostRule are removed from the child VS
+		CRDStatus: childNode.ServiceMetadata.CRDStatus,
 	}
 	childNode.ApplicationProfile = utils.DEFAULT_L7_APP_PROFILE
-	childNode.ServiceEngineGroup = lib.GetSEGName()
+	childNode.ServiceEngineGroup = parentNode[0].ServiceEngineGroup
 	childNode.VrfContext = lib.GetVrf()
 	childNode.AviMarkers = utils.AviObjectMarkers{
 		GatewayName: parentName,
@@ -89,6 +95,12 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 	// create the httppolicyset if the filter is present
 	o.BuildHTTPPolicySet(key, childNode, routeModel, rule)
 
+	// mirror the requests if the RequestMirror filter is present
+	o.BuildSidebandProfile(key, parentNode[0], childNode, rule)
+
+	// apply the HostRule of the hostnames of the route
+	o.BuildHostRule(key, childNode, routeModel)
+
 	foundEvhModel := nodes.FindAndReplaceEvhInModel(childNode, parentNode, key)
 	if !foundEvhModel {
 		parentNode[0].EvhNodes = append(parentNode[0].EvhNodes, childNode)
@@ -101,7 +113,7 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 
 	// create the PG from backends
-	routeTypeNsName := lib.HTTPRoute + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
 	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
 	_, listeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
 	//ListenerName/port/protocol/allowedRouteSpec
@@ -109,7 +121,7 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 	listenerProtocol := listenerSlice[2]
 	PGName := akogatewayapilib.GetPoolGroupName(parentNs, parentName,
 		routeModel.GetNamespace(), routeModel.GetName(),
-		utils.Stringify(rule.Matches))
+		getRuleMatchName(routeModel, rule))
 	PG := &nodes.AviPoolGroupNode{
 		Name:   PGName,
 		Tenant: lib.GetTenant(),
@@ -117,7 +129,7 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 	for _, backend := range rule.Backends {
 		poolName := akogatewayapilib.GetPoolName(parentNs, parentName,
 			routeModel.GetNamespace(), routeModel.GetName(),
-			utils.Stringify(rule.Matches),
+			getRuleMatchName(routeModel, rule),
 			backend.Namespace, backend.Name, strconv.Itoa(int(backend.Port)))
 		svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(backend.Namespace).Get(backend.Name)
 		if err != nil {
@@ -134,6 +146,9 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 				NamespaceServiceName: []string{backend.Namespace + "/" + backend.Name},
 			},
 			VrfContext: lib.GetVrf(),
+			// gRPC requires HTTP/2 to the backends
+			EnableHttp2:   routeModel.GetType() == lib.GRPCRoute,
+			ServerTimeout: getServerTimeout(rule),
 		}
 		poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
 		serviceType := lib.GetServiceType()
@@ -148,6 +163,8 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 				poolNode.Servers = servers
 			}
 		}
+		o.BuildPoolSecurity(key, poolNode, svcObj, backend.Port)
+		nodes.BuildPoolHTTPRuleForPaths(routeModel.ParseRouteRules().Hosts, getRulePaths(rule), key, poolNode)
 		if childVsNode.CheckPoolNChecksum(poolNode.Name, poolNode.GetCheckSum()) {
 			// Replace the poolNode.
 			childVsNode.ReplaceEvhPoolInEVHNode(poolNode, key)
@@ -159,6 +176,125 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 	childVsNode.DefaultPoolGroup = PG.Name
 }
 
+// BuildSidebandProfile mirrors the requests of the rule to the backend of its RequestMirror filter.
+// The sideband profile of the child VS sends a copy of each request to the addresses of the ready
+// endpoints of the mirror backend, on the port the request was received on. So only the endpoints
+// listening on a port of the parent VS are added, which are the Pod addresses in the ClusterIP mode,
+// as checked by the route validator.
+func (o *AviObjectGraph) BuildSidebandProfile(key string, parentVsNode, childVsNode *nodes.AviEvhVsNode, rule *Rule) {
+	childVsNode.SidebandProfile = nil
+	var mirrorBackend *Backend
+	for _, filter := range rule.Filters {
+		// considering only the first RequestMirror filter
+		if filter.MirrorFilter != nil {
+			mirrorBackend = filter.MirrorFilter
+			break
+		}
+	}
+	if mirrorBackend == nil {
+		return
+	}
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(mirrorBackend.Namespace).Get(mirrorBackend.Name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: mirror backend %s/%s not found, err: %v", key, mirrorBackend.Namespace, mirrorBackend.Name, err)
+		return
+	}
+	var svcPort *corev1.ServicePort
+	for i := range svcObj.Spec.Ports {
+		if svcObj.Spec.Ports[i].Port == mirrorBackend.Port || (mirrorBackend.Port == 0 && len(svcObj.Spec.Ports) == 1) {
+			svcPort = &svcObj.Spec.Ports[i]
+			break
+		}
+	}
+	if svcPort == nil {
+		utils.AviLog.Warnf("key: %s, msg: port %d not found in the mirror backend %s/%s", key, mirrorBackend.Port, mirrorBackend.Namespace, mirrorBackend.Name)
+		return
+	}
+
+	addresses := nodes.GetReadyEndpointAddresses(mirrorBackend.Namespace, mirrorBackend.Name, *svcPort, key)
+	sidebandProfile := &models.SidebandProfile{}
+	for _, portProto := range parentVsNode.PortProto {
+		for _, addr := range addresses[portProto.Port] {
+			ip, addrType := addr, "V4"
+			if !utils.IsV4(addr) {
+				addrType = "V6"
+			}
+			sidebandProfile.IP = append(sidebandProfile.IP, &models.IPAddr{Addr: &ip, Type: &addrType})
+		}
+	}
+	if len(sidebandProfile.IP) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no ready endpoint of the mirror backend %s/%s listens on the ports of the vs %s",
+			key, mirrorBackend.Namespace, mirrorBackend.Name, parentVsNode.Name)
+		return
+	}
+	childVsNode.SidebandProfile = sidebandProfile
+	utils.AviLog.Debugf("key: %s, msg: requests of the child vs %s are mirrored to %s/%s", key, childVsNode.Name, mirrorBackend.Namespace, mirrorBackend.Name)
+}
+
+// getServerTimeout returns the server timeout of the pools of a rule in milliseconds. The
+// backendRequest timeout, which can't exceed the request timeout, is preferred as it bounds each
+// request to the backend. A zero timeout disables the timeout of the route, so the pools keep
+// the Avi default.
+func getServerTimeout(rule *Rule) *int32 {
+	if rule.Timeouts == nil {
+		return nil
+	}
+	timeout := rule.Timeouts.BackendRequest
+	if timeout == nil {
+		timeout = rule.Timeouts.Request
+	}
+	if timeout == nil {
+		return nil
+	}
+	return proto.Int32(int32(timeout.Milliseconds()))
+}
+
+// BuildPoolSecurity enables the re-encryption of the connections to the servers of the pool when a
+// BackendTLSPolicy targets the Service, or the Service port, of the backend. The servers are validated
+// against the CA certificates of the policy and the hostname of the policy is sent as SNI.
+func (o *AviObjectGraph) BuildPoolSecurity(key string, poolNode *nodes.AviPoolNode, svcObj *corev1.Service, port int32) {
+	portName := ""
+	for _, svcPort := range svcObj.Spec.Ports {
+		if svcPort.Port == port {
+			portName = svcPort.Name
+			break
+		}
+	}
+	policy, caCert := akogatewayapilib.GetBackendTLSPolicyForPort(key, svcObj.Namespace, svcObj.Name, portName)
+	if policy == nil {
+		return
+	}
+	poolNode.SniEnabled = true
+	poolNode.ServerName = string(policy.Spec.TLS.Hostname)
+	poolNode.SslProfileRef = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile))
+	poolNode.PkiProfile = &nodes.AviPkiProfileNode{
+		Name:   lib.GetPoolPKIProfileName(poolNode.Name),
+		Tenant: lib.GetTenant(),
+		CACert: caCert,
+	}
+	utils.AviLog.Infof("key: %s, msg: applied BackendTLSPolicy %s/%s to pool %s", key, policy.Namespace, policy.Name, poolNode.Name)
+}
+
+// BuildHostRule applies the HostRule of the first hostname of the route which has one on the child VS.
+// The child VS which had a HostRule earlier is evaluated as well, so that the settings of the HostRule
+// are removed once it is detached.
+func (o *AviObjectGraph) BuildHostRule(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel) {
+	hosts := routeModel.ParseRouteRules().Hosts
+	for _, host := range hosts {
+		if found, _ := objects.SharedCRDLister().GetFQDNToHostruleMappingWithType(host); found {
+			nodes.BuildL7HostRule(host, key, vsNode)
+			return
+		}
+	}
+	if vsNode.ServiceMetadata.CRDStatus.Value != "" {
+		var host string
+		if len(hosts) != 0 {
+			host = hosts[0]
+		}
+		nodes.BuildL7HostRule(host, key, vsNode)
+	}
+}
+
 func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 	var vhMatches []*models.VHMatch
 
@@ -185,6 +321,8 @@ func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, ro
 					rule.Matches.Path.MatchCriteria = proto.String("EQUALS")
 				} else if match.PathMatch.Type == "PathPrefix" {
 					rule.Matches.Path.MatchCriteria = proto.String("BEGINS_WITH")
+				} else if match.PathMatch.Type == "PathSuffix" {
+					rule.Matches.Path.MatchCriteria = proto.String("ENDS_WITH")
 				}
 			}
 
@@ -201,6 +339,24 @@ func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, ro
 				rule.Matches.Hdrs = append(rule.Matches.Hdrs, hdrMatch)
 			}
 
+			// query param match, only a single query param per match is supported
+			if len(match.QueryParamMatch) != 0 {
+				queryParam := match.QueryParamMatch[0]
+				rule.Matches.Query = &models.QueryMatch{
+					MatchCase:     proto.String("SENSITIVE"),
+					MatchCriteria: proto.String("QUERY_MATCH_REGEX_MATCH"),
+					MatchStr:      []string{"(^|&)" + regexp.QuoteMeta(queryParam.Name+"="+queryParam.Value) + "(&|$)"},
+				}
+			}
+
+			// method match
+			if match.MethodMatch != "" {
+				rule.Matches.Method = &models.MethodMatch{
+					MatchCriteria: proto.String("IS_IN"),
+					Methods:       []string{"HTTP_METHOD_" + match.MethodMatch},
+				}
+			}
+
 			vhMatch.Rules = append(vhMatch.Rules, rule)
 		}
 		vhMatches = append(vhMatches, vhMatch)
@@ -212,7 +368,15 @@ func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, ro
 
 func (o *AviObjectGraph) BuildHTTPPolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 
-	if len(rule.Filters) == 0 {
+	// The RequestMirror filter is applied through the sideband profile of the child VS.
+	hasPolicyFilter := false
+	for _, filter := range rule.Filters {
+		if filter.Type != string(gatewayv1.HTTPRouteFilterRequestMirror) {
+			hasPolicyFilter = true
+			break
+		}
+	}
+	if !hasPolicyFilter {
 		vsNode.HttpPolicyRefs = nil
 		return
 	}
@@ -228,6 +392,7 @@ func (o *AviObjectGraph) BuildHTTPPolicySet(key string, vsNode *nodes.AviEvhVsNo
 		return
 	}
 	o.BuildHTTPPolicySetHTTPRequestRules(key, vsNode, routeModel, rule.Filters)
+	o.BuildHTTPPolicySetHTTPRequestRewriteRules(key, vsNode, routeModel, rule)
 	o.BuildHTTPPolicySetHTTPResponseRules(key, vsNode, routeModel, rule.Filters)
 	utils.AviLog.Infof("key: %s, msg: Attached HTTP policies to vs %s", key, vsNode.Name)
 }
@@ -324,3 +489,126 @@ func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRedirectRules(key string,
 		}
 	}
 }
+
+// BuildHTTPPolicySetHTTPRequestRewriteRules adds the request rules for the URLRewrite filter
+// of the rule. The prefix replacement depends on the number of path segments of the matched
+// prefix, hence a request rule is added for every PathPrefix match of the rule.
+func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRewriteRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+	var rewriteFilter *RewriteFilter
+	for _, filter := range rule.Filters {
+		// considering only the first RewriteFilter
+		if filter.RewriteFilter != nil {
+			rewriteFilter = filter.RewriteFilter
+			break
+		}
+	}
+	if rewriteFilter == nil {
+		return
+	}
+
+	index := int32(len(vsNode.HttpPolicyRefs[0].RequestRules) + 1)
+	addRewriteRule := func(matchTarget *models.MatchTarget, pathTokens []*models.URIParamToken) {
+		rewriteAction := &models.HTTPRewriteURLAction{}
+		if rewriteFilter.Host != "" {
+			rewriteAction.HostHdr = &models.URIParam{
+				Tokens: []*models.URIParamToken{{
+					StrValue: proto.String(rewriteFilter.Host),
+					Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+				}},
+				Type: proto.String("URI_PARAM_TYPE_TOKENIZED"),
+			}
+		}
+		if len(pathTokens) != 0 {
+			rewriteAction.Path = &models.URIParam{
+				Tokens: pathTokens,
+				Type:   proto.String("URI_PARAM_TYPE_TOKENIZED"),
+			}
+			rewriteAction.Query = &models.URIParamQuery{KeepQuery: proto.Bool(true)}
+		}
+		ruleName := fmt.Sprintf("%s-rewrite-%d", vsNode.Name, index)
+		requestRule := &models.HTTPRequestRule{
+			Name:             &ruleName,
+			Enable:           proto.Bool(true),
+			Index:            proto.Int32(index),
+			Match:            matchTarget,
+			RewriteURLAction: rewriteAction,
+		}
+		vsNode.HttpPolicyRefs[0].RequestRules = append(vsNode.HttpPolicyRefs[0].RequestRules, requestRule)
+		index++
+	}
+
+	replacementPath := strings.Trim(rewriteFilter.Path, "/")
+	switch rewriteFilter.PathType {
+	case string(gatewayv1.PrefixMatchHTTPPathModifier):
+		var prefixes []string
+		for _, match := range rule.Matches {
+			if match.PathMatch == nil || match.PathMatch.Type != "PathPrefix" || utils.HasElem(prefixes, match.PathMatch.Path) {
+				continue
+			}
+			prefixes = append(prefixes, match.PathMatch.Path)
+		}
+		for _, prefix := range prefixes {
+			var pathTokens []*models.URIParamToken
+			if replacementPath != "" {
+				pathTokens = append(pathTokens, &models.URIParamToken{
+					StrValue: proto.String(replacementPath),
+					Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+				})
+			}
+			// the path segments after the matched prefix are retained
+			var segments int32
+			if trimmedPrefix := strings.Trim(prefix, "/"); trimmedPrefix != "" {
+				segments = int32(len(strings.Split(trimmedPrefix, "/")))
+			}
+			pathTokens = append(pathTokens, &models.URIParamToken{
+				StartIndex: proto.Int32(segments),
+				EndIndex:   proto.Int32(65535),
+				Type:       proto.String("URI_TOKEN_TYPE_PATH"),
+			})
+			matchTarget := &models.MatchTarget{
+				Path: &models.PathMatch{
+					MatchCase:     proto.String("SENSITIVE"),
+					MatchCriteria: proto.String("BEGINS_WITH"),
+					MatchStr:      []string{prefix},
+				},
+			}
+			addRewriteRule(matchTarget, pathTokens)
+		}
+	case string(gatewayv1.FullPathHTTPPathModifier):
+		addRewriteRule(nil, []*models.URIParamToken{{
+			StrValue: proto.String(replacementPath),
+			Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+		}})
+	default:
+		if rewriteFilter.Host != "" {
+			addRewriteRule(nil, nil)
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Attached HTTP request rewrite policies %s to vs %s", key, utils.Stringify(vsNode.HttpPolicyRefs[0].RequestRules), vsNode.Name)
+}
+
+// getRulePaths returns the paths of the matches of the rule, which are used to look up the HTTPRule
+// of the pools of the rule. A match without a path matches all the paths.
+func getRulePaths(rule *Rule) []string {
+	var paths []string
+	for _, match := range rule.Matches {
+		path := "/"
+		if match.PathMatch != nil && match.PathMatch.Path != "" {
+			path = match.PathMatch.Path
+		}
+		if !utils.HasElem(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// getRuleMatchName returns the string which identifies the matches of the rule in the
+// names of the child VS, the poolgroup and the pools. The route kind is added for the
+// routes other than HTTPRoute, so that these don't clash with an HTTPRoute of the same name.
+func getRuleMatchName(routeModel RouteModel, rule *Rule) string {
+	if routeModel.GetType() == lib.HTTPRoute {
+		return utils.Stringify(rule.Matches)
+	}
+	return routeModel.GetType() + "/" + utils.Stringify(rule.Matches)
+}
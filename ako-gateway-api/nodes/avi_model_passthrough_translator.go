@@ -0,0 +1,218 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// BuildGatewayPassthrough programs the TLS passthrough listeners of the gateway on the EVH parent VS.
+// The ports of the listeners use the L4 application profile, and the connections on these ports are
+// switched to a poolgroup per SNI hostname by the passthrough datascript of the parent VS.
+func (o *AviObjectGraph) BuildGatewayPassthrough(gateway *gatewayv1.Gateway, parentVsNode *nodes.AviEvhVsNode, key string) {
+	// the hostnames of the previous passthrough pools are removed from the vsvip, and added back if still in use
+	vsvipNode := parentVsNode.VSVIPRefs[0]
+	listenerHostnames := GetPassthroughListenerHostnames(gateway)
+	for _, poolNode := range parentVsNode.PoolRefs {
+		for _, hostname := range poolNode.ServiceMetadata.HostNames {
+			if !utils.HasElem(listenerHostnames, hostname) {
+				vsvipNode.FQDNs = utils.Remove(vsvipNode.FQDNs, hostname)
+			}
+		}
+	}
+	parentVsNode.PoolRefs = nil
+	parentVsNode.PoolGroupRefs = nil
+	parentVsNode.HTTPDSrefs = nil
+	if !HasPassthroughListeners(gateway) {
+		return
+	}
+
+	dsNode := &nodes.AviHTTPDataScriptNode{
+		Name:            akogatewayapilib.GetPassthroughDSName(gateway.Namespace, gateway.Name),
+		Tenant:          lib.GetTenant(),
+		DataScript:      &nodes.DataScript{Evt: "VS_DATASCRIPT_EVT_L4_REQUEST"},
+		ProtocolParsers: []string{"/api/protocolparser/?name=Default-TLS"},
+	}
+
+	hostToBackends := o.getPassthroughHostToBackends(gateway, key)
+	hostnames := make([]string, 0, len(hostToBackends))
+	for hostname := range hostToBackends {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	hostToPoolGroup := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		// the wildcard hostnames are matched by the datascript, and are not added as the FQDNs of the vsvip
+		if !strings.Contains(hostname, "*") && !utils.HasElem(vsvipNode.FQDNs, hostname) {
+			vsvipNode.FQDNs = append(vsvipNode.FQDNs, hostname)
+		}
+		pgName := akogatewayapilib.GetPassthroughPGName(gateway.Namespace, gateway.Name, hostname)
+		pgNode, poolNodes := buildL4PoolGroup(key, pgName, utils.TCP, []string{hostname}, hostToBackends[hostname], func(backend *Backend) string {
+			return akogatewayapilib.GetPassthroughPoolName(gateway.Namespace, gateway.Name, hostname,
+				backend.Namespace, backend.Name, strconv.Itoa(int(backend.Port)))
+		})
+		parentVsNode.PoolRefs = append(parentVsNode.PoolRefs, poolNodes...)
+		parentVsNode.PoolGroupRefs = append(parentVsNode.PoolGroupRefs, pgNode)
+		dsNode.PoolGroupRefs = append(dsNode.PoolGroupRefs, pgNode.Name)
+		hostToPoolGroup = append(hostToPoolGroup, fmt.Sprintf("[%q] = %q", hostname, pgName))
+	}
+	dsNode.Script = strings.Replace(akogatewayapilib.PassthroughDatascript, "HOSTNAME_POOLGROUPS", "{"+strings.Join(hostToPoolGroup, ", ")+"}", 1)
+	parentVsNode.HTTPDSrefs = []*nodes.AviHTTPDataScriptNode{dsNode}
+	utils.AviLog.Infof("key: %s, msg: passthrough hostnames of the gateway %s/%s: %v", key, gateway.Namespace, gateway.Name, hostnames)
+}
+
+// ProcessPassthroughRoutes rebuilds the passthrough poolgroups of the EVH parent VS of the gateway,
+// on the changes of the TLSRoutes attached to the gateway and of their backends.
+func (o *AviObjectGraph) ProcessPassthroughRoutes(key string, gateway *gatewayv1.Gateway) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+
+	parentVsNodes := o.GetAviEvhVS()
+	if len(parentVsNodes) == 0 || len(parentVsNodes[0].VSVIPRefs) == 0 {
+		return
+	}
+	o.BuildGatewayPassthrough(gateway, parentVsNodes[0], key)
+}
+
+// getPassthroughHostToBackends returns the backends of the TLSRoutes attached to the
+// passthrough listeners of the gateway, keyed by the SNI hostname. A hostname claimed
+// by more than one TLSRoute is given to the oldest route.
+func (o *AviObjectGraph) getPassthroughHostToBackends(gateway *gatewayv1.Gateway, key string) map[string][]*Backend {
+	hostToBackends := make(map[string][]*Backend)
+	gwNsName := gateway.Namespace + "/" + gateway.Name
+	_, routeTypeNsNameList := akogatewayapiobjects.GatewayApiLister().GetGatewayToRoute(gwNsName)
+
+	var tlsRoutes []*gatewayv1alpha2.TLSRoute
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		routeType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
+		if routeType != lib.TLSRoute {
+			continue
+		}
+		routeObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the TLSRoute %s, err: %v", key, routeTypeNsName, err)
+			continue
+		}
+		tlsRoutes = append(tlsRoutes, routeObj)
+	}
+	sort.Slice(tlsRoutes, func(i, j int) bool {
+		if !tlsRoutes[i].CreationTimestamp.Equal(&tlsRoutes[j].CreationTimestamp) {
+			return tlsRoutes[i].CreationTimestamp.Before(&tlsRoutes[j].CreationTimestamp)
+		}
+		return tlsRoutes[i].Namespace+"/"+tlsRoutes[i].Name < tlsRoutes[j].Namespace+"/"+tlsRoutes[j].Name
+	})
+
+	for _, routeObj := range tlsRoutes {
+		routeModel := &tlsRoute{
+			key:       key,
+			name:      routeObj.Name,
+			namespace: routeObj.Namespace,
+			spec:      routeObj.Spec.DeepCopy(),
+		}
+		var backends []*Backend
+		for _, rule := range routeModel.ParseRouteRules().Rules {
+			backends = append(backends, rule.Backends...)
+		}
+		for _, hostname := range getPassthroughHostnamesForRoute(gateway, routeObj) {
+			if _, ok := hostToBackends[hostname]; ok {
+				utils.AviLog.Warnf("key: %s, msg: hostname %s of TLSRoute %s/%s is already attached to the gateway %s", key, hostname, routeObj.Namespace, routeObj.Name, gwNsName)
+				continue
+			}
+			hostToBackends[hostname] = backends
+		}
+	}
+	return hostToBackends
+}
+
+// getPassthroughHostnamesForRoute returns the SNI hostnames for which the TLSRoute
+// is attached to a passthrough listener of the gateway.
+func getPassthroughHostnamesForRoute(gateway *gatewayv1.Gateway, tlsRoute *gatewayv1alpha2.TLSRoute) []string {
+	var hostnames []string
+	for _, listener := range gateway.Spec.Listeners {
+		if !akogatewayapilib.IsPassthroughListener(listener) || listener.Hostname == nil {
+			continue
+		}
+		if !isRouteAttachedToListener(gateway, listener, tlsRoute.Namespace, tlsRoute.Spec.ParentRefs) {
+			continue
+		}
+		listenerHostname := string(*listener.Hostname)
+		if len(tlsRoute.Spec.Hostnames) == 0 {
+			// TLSRoute without hostnames inherits the hostname of the listener
+			if !utils.HasElem(hostnames, listenerHostname) {
+				hostnames = append(hostnames, listenerHostname)
+			}
+			continue
+		}
+		for _, routeHostname := range tlsRoute.Spec.Hostnames {
+			hostname, matched := akogatewayapilib.GetMatchedHostname(listenerHostname, string(routeHostname))
+			if matched && !utils.HasElem(hostnames, hostname) {
+				hostnames = append(hostnames, hostname)
+			}
+		}
+	}
+	return hostnames
+}
+
+func isRouteNamespaceAllowed(gateway *gatewayv1.Gateway, listener gatewayv1.Listener, routeNamespace string) bool {
+	return akogatewayapilib.IsRouteNamespaceAllowed(listener, gateway.Namespace, routeNamespace)
+}
+
+// HasParentListeners returns true if the gateway has listeners which are served by the EVH parent VS,
+// that is the HTTP, HTTPS and TLS passthrough listeners.
+func HasParentListeners(gateway *gatewayv1.Gateway) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if !akogatewayapilib.IsL4Listener(listener) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPassthroughListeners returns true if the gateway has TLS passthrough listeners.
+func HasPassthroughListeners(gateway *gatewayv1.Gateway) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if akogatewayapilib.IsPassthroughListener(listener) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPassthroughListenerHostnames returns the non wildcard hostnames of the passthrough listeners.
+func GetPassthroughListenerHostnames(gateway *gatewayv1.Gateway) []string {
+	var hostnames []string
+	for _, listener := range gateway.Spec.Listeners {
+		if !akogatewayapilib.IsPassthroughListener(listener) || listener.Hostname == nil {
+			continue
+		}
+		hostname := string(*listener.Hostname)
+		if !strings.Contains(hostname, "*") && !utils.HasElem(hostnames, hostname) {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	return hostnames
+}
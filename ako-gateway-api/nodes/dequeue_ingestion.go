@@ -16,9 +16,11 @@ package nodes
 
 import (
 	"context"
+	"strings"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -58,6 +60,10 @@ func DequeueIngestion(key string, fullsync bool) {
 	for _, gatewayNsName := range gatewayNsNameList {
 
 		parentNs, _, parentName := lib.ExtractTypeNameNamespace(gatewayNsName)
+		if objType == lib.Gateway || hasRouteOfType(routeTypeNsNameList, lib.TCPRoute) || hasRouteOfType(routeTypeNsNameList, lib.UDPRoute) {
+			handleGatewayL4(parentNs, parentName, fullsync, key)
+		}
+
 		modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(parentNs, parentName))
 
 		modelFound, modelIntf := objects.SharedAviGraphLister().Get(modelName)
@@ -70,8 +76,17 @@ func DequeueIngestion(key string, fullsync bool) {
 		if objType == utils.Secret {
 			handleSecrets(parentNs, parentName, key, model)
 		}
+		if objType != lib.Gateway && hasRouteOfType(routeTypeNsNameList, lib.TLSRoute) {
+			// the passthrough poolgroups are built on the gateway changes along with the parent VS
+			handlePassthroughRoutes(parentNs, parentName, key, model)
+		}
 		for _, routeTypeNsName := range routeTypeNsNameList {
 			objType, namespace, name := lib.ExtractTypeNameNamespace(routeTypeNsName)
+			if objType == lib.TLSRoute || objType == lib.TCPRoute || objType == lib.UDPRoute {
+				// TLSRoutes are programmed by the passthrough datascript of the parent VS,
+				// TCPRoutes and UDPRoutes on the L4 VS of the gateway
+				continue
+			}
 			utils.AviLog.Infof("key: %s, msg: processing route %s mapped to gateway %s", key, routeTypeNsName, gatewayNsName)
 
 			routeModel, err := NewRouteModel(key, objType, name, namespace)
@@ -86,8 +101,11 @@ func DequeueIngestion(key string, fullsync bool) {
 			childVSes := make(map[string]struct{}, 0)
 
 			switch objType {
-			case lib.HTTPRoute:
-				model.ProcessL7Routes(key, routeModel, gatewayNsName, childVSes)
+			case lib.HTTPRoute, lib.GRPCRoute:
+				// the child VSes of a route from a namespace which isn't admitted by any listener of the gateway are removed
+				if isRouteNamespaceAdmitted(parentNs, parentName, namespace) {
+					model.ProcessL7Routes(key, routeModel, gatewayNsName, childVSes)
+				}
 			default:
 				utils.AviLog.Warnf("key: %s, msg: route of type %s not supported", key, objType)
 				continue
@@ -177,6 +195,17 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 		utils.AviLog.Infof("key: %s, msg: Controller is not AKO for %s, not building VS model", key, modelName)
 		return
 	}
+	if !HasParentListeners(gatewayObj) {
+		// only TCP and UDP listeners, the VS is built by handleGatewayL4
+		if modelFound {
+			objects.SharedAviGraphLister().Save(modelName, nil)
+			if !fullsync {
+				sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+				nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
+			}
+		}
+		return
+	}
 	aviModelGraph := NewAviObjectGraph()
 	aviModelGraph.BuildGatewayVs(gatewayObj, key)
 
@@ -187,6 +216,95 @@ func handleGateway(namespace, name string, fullsync bool, key string) {
 	}
 }
 
+func handlePassthroughRoutes(gatewayNamespace, gatewayName, key string, object *AviObjectGraph) {
+	gatewayObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(gatewayNamespace).Get(gatewayName)
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: unable to get the gateway object. err: %s", key, err)
+		return
+	}
+	object.ProcessPassthroughRoutes(key, gatewayObj)
+}
+
+func handleGatewayL4(namespace, name string, fullsync bool, key string) {
+	utils.AviLog.Debugf("key: %s, msg: processing TCP and UDP listeners of gateway: %s", key, name)
+	vsName := akogatewayapilib.GetGatewayL4Name(namespace, name)
+	handleGatewaySharedVipVs(namespace, name, vsName, HasL4Listeners, (*AviObjectGraph).BuildGatewayL4Vs, fullsync, key)
+}
+
+// handleGatewaySharedVipVs builds the model of a VS, which serves a subset of the listeners of the
+// gateway, and shares the vsvip with the other VSes of the gateway. The model is deleted when the
+// gateway doesn't have any listener served by the VS.
+func handleGatewaySharedVipVs(namespace, name, vsName string, hasListeners func(*gatewayv1.Gateway) bool,
+	buildVs func(*AviObjectGraph, *gatewayv1.Gateway, string), fullsync bool, key string) {
+
+	modelName := lib.GetModelName(lib.GetTenant(), vsName)
+	modelFound, modelIntf := objects.SharedAviGraphLister().Get(modelName)
+
+	var gatewayObj *gatewayv1.Gateway
+	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			utils.AviLog.Infof("key: %s, msg: got error while getting gateway: %v", key, err)
+			return
+		}
+		utils.AviLog.Debugf("key: %s, msg: gateway not found: %s/%s", key, namespace, name)
+	} else {
+		gwClass := string(obj.Spec.GatewayClassName)
+		found, isAkoCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(gwClass)
+		if found && !isAkoCtrl {
+			//AKO is not the controller, do not build model
+			utils.AviLog.Infof("key: %s, msg: Controller is not AKO for %s, not building VS model", key, modelName)
+			return
+		}
+		if found && hasListeners(obj) {
+			gatewayObj = obj
+		}
+	}
+
+	if gatewayObj == nil {
+		// gateway or gateway class deleted, or no listeners served by the VS left in the gateway
+		if modelFound && modelIntf != nil {
+			objects.SharedAviGraphLister().Save(modelName, nil)
+			if !fullsync {
+				sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+				nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
+			}
+		}
+		return
+	}
+
+	aviModelGraph := NewAviObjectGraph()
+	buildVs(aviModelGraph, gatewayObj, key)
+
+	modelChanged := saveAviModel(modelName, aviModelGraph.AviObjectGraph, key)
+	if modelChanged && !fullsync {
+		sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+		nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
+	}
+}
+
+func isRouteNamespaceAdmitted(gwNamespace, gwName, routeNamespace string) bool {
+	gateway, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(gwNamespace).Get(gwName)
+	if err != nil {
+		return false
+	}
+	for _, listener := range gateway.Spec.Listeners {
+		if isRouteNamespaceAllowed(gateway, listener, routeNamespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRouteOfType(routeTypeNsNameList []string, routeType string) bool {
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		if strings.HasPrefix(routeTypeNsName, routeType+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func saveAviModel(modelName string, aviGraph *nodes.AviObjectGraph, key string) bool {
 	utils.AviLog.Debugf("key: %s, msg: Evaluating model :%s", key, modelName)
 	if lib.DisableSync {
@@ -24,6 +24,7 @@ import (
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -48,10 +49,10 @@ func (o *AviObjectGraph) BuildGatewayVs(gateway *gatewayv1.Gateway, key string)
 
 func (o *AviObjectGraph) BuildGatewayParent(gateway *gatewayv1.Gateway, key string) *nodes.AviEvhVsNode {
 	vsName := akogatewayapilib.GetGatewayParentName(gateway.Namespace, gateway.Name)
+	infraSetting := akogatewayapilib.GetAviInfraSetting(key, gateway)
 	parentVsNode := &nodes.AviEvhVsNode{
 		Name:               vsName,
 		Tenant:             lib.GetTenant(),
-		ServiceEngineGroup: lib.GetSEGName(),
 		ApplicationProfile: utils.DEFAULT_L7_APP_PROFILE,
 		NetworkProfile:     utils.DEFAULT_TCP_NW_PROFILE,
 		EVHParent:          true,
@@ -61,6 +62,7 @@ func (o *AviObjectGraph) BuildGatewayParent(gateway *gatewayv1.Gateway, key stri
 		},
 	}
 
+	parentVsNode.ServiceEngineGroup, parentVsNode.EnableRhi = getServiceEngineGroupAndRhi(infraSetting)
 	parentVsNode.PortProto = BuildPortProtocols(gateway, key)
 
 	tlsNodes := BuildTLSNodesForGateway(gateway, key)
@@ -68,15 +70,65 @@ func (o *AviObjectGraph) BuildGatewayParent(gateway *gatewayv1.Gateway, key stri
 		parentVsNode.SSLKeyCertRefs = tlsNodes
 	}
 
-	vsvipNode := BuildVsVipNodeForGateway(gateway, parentVsNode.Name)
+	vsvipNode := BuildVsVipNodeForGateway(gateway, parentVsNode.Name, infraSetting)
 	parentVsNode.VSVIPRefs = []*nodes.AviVSVIPNode{vsvipNode}
 
+	// the L4 VS shares the vsvip of the parent VS
+	setGatewayVsRefs(gateway, vsName, &parentVsNode.ServiceMetadata)
+	vsvipNode.FQDNs = GetPassthroughListenerHostnames(gateway)
+	o.BuildGatewayPassthrough(gateway, parentVsNode, key)
+
 	return parentVsNode
 }
 
+// GetGatewayVsNames returns the names of the VSes built for the gateway. The first VS owns
+// the vsvip, which is shared by the rest of the VSes. Each VS refers to the next one as its
+// child, so that the VSes sharing the vsvip are deleted before the owner of the vsvip.
+func GetGatewayVsNames(gateway *gatewayv1.Gateway) []string {
+	var vsNames []string
+	if HasParentListeners(gateway) {
+		vsNames = append(vsNames, akogatewayapilib.GetGatewayParentName(gateway.Namespace, gateway.Name))
+	}
+	if HasL4Listeners(gateway) {
+		vsNames = append(vsNames, akogatewayapilib.GetGatewayL4Name(gateway.Namespace, gateway.Name))
+	}
+	return vsNames
+}
+
+// setGatewayVsRefs sets the vsvip owner and the child VS references in the service metadata
+// of the VS, and returns the name of the VS which owns the vsvip.
+func setGatewayVsRefs(gateway *gatewayv1.Gateway, vsName string, serviceMetadata *lib.ServiceMetadataObj) string {
+	vsNames := GetGatewayVsNames(gateway)
+	for i := range vsNames {
+		if vsNames[i] != vsName {
+			continue
+		}
+		if i == 0 {
+			serviceMetadata.Gateway = gateway.Namespace + "/" + gateway.Name
+		} else {
+			serviceMetadata.PassthroughParentRef = vsNames[0]
+		}
+		if i+1 < len(vsNames) {
+			serviceMetadata.PassthroughChildRef = vsNames[i+1]
+		}
+		return vsNames[0]
+	}
+	return vsName
+}
+
 func BuildPortProtocols(gateway *gatewayv1.Gateway, key string) []nodes.AviPortHostProtocol {
 	var portProtocols []nodes.AviPortHostProtocol
 	for _, listener := range gateway.Spec.Listeners {
+		if akogatewayapilib.IsL4Listener(listener) {
+			// served by the L4 VS of the gateway
+			continue
+		}
+		if akogatewayapilib.IsPassthroughListener(listener) {
+			// the TLS connections are passed through with the L4 application profile on the port
+			pp := nodes.AviPortHostProtocol{Port: int32(listener.Port), Protocol: utils.TCP, Passthrough: true}
+			portProtocols = append(portProtocols, pp)
+			continue
+		}
 		pp := nodes.AviPortHostProtocol{Port: int32(listener.Port), Protocol: string(listener.Protocol)}
 		//TLS config on listener is present
 		if listener.TLS != nil && len(listener.TLS.CertificateRefs) > 0 {
@@ -101,6 +153,10 @@ func BuildTLSNodesForGateway(gateway *gatewayv1.Gateway, key string) []*nodes.Av
 					ns = string(*certRef.Namespace)
 				}
 				name = string(certRef.Name)
+				if !akogatewayapilib.IsReferencePermitted(lib.Gateway, gateway.Namespace, utils.Secret, ns, name) {
+					utils.AviLog.Warnf("key: %s, msg: secret %s/%s is not permitted by any ReferenceGrant", key, ns, name)
+					continue
+				}
 				secretObj, err := cs.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
 				if err != nil || secretObj == nil {
 					utils.AviLog.Warnf("key: %s, msg: secret %s has been deleted, err: %s", key, name, err)
@@ -134,7 +190,7 @@ func TLSNodeFromSecret(secretObj *corev1.Secret, hostname, certName, key string)
 	return tlsNode
 }
 
-func BuildVsVipNodeForGateway(gateway *gatewayv1.Gateway, vsName string) *nodes.AviVSVIPNode {
+func BuildVsVipNodeForGateway(gateway *gatewayv1.Gateway, vsName string, infraSetting *akov1beta1.AviInfraSetting) *nodes.AviVSVIPNode {
 	vsvipNode := &nodes.AviVSVIPNode{
 		Name:        lib.GetVsVipName(vsName),
 		Tenant:      lib.GetTenant(),
@@ -147,9 +203,46 @@ func BuildVsVipNodeForGateway(gateway *gatewayv1.Gateway, vsName string) *nodes.
 	if len(gateway.Spec.Addresses) == 1 {
 		vsvipNode.IPAddress = gateway.Spec.Addresses[0].Value
 	}
+
+	if infraSetting != nil {
+		if _, enableRhi := getServiceEngineGroupAndRhi(infraSetting); *enableRhi {
+			if infraSetting.Spec.Network.BgpPeerLabels != nil {
+				vsvipNode.BGPPeerLabels = infraSetting.Spec.Network.BgpPeerLabels
+			} else {
+				vsvipNode.BGPPeerLabels = lib.GetGlobalBgpPeerLabels()
+			}
+		}
+		if len(infraSetting.Spec.Network.VipNetworks) > 0 {
+			vsvipNode.VipNetworks = lib.GetVipInfraNetworkList(infraSetting.Name)
+		}
+		if lib.IsPublicCloud() {
+			vsvipNode.EnablePublicIP = infraSetting.Spec.Network.EnablePublicIP
+		}
+		if infraSetting.Spec.NSXSettings.T1LR != nil {
+			vsvipNode.T1Lr = *infraSetting.Spec.NSXSettings.T1LR
+		}
+	}
 	return vsvipNode
 }
 
+// getServiceEngineGroupAndRhi returns the SE group and the RHI setting of the VSes of the gateway,
+// which are taken from the AviInfraSetting of the gateway if any.
+func getServiceEngineGroupAndRhi(infraSetting *akov1beta1.AviInfraSetting) (string, *bool) {
+	if infraSetting == nil {
+		return lib.GetSEGName(), nil
+	}
+	seGroup := lib.GetSEGName()
+	if infraSetting.Spec.SeGroup.Name != "" {
+		// This assumes that the SeGroup has the appropriate labels configured
+		seGroup = infraSetting.Spec.SeGroup.Name
+	}
+	enableRhi := lib.GetEnableRHI()
+	if infraSetting.Spec.Network.EnableRhi != nil {
+		enableRhi = *infraSetting.Spec.Network.EnableRhi
+	}
+	return seGroup, &enableRhi
+}
+
 func DeleteTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gateway, secretObj *corev1.Secret, encodedCertNameIndexMap map[string][]int) {
 	var tlsNodes []*nodes.AviTLSKeyCertNode
 	_, _, secretName := lib.ExtractTypeNameNamespace(key)
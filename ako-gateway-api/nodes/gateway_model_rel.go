@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -79,6 +80,26 @@ var (
 		GetGateways: HTTPRouteToGateway,
 		GetRoutes:   HTTPRouteChanges,
 	}
+	GRPCRoute = GraphSchema{
+		Type:        lib.GRPCRoute,
+		GetGateways: GRPCRouteToGateway,
+		GetRoutes:   GRPCRouteChanges,
+	}
+	TLSRoute = GraphSchema{
+		Type:        lib.TLSRoute,
+		GetGateways: TLSRouteToGateway,
+		GetRoutes:   TLSRouteChanges,
+	}
+	TCPRoute = GraphSchema{
+		Type:        lib.TCPRoute,
+		GetGateways: TCPRouteToGateway,
+		GetRoutes:   TCPRouteChanges,
+	}
+	UDPRoute = GraphSchema{
+		Type:        lib.UDPRoute,
+		GetGateways: UDPRouteToGateway,
+		GetRoutes:   UDPRouteChanges,
+	}
 	SupportedGraphTypes = GraphDescriptor{
 		Gateway,
 		GatewayClass,
@@ -86,6 +107,10 @@ var (
 		Service,
 		Endpoint,
 		HTTPRoute,
+		GRPCRoute,
+		TLSRoute,
+		TCPRoute,
+		UDPRoute,
 	}
 )
 
@@ -116,19 +141,19 @@ func GatewayGetGw(namespace, name, key string) ([]string, bool) {
 	for _, listenerObj := range gwObj.Spec.Listeners {
 		listenerString := string(listenerObj.Name) + "/" +
 			strconv.Itoa(int(listenerObj.Port)) + "/" + string(listenerObj.Protocol)
-		if listenerObj.AllowedRoutes == nil {
-			listenerString += "/" + string(gwObj.Namespace)
-		} else {
-			if listenerObj.AllowedRoutes.Namespaces != nil {
-				if listenerObj.AllowedRoutes.Namespaces.From != nil {
-					if string(*listenerObj.AllowedRoutes.Namespaces.From) == "Same" {
-						listenerString += "/" + string(gwObj.Namespace)
-					} else if string(*listenerObj.AllowedRoutes.Namespaces.From) == "All" {
-						listenerString += "/All"
-					}
-				}
+		// the routes are admitted from the namespace of the gateway unless specified otherwise,
+		// the Selector is evaluated against the namespace of the route when the route is processed.
+		allowedNS := string(gwObj.Namespace)
+		if listenerObj.AllowedRoutes != nil && listenerObj.AllowedRoutes.Namespaces != nil &&
+			listenerObj.AllowedRoutes.Namespaces.From != nil {
+			switch *listenerObj.AllowedRoutes.Namespaces.From {
+			case gatewayv1.NamespacesFromAll:
+				allowedNS = string(gatewayv1.NamespacesFromAll)
+			case gatewayv1.NamespacesFromSelector:
+				allowedNS = string(gatewayv1.NamespacesFromSelector)
 			}
 		}
+		listenerString += "/" + allowedNS
 		if listenerObj.TLS != nil {
 			for _, cert := range listenerObj.TLS.CertificateRefs {
 				certNs := gwObj.Namespace
@@ -139,7 +164,9 @@ func GatewayGetGw(namespace, name, key string) ([]string, bool) {
 			}
 		}
 		listeners = append(listeners, listenerString)
-		hostnames[string(listenerObj.Name)] = string(*listenerObj.Hostname)
+		if listenerObj.Hostname != nil {
+			hostnames[string(listenerObj.Name)] = string(*listenerObj.Hostname)
+		}
 	}
 	sort.Strings(listeners)
 	akogatewayapiobjects.GatewayApiLister().UpdateGatewayToListener(gwNsName, listeners)
@@ -205,11 +232,88 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 		}
 		return gwNsNameList, true
 	}
+	return routeToGateway(key, lib.HTTPRoute, hrObj.Namespace, hrObj.Name, hrObj.Spec.ParentRefs, hrObj.Spec.Hostnames)
+}
+
+func GRPCRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.GRPCRoute + "/" + namespace + "/" + name
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting grpcroute: %v", key, err)
+			return []string{}, false
+		}
+		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		if !found {
+			return []string{}, true
+		}
+		return gwNsNameList, true
+	}
+	return routeToGateway(key, lib.GRPCRoute, grObj.Namespace, grObj.Name, grObj.Spec.ParentRefs, grObj.Spec.Hostnames)
+}
+
+func TLSRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.TLSRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting tlsroute: %v", key, err)
+			return []string{}, false
+		}
+		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		if !found {
+			return []string{}, true
+		}
+		return gwNsNameList, true
+	}
+	return routeToGateway(key, lib.TLSRoute, trObj.Namespace, trObj.Name, trObj.Spec.ParentRefs, trObj.Spec.Hostnames)
+}
+
+func TCPRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.TCPRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting tcproute: %v", key, err)
+			return []string{}, false
+		}
+		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		if !found {
+			return []string{}, true
+		}
+		return gwNsNameList, true
+	}
+	return routeToGateway(key, lib.TCPRoute, trObj.Namespace, trObj.Name, trObj.Spec.ParentRefs, nil)
+}
+
+func UDPRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.UDPRoute + "/" + namespace + "/" + name
+	urObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting udproute: %v", key, err)
+			return []string{}, false
+		}
+		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		if !found {
+			return []string{}, true
+		}
+		return gwNsNameList, true
+	}
+	return routeToGateway(key, lib.UDPRoute, urObj.Namespace, urObj.Name, urObj.Spec.ParentRefs, nil)
+}
+
+func routeToGateway(key, routeKind, namespace, name string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname) ([]string, bool) {
+	routeTypeNsName := routeKind + "/" + namespace + "/" + name
 	var listenerList []string
 	var gatewayList []string
 	var hostnameIntersection []string
 	var gwNsNameList []string
-	for _, parentRef := range hrObj.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		ns := namespace
 		if parentRef.Namespace != nil {
 			ns = string(*parentRef.Namespace)
@@ -220,29 +324,41 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 		var gatewayListenerList []string
 		gwNsName := ns + "/" + string(parentRef.Name)
 		listeners := akogatewayapiobjects.GatewayApiLister().GetGatewayToListeners(gwNsName)
+		gwObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(ns).Get(string(parentRef.Name))
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the gateway %s, err: %v", key, gwNsName, err)
+		}
 		for _, listener := range listeners {
 			listenerSlice := strings.Split(listener, "/")
 			listenerName := listenerSlice[0]
 			listenerPort := listenerSlice[1]
+			listenerProtocol := listenerSlice[2]
 			listenerAllowedNS := listenerSlice[3]
+			//check if the listener accepts this kind of route
+			if !akogatewayapilib.IsRouteKindSupported(gatewayv1.ProtocolType(listenerProtocol), routeKind) {
+				continue
+			}
 			//check if namespace is allowed
-			if listenerAllowedNS == "All" || listenerAllowedNS == hrObj.Namespace {
+			if isListenerNamespaceAllowed(gwObj, listenerName, listenerAllowedNS, namespace) {
 				//if provided, check if section name and port matches
 				if (parentRef.SectionName == nil || string(*parentRef.SectionName) == listenerName) &&
-					(parentRef.Port == nil || string(*parentRef.Port) == listenerPort) {
+					(parentRef.Port == nil || strconv.Itoa(int(*parentRef.Port)) == listenerPort) {
 					listenerHostname := akogatewayapiobjects.GatewayApiLister().GetGatewayListenerToHostname(gwNsName, listenerName)
 					if strings.HasPrefix(listenerHostname, "*") {
 						listenerHostname = listenerHostname[1:]
 					}
-					hostnameMatched := false
-					for _, routeHostname := range hrObj.Spec.Hostnames {
+					// TLSRoute without hostnames inherits the hostname of the listener, and
+					// TCPRoute and UDPRoute attach to the listener irrespective of the hostname
+					hostnameMatched := (routeKind == lib.TLSRoute && len(hostnames) == 0) ||
+						routeKind == lib.TCPRoute || routeKind == lib.UDPRoute
+					for _, routeHostname := range hostnames {
 						if strings.HasSuffix(string(routeHostname), listenerHostname) {
 							hostnameIntersection = append(hostnameIntersection, string(routeHostname))
 							hostnameMatched = true
 						}
 					}
-					if hostnameMatched && !utils.HasElem(listenerList, gwNsName+"/"+listenerName) {
-						gatewayListenerList = append(listenerList, gwNsName+"/"+listenerName)
+					if hostnameMatched && !utils.HasElem(gatewayListenerList, gwNsName+"/"+listenerName) {
+						gatewayListenerList = append(gatewayListenerList, gwNsName+"/"+listenerName)
 					}
 				}
 			}
@@ -258,7 +374,22 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 				}
 			}
 		}
-		akogatewayapiobjects.GatewayApiLister().UpdateGatewayRouteToHostname(gwNsName, hostnameIntersection)
+		// detach the route from the listeners of the gateway which don't admit it anymore
+		var staleListenerList []string
+		_, routeListenerList := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
+		for _, gwListener := range routeListenerList {
+			if strings.HasPrefix(gwListener, gwNsName+"/") && !utils.HasElem(listenerList, gwListener) {
+				staleListenerList = append(staleListenerList, gwListener)
+			}
+		}
+		for _, gwListener := range staleListenerList {
+			akogatewayapiobjects.GatewayApiLister().DeleteGatewayRouteMappings(gwNsName, gwListener, routeTypeNsName)
+		}
+		// hostnames of the TLSRoutes are resolved by the passthrough translator, these
+		// are used for the child VSes of the HTTPRoutes and the GRPCRoutes only.
+		if routeKind == lib.HTTPRoute || routeKind == lib.GRPCRoute {
+			akogatewayapiobjects.GatewayApiLister().UpdateGatewayRouteToHostname(gwNsName, hostnameIntersection)
+		}
 
 		akogatewayapiobjects.GatewayApiLister().UpdateGatewayRouteMappings(gwNsName, listenerList, routeTypeNsName)
 		if !utils.HasElem(gwNsNameList, gwNsName) {
@@ -270,6 +401,25 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 	return gwNsNameList, true
 }
 
+// isListenerNamespaceAllowed checks the namespace of the route against the allowed namespace stored
+// for the listener, the Selector is evaluated against the listener of the gateway object.
+func isListenerNamespaceAllowed(gwObj *gatewayv1.Gateway, listenerName, listenerAllowedNS, namespace string) bool {
+	switch listenerAllowedNS {
+	case string(gatewayv1.NamespacesFromAll):
+		return true
+	case string(gatewayv1.NamespacesFromSelector):
+		if gwObj == nil {
+			return false
+		}
+		i := akogatewayapilib.FindListenerByName(listenerName, gwObj.Spec.Listeners)
+		if i == -1 {
+			return false
+		}
+		return isRouteNamespaceAllowed(gwObj, gwObj.Spec.Listeners[i], namespace)
+	}
+	return listenerAllowedNS == namespace
+}
+
 func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 	routeTypeNsName := lib.HTTPRoute + "/" + namespace + "/" + name
 	hrObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Lister().HTTPRoutes(namespace).Get(name)
@@ -278,20 +428,133 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 			utils.AviLog.Errorf("key: %s, msg: got error while getting gateway: %v", key, err)
 			return []string{}, false
 		}
-		_, svcNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToService(routeTypeNsName)
-		_, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
-		for _, gwNsName := range gwNsNameList {
-			for _, svcNsName := range svcNsNameList {
-				akogatewayapiobjects.GatewayApiLister().DeleteGatewayServiceMappings(gwNsName, svcNsName)
+		deleteRouteMappings(routeTypeNsName)
+		return []string{routeTypeNsName}, true
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range hrObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		// The mirror backends are mapped as well, so that their endpoint changes update the sideband profile.
+		for _, filter := range rule.Filters {
+			if filter.RequestMirror != nil {
+				backendRefs = append(backendRefs, gatewayv1.BackendRef{BackendObjectReference: filter.RequestMirror.BackendRef})
 			}
 		}
-		akogatewayapiobjects.GatewayApiLister().DeleteRouteServiceMappings(routeTypeNsName)
-		akogatewayapiobjects.GatewayApiLister().DeleteRouteGatewayMappings(routeTypeNsName)
+	}
+	updateRouteMappings(routeTypeNsName, namespace, hrObj.Spec.ParentRefs, backendRefs)
+
+	utils.AviLog.Debugf("key: %s, msg: HTTPRoutes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}, true
+}
+
+func GRPCRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.GRPCRoute + "/" + namespace + "/" + name
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting grpcroute: %v", key, err)
+			return []string{}, false
+		}
+		deleteRouteMappings(routeTypeNsName)
+		return []string{routeTypeNsName}, true
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range grObj.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	updateRouteMappings(routeTypeNsName, namespace, grObj.Spec.ParentRefs, backendRefs)
+
+	utils.AviLog.Debugf("key: %s, msg: GRPCRoutes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}, true
+}
+
+func TLSRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.TLSRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting tlsroute: %v", key, err)
+			return []string{}, false
+		}
+		deleteRouteMappings(routeTypeNsName)
+		return []string{routeTypeNsName}, true
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range trObj.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	updateRouteMappings(routeTypeNsName, namespace, trObj.Spec.ParentRefs, backendRefs)
+
+	utils.AviLog.Debugf("key: %s, msg: TLSRoutes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}, true
+}
+
+func TCPRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.TCPRoute + "/" + namespace + "/" + name
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting tcproute: %v", key, err)
+			return []string{}, false
+		}
+		deleteRouteMappings(routeTypeNsName)
+		return []string{routeTypeNsName}, true
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range trObj.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	updateRouteMappings(routeTypeNsName, namespace, trObj.Spec.ParentRefs, backendRefs)
+
+	utils.AviLog.Debugf("key: %s, msg: TCPRoutes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}, true
+}
+
+func UDPRouteChanges(namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := lib.UDPRoute + "/" + namespace + "/" + name
+	urObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting udproute: %v", key, err)
+			return []string{}, false
+		}
+		deleteRouteMappings(routeTypeNsName)
 		return []string{routeTypeNsName}, true
 	}
 
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range urObj.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs...)
+	}
+	updateRouteMappings(routeTypeNsName, namespace, urObj.Spec.ParentRefs, backendRefs)
+
+	utils.AviLog.Debugf("key: %s, msg: UDPRoutes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}, true
+}
+
+func deleteRouteMappings(routeTypeNsName string) {
+	_, svcNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToService(routeTypeNsName)
+	_, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+	for _, gwNsName := range gwNsNameList {
+		for _, svcNsName := range svcNsNameList {
+			akogatewayapiobjects.GatewayApiLister().DeleteGatewayServiceMappings(gwNsName, svcNsName)
+		}
+	}
+	akogatewayapiobjects.GatewayApiLister().DeleteRouteServiceMappings(routeTypeNsName)
+	akogatewayapiobjects.GatewayApiLister().DeleteRouteGatewayMappings(routeTypeNsName)
+}
+
+func updateRouteMappings(routeTypeNsName, namespace string, parentRefs []gatewayv1.ParentReference, backendRefs []gatewayv1.BackendRef) {
 	var gwNsNameList []string
-	for _, parentRef := range hrObj.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		ns := namespace
 		if parentRef.Namespace != nil {
 			ns = string(*parentRef.Namespace)
@@ -301,15 +564,13 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 	}
 
 	var svcNsNameList []string
-	for _, rule := range hrObj.Spec.Rules {
-		for _, backendRef := range rule.BackendRefs {
-			ns := namespace
-			if backendRef.Namespace != nil {
-				ns = string(*backendRef.Namespace)
-			}
-			svcNsName := ns + "/" + string(backendRef.Name)
-			svcNsNameList = append(svcNsNameList, svcNsName)
+	for _, backendRef := range backendRefs {
+		ns := namespace
+		if backendRef.Namespace != nil {
+			ns = string(*backendRef.Namespace)
 		}
+		svcNsName := ns + "/" + string(backendRef.Name)
+		svcNsNameList = append(svcNsNameList, svcNsName)
 	}
 
 	// deletes the services, which are removed, from the gateway <-> service and route <-> service mappings
@@ -336,9 +597,6 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 			akogatewayapiobjects.GatewayApiLister().UpdateGatewayServiceMappings(gwNsName, svcNsName)
 		}
 	}
-
-	utils.AviLog.Debugf("key: %s, msg: HTTPRoutes retrieved %s", key, []string{routeTypeNsName})
-	return []string{routeTypeNsName}, true
 }
 
 func ServiceToGateways(namespace, name, key string) ([]string, bool) {
@@ -17,12 +17,15 @@ package nodes
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
 type RouteModel interface {
@@ -39,6 +42,14 @@ func NewRouteModel(key, objType, name, namespace string) (RouteModel, error) {
 	switch objType {
 	case lib.HTTPRoute:
 		return GetHTTPRouteModel(key, name, namespace)
+	case lib.GRPCRoute:
+		return GetGRPCRouteModel(key, name, namespace)
+	case lib.TLSRoute:
+		return GetTLSRouteModel(key, name, namespace)
+	case lib.TCPRoute:
+		return GetTCPRouteModel(key, name, namespace)
+	case lib.UDPRoute:
+		return GetUDPRouteModel(key, name, namespace)
 	}
 	return nil, fmt.Errorf("object of type %s not supported", objType)
 }
@@ -51,13 +62,22 @@ type HeaderMatch struct {
 
 type PathMatch struct {
 	Path string
-	//Exact, PathPrefix
+	//Exact, PathPrefix, PathSuffix
 	Type string
 }
 
+type QueryParamMatch struct {
+	Name  string
+	Value string
+}
+
+// Match fields added after PathMatch and HeaderMatch are omitted when empty, so that
+// the names of the child VS and the pools derived from the matches stay unchanged.
 type Match struct {
-	PathMatch   *PathMatch
-	HeaderMatch []*HeaderMatch
+	PathMatch       *PathMatch
+	HeaderMatch     []*HeaderMatch
+	QueryParamMatch []*QueryParamMatch `json:",omitempty"`
+	MethodMatch     string             `json:",omitempty"`
 }
 
 type Matches []*Match
@@ -93,11 +113,20 @@ type RedirectFilter struct {
 	StatusCode int32
 }
 
+type RewriteFilter struct {
+	Host string
+	// ReplaceFullPath or ReplacePrefixMatch
+	PathType string
+	Path     string
+}
+
 type Filter struct {
 	Type           string
 	RequestFilter  *HeaderFilter
 	ResponseFilter *HeaderFilter
 	RedirectFilter *RedirectFilter
+	RewriteFilter  *RewriteFilter
+	MirrorFilter   *Backend
 }
 
 type Backend struct {
@@ -107,10 +136,16 @@ type Backend struct {
 	Weight    int32
 }
 
+type Timeouts struct {
+	Request        *time.Duration
+	BackendRequest *time.Duration
+}
+
 type Rule struct {
 	Matches  []*Match
 	Filters  []*Filter
 	Backends []*Backend
+	Timeouts *Timeouts
 }
 
 type RouteConfig struct {
@@ -202,6 +237,19 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 				match.HeaderMatch = append(match.HeaderMatch, headerMatch)
 			}
 
+			// query param match
+			for _, queryParam := range ruleMatch.QueryParams {
+				match.QueryParamMatch = append(match.QueryParamMatch, &QueryParamMatch{
+					Name:  string(queryParam.Name),
+					Value: queryParam.Value,
+				})
+			}
+
+			// method match
+			if ruleMatch.Method != nil {
+				match.MethodMatch = string(*ruleMatch.Method)
+			}
+
 			routeConfigRule.Matches = append(routeConfigRule.Matches, match)
 		}
 		sort.Sort((Matches)(routeConfigRule.Matches))
@@ -213,56 +261,12 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 
 			// request header filter
 			if ruleFilter.RequestHeaderModifier != nil {
-				filter.RequestFilter = &HeaderFilter{}
-				filter.RequestFilter.Add = make([]*Header, 0, len(ruleFilter.RequestHeaderModifier.Add))
-				for _, addFilter := range ruleFilter.RequestHeaderModifier.Add {
-					addHeader := &Header{
-						Name:  string(addFilter.Name),
-						Value: addFilter.Value,
-					}
-					filter.RequestFilter.Add = append(filter.RequestFilter.Add, addHeader)
-				}
-				filter.RequestFilter.Set = make([]*Header, 0, len(ruleFilter.RequestHeaderModifier.Set))
-				for _, setFilter := range ruleFilter.RequestHeaderModifier.Set {
-					setHeader := &Header{
-						Name:  string(setFilter.Name),
-						Value: setFilter.Value,
-					}
-					filter.RequestFilter.Set = append(filter.RequestFilter.Set, setHeader)
-				}
-				filter.RequestFilter.Remove = make([]string, len(ruleFilter.RequestHeaderModifier.Remove))
-				copy(filter.RequestFilter.Remove, ruleFilter.RequestHeaderModifier.Remove)
-
-				sort.Sort((Headers)(filter.RequestFilter.Add))
-				sort.Sort((Headers)(filter.RequestFilter.Set))
-				sort.Strings(filter.RequestFilter.Remove)
+				filter.RequestFilter = parseHeaderFilter(ruleFilter.RequestHeaderModifier)
 			}
 
 			// response header filter
 			if ruleFilter.ResponseHeaderModifier != nil {
-				filter.ResponseFilter = &HeaderFilter{}
-				filter.ResponseFilter.Add = make([]*Header, 0, len(ruleFilter.ResponseHeaderModifier.Add))
-				for _, addFilter := range ruleFilter.ResponseHeaderModifier.Add {
-					addHeader := &Header{
-						Name:  string(addFilter.Name),
-						Value: addFilter.Value,
-					}
-					filter.ResponseFilter.Add = append(filter.ResponseFilter.Add, addHeader)
-				}
-				filter.ResponseFilter.Set = make([]*Header, 0, len(ruleFilter.ResponseHeaderModifier.Set))
-				for _, setFilter := range ruleFilter.ResponseHeaderModifier.Set {
-					setHeader := &Header{
-						Name:  string(setFilter.Name),
-						Value: setFilter.Value,
-					}
-					filter.ResponseFilter.Set = append(filter.ResponseFilter.Set, setHeader)
-				}
-				filter.ResponseFilter.Remove = make([]string, len(ruleFilter.ResponseHeaderModifier.Remove))
-				copy(filter.ResponseFilter.Remove, ruleFilter.ResponseHeaderModifier.Remove)
-
-				sort.Sort((Headers)(filter.ResponseFilter.Add))
-				sort.Sort((Headers)(filter.ResponseFilter.Set))
-				sort.Strings(filter.ResponseFilter.Remove)
+				filter.ResponseFilter = parseHeaderFilter(ruleFilter.ResponseHeaderModifier)
 			}
 
 			// request redirect filter
@@ -275,25 +279,45 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 					filter.RedirectFilter.StatusCode = int32(*ruleFilter.RequestRedirect.StatusCode)
 				}
 			}
-			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
-		}
-		for _, ruleBackend := range rule.BackendRefs {
-			backend := &Backend{}
-			backend.Name = string(ruleBackend.Name)
-			if ruleBackend.Namespace != nil {
-				backend.Namespace = string(*ruleBackend.Namespace)
-			} else {
-				backend.Namespace = hr.namespace
+
+			// url rewrite filter
+			if ruleFilter.URLRewrite != nil {
+				filter.RewriteFilter = &RewriteFilter{}
+				if ruleFilter.URLRewrite.Hostname != nil {
+					filter.RewriteFilter.Host = string(*ruleFilter.URLRewrite.Hostname)
+				}
+				if pathModifier := ruleFilter.URLRewrite.Path; pathModifier != nil {
+					filter.RewriteFilter.PathType = string(pathModifier.Type)
+					if pathModifier.Type == gatewayv1.FullPathHTTPPathModifier && pathModifier.ReplaceFullPath != nil {
+						filter.RewriteFilter.Path = *pathModifier.ReplaceFullPath
+					} else if pathModifier.Type == gatewayv1.PrefixMatchHTTPPathModifier && pathModifier.ReplacePrefixMatch != nil {
+						filter.RewriteFilter.Path = *pathModifier.ReplacePrefixMatch
+					}
+				}
 			}
-			if ruleBackend.Port != nil {
-				//Default 0
-				backend.Port = int32(*ruleBackend.Port)
+
+			// request mirror filter, the backend is subject to the ReferenceGrants as the backendRefs
+			if ruleFilter.RequestMirror != nil {
+				mirrorBackends := parseBackendRefs(hr.key, lib.HTTPRoute, hr.namespace,
+					[]gatewayv1.BackendRef{{BackendObjectReference: ruleFilter.RequestMirror.BackendRef}})
+				if len(mirrorBackends) > 0 {
+					filter.MirrorFilter = mirrorBackends[0]
+				}
 			}
-			backend.Weight = 1
-			if ruleBackend.Weight != nil {
-				backend.Weight = *ruleBackend.Weight
+			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
+		}
+		backendRefs := make([]gatewayv1.BackendRef, 0, len(rule.BackendRefs))
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		routeConfigRule.Backends = parseBackendRefs(hr.key, lib.HTTPRoute, hr.namespace, backendRefs)
+
+		// timeouts, the values are checked by the route validator
+		if rule.Timeouts != nil {
+			routeConfigRule.Timeouts = &Timeouts{
+				Request:        parseDuration(rule.Timeouts.Request),
+				BackendRequest: parseDuration(rule.Timeouts.BackendRequest),
 			}
-			routeConfigRule.Backends = append(routeConfigRule.Backends, backend)
 		}
 		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
 	}
@@ -316,3 +340,414 @@ func (hr *httpRoute) GetParents() sets.Set[string] {
 	}
 	return parents
 }
+
+type grpcRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.GRPCRouteSpec
+}
+
+func GetGRPCRouteModel(key string, name, namespace string) (RouteModel, error) {
+	gr := &grpcRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		return gr, err
+	}
+	gr.spec = grObj.Spec.DeepCopy()
+	return gr, nil
+}
+
+func (gr *grpcRoute) GetName() string {
+	return gr.name
+}
+
+func (gr *grpcRoute) GetNamespace() string {
+	return gr.namespace
+}
+
+func (gr *grpcRoute) GetType() string {
+	return lib.GRPCRoute
+}
+
+func (gr *grpcRoute) GetSpec() interface{} {
+	return gr.spec
+}
+
+// ParseRouteRules for a GRPCRoute translates the service and the method matches
+// to the path matches, as the gRPC requests are sent to /<service>/<method>.
+// A rule without any matches matches all the requests.
+func (gr *grpcRoute) ParseRouteRules() *RouteConfig {
+	if gr.routeConfig != nil {
+		return gr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+
+	routeConfig.Hosts = make([]string, len(gr.spec.Hostnames))
+	for i := range gr.spec.Hostnames {
+		routeConfig.Hosts[i] = string(gr.spec.Hostnames[i])
+	}
+
+	routeConfig.Rules = make([]*Rule, 0, len(gr.spec.Rules))
+	for _, rule := range gr.spec.Rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Matches = make([]*Match, 0, len(rule.Matches))
+		for _, ruleMatch := range rule.Matches {
+			match := &Match{}
+
+			// method match
+			match.PathMatch = getGRPCPathMatch(ruleMatch.Method)
+
+			// header match
+			match.HeaderMatch = make([]*HeaderMatch, 0, len(ruleMatch.Headers))
+			for _, header := range ruleMatch.Headers {
+				headerMatch := &HeaderMatch{}
+				if header.Type != nil {
+					headerMatch.Type = string(*header.Type)
+				}
+				headerMatch.Name = string(header.Name)
+				headerMatch.Value = header.Value
+				match.HeaderMatch = append(match.HeaderMatch, headerMatch)
+			}
+
+			routeConfigRule.Matches = append(routeConfigRule.Matches, match)
+		}
+		if len(routeConfigRule.Matches) == 0 {
+			routeConfigRule.Matches = append(routeConfigRule.Matches, &Match{
+				PathMatch:   getGRPCPathMatch(nil),
+				HeaderMatch: []*HeaderMatch{},
+			})
+		}
+		sort.Sort((Matches)(routeConfigRule.Matches))
+
+		routeConfigRule.Filters = make([]*Filter, 0, len(rule.Filters))
+		for _, ruleFilter := range rule.Filters {
+			filter := &Filter{}
+			filter.Type = string(ruleFilter.Type)
+			if ruleFilter.RequestHeaderModifier != nil {
+				filter.RequestFilter = parseHeaderFilter(ruleFilter.RequestHeaderModifier)
+			}
+			if ruleFilter.ResponseHeaderModifier != nil {
+				filter.ResponseFilter = parseHeaderFilter(ruleFilter.ResponseHeaderModifier)
+			}
+			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
+		}
+
+		backendRefs := make([]gatewayv1.BackendRef, 0, len(rule.BackendRefs))
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		routeConfigRule.Backends = parseBackendRefs(gr.key, lib.GRPCRoute, gr.namespace, backendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	gr.routeConfig = routeConfig
+	return gr.routeConfig
+}
+
+func (gr *grpcRoute) Exists() bool {
+	return gr != nil
+}
+
+func (gr *grpcRoute) GetParents() sets.Set[string] {
+	return getParents(gr.namespace, gr.spec.ParentRefs)
+}
+
+// getGRPCPathMatch returns the path match for the gRPC method match, only the
+// Exact match type is supported and it is validated at the ingestion.
+func getGRPCPathMatch(method *gatewayv1alpha2.GRPCMethodMatch) *PathMatch {
+	if method == nil || (method.Service == nil && method.Method == nil) {
+		return &PathMatch{Path: "/", Type: "PathPrefix"}
+	}
+	if method.Service == nil {
+		return &PathMatch{Path: "/" + *method.Method, Type: "PathSuffix"}
+	}
+	if method.Method == nil {
+		return &PathMatch{Path: "/" + *method.Service + "/", Type: "PathPrefix"}
+	}
+	return &PathMatch{Path: "/" + *method.Service + "/" + *method.Method, Type: "Exact"}
+}
+
+type tlsRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.TLSRouteSpec
+}
+
+func GetTLSRouteModel(key string, name, namespace string) (RouteModel, error) {
+	tr := &tlsRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	if err != nil {
+		return tr, err
+	}
+	tr.spec = trObj.Spec.DeepCopy()
+	return tr, nil
+}
+
+func (tr *tlsRoute) GetName() string {
+	return tr.name
+}
+
+func (tr *tlsRoute) GetNamespace() string {
+	return tr.namespace
+}
+
+func (tr *tlsRoute) GetType() string {
+	return lib.TLSRoute
+}
+
+func (tr *tlsRoute) GetSpec() interface{} {
+	return tr.spec
+}
+
+// ParseRouteRules for a TLSRoute returns the SNI hostnames and the backends,
+// the rules of a TLSRoute don't have any matches or filters.
+func (tr *tlsRoute) ParseRouteRules() *RouteConfig {
+	if tr.routeConfig != nil {
+		return tr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+
+	routeConfig.Hosts = make([]string, len(tr.spec.Hostnames))
+	for i := range tr.spec.Hostnames {
+		routeConfig.Hosts[i] = string(tr.spec.Hostnames[i])
+	}
+
+	routeConfig.Rules = make([]*Rule, 0, len(tr.spec.Rules))
+	for _, rule := range tr.spec.Rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Backends = parseBackendRefs(tr.key, lib.TLSRoute, tr.namespace, rule.BackendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	tr.routeConfig = routeConfig
+	return tr.routeConfig
+}
+
+func (tr *tlsRoute) Exists() bool {
+	return tr != nil
+}
+
+func (tr *tlsRoute) GetParents() sets.Set[string] {
+	return getParents(tr.namespace, tr.spec.ParentRefs)
+}
+
+type tcpRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.TCPRouteSpec
+}
+
+func GetTCPRouteModel(key string, name, namespace string) (RouteModel, error) {
+	tr := &tcpRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	trObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	if err != nil {
+		return tr, err
+	}
+	tr.spec = trObj.Spec.DeepCopy()
+	return tr, nil
+}
+
+func (tr *tcpRoute) GetName() string {
+	return tr.name
+}
+
+func (tr *tcpRoute) GetNamespace() string {
+	return tr.namespace
+}
+
+func (tr *tcpRoute) GetType() string {
+	return lib.TCPRoute
+}
+
+func (tr *tcpRoute) GetSpec() interface{} {
+	return tr.spec
+}
+
+// ParseRouteRules for a TCPRoute returns the backends, the rules of a
+// TCPRoute don't have any hostnames, matches or filters.
+func (tr *tcpRoute) ParseRouteRules() *RouteConfig {
+	if tr.routeConfig != nil {
+		return tr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+	routeConfig.Rules = make([]*Rule, 0, len(tr.spec.Rules))
+	for _, rule := range tr.spec.Rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Backends = parseBackendRefs(tr.key, lib.TCPRoute, tr.namespace, rule.BackendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	tr.routeConfig = routeConfig
+	return tr.routeConfig
+}
+
+func (tr *tcpRoute) Exists() bool {
+	return tr != nil
+}
+
+func (tr *tcpRoute) GetParents() sets.Set[string] {
+	return getParents(tr.namespace, tr.spec.ParentRefs)
+}
+
+type udpRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.UDPRouteSpec
+}
+
+func GetUDPRouteModel(key string, name, namespace string) (RouteModel, error) {
+	ur := &udpRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	urObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	if err != nil {
+		return ur, err
+	}
+	ur.spec = urObj.Spec.DeepCopy()
+	return ur, nil
+}
+
+func (ur *udpRoute) GetName() string {
+	return ur.name
+}
+
+func (ur *udpRoute) GetNamespace() string {
+	return ur.namespace
+}
+
+func (ur *udpRoute) GetType() string {
+	return lib.UDPRoute
+}
+
+func (ur *udpRoute) GetSpec() interface{} {
+	return ur.spec
+}
+
+// ParseRouteRules for a UDPRoute returns the backends, the rules of a
+// UDPRoute don't have any hostnames, matches or filters.
+func (ur *udpRoute) ParseRouteRules() *RouteConfig {
+	if ur.routeConfig != nil {
+		return ur.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+	routeConfig.Rules = make([]*Rule, 0, len(ur.spec.Rules))
+	for _, rule := range ur.spec.Rules {
+		routeConfigRule := &Rule{}
+		routeConfigRule.Backends = parseBackendRefs(ur.key, lib.UDPRoute, ur.namespace, rule.BackendRefs)
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	ur.routeConfig = routeConfig
+	return ur.routeConfig
+}
+
+func (ur *udpRoute) Exists() bool {
+	return ur != nil
+}
+
+func (ur *udpRoute) GetParents() sets.Set[string] {
+	return getParents(ur.namespace, ur.spec.ParentRefs)
+}
+
+func parseHeaderFilter(headerModifier *gatewayv1.HTTPHeaderFilter) *HeaderFilter {
+	headerFilter := &HeaderFilter{}
+	headerFilter.Add = make([]*Header, 0, len(headerModifier.Add))
+	for _, addFilter := range headerModifier.Add {
+		addHeader := &Header{
+			Name:  string(addFilter.Name),
+			Value: addFilter.Value,
+		}
+		headerFilter.Add = append(headerFilter.Add, addHeader)
+	}
+	headerFilter.Set = make([]*Header, 0, len(headerModifier.Set))
+	for _, setFilter := range headerModifier.Set {
+		setHeader := &Header{
+			Name:  string(setFilter.Name),
+			Value: setFilter.Value,
+		}
+		headerFilter.Set = append(headerFilter.Set, setHeader)
+	}
+	headerFilter.Remove = make([]string, len(headerModifier.Remove))
+	copy(headerFilter.Remove, headerModifier.Remove)
+
+	sort.Sort((Headers)(headerFilter.Add))
+	sort.Sort((Headers)(headerFilter.Set))
+	sort.Strings(headerFilter.Remove)
+	return headerFilter
+}
+
+// parseBackendRefs returns the backends of a rule, the cross namespace backends which
+// are not permitted by a ReferenceGrant are skipped.
+func parseBackendRefs(key, routeKind, namespace string, backendRefs []gatewayv1.BackendRef) []*Backend {
+	var backends []*Backend
+	for _, ruleBackend := range backendRefs {
+		backend := &Backend{}
+		backend.Name = string(ruleBackend.Name)
+		if ruleBackend.Namespace != nil {
+			backend.Namespace = string(*ruleBackend.Namespace)
+		} else {
+			backend.Namespace = namespace
+		}
+		if !akogatewayapilib.IsReferencePermitted(routeKind, namespace, utils.Service, backend.Namespace, backend.Name) {
+			utils.AviLog.Warnf("key: %s, msg: backend %s/%s of the %s is not permitted by any ReferenceGrant", key, backend.Namespace, backend.Name, routeKind)
+			continue
+		}
+		if ruleBackend.Port != nil {
+			//Default 0
+			backend.Port = int32(*ruleBackend.Port)
+		}
+		backend.Weight = 1
+		if ruleBackend.Weight != nil {
+			backend.Weight = *ruleBackend.Weight
+		}
+		backends = append(backends, backend)
+	}
+	return backends
+}
+
+// parseDuration returns the value of a Gateway API duration, nil is returned when the duration
+// is not set or is not valid.
+func parseDuration(duration *gatewayv1.Duration) *time.Duration {
+	if duration == nil {
+		return nil
+	}
+	value, err := time.ParseDuration(string(*duration))
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+func getParents(routeNamespace string, parentRefs []gatewayv1.ParentReference) sets.Set[string] {
+	parents := sets.New[string]()
+	for _, ref := range parentRefs {
+		namespace := routeNamespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		parents.Insert(namespace + "/" + string(ref.Name))
+	}
+	return parents
+}
@@ -625,8 +625,10 @@ func (g *GWLister) GetGatewayListenerToHostname(gwNsName, listner string) string
 	defer g.gwLock.RUnlock()
 
 	key := gwNsName + "/" + listner
-	_, obj := g.gatewayToHostnameStore.Get(key)
-
+	found, obj := g.gatewayToHostnameStore.Get(key)
+	if !found {
+		return ""
+	}
 	return obj.(string)
 }
 func (g *GWLister) UpdateGatewayListenerToHostname(gwListenerNsName, hostname string) {
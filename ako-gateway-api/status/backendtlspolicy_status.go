@@ -0,0 +1,122 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+type backendtlspolicy struct{}
+
+func (o *backendtlspolicy) Get(key string, name string, namespace string) *gatewayv1alpha2.BackendTLSPolicy {
+
+	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendTLSPolicy object. err: %s", key, err)
+		return nil
+	}
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the BackendTLSPolicy object %s", key, name)
+	return obj.DeepCopy()
+}
+
+func (o *backendtlspolicy) GetAll(key string) map[string]*gatewayv1alpha2.BackendTLSPolicy {
+
+	objs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendTLSPolicy objects. err: %s", key, err)
+		return nil
+	}
+
+	policyMap := make(map[string]*gatewayv1alpha2.BackendTLSPolicy)
+	for _, obj := range objs {
+		policyMap[obj.Namespace+"/"+obj.Name] = obj.DeepCopy()
+	}
+
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the BackendTLSPolicy objects", key)
+	return policyMap
+}
+
+func (o *backendtlspolicy) Delete(key string, option status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *backendtlspolicy) Update(key string, option status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *backendtlspolicy) BulkUpdate(key string, options []status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *backendtlspolicy) Patch(key string, obj runtime.Object, status *Status, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: Patch retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	policy := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+	if o.isStatusEqual(&policy.Status, status.PolicyStatus) {
+		return
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": status.PolicyStatus,
+	})
+	_, err := akogatewayapilib.AKOControlConfig().GatewayAPIClientset().GatewayV1alpha2().BackendTLSPolicies(policy.Namespace).Patch(context.TODO(), policy.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was an error in updating the BackendTLSPolicy status. err: %+v, retry: %d", key, err, retry)
+		updatedObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(policy.Namespace).Get(policy.Name)
+		if err != nil {
+			utils.AviLog.Warnf("BackendTLSPolicy not found %v", err)
+			return
+		}
+		o.Patch(key, updatedObj, status, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the BackendTLSPolicy %s/%s status %+v", key, policy.Namespace, policy.Name, utils.Stringify(status))
+}
+
+func (o *backendtlspolicy) isStatusEqual(old, new *gatewayv1alpha2.PolicyStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	currentTime := metav1.Now()
+	for i := range oldStatus.Ancestors {
+		for j := range oldStatus.Ancestors[i].Conditions {
+			oldStatus.Ancestors[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	for i := range newStatus.Ancestors {
+		for j := range newStatus.Ancestors[i].Conditions {
+			newStatus.Ancestors[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+type grpcroute struct{}
+
+func (o *grpcroute) Get(key string, name string, namespace string) *gatewayv1alpha2.GRPCRoute {
+
+	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the GRPCRoute object. err: %s", key, err)
+		return nil
+	}
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the GRPCRoute object %s", key, name)
+	return obj.DeepCopy()
+}
+
+func (o *grpcroute) GetAll(key string) map[string]*gatewayv1alpha2.GRPCRoute {
+
+	objs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the GRPCRoute objects. err: %s", key, err)
+		return nil
+	}
+
+	grpcRouteMap := make(map[string]*gatewayv1alpha2.GRPCRoute)
+	for _, obj := range objs {
+		grpcRouteMap[obj.Namespace+"/"+obj.Name] = obj.DeepCopy()
+	}
+
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the GRPCRoute objects", key)
+	return grpcRouteMap
+}
+
+func (o *grpcroute) Delete(key string, option status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *grpcroute) Update(key string, option status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *grpcroute) BulkUpdate(key string, options []status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *grpcroute) Patch(key string, obj runtime.Object, status *Status, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: Patch retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	grpcRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+	if o.isStatusEqual(&grpcRoute.Status, status.GRPCRouteStatus) {
+		return
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": status.GRPCRouteStatus,
+	})
+	_, err := akogatewayapilib.AKOControlConfig().GatewayAPIClientset().GatewayV1alpha2().GRPCRoutes(grpcRoute.Namespace).Patch(context.TODO(), grpcRoute.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was an error in updating the GRPCRoute status. err: %+v, retry: %d", key, err, retry)
+		updatedObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(grpcRoute.Namespace).Get(grpcRoute.Name)
+		if err != nil {
+			utils.AviLog.Warnf("GRPCRoute not found %v", err)
+			return
+		}
+		o.Patch(key, updatedObj, status, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the GRPCRoute %s/%s status %+v", key, grpcRoute.Namespace, grpcRoute.Name, utils.Stringify(status))
+}
+
+func (o *grpcroute) isStatusEqual(old, new *gatewayv1alpha2.GRPCRouteStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	currentTime := metav1.Now()
+	for i := range oldStatus.Parents {
+		for j := range oldStatus.Parents[i].Conditions {
+			oldStatus.Parents[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	for i := range newStatus.Parents {
+		for j := range newStatus.Parents[i].Conditions {
+			newStatus.Parents[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
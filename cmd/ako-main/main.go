@@ -30,6 +30,7 @@ import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	crd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned"
@@ -69,7 +70,21 @@ func InitializeAKOApi() {
 	if lib.IsPrometheusEnabled() {
 		lib.SetPrometheusRegistry()
 	}
-	akoApi := api.NewServer(lib.GetAkoApiServerPort(), []models.ApiModel{}, lib.IsPrometheusEnabled(), lib.GetPrometheusRegistry())
+	apiModels := []models.ApiModel{}
+	if lib.IsIntrospectionEnabled() {
+		apiModels = append(apiModels,
+			&models.GraphModel{ListModels: nodes.ListModelViews, GetModels: nodes.GetModelViews},
+			&models.CacheModel{GetCachedVS: avicache.GetCachedVSViews})
+	}
+	if lib.IsDryRunEnabled() {
+		utils.AviLog.Infof("AKO is running in dry-run mode, the rest operations won't be sent to the Avi controller")
+		// The plan only exposes a redacted view of the rest operations, hence it is registered regardless of the introspection APIs.
+		apiModels = append(apiModels, models.InitDryRunPlan())
+	}
+	if lib.IsOrphanGCEnabled() {
+		apiModels = append(apiModels, models.OrphanReport)
+	}
+	akoApi := api.NewServer(lib.GetAkoApiServerPort(), apiModels, lib.IsPrometheusEnabled(), lib.GetPrometheusRegistry())
 	akoApi.InitApi()
 	lib.SetApiServerInstance(akoApi)
 }
@@ -33,6 +33,7 @@ import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	v1beta1crd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1beta1/clientset/versioned"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -95,6 +96,13 @@ func Initialize() {
 		utils.AviLog.Fatalf("Error building kubernetes clientset: %s", err.Error())
 	}
 
+	// AviInfraSettings referred by the GatewayClasses, HostRules and HTTPRules are read with the AKO CRD clientset
+	v1beta1crdClient, err := v1beta1crd.NewForConfig(cfg)
+	if err != nil {
+		utils.AviLog.Fatalf("Error building AKO CRD v1beta1 clientset: %s", err.Error())
+	}
+	lib.AKOControlConfig().SetCRDClientsetAndEnableInfraSettingParam(v1beta1crdClient)
+
 	utils.AviLog.Infof("Successfully created kube client for ako-gateway-api")
 
 	akoControlConfig.SetEventRecorder(lib.AKOGatewayEventComponent, kubeClient, false)
@@ -114,6 +122,7 @@ func Initialize() {
 	utils.NewInformers(utils.KubeClientIntf{ClientSet: kubeClient}, registeredInformers, informersArg)
 
 	informers := k8s.K8sinformers{Cs: kubeClient}
+	k8s.NewGatewayCRDInformers()
 	c := akogatewayk8s.SharedGatewayController()
 	c.InitGatewayAPIInformers(gwApiClient)
 	stopCh := utils.SetupSignalHandler()
@@ -0,0 +1,578 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+const (
+	cacheSnapshotVersion = 1
+	// snapshotFetchBatchSize is the number of object names filtered by a single GET of the modified objects.
+	snapshotFetchBatchSize = 50
+)
+
+// snapshotObjectTypes are the Avi object types restored from the snapshot, other than the virtualservices. The
+// types are reconciled in this order, as the objects of a type are resolved against the objects of the former types.
+var snapshotObjectTypes = []string{"pkiprofile", "sslkeyandcertificate", "vsvip", "pool", "poolgroup",
+	"vsdatascriptset", "httppolicyset", "l4policyset", "networksecuritypolicy", "healthmonitor"}
+
+// AviObjCacheSnapshot is the copy of the Avi object cache persisted on the PVC, which is used to warm start the
+// cache on the restarts of AKO.
+type AviObjCacheSnapshot struct {
+	Version                 int
+	ClusterName             string
+	CloudName               string
+	Tenant                  string
+	ControllerVersion       string
+	CreatedAt               time.Time
+	VirtualServices         []*AviVsCache
+	PKIProfiles             []*AviPkiProfileCache
+	SSLKeys                 []*AviSSLCache
+	VSVips                  []*AviVSVIPCache
+	Pools                   []*AviPoolCache
+	PoolGroups              []*AviPGCache
+	DataScripts             []*AviDSCache
+	HTTPPolicySets          []*AviHTTPPolicyCache
+	L4PolicySets            []*AviL4PolicyCache
+	NetworkSecurityPolicies []*AviNetworkSecurityPolicyCache
+	HealthMonitors          []*AviHealthMonitorCache
+}
+
+// SaveCacheSnapshot writes the Avi object cache to the snapshot file on the PVC.
+func (c *AviObjCache) SaveCacheSnapshot(version, cloud string) error {
+	snapshot := AviObjCacheSnapshot{
+		Version:           cacheSnapshotVersion,
+		ClusterName:       lib.GetClusterName(),
+		CloudName:         cloud,
+		Tenant:            lib.GetTenant(),
+		ControllerVersion: version,
+		CreatedAt:         time.Now(),
+	}
+	for _, objIntf := range c.VsCacheMeta.ShallowCopy() {
+		if vsCacheObj, ok := objIntf.(*AviVsCache); ok && vsCacheObj != nil {
+			if vsCopy, done := vsCacheObj.GetVSCopy(); done {
+				snapshot.VirtualServices = append(snapshot.VirtualServices, vsCopy)
+			}
+		}
+	}
+	for _, objType := range snapshotObjectTypes {
+		for _, objIntf := range getSnapshotObjCache(c, objType).ShallowCopy() {
+			snapshot.add(objIntf)
+		}
+	}
+
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return err
+	}
+	// The snapshot is written to a temporary file first, so that a restart of AKO in the middle of the write
+	// does not leave a truncated snapshot behind. The snapshot lists all the Avi objects of the tenant, so it is
+	// readable only by AKO. A temporary file left behind by an earlier write is removed, as its mode is retained.
+	snapshotPath := lib.GetCacheSnapshotFilePath()
+	if err = os.Remove(snapshotPath + ".tmp"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = os.WriteFile(snapshotPath+".tmp", data, 0600); err != nil {
+		return err
+	}
+	if err = os.Rename(snapshotPath+".tmp", snapshotPath); err != nil {
+		return err
+	}
+	utils.AviLog.Infof("Saved the snapshot of the Avi object cache with %d virtualservices to %s", len(snapshot.VirtualServices), snapshotPath)
+	return nil
+}
+
+// LoadCacheSnapshot reads the snapshot of the Avi object cache from the PVC. An error is returned if the snapshot
+// is not present, or it was written for another cluster, cloud, tenant or controller version.
+func LoadCacheSnapshot(version, cloud string) (*AviObjCacheSnapshot, error) {
+	data, err := os.ReadFile(lib.GetCacheSnapshotFilePath())
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &AviObjCacheSnapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != cacheSnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is not supported", snapshot.Version)
+	}
+	if snapshot.ClusterName != lib.GetClusterName() || snapshot.CloudName != cloud ||
+		snapshot.Tenant != lib.GetTenant() || snapshot.ControllerVersion != version {
+		return nil, fmt.Errorf("snapshot is written for cluster %s, cloud %s, tenant %s and controller version %s",
+			snapshot.ClusterName, snapshot.CloudName, snapshot.Tenant, snapshot.ControllerVersion)
+	}
+	return snapshot, nil
+}
+
+// AviObjCacheWarmStart populates the cache from the snapshot. The names, uuids and last modified times of the Avi
+// objects are listed from the controller, and only the objects created or modified since the snapshot was written,
+// along with the virtualservices referring to them, are fetched. The objects which are not listed are dropped. The
+// cache is flushed if the warm start fails, so that it can be populated from scratch.
+func (c *AviObjCache) AviObjCacheWarmStart(client []*clients.AviClient, cloud string, snapshot *AviObjCacheSnapshot) error {
+	err := c.aviObjCacheWarmStart(client[0], cloud, snapshot)
+	if err != nil {
+		c.flushObjCaches()
+	}
+	return err
+}
+
+func (c *AviObjCache) aviObjCacheWarmStart(client *clients.AviClient, cloud string, snapshot *AviObjCacheSnapshot) error {
+	if err := c.AviObjVrfCachePopulate(client, cloud); err != nil {
+		return err
+	}
+	snapshotObjs := make(map[string][]interface{})
+	for _, objIntf := range snapshot.objects() {
+		objType := getSnapshotObjType(objIntf)
+		snapshotObjs[objType] = append(snapshotObjs[objType], objIntf)
+	}
+	// changedRefs holds the names of the objects, per type, whose uuid or references changed since the snapshot.
+	changedRefs := make(map[string]map[string]bool)
+	for _, objType := range snapshotObjectTypes {
+		changed, err := c.reconcileSnapshotObjects(client, cloud, objType, snapshotObjs[objType])
+		if err != nil {
+			return err
+		}
+		changedRefs[objType] = changed
+	}
+	if err := c.reconcileSnapshotVSes(client, cloud, snapshot.VirtualServices, changedRefs); err != nil {
+		return err
+	}
+	// Populate the SNI VS keys to their respective parents
+	c.PopulateVsMetaCache()
+	return c.AviCloudPropertiesPopulate(client, cloud)
+}
+
+// reconcileSnapshotObjects adds the objects of the given type from the snapshot to the cache, if they are not
+// modified on the controller, and fetches the rest of the objects listed on the controller. It returns the names of
+// the objects whose uuid or references to the other objects changed.
+func (c *AviObjCache) reconcileSnapshotObjects(client *clients.AviClient, cloud, objType string, snapshotObjs []interface{}) (map[string]bool, error) {
+	liveObjs, err := listSnapshotObjectVersions(client, objType, cloud)
+	if err != nil {
+		return nil, err
+	}
+	objCache := getSnapshotObjCache(c, objType)
+	snapshotRefs := make(map[string]string)
+	for _, objIntf := range snapshotObjs {
+		name, uuid, lastModified, valid := getSnapshotObjMeta(objIntf)
+		snapshotRefs[name] = getSnapshotObjRefs(objIntf)
+		liveObj, found := liveObjs[name]
+		if !found || !valid || lastModified == "" || liveObj.UUID != uuid || liveObj.LastModified != lastModified {
+			continue
+		}
+		objCache.AviCacheAdd(NamespaceName{Namespace: lib.GetTenant(), Name: name}, objIntf)
+		delete(liveObjs, name)
+	}
+
+	changed := make(map[string]bool)
+	names := make([]string, 0, len(liveObjs))
+	for name := range liveObjs {
+		names = append(names, name)
+	}
+	utils.AviLog.Infof("Fetching %d %s objects modified since the snapshot", len(names), objType)
+	for _, batch := range batchSnapshotNames(names) {
+		fetched, err := c.fetchSnapshotObjects(client, cloud, objType, getSnapshotObjectsURI(objType, cloud)+"&name.in="+batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, objIntf := range fetched {
+			name, _, _, _ := getSnapshotObjMeta(objIntf)
+			objCache.AviCacheAdd(NamespaceName{Namespace: lib.GetTenant(), Name: name}, objIntf)
+			if refs, found := snapshotRefs[name]; !found || refs != getSnapshotObjRefs(objIntf) {
+				changed[name] = true
+			}
+		}
+	}
+	// The objects of the snapshot which are no longer present on the controller.
+	for name := range snapshotRefs {
+		if _, cached := objCache.AviCacheGet(NamespaceName{Namespace: lib.GetTenant(), Name: name}); !cached {
+			changed[name] = true
+		}
+	}
+	return changed, nil
+}
+
+// reconcileSnapshotVSes adds the virtualservices from the snapshot to the cache, if neither they nor the objects
+// they refer to are modified on the controller, and fetches the rest of the virtualservices listed on the controller.
+func (c *AviObjCache) reconcileSnapshotVSes(client *clients.AviClient, cloud string, snapshotVSes []*AviVsCache, changedRefs map[string]map[string]bool) error {
+	liveVSes, err := listSnapshotObjectVersions(client, "virtualservice", cloud)
+	if err != nil {
+		return err
+	}
+	for _, vsCacheObj := range snapshotVSes {
+		liveVS, found := liveVSes[vsCacheObj.Name]
+		if !found || vsCacheObj.InvalidData || vsCacheObj.LastModified == "" || liveVS.UUID != vsCacheObj.Uuid ||
+			liveVS.LastModified != vsCacheObj.LastModified || vsRefersChangedObjects(vsCacheObj, changedRefs) {
+			continue
+		}
+		c.VsCacheLocal.AviCacheAdd(NamespaceName{Namespace: lib.GetTenant(), Name: vsCacheObj.Name}, vsCacheObj)
+		delete(liveVSes, vsCacheObj.Name)
+	}
+
+	names := make([]string, 0, len(liveVSes))
+	for name := range liveVSes {
+		names = append(names, name)
+	}
+	utils.AviLog.Infof("Fetching %d virtualservices modified since the snapshot", len(names))
+	var vsKeys []NamespaceName
+	for _, batch := range batchSnapshotNames(names) {
+		nextPage := NextPage{NextURI: getSnapshotObjectsURI("virtualservice", cloud) + "&name.in=" + batch}
+		if err := c.AviObjVSCachePopulate(client, cloud, &vsKeys, nextPage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func vsRefersChangedObjects(vsCacheObj *AviVsCache, changedRefs map[string]map[string]bool) bool {
+	collections := map[string][]NamespaceName{
+		"vsvip":                 vsCacheObj.VSVipKeyCollection,
+		"sslkeyandcertificate":  vsCacheObj.SSLKeyCertCollection,
+		"pool":                  vsCacheObj.PoolKeyCollection,
+		"poolgroup":             vsCacheObj.PGKeyCollection,
+		"vsdatascriptset":       vsCacheObj.DSKeyCollection,
+		"httppolicyset":         vsCacheObj.HTTPKeyCollection,
+		"l4policyset":           vsCacheObj.L4PolicyCollection,
+		"networksecuritypolicy": vsCacheObj.NSPKeyCollection,
+		"healthmonitor":         vsCacheObj.HMKeyCollection,
+	}
+	for objType, objKeys := range collections {
+		for _, objKey := range objKeys {
+			if changedRefs[objType][objKey.Name] {
+				return true
+			}
+		}
+	}
+	// The health monitors of the pools are resolved by the names of the pools.
+	for _, poolKey := range vsCacheObj.PoolKeyCollection {
+		if changedRefs["healthmonitor"][poolKey.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// listSnapshotObjectVersions lists the name, uuid and last modified time of the objects of the given type, created
+// by AKO on the controller, by the name of the objects.
+func listSnapshotObjectVersions(client *clients.AviClient, objType, cloud string) (map[string]aviObjectVersion, error) {
+	liveObjs := make(map[string]aviObjectVersion)
+	uri := getSnapshotObjectsURI(objType, cloud) + "&fields=name,uuid,_last_modified"
+	if err := getAviObjectVersions(client, objType, uri, liveObjs); err != nil {
+		return nil, err
+	}
+	objsByName := make(map[string]aviObjectVersion, len(liveObjs))
+	for _, liveObj := range liveObjs {
+		objsByName[liveObj.Name] = liveObj
+	}
+	return objsByName, nil
+}
+
+// getSnapshotObjectsURI returns the URI listing the objects of the given type, with the same filters as used while
+// populating the cache from scratch.
+func getSnapshotObjectsURI(objType, cloud string) string {
+	uri := "/api/" + objType + "/?include_name=true&page_size=100"
+	switch objType {
+	case "virtualservice", "pool", "poolgroup":
+		return uri + "&cloud_ref.name=" + cloud + "&created_by=" + lib.AKOUser
+	case "vsvip":
+		return uri + "&name.contains=" + lib.GetNamePrefix() + "&cloud_ref.name=" + cloud
+	case "healthmonitor":
+		return uri + "&name.contains=" + lib.GetNamePrefix()
+	}
+	return uri + "&created_by=" + lib.AKOUser
+}
+
+func batchSnapshotNames(names []string) []string {
+	var batches []string
+	for start := 0; start < len(names); start += snapshotFetchBatchSize {
+		end := start + snapshotFetchBatchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		escaped := make([]string, 0, end-start)
+		for _, name := range names[start:end] {
+			escaped = append(escaped, url.QueryEscape(name))
+		}
+		batches = append(batches, strings.Join(escaped, ","))
+	}
+	return batches
+}
+
+// fetchSnapshotObjects fetches the objects of the given type from the given URI, in the form stored in the cache.
+func (c *AviObjCache) fetchSnapshotObjects(client *clients.AviClient, cloud, objType, uri string) ([]interface{}, error) {
+	var objs []interface{}
+	var err error
+	nextPage := NextPage{NextURI: uri}
+	switch objType {
+	case "pkiprofile":
+		var data []AviPkiProfileCache
+		if _, _, err = c.AviPopulateAllPkiPRofiles(client, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "sslkeyandcertificate":
+		var data []AviSSLCache
+		if _, _, err = c.AviPopulateAllSSLKeys(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "vsvip":
+		var data []AviVSVIPCache
+		if _, err = c.AviPopulateAllVSVips(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "pool":
+		var data []AviPoolCache
+		if _, _, err = c.AviPopulateAllPools(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "poolgroup":
+		var data []AviPGCache
+		if _, _, err = c.AviPopulateAllPGs(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "vsdatascriptset":
+		var data []AviDSCache
+		if _, _, err = c.AviPopulateAllDSs(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "httppolicyset":
+		var data []AviHTTPPolicyCache
+		if _, _, err = c.AviPopulateAllHttpPolicySets(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "l4policyset":
+		var data []AviL4PolicyCache
+		if _, _, err = c.AviPopulateAllL4PolicySets(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "networksecuritypolicy":
+		var data []AviNetworkSecurityPolicyCache
+		if _, _, err = c.AviPopulateAllNetworkSecurityPolicies(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	case "healthmonitor":
+		var data []AviHealthMonitorCache
+		if _, _, err = c.AviPopulateAllHealthMonitors(client, cloud, &data, nextPage); err == nil {
+			for i := range data {
+				objs = append(objs, &data[i])
+			}
+		}
+	}
+	return objs, err
+}
+
+// flushObjCaches removes the objects added to the cache during a failed warm start.
+func (c *AviObjCache) flushObjCaches() {
+	objCaches := []*AviCache{c.VsCacheMeta, c.VsCacheLocal, c.VrfCache}
+	for _, objType := range snapshotObjectTypes {
+		objCaches = append(objCaches, getSnapshotObjCache(c, objType))
+	}
+	for _, objCache := range objCaches {
+		objCache.cache_lock.Lock()
+		objCache.cache = make(map[interface{}]interface{})
+		objCache.cache_lock.Unlock()
+	}
+}
+
+func (s *AviObjCacheSnapshot) add(objIntf interface{}) {
+	switch obj := objIntf.(type) {
+	case *AviPkiProfileCache:
+		s.PKIProfiles = append(s.PKIProfiles, obj)
+	case *AviSSLCache:
+		s.SSLKeys = append(s.SSLKeys, obj)
+	case *AviVSVIPCache:
+		s.VSVips = append(s.VSVips, obj)
+	case *AviPoolCache:
+		s.Pools = append(s.Pools, obj)
+	case *AviPGCache:
+		s.PoolGroups = append(s.PoolGroups, obj)
+	case *AviDSCache:
+		s.DataScripts = append(s.DataScripts, obj)
+	case *AviHTTPPolicyCache:
+		s.HTTPPolicySets = append(s.HTTPPolicySets, obj)
+	case *AviL4PolicyCache:
+		s.L4PolicySets = append(s.L4PolicySets, obj)
+	case *AviNetworkSecurityPolicyCache:
+		s.NetworkSecurityPolicies = append(s.NetworkSecurityPolicies, obj)
+	case *AviHealthMonitorCache:
+		s.HealthMonitors = append(s.HealthMonitors, obj)
+	}
+}
+
+// objects returns the objects of the snapshot other than the virtualservices, with the references to the objects
+// marked during the last cache population cleared.
+func (s *AviObjCacheSnapshot) objects() []interface{} {
+	var objs []interface{}
+	for _, obj := range s.PKIProfiles {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.SSLKeys {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.VSVips {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.Pools {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.PoolGroups {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.DataScripts {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.HTTPPolicySets {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.L4PolicySets {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.NetworkSecurityPolicies {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	for _, obj := range s.HealthMonitors {
+		obj.HasReference = false
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+func getSnapshotObjCache(c *AviObjCache, objType string) *AviCache {
+	switch objType {
+	case "pkiprofile":
+		return c.PKIProfileCache
+	case "sslkeyandcertificate":
+		return c.SSLKeyCache
+	case "vsvip":
+		return c.VSVIPCache
+	case "vsdatascriptset":
+		return c.DSCache
+	case "l4policyset":
+		return c.L4PolicyCache
+	case "networksecuritypolicy":
+		return c.NSPCache
+	case "healthmonitor":
+		return c.HMCache
+	}
+	return getObjCacheForType(c, objType)
+}
+
+func getSnapshotObjType(objIntf interface{}) string {
+	switch objIntf.(type) {
+	case *AviPkiProfileCache:
+		return "pkiprofile"
+	case *AviSSLCache:
+		return "sslkeyandcertificate"
+	case *AviVSVIPCache:
+		return "vsvip"
+	case *AviPoolCache:
+		return "pool"
+	case *AviPGCache:
+		return "poolgroup"
+	case *AviDSCache:
+		return "vsdatascriptset"
+	case *AviHTTPPolicyCache:
+		return "httppolicyset"
+	case *AviL4PolicyCache:
+		return "l4policyset"
+	case *AviNetworkSecurityPolicyCache:
+		return "networksecuritypolicy"
+	case *AviHealthMonitorCache:
+		return "healthmonitor"
+	}
+	return ""
+}
+
+// getSnapshotObjMeta returns the name, uuid and last modified time of a cached object, and false if the cached
+// object is marked invalid.
+func getSnapshotObjMeta(objIntf interface{}) (string, string, string, bool) {
+	switch obj := objIntf.(type) {
+	case *AviPkiProfileCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviSSLCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviVSVIPCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviPoolCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviPGCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviDSCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviHTTPPolicyCache:
+		return obj.Name, obj.Uuid, obj.LastModified, !obj.InvalidData
+	case *AviL4PolicyCache:
+		return obj.Name, obj.Uuid, obj.LastModified, true
+	case *AviNetworkSecurityPolicyCache:
+		return obj.Name, obj.Uuid, obj.LastModified, true
+	case *AviHealthMonitorCache:
+		return obj.Name, obj.Uuid, obj.LastModified, true
+	}
+	return "", "", "", false
+}
+
+// getSnapshotObjRefs returns the uuid of a cached object along with its references to the other objects, which are
+// resolved into the collections of the virtualservices.
+func getSnapshotObjRefs(objIntf interface{}) string {
+	switch obj := objIntf.(type) {
+	case *AviSSLCache:
+		return obj.Uuid + ";" + obj.CACertUUID
+	case *AviPGCache:
+		return obj.Uuid + ";" + strings.Join(obj.Members, ",")
+	case *AviDSCache:
+		return obj.Uuid + ";" + strings.Join(obj.PoolGroups, ",")
+	case *AviHTTPPolicyCache:
+		return obj.Uuid + ";" + strings.Join(obj.PoolGroups, ",")
+	case *AviL4PolicyCache:
+		return obj.Uuid + ";" + strings.Join(obj.Pools, ",") + ";" + strings.Join(obj.PoolGroups, ",")
+	}
+	_, uuid, _, _ := getSnapshotObjMeta(objIntf)
+	return uuid
+}
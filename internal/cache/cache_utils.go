@@ -78,6 +78,8 @@ type AviVsCache struct {
 	HTTPKeyCollection    []NamespaceName
 	SSLKeyCertCollection []NamespaceName
 	L4PolicyCollection   []NamespaceName
+	NSPKeyCollection     []NamespaceName
+	HMKeyCollection      []NamespaceName
 	SNIChildCollection   []string
 	ParentVSRef          NamespaceName
 	PassthroughParentRef NamespaceName
@@ -247,6 +249,38 @@ func (v *AviVsCache) RemoveFromL4PolicyCollection(k NamespaceName) {
 	v.L4PolicyCollection = RemoveNamespaceName(v.L4PolicyCollection, k)
 }
 
+func (v *AviVsCache) AddToNSPKeyCollection(k NamespaceName) {
+	if v.NSPKeyCollection == nil {
+		v.NSPKeyCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.NSPKeyCollection, k) {
+		v.NSPKeyCollection = append(v.NSPKeyCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromNSPKeyCollection(k NamespaceName) {
+	if v.NSPKeyCollection == nil {
+		return
+	}
+	v.NSPKeyCollection = RemoveNamespaceName(v.NSPKeyCollection, k)
+}
+
+func (v *AviVsCache) AddToHMKeyCollection(k NamespaceName) {
+	if v.HMKeyCollection == nil {
+		v.HMKeyCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.HMKeyCollection, k) {
+		v.HMKeyCollection = append(v.HMKeyCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromHMKeyCollection(k NamespaceName) {
+	if v.HMKeyCollection == nil {
+		return
+	}
+	v.HMKeyCollection = RemoveNamespaceName(v.HMKeyCollection, k)
+}
+
 func (v *AviVsCache) AddToSNIChildCollection(k string) {
 	if v.SNIChildCollection == nil {
 		v.SNIChildCollection = []string{k}
@@ -339,6 +373,25 @@ type AviL4PolicyCache struct {
 	Uuid             string
 	CloudConfigCksum uint32
 	Pools            []string
+	PoolGroups       []string
+	LastModified     string
+	HasReference     bool
+}
+
+type AviNetworkSecurityPolicyCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+	HasReference     bool
+}
+
+type AviHealthMonitorCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
 	LastModified     string
 	HasReference     bool
 }
@@ -482,6 +535,18 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			} else if value.(*AviL4PolicyCache).Uuid == uuid {
 				return value.(*AviL4PolicyCache).Name, true
 			}
+		case *AviNetworkSecurityPolicyCache:
+			if value.(*AviNetworkSecurityPolicyCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for network security policy key %v", reflect.ValueOf(key))
+			} else if value.(*AviNetworkSecurityPolicyCache).Uuid == uuid {
+				return value.(*AviNetworkSecurityPolicyCache).Name, true
+			}
+		case *AviHealthMonitorCache:
+			if value.(*AviHealthMonitorCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for health monitor key %v", reflect.ValueOf(key))
+			} else if value.(*AviHealthMonitorCache).Uuid == uuid {
+				return value.(*AviHealthMonitorCache).Name, true
+			}
 		case *AviHTTPPolicyCache:
 			if value.(*AviHTTPPolicyCache) == nil {
 				utils.AviLog.Warnf("Got nil value in cache for http policy key %v", reflect.ValueOf(key))
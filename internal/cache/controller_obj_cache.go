@@ -47,6 +47,8 @@ type AviObjCache struct {
 	CloudKeyCache      *AviCache
 	HTTPPolicyCache    *AviCache
 	L4PolicyCache      *AviCache
+	NSPCache           *AviCache
+	HMCache            *AviCache
 	SSLKeyCache        *AviCache
 	PKIProfileCache    *AviCache
 	VSVIPCache         *AviCache
@@ -67,6 +69,8 @@ func NewAviObjCache() *AviObjCache {
 	c.CloudKeyCache = NewAviCache()
 	c.HTTPPolicyCache = NewAviCache()
 	c.L4PolicyCache = NewAviCache()
+	c.NSPCache = NewAviCache()
+	c.HMCache = NewAviCache()
 	c.VSVIPCache = NewAviCache()
 	c.VrfCache = NewAviCache()
 	c.PKIProfileCache = NewAviCache()
@@ -112,6 +116,8 @@ func (c *AviObjCache) AviRefreshObjectCache(client []*clients.AviClient, cloud s
 	go func() {
 		defer wg.Done()
 		c.PopulateL4PolicySetToCache(client[6], cloud)
+		c.PopulateNetworkSecurityPolicyToCache(client[6], cloud)
+		c.PopulateHealthMonitorToCache(client[6], cloud)
 	}()
 
 	wg.Wait()
@@ -264,6 +270,22 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 		}
 	}
 
+	for _, objKey := range vsCacheObj.NSPKeyCollection {
+		if intf, found := c.NSPCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviNetworkSecurityPolicyCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
+	for _, objKey := range vsCacheObj.HMKeyCollection {
+		if intf, found := c.HMCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviHealthMonitorCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
 	for _, objKey := range vsCacheObj.PGKeyCollection {
 		if intf, found := c.PgCache.AviCacheGet(objKey); found {
 			if obj, ok := intf.(*AviPGCache); ok {
@@ -302,7 +324,7 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 
 	var dsKeys, vsVipKeys, httpKeys, sslKeys []NamespaceName
-	var pgKeys, poolKeys, l4Keys []NamespaceName
+	var pgKeys, poolKeys, l4Keys, nspKeys, hmKeys []NamespaceName
 	for _, objkey := range c.DSCache.AviGetAllKeys() {
 		intf, _ := c.DSCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviDSCache); ok {
@@ -333,6 +355,26 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		}
 	}
 
+	for _, objkey := range c.NSPCache.AviGetAllKeys() {
+		intf, _ := c.NSPCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviNetworkSecurityPolicyCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for network security policy: %s", objkey)
+				nspKeys = append(nspKeys, objkey)
+			}
+		}
+	}
+
+	for _, objkey := range c.HMCache.AviGetAllKeys() {
+		intf, _ := c.HMCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviHealthMonitorCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for health monitor: %s", objkey)
+				hmKeys = append(hmKeys, objkey)
+			}
+		}
+	}
+
 	for _, objkey := range c.PgCache.AviGetAllKeys() {
 		intf, _ := c.PgCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviPGCache); ok {
@@ -393,6 +435,8 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		PGKeyCollection:      pgKeys,
 		PoolKeyCollection:    poolKeys,
 		L4PolicyCollection:   l4Keys,
+		NSPKeyCollection:     nspKeys,
+		HMKeyCollection:      hmKeys,
 		SNIChildCollection:   childCollection,
 	}
 	vsKey := NamespaceName{
@@ -879,6 +923,9 @@ func (c *AviObjCache) AviPopulateAllDSs(client *clients.AviClient, cloud string,
 			checksum += utils.Hash(*ds.Datascript[0].Script)
 		}
 		dsCacheObj.CloudConfigCksum = checksum
+		if ds.LastModified != nil {
+			dsCacheObj.LastModified = *ds.LastModified
+		}
 		*DsData = append(*DsData, dsCacheObj)
 	}
 	if result.Next != "" {
@@ -981,6 +1028,9 @@ func (c *AviObjCache) AviPopulateAllSSLKeys(client *clients.AviClient, cloud str
 			CACertUUID:       cacertUUID,
 			CloudConfigCksum: lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true),
 		}
+		if sslkey.LastModified != nil {
+			sslCacheObj.LastModified = *sslkey.LastModified
+		}
 		*SslData = append(*SslData, sslCacheObj)
 	}
 	if result.Next != "" {
@@ -1052,6 +1102,9 @@ func (c *AviObjCache) AviPopulateOneSSLCache(client *clients.AviClient,
 			CloudConfigCksum: lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true),
 			HasCARef:         hasCA,
 		}
+		if sslkey.LastModified != nil {
+			sslCacheObj.LastModified = *sslkey.LastModified
+		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *sslkey.Name}
 		c.SSLKeyCache.AviCacheAdd(k, &sslCacheObj)
 		utils.AviLog.Debugf("Adding sslkey to Cache during refresh %s", k)
@@ -1224,6 +1277,9 @@ func (c *AviObjCache) AviPopulateOneVsDSCache(client *clients.AviClient,
 			checksum += utils.Hash(*ds.Datascript[0].Script)
 		}
 		dsCacheObj.CloudConfigCksum = checksum
+		if ds.LastModified != nil {
+			dsCacheObj.LastModified = *ds.LastModified
+		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *ds.Name}
 		c.DSCache.AviCacheAdd(k, &dsCacheObj)
 		utils.AviLog.Debugf("Adding ds to Cache during refresh %s", k)
@@ -1481,16 +1537,26 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 		}
 		// Fetch the pools associated with the l4 policyset object
 		var pools []string
+		var pgs []string
 		var ports []int64
 		var protocols []string
 		if l4pol.L4ConnectionPolicy != nil {
 			for _, rule := range l4pol.L4ConnectionPolicy.Rules {
 				protocols = append(protocols, *rule.Match.Protocol.Protocol)
 				if rule.Action != nil {
-					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
-					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
-					if found {
-						pools = append(pools, poolName.(string))
+					if rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolRef != nil {
+						poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
+						poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
+						if found {
+							pools = append(pools, poolName.(string))
+						}
+					}
+					if rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolGroupRef != nil {
+						pgUuid := ExtractUuid(*rule.Action.SelectPool.PoolGroupRef, "poolgroup-.*.#")
+						pgName, found := c.PgCache.AviCacheGetNameByUuid(pgUuid)
+						if found {
+							pgs = append(pgs, pgName.(string))
+						}
 					}
 				}
 				if rule.Match != nil {
@@ -1504,6 +1570,7 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 			Name:             *l4pol.Name,
 			Uuid:             *l4pol.UUID,
 			Pools:            pools,
+			PoolGroups:       pgs,
 			LastModified:     *l4pol.LastModified,
 			CloudConfigCksum: cksum,
 		}
@@ -1514,6 +1581,84 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 	return nil
 }
 
+func (c *AviObjCache) AviPopulateOneVsNSPCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	var uri string
+	akoUser := lib.AKOUser
+
+	uri = "/api/networksecuritypolicy?name=" + objName + "&created_by=" + akoUser
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete networksecuritypolicy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		//Only cache the network security policies that belong to this AKO.
+		if !strings.HasPrefix(*nsp.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		nspCacheObj := buildNetworkSecurityPolicyCacheObj(nsp)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *nsp.Name}
+		c.NSPCache.AviCacheAdd(k, &nspCacheObj)
+		utils.AviLog.Infof("Adding networksecuritypolicy to Cache during refresh %s", utils.Stringify(nspCacheObj))
+	}
+	return nil
+}
+
+func (c *AviObjCache) AviPopulateOneVsHMCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	uri := "/api/healthmonitor?name=" + objName
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for healthmonitor %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		hm := models.HealthMonitor{}
+		err = json.Unmarshal(elems[i], &hm)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+			continue
+		}
+		if hm.Name == nil || hm.UUID == nil {
+			utils.AviLog.Warnf("Incomplete healthmonitor data unmarshalled, %s", utils.Stringify(hm))
+			continue
+		}
+		if !isHealthMonitorOwned(hm) {
+			continue
+		}
+		hmCacheObj := buildHealthMonitorCacheObj(hm)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *hm.Name}
+		c.HMCache.AviCacheAdd(k, &hmCacheObj)
+		utils.AviLog.Infof("Adding healthmonitor to Cache during refresh %s", utils.Stringify(hmCacheObj))
+	}
+	return nil
+}
+
 func (c *AviObjCache) PopulateSSLKeyToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
 	var SslKeyData []AviSSLCache
 	c.AviPopulateAllSSLKeys(client, cloud, &SslKeyData)
@@ -1693,6 +1838,7 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 		// Fetch the pgs associated with the http policyset object
 		// Fetch the pools associated with the l4 policyset object
 		var pools []string
+		var pgs []string
 		var ports []int64
 		var protocols []string
 		if l4pol.L4ConnectionPolicy != nil {
@@ -1705,10 +1851,19 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 						protocol = utils.UDP
 					}
 					protocols = append(protocols, protocol)
-					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
-					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
-					if found {
-						pools = append(pools, poolName.(string))
+					if rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolRef != nil {
+						poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
+						poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
+						if found {
+							pools = append(pools, poolName.(string))
+						}
+					}
+					if rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolGroupRef != nil {
+						pgUuid := ExtractUuid(*rule.Action.SelectPool.PoolGroupRef, "poolgroup-.*.#")
+						pgName, found := c.PgCache.AviCacheGetNameByUuid(pgUuid)
+						if found {
+							pgs = append(pgs, pgName.(string))
+						}
 					}
 				}
 				if rule.Match != nil {
@@ -1723,6 +1878,7 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 			Name:             *l4pol.Name,
 			Uuid:             *l4pol.UUID,
 			Pools:            pools,
+			PoolGroups:       pgs,
 			LastModified:     *l4pol.LastModified,
 			CloudConfigCksum: cksum,
 		}
@@ -1765,6 +1921,216 @@ func (c *AviObjCache) PopulateL4PolicySetToCache(client *clients.AviClient, clou
 	}
 }
 
+// NetworkSecurityPolicySourceRanges rebuilds the source ranges from the client ip
+// prefixes of the rules, so that the checksum matches the one of the model node.
+func NetworkSecurityPolicySourceRanges(nsp models.NetworkSecurityPolicy) []string {
+	var sourceRanges []string
+	for _, rule := range nsp.Rules {
+		if rule.Match == nil || rule.Match.ClientIP == nil {
+			continue
+		}
+		for _, prefix := range rule.Match.ClientIP.Prefixes {
+			if prefix.IPAddr == nil || prefix.IPAddr.Addr == nil || prefix.Mask == nil {
+				continue
+			}
+			sourceRanges = append(sourceRanges, fmt.Sprintf("%s/%d", *prefix.IPAddr.Addr, *prefix.Mask))
+		}
+	}
+	return sourceRanges
+}
+
+func buildNetworkSecurityPolicyCacheObj(nsp models.NetworkSecurityPolicy) AviNetworkSecurityPolicyCache {
+	sourceRanges := NetworkSecurityPolicySourceRanges(nsp)
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	cksum := lib.NetworkSecurityPolicyChecksum(sourceRanges, emptyIngestionMarkers, nsp.Markers, true)
+	nspCacheObj := AviNetworkSecurityPolicyCache{
+		Name:             *nsp.Name,
+		Tenant:           lib.GetTenant(),
+		Uuid:             *nsp.UUID,
+		CloudConfigCksum: cksum,
+	}
+	if nsp.LastModified != nil {
+		nspCacheObj.LastModified = *nsp.LastModified
+	}
+	return nspCacheObj
+}
+
+func (c *AviObjCache) AviPopulateAllNetworkSecurityPolicies(client *clients.AviClient, cloud string, nspData *[]AviNetworkSecurityPolicyCache, nextPage ...NextPage) (*[]AviNetworkSecurityPolicyCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/networksecuritypolicy/?" + "&include_name=true" + "&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete networksecuritypolicy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		*nspData = append(*nspData, buildNetworkSecurityPolicyCacheObj(nsp))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/networksecuritypolicy")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/networksecuritypolicy" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllNetworkSecurityPolicies(client, cloud, nspData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return nspData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateNetworkSecurityPolicyToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
+	var nspData []AviNetworkSecurityPolicyCache
+	_, count, err := c.AviPopulateAllNetworkSecurityPolicies(client, cloud, &nspData)
+	if err != nil || len(nspData) != count {
+		return
+	}
+	nspCacheData := c.NSPCache.ShallowCopy()
+	for i, nspCacheObj := range nspData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: nspCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to networksecuritypolicy cache :%s", utils.Stringify(nspCacheObj))
+		c.NSPCache.AviCacheAdd(k, &nspData[i])
+		delete(nspCacheData, k)
+	}
+	// The data that is left in nspCacheData should be explicitly removed
+	for key := range nspCacheData {
+		utils.AviLog.Debugf("Deleting key from networksecuritypolicy cache :%s", key)
+		c.NSPCache.AviCacheDelete(key)
+	}
+}
+
+// isHealthMonitorOwned returns true for the health monitors created by this AKO. The health monitors
+// do not carry a created_by field, so they are matched with the name prefix and the cluster name marker.
+func isHealthMonitorOwned(hm models.HealthMonitor) bool {
+	if !strings.HasPrefix(*hm.Name, lib.GetNamePrefix()) {
+		return false
+	}
+	for _, marker := range hm.Markers {
+		if marker.Key != nil && *marker.Key == lib.ClusterNameLabelKey &&
+			utils.HasElem(marker.Values, lib.GetClusterName()) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildHealthMonitorCacheObj(hm models.HealthMonitor) AviHealthMonitorCache {
+	var monitorPort int32
+	if hm.MonitorPort != nil {
+		monitorPort = *hm.MonitorPort
+	}
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	cksum := lib.HealthMonitorChecksum(monitorPort, emptyIngestionMarkers, hm.Markers, true)
+	hmCacheObj := AviHealthMonitorCache{
+		Name:             *hm.Name,
+		Tenant:           lib.GetTenant(),
+		Uuid:             *hm.UUID,
+		CloudConfigCksum: cksum,
+	}
+	if hm.LastModified != nil {
+		hmCacheObj.LastModified = *hm.LastModified
+	}
+	return hmCacheObj
+}
+
+func (c *AviObjCache) AviPopulateAllHealthMonitors(client *clients.AviClient, cloud string, hmData *[]AviHealthMonitorCache, nextPage ...NextPage) (*[]AviHealthMonitorCache, int, error) {
+	var uri string
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/healthmonitor/?" + "name.contains=" + lib.GetNamePrefix() + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for healthmonitor %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		hm := models.HealthMonitor{}
+		err = json.Unmarshal(elems[i], &hm)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+			continue
+		}
+		if hm.Name == nil || hm.UUID == nil {
+			utils.AviLog.Warnf("Incomplete healthmonitor data unmarshalled, %s", utils.Stringify(hm))
+			continue
+		}
+		if !isHealthMonitorOwned(hm) {
+			continue
+		}
+		*hmData = append(*hmData, buildHealthMonitorCacheObj(hm))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/healthmonitor")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/healthmonitor" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllHealthMonitors(client, cloud, hmData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return hmData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateHealthMonitorToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
+	var hmData []AviHealthMonitorCache
+	_, count, err := c.AviPopulateAllHealthMonitors(client, cloud, &hmData)
+	if err != nil || len(hmData) != count {
+		return
+	}
+	hmCacheData := c.HMCache.ShallowCopy()
+	for i, hmCacheObj := range hmData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: hmCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to healthmonitor cache :%s", utils.Stringify(hmCacheObj))
+		c.HMCache.AviCacheAdd(k, &hmData[i])
+		delete(hmCacheData, k)
+	}
+	// The data that is left in hmCacheData should be explicitly removed
+	for key := range hmCacheData {
+		utils.AviLog.Debugf("Deleting key from healthmonitor cache :%s", key)
+		c.HMCache.AviCacheDelete(key)
+	}
+}
+
 func (c *AviObjCache) AviObjVrfCachePopulate(client *clients.AviClient, cloud string) error {
 	if lib.GetDisableStaticRoute() {
 		utils.AviLog.Debugf("Static route sync disabled, skipping vrf cache population")
@@ -1887,6 +2253,7 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 				var dsKeys []NamespaceName
 				var httpKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var nspKeys []NamespaceName
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var sharedVsOrL4 bool
@@ -1988,11 +2355,24 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 									poolKey := NamespaceName{Namespace: lib.GetTenant(), Name: poolName}
 									poolKeys = append(poolKeys, poolKey)
 								}
+								for _, pgName := range l4Obj.(*AviL4PolicyCache).PoolGroups {
+									pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+									poolgroupKeys = append(poolgroupKeys, pgKey)
+									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName)
+									poolKeys = append(poolKeys, pgpoolKeys...)
+								}
 								l4Keys = append(l4Keys, l4key)
 							}
 						}
 					}
 				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					nspName, foundNsp := c.NSPCache.AviCacheGetNameByUuid(nspUuid)
+					if foundNsp {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
 				if vs["http_policies"] != nil {
 					for _, http_intf := range vs["http_policies"].([]interface{}) {
 						httpmap, ok := http_intf.(map[string]interface{})
@@ -2038,6 +2418,13 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 					}
 				}
 
+				// The health monitors generated for the healthCheckNodePort are named after their pools.
+				var hmKeys []NamespaceName
+				for _, poolKey := range poolKeys {
+					if _, foundHM := c.HMCache.AviCacheGet(poolKey); foundHM {
+						hmKeys = append(hmKeys, poolKey)
+					}
+				}
 				// Populate the vscache meta object here.
 				vsMetaObj := AviVsCache{
 					Name:                 vs["name"].(string),
@@ -2053,6 +2440,8 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 					ParentVSRef:          parentVSKey,
 					ServiceMetadataObj:   svc_mdata_obj,
 					L4PolicyCollection:   l4Keys,
+					NSPKeyCollection:     nspKeys,
+					HMKeyCollection:      hmKeys,
 					LastModified:         vs["_last_modified"].(string),
 				}
 				if val, ok := vs["enable_rhi"]; ok {
@@ -2151,6 +2540,7 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var nspKeys []NamespaceName
 
 				// Populate the VSVIP cache
 				if vs["vsvip_ref"] != nil {
@@ -2241,11 +2631,24 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 									poolKey := NamespaceName{Namespace: lib.GetTenant(), Name: poolName}
 									poolKeys = append(poolKeys, poolKey)
 								}
+								for _, pgName := range l4Obj.(*AviL4PolicyCache).PoolGroups {
+									pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+									poolgroupKeys = append(poolgroupKeys, pgKey)
+									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName)
+									poolKeys = append(poolKeys, pgpoolKeys...)
+								}
 								l4Keys = append(l4Keys, l4key)
 							}
 						}
 					}
 				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					nspName, foundNsp := c.NSPCache.AviCacheGetNameByUuid(nspUuid)
+					if foundNsp {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
 				if vs["http_policies"] != nil {
 					for _, http_intf := range vs["http_policies"].([]interface{}) {
 						// find the sslkey name from the ssl key cache
@@ -2269,6 +2672,13 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 						}
 					}
 				}
+				// The health monitors generated for the healthCheckNodePort are named after their pools.
+				var hmKeys []NamespaceName
+				for _, poolKey := range poolKeys {
+					if _, foundHM := c.HMCache.AviCacheGet(poolKey); foundHM {
+						hmKeys = append(hmKeys, poolKey)
+					}
+				}
 				// Populate the vscache meta object here.
 				vsMetaObj := AviVsCache{
 					Name:                 vs["name"].(string),
@@ -2283,6 +2693,8 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 					SNIChildCollection:   sni_child_collection,
 					ParentVSRef:          parentVSKey,
 					L4PolicyCollection:   l4Keys,
+					NSPKeyCollection:     nspKeys,
+					HMKeyCollection:      hmKeys,
 					ServiceMetadataObj:   svc_mdata_obj,
 				}
 				if val, ok := vs["enable_rhi"]; ok {
@@ -2945,6 +3357,10 @@ func ConfigureSeGroupLabels(client *clients.AviClient, seGroup *models.ServiceEn
 	SetAdminTenant := session.SetTenant(lib.GetAdminTenant())
 	SetTenant := session.SetTenant(lib.GetTenant())
 	if len(labels) == 0 {
+		if lib.IsDryRunEnabled() {
+			utils.AviLog.Infof("dry-run mode, skipping setting labels: %v on Service Engine Group :%v", utils.Stringify(lib.GetLabels()), segName)
+			return nil
+		}
 		uri := "/api/serviceenginegroup/" + *seGroup.UUID
 		seGroup.Labels = lib.GetLabels()
 		response := models.ServiceEngineGroupAPIResponse{}
@@ -2983,7 +3399,7 @@ func DeConfigureSeGroupLabels() {
 		return
 	}
 
-	if len(lib.GetLabels()) == 0 {
+	if len(lib.GetLabels()) == 0 || lib.IsDryRunEnabled() {
 		return
 	}
 	segName := lib.GetSEGName()
@@ -0,0 +1,323 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+// driftObjectTypes are the Avi object types checked for the out of band modifications.
+var driftObjectTypes = []string{"virtualservice", "pool", "poolgroup", "httppolicyset"}
+
+// aviObjectVersion holds the fields of an Avi object which change on any modification of the object.
+type aviObjectVersion struct {
+	Name             string `json:"name"`
+	UUID             string `json:"uuid"`
+	CloudConfigCksum string `json:"cloud_config_cksum"`
+	LastModified     string `json:"_last_modified"`
+}
+
+// driftCacheObj holds the fields of a cached Avi object compared against the live object.
+type driftCacheObj struct {
+	uuid             string
+	cloudConfigCksum string
+	lastModified     string
+	invalidData      bool
+	svcMetadata      lib.ServiceMetadataObj
+}
+
+type DriftDetector struct {
+	lock sync.Mutex
+	// drifted holds the last modification time of the drifted objects already reported, per object.
+	drifted map[string]string
+}
+
+var driftDetectorInstance *DriftDetector
+var driftDetectorOnce sync.Once
+
+func SharedDriftDetector() *DriftDetector {
+	driftDetectorOnce.Do(func() {
+		driftDetectorInstance = &DriftDetector{drifted: make(map[string]string)}
+	})
+	return driftDetectorInstance
+}
+
+// DetectDrift compares the virtualservices, pools, poolgroups and httppolicysets created by AKO on the Avi
+// controller against the cache, and reports the objects modified or deleted out of band. For the objects
+// with the revert policy, the cached checksum is cleared, and the keys of the virtualservices whose models
+// have to be published again to restore the objects are returned.
+func (d *DriftDetector) DetectDrift(client *clients.AviClient) []NamespaceName {
+	if lib.GetDriftDetectionPolicy() == "" || !lib.AKOControlConfig().IsLeader() {
+		return nil
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	c := SharedAviObjCache()
+	vsCacheCopy := c.VsCacheMeta.ShallowCopy()
+	ownerVS := make(map[string]NamespaceName)
+	for vsKey, vsIntf := range vsCacheCopy {
+		vsCacheObj, ok := vsIntf.(*AviVsCache)
+		if !ok {
+			continue
+		}
+		for _, poolKey := range vsCacheObj.PoolKeyCollection {
+			ownerVS["pool/"+poolKey.Namespace+"/"+poolKey.Name] = vsKey.(NamespaceName)
+		}
+		for _, pgKey := range vsCacheObj.PGKeyCollection {
+			ownerVS["poolgroup/"+pgKey.Namespace+"/"+pgKey.Name] = vsKey.(NamespaceName)
+		}
+		for _, httpKey := range vsCacheObj.HTTPKeyCollection {
+			ownerVS["httppolicyset/"+httpKey.Namespace+"/"+httpKey.Name] = vsKey.(NamespaceName)
+		}
+	}
+
+	var revertVSKeys []NamespaceName
+	stillDrifted := make(map[string]bool)
+	for _, objType := range driftObjectTypes {
+		liveObjs := make(map[string]aviObjectVersion)
+		uri := "/api/" + objType + "/?include_name=true&created_by=" + lib.AKOUser + "&fields=name,uuid,cloud_config_cksum,_last_modified&page_size=100"
+		if err := getAviObjectVersions(client, objType, uri, liveObjs); err != nil {
+			utils.AviLog.Warnf("Error in getting the %s objects for drift detection: %v", objType, err)
+			// Keep the drifted objects of this type as they are, until the next successful run.
+			for driftKey := range d.drifted {
+				if strings.HasPrefix(driftKey, objType+"/") {
+					stillDrifted[driftKey] = true
+				}
+			}
+			continue
+		}
+
+		for key, objIntf := range getObjCacheForType(c, objType).ShallowCopy() {
+			objKey := key.(NamespaceName)
+			cacheObj, ok := getDriftCacheObj(objIntf)
+			if !ok || cacheObj.uuid == "" || cacheObj.invalidData {
+				continue
+			}
+			liveObj, found := liveObjs[cacheObj.uuid]
+			if found && liveObj.CloudConfigCksum == cacheObj.cloudConfigCksum &&
+				(cacheObj.lastModified == "" || liveObj.LastModified == cacheObj.lastModified) {
+				continue
+			}
+
+			vsKey := objKey
+			if objType != "virtualservice" {
+				vsKey, ok = ownerVS[objType+"/"+objKey.Namespace+"/"+objKey.Name]
+				if !ok {
+					utils.AviLog.Debugf("No virtualservice found in the cache for drifted %s %s", objType, objKey)
+					continue
+				}
+			}
+			var vsCacheObj *AviVsCache
+			if vsIntf, found := vsCacheCopy[vsKey]; found {
+				vsCacheObj, _ = vsIntf.(*AviVsCache)
+			}
+			if vsCacheObj == nil {
+				continue
+			}
+			svcMetadata := cacheObj.svcMetadata
+			if objType != "virtualservice" && isServiceMetadataEmpty(svcMetadata) {
+				svcMetadata = vsCacheObj.ServiceMetadataObj
+			}
+			policy, k8sObj := getDriftPolicy(svcMetadata)
+			if policy == "" {
+				continue
+			}
+
+			driftKey := objType + "/" + objKey.Namespace + "/" + objKey.Name
+			stillDrifted[driftKey] = true
+			driftState := "modified"
+			if !found {
+				driftState = "deleted"
+			}
+			if lastModified, reported := d.drifted[driftKey]; !reported || lastModified != liveObj.LastModified {
+				message := fmt.Sprintf("Avi %s %s is %s out of band", objType, objKey.Name, driftState)
+				utils.AviLog.Warnf("%s, drift policy: %s", message, policy)
+				lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.AviObjectDrifted, message)
+				if k8sObj != nil {
+					lib.AKOControlConfig().EventRecorder().Event(k8sObj, corev1.EventTypeWarning, lib.AviObjectDrifted, message)
+				}
+				d.drifted[driftKey] = liveObj.LastModified
+			}
+			if policy != lib.DriftPolicyRevert {
+				continue
+			}
+
+			// Clearing the cached checksum makes the rest layer update the object with the configuration of
+			// AKO. The deleted objects are created again, once the update fails with 404.
+			clearCloudConfigCksum(objIntf)
+			if vsCacheObj.ParentVSRef != (NamespaceName{}) {
+				vsKey = vsCacheObj.ParentVSRef
+			}
+			revertVSKeys = append(revertVSKeys, vsKey)
+			utils.AviLog.Infof("Reverting the out of band changes of Avi %s %s", objType, objKey.Name)
+			lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AviObjectDriftReverted, fmt.Sprintf("Reverting the out of band changes of Avi %s %s", objType, objKey.Name))
+		}
+	}
+
+	driftCount := make(map[string]int)
+	for driftKey := range d.drifted {
+		if !stillDrifted[driftKey] {
+			delete(d.drifted, driftKey)
+			continue
+		}
+		driftCount[strings.SplitN(driftKey, "/", 2)[0]]++
+	}
+	// The objects are counted per type, the drifted objects are reported individually through the events.
+	for _, objType := range driftObjectTypes {
+		lib.SetDriftedObjectsGauge(objType, driftCount[objType])
+	}
+	return revertVSKeys
+}
+
+func getAviObjectVersions(client *clients.AviClient, objType, uri string, liveObjs map[string]aviObjectVersion) error {
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		return err
+	}
+	elems := make([]aviObjectVersion, result.Count)
+	if err = json.Unmarshal(result.Results, &elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if elem.UUID != "" {
+			liveObjs[elem.UUID] = elem
+		}
+	}
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		nextURI := strings.Split(result.Next, "/api/"+objType)
+		if len(nextURI) > 1 {
+			return getAviObjectVersions(client, objType, "/api/"+objType+nextURI[1], liveObjs)
+		}
+	}
+	return nil
+}
+
+func getObjCacheForType(c *AviObjCache, objType string) *AviCache {
+	switch objType {
+	case "virtualservice":
+		return c.VsCacheMeta
+	case "pool":
+		return c.PoolCache
+	case "poolgroup":
+		return c.PgCache
+	}
+	return c.HTTPPolicyCache
+}
+
+func getDriftCacheObj(objIntf interface{}) (driftCacheObj, bool) {
+	switch obj := objIntf.(type) {
+	case *AviVsCache:
+		if obj == nil {
+			return driftCacheObj{}, false
+		}
+		obj.VSCacheLock.RLock()
+		defer obj.VSCacheLock.RUnlock()
+		return driftCacheObj{obj.Uuid, obj.CloudConfigCksum, obj.LastModified, obj.InvalidData, obj.ServiceMetadataObj}, true
+	case *AviPoolCache:
+		if obj == nil {
+			return driftCacheObj{}, false
+		}
+		return driftCacheObj{obj.Uuid, obj.CloudConfigCksum, obj.LastModified, obj.InvalidData, obj.ServiceMetadataObj}, true
+	case *AviPGCache:
+		if obj == nil {
+			return driftCacheObj{}, false
+		}
+		return driftCacheObj{uuid: obj.Uuid, cloudConfigCksum: obj.CloudConfigCksum, lastModified: obj.LastModified, invalidData: obj.InvalidData}, true
+	case *AviHTTPPolicyCache:
+		if obj == nil {
+			return driftCacheObj{}, false
+		}
+		return driftCacheObj{uuid: obj.Uuid, cloudConfigCksum: obj.CloudConfigCksum, lastModified: obj.LastModified, invalidData: obj.InvalidData}, true
+	}
+	return driftCacheObj{}, false
+}
+
+func clearCloudConfigCksum(objIntf interface{}) {
+	switch obj := objIntf.(type) {
+	case *AviVsCache:
+		obj.VSCacheLock.Lock()
+		defer obj.VSCacheLock.Unlock()
+		obj.CloudConfigCksum = ""
+	case *AviPoolCache:
+		obj.CloudConfigCksum = ""
+	case *AviPGCache:
+		obj.CloudConfigCksum = ""
+	case *AviHTTPPolicyCache:
+		obj.CloudConfigCksum = ""
+	}
+}
+
+func isServiceMetadataEmpty(svcMetadata lib.ServiceMetadataObj) bool {
+	return len(svcMetadata.NamespaceServiceName) == 0 && len(svcMetadata.NamespaceIngressName) == 0 && svcMetadata.IngressName == ""
+}
+
+// getDriftPolicy returns the drift policy of an Avi object, along with the Kubernetes object it is created for.
+// The policy set in the drift policy annotation of the Service, Ingress or Route overrides the default policy.
+func getDriftPolicy(svcMetadata lib.ServiceMetadataObj) (string, runtime.Object) {
+	policy := lib.GetDriftDetectionPolicy()
+	var k8sObj runtime.Object
+	var annotations map[string]string
+	informers := utils.GetInformers()
+	for _, nsSvc := range svcMetadata.NamespaceServiceName {
+		nsName := strings.SplitN(nsSvc, "/", 2)
+		if len(nsName) != 2 || informers.ServiceInformer == nil {
+			continue
+		}
+		if svcObj, err := informers.ServiceInformer.Lister().Services(nsName[0]).Get(nsName[1]); err == nil {
+			k8sObj, annotations = svcObj, svcObj.Annotations
+			break
+		}
+	}
+
+	nsIngresses := svcMetadata.NamespaceIngressName
+	if svcMetadata.IngressName != "" && svcMetadata.Namespace != "" {
+		nsIngresses = append([]string{svcMetadata.Namespace + "/" + svcMetadata.IngressName}, nsIngresses...)
+	}
+	for _, nsIngress := range nsIngresses {
+		if k8sObj != nil {
+			break
+		}
+		nsName := strings.SplitN(nsIngress, "/", 2)
+		if len(nsName) != 2 {
+			continue
+		}
+		if informers.RouteInformer != nil {
+			if routeObj, err := informers.RouteInformer.Lister().Routes(nsName[0]).Get(nsName[1]); err == nil {
+				k8sObj, annotations = routeObj, routeObj.Annotations
+			}
+		} else if informers.IngressInformer != nil {
+			if ingObj, err := informers.IngressInformer.Lister().Ingresses(nsName[0]).Get(nsName[1]); err == nil {
+				k8sObj, annotations = ingObj, ingObj.Annotations
+			}
+		}
+	}
+
+	if objPolicy, ok := annotations[lib.DriftPolicyAnnotation]; ok {
+		policy = lib.ParseDriftPolicy(objPolicy)
+	}
+	return policy, k8sObj
+}
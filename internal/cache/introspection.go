@@ -0,0 +1,110 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+package cache
+
+import (
+	"sort"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+)
+
+// GetCachedVSViews returns the cached virtualservices with the given name, across all the tenants.
+func GetCachedVSViews(name string) []models.CachedVS {
+	aviObjCache := SharedAviObjCache()
+	vsList := []models.CachedVS{}
+	for _, vsKey := range aviObjCache.VsCacheMeta.AviGetAllKeys() {
+		if vsKey.Name != name {
+			continue
+		}
+		vsCacheIntf, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			continue
+		}
+		vsCache, ok := vsCacheIntf.(*AviVsCache)
+		if !ok {
+			continue
+		}
+		vsList = append(vsList, buildCachedVS(aviObjCache, vsKey, vsCache))
+	}
+	sort.Slice(vsList, func(i, j int) bool {
+		return vsList[i].Tenant < vsList[j].Tenant
+	})
+	return vsList
+}
+
+func buildCachedVS(aviObjCache *AviObjCache, vsKey NamespaceName, vsCache *AviVsCache) models.CachedVS {
+	vsCache.VSCacheLock.RLock()
+	defer vsCache.VSCacheLock.RUnlock()
+	cachedVS := models.CachedVS{
+		Name:               vsKey.Name,
+		Tenant:             vsKey.Namespace,
+		Uuid:               vsCache.Uuid,
+		Checksum:           vsCache.CloudConfigCksum,
+		SNIChildUuids:      vsCache.SNIChildCollection,
+		ServiceMetadataObj: vsCache.ServiceMetadataObj,
+		LastModified:       vsCache.LastModified,
+		InvalidData:        vsCache.InvalidData,
+		Objects:            make(map[string][]models.CachedObject),
+	}
+	if vsCache.ParentVSRef.Name != "" {
+		cachedVS.ParentVS = vsCache.ParentVSRef.Namespace + "/" + vsCache.ParentVSRef.Name
+	}
+	if vsCache.PassthroughParentRef.Name != "" {
+		cachedVS.PassthroughParent = vsCache.PassthroughParentRef.Namespace + "/" + vsCache.PassthroughParentRef.Name
+	}
+	cachedVS.Objects["vsvips"] = getCachedObjects(aviObjCache.VSVIPCache, vsCache.VSVipKeyCollection)
+	cachedVS.Objects["pools"] = getCachedObjects(aviObjCache.PoolCache, vsCache.PoolKeyCollection)
+	cachedVS.Objects["poolgroups"] = getCachedObjects(aviObjCache.PgCache, vsCache.PGKeyCollection)
+	cachedVS.Objects["datascripts"] = getCachedObjects(aviObjCache.DSCache, vsCache.DSKeyCollection)
+	cachedVS.Objects["httppolicysets"] = getCachedObjects(aviObjCache.HTTPPolicyCache, vsCache.HTTPKeyCollection)
+	cachedVS.Objects["sslkeyandcertificates"] = getCachedObjects(aviObjCache.SSLKeyCache, vsCache.SSLKeyCertCollection)
+	cachedVS.Objects["l4policysets"] = getCachedObjects(aviObjCache.L4PolicyCache, vsCache.L4PolicyCollection)
+	cachedVS.Objects["networksecuritypolicies"] = getCachedObjects(aviObjCache.NSPCache, vsCache.NSPKeyCollection)
+	cachedVS.Objects["healthmonitors"] = getCachedObjects(aviObjCache.HMCache, vsCache.HMKeyCollection)
+	return cachedVS
+}
+
+// getCachedObjects returns the uuid and the checksum of the objects referred by the virtualservice. The objects
+// which are referred by the virtualservice but are missing in the cache are reported as not found.
+func getCachedObjects(aviCache *AviCache, keys []NamespaceName) []models.CachedObject {
+	cachedObjects := []models.CachedObject{}
+	for _, key := range keys {
+		cachedObject := models.CachedObject{Name: key.Name, Tenant: key.Namespace}
+		if objIntf, found := aviCache.AviCacheGet(key); found {
+			cachedObject.Found = true
+			switch obj := objIntf.(type) {
+			case *AviVSVIPCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviPoolCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviPGCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviDSCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviHTTPPolicyCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviSSLCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviL4PolicyCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviNetworkSecurityPolicyCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			case *AviHealthMonitorCache:
+				cachedObject.Uuid, cachedObject.Checksum = obj.Uuid, obj.CloudConfigCksum
+			}
+		}
+		cachedObjects = append(cachedObjects, cachedObject)
+	}
+	return cachedObjects
+}
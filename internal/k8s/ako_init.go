@@ -56,11 +56,19 @@ func PopulateCache() error {
 	aviObjCache := avicache.SharedAviObjCache()
 	// Randomly pickup a client.
 	if aviRestClientPool != nil && len(aviRestClientPool.AviClient) > 0 {
-		_, _, err = aviObjCache.AviObjCachePopulate(aviRestClientPool.AviClient, lib.AKOControlConfig().ControllerVersion(), utils.CloudName)
-		if err != nil {
-			utils.AviLog.Warnf("failed to populate avi cache with error: %v", err.Error())
-			return err
+		controllerVersion := lib.AKOControlConfig().ControllerVersion()
+		warmStarted := false
+		if lib.IsCacheSnapshotEnabled() {
+			warmStarted = warmStartCache(aviRestClientPool, controllerVersion)
+		}
+		if !warmStarted {
+			_, _, err = aviObjCache.AviObjCachePopulate(aviRestClientPool.AviClient, controllerVersion, utils.CloudName)
+			if err != nil {
+				utils.AviLog.Warnf("failed to populate avi cache with error: %v", err.Error())
+				return err
+			}
 		}
+		saveCacheSnapshot()
 		if err = avicache.SetControllerClusterUUID(aviRestClientPool); err != nil {
 			utils.AviLog.Warnf("Failed to set the controller cluster uuid with error: %v", err)
 		}
@@ -69,6 +77,32 @@ func PopulateCache() error {
 	return nil
 }
 
+// warmStartCache populates the cache from the snapshot persisted on the PVC, and returns false if the cache has to
+// be populated from scratch.
+func warmStartCache(aviRestClientPool *utils.AviRestClientPool, controllerVersion string) bool {
+	snapshot, err := avicache.LoadCacheSnapshot(controllerVersion, utils.CloudName)
+	if err != nil {
+		utils.AviLog.Infof("Not using the snapshot of the avi cache: %v", err)
+		return false
+	}
+	utils.AviLog.Infof("Warm starting the avi cache from the snapshot written at %v", snapshot.CreatedAt)
+	if err = avicache.SharedAviObjCache().AviObjCacheWarmStart(aviRestClientPool.AviClient, utils.CloudName, snapshot); err != nil {
+		utils.AviLog.Warnf("failed to warm start avi cache with error: %v, populating the cache from scratch", err)
+		return false
+	}
+	utils.AviLog.Infof("Finished warm starting the avi cache from the snapshot")
+	return true
+}
+
+func saveCacheSnapshot() {
+	if !lib.IsCacheSnapshotEnabled() {
+		return
+	}
+	if err := avicache.SharedAviObjCache().SaveCacheSnapshot(lib.AKOControlConfig().ControllerVersion(), utils.CloudName); err != nil {
+		utils.AviLog.Warnf("Failed to save the snapshot of the avi cache with error: %v", err)
+	}
+}
+
 func (c *AviController) CleanupStaleVSes() {
 
 	aviRestClientPool := avicache.SharedAVIClients()
@@ -92,17 +126,7 @@ func (c *AviController) CleanupStaleVSes() {
 		utils.AviLog.Errorf("AKO cluster name is invalid.")
 		return
 	}
-	if aviRestClientPool != nil && len(aviRestClientPool.AviClient) > 0 {
-		utils.AviLog.Infof("Starting clean up of stale objects")
-		restlayer := rest.NewRestOperations(aviObjCache, aviRestClientPool)
-		staleVSKey := lib.GetTenant() + "/" + lib.DummyVSForStaleData
-		restlayer.CleanupVS(staleVSKey, true)
-		staleCacheKey := avicache.NamespaceName{
-			Name:      lib.DummyVSForStaleData,
-			Namespace: lib.GetTenant(),
-		}
-		aviObjCache.VsCacheMeta.AviCacheDelete(staleCacheKey)
-	}
+	DeleteStaleObjects(aviObjCache, aviRestClientPool)
 
 	vsKeysPending := aviObjCache.VsCacheMeta.AviGetAllKeys()
 	if delModels {
@@ -116,7 +140,17 @@ func (c *AviController) CleanupStaleVSes() {
 	}
 }
 
+// DeleteAviObjects deletes the given parent virtualservices along with their children, if the deletions are allowed
+// by the MassDeletionGuard. Otherwise the deletions are resumed once the override is set in the AKO configmap.
 func DeleteAviObjects(parentVSKeys []avicache.NamespaceName, avi_obj_cache *avicache.AviObjCache, avi_rest_client_pool *utils.AviRestClientPool) {
+	var modelNames []string
+	for _, pvsKey := range parentVSKeys {
+		modelNames = append(modelNames, pvsKey.Namespace+"/"+pvsKey.Name)
+	}
+	vsCount := getVSDeletionCount(avi_obj_cache, parentVSKeys)
+	if !SharedMassDeletionGuard().AllowDeletions(modelNames, vsCount, len(avi_obj_cache.VsCacheMeta.AviGetAllKeys())) {
+		return
+	}
 	for _, pvsKey := range parentVSKeys {
 		// Fetch the parent VS cache and update the SNI child
 		vsObj, parentFound := avi_obj_cache.VsCacheMeta.AviCacheGet(pvsKey)
@@ -133,6 +167,31 @@ func DeleteAviObjects(parentVSKeys []avicache.NamespaceName, avi_obj_cache *avic
 	}
 }
 
+// DeleteStaleObjects deletes the stale objects found while populating the cache during the bootup, which are held by
+// the dummy virtualservice in the cache. The stale child virtualservices are deleted only if allowed by the
+// MassDeletionGuard, otherwise the dummy virtualservice is removed from the cache until the deletion is resumed.
+func DeleteStaleObjects(aviObjCache *avicache.AviObjCache, aviRestClientPool *utils.AviRestClientPool) {
+	if aviRestClientPool == nil || len(aviRestClientPool.AviClient) == 0 {
+		return
+	}
+	staleCacheKey := avicache.NamespaceName{
+		Name:      lib.DummyVSForStaleData,
+		Namespace: lib.GetTenant(),
+	}
+	if vsObj, found := aviObjCache.VsCacheMeta.AviCacheGet(staleCacheKey); found {
+		if staleVSCache, ok := vsObj.(*avicache.AviVsCache); ok &&
+			!SharedMassDeletionGuard().AllowStaleVSDeletion(staleVSCache, len(aviObjCache.VsCacheMeta.AviGetAllKeys())-1) {
+			aviObjCache.VsCacheMeta.AviCacheDelete(staleCacheKey)
+			return
+		}
+	}
+	utils.AviLog.Infof("Starting clean up of stale objects")
+	restlayer := rest.NewRestOperations(aviObjCache, aviRestClientPool)
+	staleVSKey := lib.GetTenant() + "/" + lib.DummyVSForStaleData
+	restlayer.CleanupVS(staleVSKey, true)
+	aviObjCache.VsCacheMeta.AviCacheDelete(staleCacheKey)
+}
+
 func PopulateNodeCache(cs *kubernetes.Clientset) {
 	nodeCache := objects.SharedNodeLister()
 	var nodeLabels map[string]string
@@ -170,6 +229,8 @@ func DeleteConfigFromConfigmap(cs kubernetes.Interface) (bool, error) {
 	cmNS := utils.GetAKONamespace()
 	cm, err := cs.CoreV1().ConfigMaps(cmNS).Get(context.TODO(), lib.AviConfigMap, metav1.GetOptions{})
 	if err == nil {
+		// The override of the MassDeletionGuard is read along, as the deletions are guarded during the bootup.
+		lib.SetAllowMassDeletion(cm.Data[lib.ALLOW_MASS_DELETION])
 		return delConfigFromData(cm.Data), err
 	}
 	utils.AviLog.Warnf("error while reading configmap, sync would be disabled: %v", err)
@@ -277,6 +338,7 @@ func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct
 			lib.SetLayer7Only(cm.Data[lib.LAYER7_ONLY])
 			// Check if we need to use PGs for SNIs or not.
 			lib.SetNoPGForSNI(cm.Data[lib.NO_PG_FOR_SNI])
+			lib.SetAllowMassDeletion(cm.Data[lib.ALLOW_MASS_DELETION])
 
 			delModels := delConfigFromData(cm.Data)
 
@@ -308,6 +370,14 @@ func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct
 				lib.AKOControlConfig().EventsSetEnabled(cm.Data[lib.EnableEvents])
 			}
 
+			if oldcm.Data[lib.ALLOW_MASS_DELETION] != cm.Data[lib.ALLOW_MASS_DELETION] {
+				lib.SetAllowMassDeletion(cm.Data[lib.ALLOW_MASS_DELETION])
+				// The deletions halted while deleteConfig is set are resumed as well, as sync is disabled only for the updates.
+				if lib.IsMassDeletionAllowed() && (!c.DisableSync || lib.GetDeleteConfigMap()) {
+					SharedMassDeletionGuard().ResumeHaltedDeletions()
+				}
+			}
+
 			if oldcm.Data[lib.DeleteConfig] == cm.Data[lib.DeleteConfig] {
 				return
 			}
@@ -453,6 +523,7 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	// set up signals so we handle the first shutdown signal gracefully
 	var worker *utils.FullSyncThread
 	var tokenWorker *utils.FullSyncThread
+	var podReadinessWorker *utils.FullSyncThread
 	informersArg := make(map[string]interface{})
 	informersArg[utils.INFORMERS_OPENSHIFT_CLIENT] = informers.OshiftClient
 	if lib.GetNamespaceToSync() != "" {
@@ -573,6 +644,11 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 		tokenWorker.SyncFunction = c.RefreshAuthToken
 		go tokenWorker.Run()
 	}
+	if lib.IsPodReadinessGateEnabled() {
+		podReadinessWorker = utils.NewFullSyncThread(status.PoolMemberReadinessSyncInterval)
+		podReadinessWorker.SyncFunction = status.UpdatePoolMemberReadiness
+		go podReadinessWorker.Run()
+	}
 	if lib.DisableSync {
 		lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKODeleteConfigSet, "AKO is in disable sync state")
 	} else {
@@ -607,6 +683,9 @@ LABEL:
 	if worker != nil {
 		worker.Shutdown()
 	}
+	if podReadinessWorker != nil {
+		podReadinessWorker.Shutdown()
+	}
 
 	cancel()
 	if !lib.IsWCP() {
@@ -763,6 +842,17 @@ func (c *AviController) FullSync() {
 		aviObjCache.AviClusterStatusPopulate(aviRestClientPool.AviClient[0])
 		if !lib.IsWCP() {
 			aviObjCache.AviCacheRefresh(aviRestClientPool.AviClient[0], utils.CloudName)
+			// Publish the models of the objects modified out of band, to revert the objects.
+			revertModels := make(map[string]bool)
+			for _, vsKey := range avicache.SharedDriftDetector().DetectDrift(aviRestClientPool.AviClient[0]) {
+				revertModels[lib.GetModelName(vsKey.Namespace, vsKey.Name)] = true
+			}
+			sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+			for modelName := range revertModels {
+				nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+			}
+			SharedOrphanCollector().CollectOrphanObjects(aviRestClientPool.AviClient[0])
+			saveCacheSnapshot()
 		} else {
 			// In this case we just sync the Gateway status to the LB status
 			restlayer := rest.NewRestOperations(aviObjCache, aviRestClientPool)
@@ -1244,6 +1334,7 @@ func (c *AviController) publishAllParentVSKeysToRestLayer() {
 	}
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	syncNamespace := lib.GetNamespaceToSync()
+	var publishVSKeys []avicache.NamespaceName
 	for _, vsCacheKey := range vsKeys {
 		modelName := vsCacheKey.Namespace + "/" + vsCacheKey.Name
 		// Reverse map the model key from this.
@@ -1253,21 +1344,22 @@ func (c *AviController) publishAllParentVSKeysToRestLayer() {
 				if strings.HasPrefix(vsCacheKey.Name, shardVsPrefix) {
 					delete(allModels, modelName)
 					utils.AviLog.Infof("Model published L7 VS during namespace based sync: %s", modelName)
-					nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+					publishVSKeys = append(publishVSKeys, vsCacheKey)
 				}
 			}
 			// For namespace based syncs, the L4 VSes would be named: clusterName + "--" + namespace
 			if strings.HasPrefix(vsCacheKey.Name, lib.GetNamePrefix()+syncNamespace) {
 				delete(allModels, modelName)
 				utils.AviLog.Infof("Model published L4 VS during namespace based sync: %s", modelName)
-				nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+				publishVSKeys = append(publishVSKeys, vsCacheKey)
 			}
 		} else {
 			delete(allModels, modelName)
 			utils.AviLog.Infof("Model published in full sync %s", modelName)
-			nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+			publishVSKeys = append(publishVSKeys, vsCacheKey)
 		}
 	}
+	PublishVSModelsToRestLayer(publishVSKeys, allModelsMap.(map[string]interface{}))
 	// Now also publish the newly generated models (if any)
 	// Publish all the models to REST layer.
 	utils.AviLog.Debugf("Newly generated models that do not exist in cache %s", utils.Stringify(allModels))
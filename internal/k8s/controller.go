@@ -52,6 +52,7 @@ var ctrlonce sync.Once
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;delete;update;patch
 // +kubebuilder:rbac:groups=core,resources=services;services/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=topology.tanzu.vmware.com,resources=availabilityzones,verbs=get;list;watch
@@ -586,6 +587,38 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 	return podEventHandler
 }
 
+// AddPodReadinessGateEventHandler re-evaluates the pool servers of the Services selecting a Pod, when the Pod
+// starts or stops waiting for its pool member readiness condition. The endpoints of such Pods are not ready,
+// hence no endpoint event is received for them.
+func AddPodReadinessGateEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
+	podEventHandler := cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldPod := old.(*corev1.Pod)
+			newPod := cur.(*corev1.Pod)
+			if lib.IsPodPendingPoolMemberReadiness(oldPod) == lib.IsPodPendingPoolMemberReadiness(newPod) {
+				return
+			}
+			namespace := newPod.Namespace
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: Pod Update event: Namespace: %s didn't qualify filter", utils.ObjKey(newPod), namespace)
+				return
+			}
+			svcList, _ := lib.GetServicesForPod(newPod)
+			bkt := utils.Bkt(namespace, numWorkers)
+			for _, svcKey := range svcList {
+				key := utils.Endpoints + "/" + svcKey
+				c.workqueue[bkt].AddRateLimited(key)
+				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+				utils.AviLog.Debugf("key: %s, msg: Pod %s pool member readiness UPDATE", key, utils.ObjKey(newPod))
+			}
+		},
+	}
+	return podEventHandler
+}
+
 func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	mcpQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	c.workqueue = mcpQueue.Workqueue
@@ -658,6 +691,10 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		},
 	}
 
+	epSliceEventHandler := NewEndpointSliceEventHandler(c.workqueue, numWorkers, func() bool { return c.DisableSync }, func() {
+		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+	})
+
 	svcEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			if c.DisableSync {
@@ -804,7 +841,12 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		},
 	}
 
-	c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	if c.informers.EpInformer != nil {
+		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	}
+	if c.informers.EpSliceInformer != nil {
+		c.informers.EpSliceInformer.Informer().AddEventHandler(epSliceEventHandler)
+	}
 
 	c.informers.ServiceInformer.Informer().AddEventHandler(svcEventHandler)
 
@@ -1312,6 +1354,9 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	if lib.GetServiceType() == lib.NodePortLocal {
 		podEventHandler := AddPodEventHandler(numWorkers, c)
 		c.informers.PodInformer.Informer().AddEventHandler(podEventHandler)
+	} else if lib.IsPodReadinessGateEnabled() {
+		podEventHandler := AddPodReadinessGateEventHandler(numWorkers, c)
+		c.informers.PodInformer.Informer().AddEventHandler(podEventHandler)
 	}
 }
 
@@ -1340,21 +1385,28 @@ func checkAviSecretUpdateAndShutdown(secret *corev1.Secret) bool {
 
 func (c *AviController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 	go c.informers.NSInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 		c.informers.NSInformer.Informer().HasSynced,
 	}
 
+	if c.informers.EpInformer != nil {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+	if c.informers.EpSliceInformer != nil {
+		go c.informers.EpSliceInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSliceInformer.Informer().HasSynced)
+	}
+
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
 	}
 
-	if lib.GetServiceType() == lib.NodePortLocal {
+	if lib.GetServiceType() == lib.NodePortLocal || lib.IsPodReadinessGateEnabled() {
 		go c.informers.PodInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.PodInformer.Informer().HasSynced)
 	}
@@ -1464,8 +1516,9 @@ func (c *AviController) Start(stopCh <-chan struct{}) {
 
 func isServiceLBType(svcObj *corev1.Service) bool {
 	// If we don't find a service or it is not of type loadbalancer - return false.
+	// Services of type loadbalancer with a loadBalancerClass of another LB controller are skipped as well.
 	if svcObj.Spec.Type == "LoadBalancer" {
-		return true
+		return lib.IsLoadBalancerClassAccepted(svcObj)
 	}
 	return false
 }
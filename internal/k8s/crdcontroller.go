@@ -73,6 +73,17 @@ func NewInfraSettingCRDInformer() {
 	})
 }
 
+// NewGatewayCRDInformers initializes the informers of the AKO CRDs which are applied on the
+// Gateway API objects, i.e. the AviInfraSettings, the HostRules and the HTTPRules.
+func NewGatewayCRDInformers() {
+	akoInformerFactory := v1beta1akoinformers.NewSharedInformerFactoryWithOptions(lib.AKOControlConfig().V1beta1CRDClientset(), time.Second*30)
+	lib.AKOControlConfig().SetCRDInformers(&lib.AKOCrdInformers{
+		AviInfraSettingInformer: akoInformerFactory.Ako().V1beta1().AviInfraSettings(),
+		HostRuleInformer:        akoInformerFactory.Ako().V1beta1().HostRules(),
+		HTTPRuleInformer:        akoInformerFactory.Ako().V1beta1().HTTPRules(),
+	})
+}
+
 func NewIstioCRDInformers(cs istiocrd.Interface) {
 	var istioInformerFactory istioinformers.SharedInformerFactory
 
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"reflect"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// NewEndpointSliceEventHandler returns the event handler of the EndpointSlice informer, shared by the AKO and
+// the Gateway API controllers. The EndpointSlices are queued with the key of the Endpoints of the owning Service,
+// the servers are then computed from all the EndpointSlices of the Service. The events are dropped while
+// isSyncDisabled returns true, and onQueued, if not nil, is called for every queued key.
+func NewEndpointSliceEventHandler(queues []workqueue.RateLimitingInterface, numWorkers uint32, isSyncDisabled func() bool, onQueued func()) cache.ResourceEventHandlerFuncs {
+	enqueue := func(epSlice *discoveryv1.EndpointSlice, event string) {
+		svcKey, ok := lib.GetEndpointSliceServiceKey(epSlice)
+		if !ok {
+			return
+		}
+		key := utils.Endpoints + "/" + svcKey
+		if lib.IsNamespaceBlocked(epSlice.Namespace) {
+			utils.AviLog.Debugf("key: %s, msg: EndpointSlice %s event: Namespace: %s didn't qualify filter", key, event, epSlice.Namespace)
+			return
+		}
+		bkt := utils.Bkt(epSlice.Namespace, numWorkers)
+		queues[bkt].AddRateLimited(key)
+		if onQueued != nil {
+			onQueued()
+		}
+		utils.AviLog.Debugf("key: %s, msg: %s EndpointSlice %s", key, event, epSlice.Name)
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if isSyncDisabled() {
+				return
+			}
+			enqueue(obj.(*discoveryv1.EndpointSlice), "ADD")
+		},
+		DeleteFunc: func(obj interface{}) {
+			if isSyncDisabled() {
+				return
+			}
+			epSlice, ok := obj.(*discoveryv1.EndpointSlice)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				epSlice, ok = tombstone.Obj.(*discoveryv1.EndpointSlice)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+					return
+				}
+			}
+			enqueue(epSlice, "DELETE")
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if isSyncDisabled() {
+				return
+			}
+			oEpSlice := old.(*discoveryv1.EndpointSlice)
+			cEpSlice := cur.(*discoveryv1.EndpointSlice)
+			if reflect.DeepEqual(cEpSlice.Endpoints, oEpSlice.Endpoints) && reflect.DeepEqual(cEpSlice.Ports, oEpSlice.Ports) {
+				return
+			}
+			enqueue(cEpSlice, "UPDATE")
+		},
+	}
+}
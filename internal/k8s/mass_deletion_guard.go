@@ -0,0 +1,260 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// MassDeletionGuard halts the deletions of the virtualservices during a sync, when the number of virtualservices
+// to be deleted exceeds the configured thresholds, for instance when the informers return an empty or partial list
+// of the objects. The halted deletions are resumed once the override is set in the AKO configmap.
+type MassDeletionGuard struct {
+	lock sync.Mutex
+	// haltedModels holds the names of the models of the virtualservices, whose deletion is halted.
+	haltedModels map[string]struct{}
+	// haltedStaleVS holds the cache of the dummy virtualservice of the stale objects found during the bootup, whose
+	// deletion is halted. It is kept out of the cache, so that a full sync does not delete the stale objects.
+	haltedStaleVS *avicache.AviVsCache
+}
+
+var massDeletionGuardInstance *MassDeletionGuard
+var massDeletionGuardOnce sync.Once
+
+func SharedMassDeletionGuard() *MassDeletionGuard {
+	massDeletionGuardOnce.Do(func() {
+		massDeletionGuardInstance = &MassDeletionGuard{haltedModels: make(map[string]struct{})}
+	})
+	return massDeletionGuardInstance
+}
+
+// AllowDeletions returns true if the given models, which delete virtualservices, can be published. vsCount is the
+// number of virtualservices deleted along with the models, the SNI, EVH and passthrough children included, out of the
+// given number of cached virtualservices. Otherwise the deletions are halted, and reported on the AKO StatefulSet and pod.
+func (m *MassDeletionGuard) AllowDeletions(modelNames []string, vsCount, cachedVSCount int) bool {
+	if len(modelNames) == 0 || m.allowDeletions(vsCount, cachedVSCount) {
+		return true
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, modelName := range modelNames {
+		m.haltedModels[modelName] = struct{}{}
+	}
+	return false
+}
+
+// AllowStaleVSDeletion returns true if the stale objects of the given dummy virtualservice can be deleted, along with
+// its stale child virtualservices, out of the given number of cached virtualservices. Otherwise the deletion is
+// halted, and the dummy virtualservice is to be removed from the cache.
+func (m *MassDeletionGuard) AllowStaleVSDeletion(staleVS *avicache.AviVsCache, cachedVSCount int) bool {
+	if m.allowDeletions(len(staleVS.SNIChildCollection), cachedVSCount) {
+		return true
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.haltedStaleVS = staleVS
+	return false
+}
+
+// allowDeletions returns true if the given number of virtualservices can be deleted, out of the given number of
+// cached virtualservices. Otherwise the halt is reported on the AKO StatefulSet and pod.
+func (m *MassDeletionGuard) allowDeletions(vsCount, cachedVSCount int) bool {
+	if vsCount == 0 {
+		return true
+	}
+	thresholdCount := lib.GetMassDeletionThresholdCount()
+	thresholdPercent := lib.GetMassDeletionThresholdPercent()
+	exceedsCount := thresholdCount > 0 && vsCount > thresholdCount
+	exceedsPercent := thresholdPercent > 0 && cachedVSCount > 0 && vsCount*100 > thresholdPercent*cachedVSCount
+	if !exceedsCount && !exceedsPercent {
+		return true
+	}
+
+	message := fmt.Sprintf("Sync would delete %d out of %d virtualservices, exceeding the mass deletion threshold", vsCount, cachedVSCount)
+	if lib.IsMassDeletionAllowed() {
+		utils.AviLog.Warnf("%s, proceeding as %s is set in the configmap", message, lib.ALLOW_MASS_DELETION)
+		lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.MassDeletionAllowed, message+", proceeding as "+lib.ALLOW_MASS_DELETION+" is set")
+		return true
+	}
+
+	utils.AviLog.Warnf("%s, halting the deletions. Set %s to true in the configmap to proceed", message, lib.ALLOW_MASS_DELETION)
+	lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.MassDeletionHalted, message+", set "+lib.ALLOW_MASS_DELETION+" to true in the configmap to proceed")
+	status.NewStatusPublisher().AddStatefulSetAnnotation(lib.ObjectDeletionHaltedStatus)
+	return false
+}
+
+// GetHaltedModels returns the names of the models of the virtualservices, whose deletion is halted.
+func (m *MassDeletionGuard) GetHaltedModels() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	modelNames := make([]string, 0, len(m.haltedModels))
+	for modelName := range m.haltedModels {
+		modelNames = append(modelNames, modelName)
+	}
+	return modelNames
+}
+
+// IsStaleVSDeletionHalted returns true if the deletion of the stale objects found during the bootup is halted.
+func (m *MassDeletionGuard) IsStaleVSDeletionHalted() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.haltedStaleVS != nil
+}
+
+// ResumeHaltedDeletions publishes the models of the virtualservices whose deletion is halted to the rest layer, and
+// deletes the stale objects whose deletion is halted.
+func (m *MassDeletionGuard) ResumeHaltedDeletions() {
+	m.lock.Lock()
+	haltedModels, haltedStaleVS := m.haltedModels, m.haltedStaleVS
+	m.haltedModels, m.haltedStaleVS = make(map[string]struct{}), nil
+	m.lock.Unlock()
+	if len(haltedModels) == 0 && haltedStaleVS == nil {
+		return
+	}
+	utils.AviLog.Infof("Resuming the deletion of %d virtualservices", len(haltedModels))
+	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+	for modelName := range haltedModels {
+		nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+	}
+	if haltedStaleVS != nil {
+		aviObjCache := avicache.SharedAviObjCache()
+		aviObjCache.VsCacheMeta.AviCacheAdd(avicache.NamespaceName{Namespace: lib.GetTenant(), Name: lib.DummyVSForStaleData}, haltedStaleVS)
+		DeleteStaleObjects(aviObjCache, avicache.SharedAVIClients())
+	}
+	status.NewStatusPublisher().ResetStatefulSetAnnotation()
+}
+
+// PublishVSModelsToRestLayer publishes the models of the given cached parent virtualservices to the rest layer during
+// a full sync. The virtualservices without a model would be deleted, and so would the cached child virtualservices
+// missing from the model of their parent. Such models are published only if the deletions are allowed.
+func PublishVSModelsToRestLayer(vsCacheKeys []avicache.NamespaceName, allModelsMap map[string]interface{}) {
+	aviObjCache := avicache.SharedAviObjCache()
+	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+	var deleteModels []string
+	var deleteVSKeys []avicache.NamespaceName
+	var childVSDeletionCount int
+	vsNamesByUuid := getVSNamesByUuid(aviObjCache)
+	for _, vsCacheKey := range vsCacheKeys {
+		modelName := vsCacheKey.Namespace + "/" + vsCacheKey.Name
+		avimodel, _ := allModelsMap[modelName].(*nodes.AviObjectGraph)
+		if avimodel == nil && vsCacheKey.Name != lib.DummyVSForStaleData {
+			deleteModels = append(deleteModels, modelName)
+			deleteVSKeys = append(deleteVSKeys, vsCacheKey)
+			continue
+		}
+		if avimodel != nil {
+			if vsCount := getChildVSDeletionCount(aviObjCache, vsCacheKey, avimodel, vsNamesByUuid); vsCount > 0 {
+				deleteModels = append(deleteModels, modelName)
+				childVSDeletionCount += vsCount
+				continue
+			}
+		}
+		nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+	}
+	vsCount := getVSDeletionCount(aviObjCache, deleteVSKeys) + childVSDeletionCount
+	if SharedMassDeletionGuard().AllowDeletions(deleteModels, vsCount, len(aviObjCache.VsCacheMeta.AviGetAllKeys())) {
+		for _, modelName := range deleteModels {
+			nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
+		}
+	}
+}
+
+// getVSDeletionCount returns the number of virtualservices deleted along with the given parent virtualservices,
+// the SNI, EVH and passthrough children included.
+func getVSDeletionCount(aviObjCache *avicache.AviObjCache, parentVSKeys []avicache.NamespaceName) int {
+	var vsCount int
+	for _, vsKey := range parentVSKeys {
+		vsCount++
+		vsIntf, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			continue
+		}
+		if vsCacheObj, ok := vsIntf.(*avicache.AviVsCache); ok {
+			vsCount += len(vsCacheObj.SNIChildCollection)
+			if vsCacheObj.ServiceMetadataObj.PassthroughChildRef != "" {
+				vsCount++
+			}
+		}
+	}
+	return vsCount
+}
+
+// getChildVSDeletionCount returns the number of cached child virtualservices of the given parent virtualservice,
+// which are not present in the model of the parent virtualservice, and would be deleted once the model is published.
+// vsNamesByUuid maps the uuids of the cached virtualservices to their names.
+func getChildVSDeletionCount(aviObjCache *avicache.AviObjCache, vsKey avicache.NamespaceName, avimodel *nodes.AviObjectGraph, vsNamesByUuid map[string]string) int {
+	vsIntf, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+	if !found {
+		return 0
+	}
+	vsCacheObj, ok := vsIntf.(*avicache.AviVsCache)
+	if !ok || (len(vsCacheObj.SNIChildCollection) == 0 && vsCacheObj.ServiceMetadataObj.PassthroughChildRef == "") {
+		return 0
+	}
+	childVSNames := make(map[string]struct{})
+	for _, vsNode := range avimodel.GetAviVS() {
+		for _, sniNode := range vsNode.SniNodes {
+			childVSNames[sniNode.Name] = struct{}{}
+		}
+		for _, passthroughNode := range vsNode.PassthroughChildNodes {
+			childVSNames[passthroughNode.Name] = struct{}{}
+		}
+	}
+	for _, evhNode := range avimodel.GetAviEvhVS() {
+		for _, evhChildNode := range evhNode.EvhNodes {
+			childVSNames[evhChildNode.Name] = struct{}{}
+		}
+	}
+	var vsCount int
+	for _, childUuid := range vsCacheObj.SNIChildCollection {
+		childName, found := vsNamesByUuid[childUuid]
+		if !found {
+			continue
+		}
+		if _, found = childVSNames[childName]; !found {
+			vsCount++
+		}
+	}
+	if passthroughChild := vsCacheObj.ServiceMetadataObj.PassthroughChildRef; passthroughChild != "" {
+		if _, found := childVSNames[passthroughChild]; !found {
+			vsCount++
+		}
+	}
+	return vsCount
+}
+
+// getVSNamesByUuid returns the names of the cached virtualservices, keyed by their uuids.
+func getVSNamesByUuid(aviObjCache *avicache.AviObjCache) map[string]string {
+	vsNamesByUuid := make(map[string]string)
+	for _, vsKey := range aviObjCache.VsCacheMeta.AviGetAllKeys() {
+		vsIntf, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			continue
+		}
+		if vsCacheObj, ok := vsIntf.(*avicache.AviVsCache); ok && vsCacheObj.Uuid != "" {
+			vsNamesByUuid[vsCacheObj.Uuid] = vsKey.Name
+		}
+	}
+	return vsNamesByUuid
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	apimodels "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+// orphanGCObjectTypes are the Avi object types garbage collected, in the order of their deletion, so that
+// the objects are deleted before the objects they refer.
+var orphanGCObjectTypes = []string{"httppolicyset", "vsdatascriptset", "l4policyset", "networksecuritypolicy", "vsvip",
+	"poolgroup", "pool", "healthmonitor", "sslkeyandcertificate"}
+
+// aviMarkedObject holds the fields of an Avi object required to find out if it is an orphan.
+type aviMarkedObject struct {
+	Name    string                            `json:"name"`
+	UUID    string                            `json:"uuid"`
+	Markers []*avimodels.RoleFilterMatchLabel `json:"markers,omitempty"`
+}
+
+type OrphanCollector struct {
+	lock sync.Mutex
+	// firstSeen holds the time at which an object is found orphan for the first time, per object type and uuid.
+	firstSeen map[string]time.Time
+}
+
+var orphanCollectorInstance *OrphanCollector
+var orphanCollectorOnce sync.Once
+
+func SharedOrphanCollector() *OrphanCollector {
+	orphanCollectorOnce.Do(func() {
+		orphanCollectorInstance = &OrphanCollector{firstSeen: make(map[string]time.Time)}
+	})
+	return orphanCollectorInstance
+}
+
+// CollectOrphanObjects lists the Avi objects created by AKO with the cluster name marker, and reports the objects
+// which are referred neither by a model nor by a virtualservice in the cache. The objects orphan for longer than
+// the grace period are deleted, unless the dry-run mode is enabled.
+func (o *OrphanCollector) CollectOrphanObjects(client *clients.AviClient) {
+	if !lib.IsOrphanGCEnabled() || !lib.AKOControlConfig().IsLeader() {
+		return
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	referred := getReferredObjectNames()
+	now := time.Now()
+	gracePeriod := lib.GetOrphanGCGracePeriod()
+	orphans := []apimodels.OrphanObject{}
+	found := make(map[string]bool)
+	failedTypes := make(map[string]bool)
+	for _, objType := range orphanGCObjectTypes {
+		var aviObjects []aviMarkedObject
+		uri := "/api/" + objType + "/?include_name=true&created_by=" + lib.AKOUser + "&fields=name,uuid,markers&page_size=100"
+		if err := getAviMarkedObjects(client, objType, uri, &aviObjects); err != nil {
+			utils.AviLog.Warnf("Error in getting the %s objects for garbage collection: %v", objType, err)
+			failedTypes[objType] = true
+			continue
+		}
+		for _, aviObject := range aviObjects {
+			if !hasClusterNameMarker(aviObject.Markers) || referred[objType+"/"+aviObject.Name] || isRetainedObject(objType, aviObject.Name) {
+				continue
+			}
+			orphanKey := objType + "/" + aviObject.UUID
+			found[orphanKey] = true
+			firstSeen, ok := o.firstSeen[orphanKey]
+			if !ok {
+				firstSeen = now
+				o.firstSeen[orphanKey] = now
+				utils.AviLog.Infof("Found orphan Avi %s %s, it would be deleted after %v", objType, aviObject.Name, gracePeriod)
+			}
+			orphan := apimodels.OrphanObject{
+				Type:        objType,
+				Name:        aviObject.Name,
+				Uuid:        aviObject.UUID,
+				FirstSeen:   firstSeen,
+				DeleteAfter: firstSeen.Add(gracePeriod),
+			}
+			if now.Before(orphan.DeleteAfter) {
+				orphans = append(orphans, orphan)
+				continue
+			}
+			if lib.IsDryRunEnabled() {
+				// The orphans are only reported in the dry-run mode, the Avi controller is left untouched.
+				utils.AviLog.Infof("Dry-run mode, skipping the deletion of the orphan Avi %s %s", objType, aviObject.Name)
+				orphans = append(orphans, orphan)
+				continue
+			}
+			if err := lib.AviDelete(client, "/api/"+objType+"/"+aviObject.UUID); err != nil {
+				utils.AviLog.Warnf("Error in deleting the orphan Avi %s %s: %v", objType, aviObject.Name, err)
+				orphans = append(orphans, orphan)
+				continue
+			}
+			utils.AviLog.Infof("Deleted the orphan Avi %s %s", objType, aviObject.Name)
+			delete(o.firstSeen, orphanKey)
+			if objCache := getOrphanObjCache(objType); objCache != nil {
+				objCache.AviCacheDelete(avicache.NamespaceName{Namespace: lib.GetTenant(), Name: aviObject.Name})
+			}
+		}
+	}
+
+	// The objects deleted or referred again since the last run are not tracked anymore.
+	for orphanKey := range o.firstSeen {
+		if !found[orphanKey] && !failedTypes[strings.SplitN(orphanKey, "/", 2)[0]] {
+			delete(o.firstSeen, orphanKey)
+		}
+	}
+	apimodels.OrphanReport.SetOrphans(orphans)
+}
+
+func getAviMarkedObjects(client *clients.AviClient, objType, uri string, aviObjects *[]aviMarkedObject) error {
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		return err
+	}
+	elems := make([]aviMarkedObject, result.Count)
+	if err = json.Unmarshal(result.Results, &elems); err != nil {
+		return err
+	}
+	*aviObjects = append(*aviObjects, elems...)
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		nextURI := strings.Split(result.Next, "/api/"+objType)
+		if len(nextURI) > 1 {
+			return getAviMarkedObjects(client, objType, "/api/"+objType+nextURI[1], aviObjects)
+		}
+	}
+	return nil
+}
+
+func hasClusterNameMarker(markers []*avimodels.RoleFilterMatchLabel) bool {
+	for _, marker := range markers {
+		if marker != nil && marker.Key != nil && *marker.Key == lib.ClusterNameLabelKey &&
+			utils.HasElem(marker.Values, lib.GetClusterName()) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRetainedObject(objType, name string) bool {
+	// The istio workload certificate is not referred by any virtualservice.
+	return objType == "sslkeyandcertificate" && lib.IsIstioEnabled() && name == lib.GetIstioWorkloadCertificateName()
+}
+
+func getOrphanObjCache(objType string) *avicache.AviCache {
+	aviObjCache := avicache.SharedAviObjCache()
+	switch objType {
+	case "httppolicyset":
+		return aviObjCache.HTTPPolicyCache
+	case "vsdatascriptset":
+		return aviObjCache.DSCache
+	case "l4policyset":
+		return aviObjCache.L4PolicyCache
+	case "networksecuritypolicy":
+		return aviObjCache.NSPCache
+	case "vsvip":
+		return aviObjCache.VSVIPCache
+	case "poolgroup":
+		return aviObjCache.PgCache
+	case "pool":
+		return aviObjCache.PoolCache
+	case "healthmonitor":
+		return aviObjCache.HMCache
+	case "sslkeyandcertificate":
+		return aviObjCache.SSLKeyCache
+	}
+	return nil
+}
+
+// getReferredObjectNames returns the objects referred by the virtualservices in the cache, and by the models,
+// which may not be synced to the Avi controller yet. The objects are keyed with their type and name.
+func getReferredObjectNames() map[string]bool {
+	referred := make(map[string]bool)
+	for key, vsIntf := range avicache.SharedAviObjCache().VsCacheMeta.ShallowCopy() {
+		vsCacheObj, ok := vsIntf.(*avicache.AviVsCache)
+		if !ok || key.(avicache.NamespaceName).Name == lib.DummyVSForStaleData {
+			continue
+		}
+		collections := map[string][]avicache.NamespaceName{
+			"httppolicyset":         vsCacheObj.HTTPKeyCollection,
+			"vsdatascriptset":       vsCacheObj.DSKeyCollection,
+			"l4policyset":           vsCacheObj.L4PolicyCollection,
+			"networksecuritypolicy": vsCacheObj.NSPKeyCollection,
+			"vsvip":                 vsCacheObj.VSVipKeyCollection,
+			"poolgroup":             vsCacheObj.PGKeyCollection,
+			"pool":                  vsCacheObj.PoolKeyCollection,
+			"healthmonitor":         vsCacheObj.HMKeyCollection,
+			"sslkeyandcertificate":  vsCacheObj.SSLKeyCertCollection,
+		}
+		for objType, objKeys := range collections {
+			for _, objKey := range objKeys {
+				referred[objType+"/"+objKey.Name] = true
+			}
+		}
+	}
+
+	allModels := objects.SharedAviGraphLister().GetAll()
+	for _, modelIntf := range allModels.(map[string]interface{}) {
+		aviModel, ok := modelIntf.(*nodes.AviObjectGraph)
+		if !ok || aviModel == nil {
+			continue
+		}
+		for _, vsNode := range aviModel.GetAviVS() {
+			addVsNodeReferences(vsNode, referred)
+		}
+		for _, evhNode := range aviModel.GetAviEvhVS() {
+			addEvhNodeReferences(evhNode, referred)
+		}
+	}
+	return referred
+}
+
+func addVsNodeReferences(vsNode *nodes.AviVsNode, referred map[string]bool) {
+	for _, httpPolicy := range vsNode.HttpPolicyRefs {
+		referred["httppolicyset/"+httpPolicy.Name] = true
+	}
+	for _, dataScript := range vsNode.HTTPDSrefs {
+		referred["vsdatascriptset/"+dataScript.Name] = true
+	}
+	for _, l4Policy := range vsNode.L4PolicyRefs {
+		referred["l4policyset/"+l4Policy.Name] = true
+	}
+	for _, nsp := range vsNode.NetworkSecurityPolicyRefs {
+		referred["networksecuritypolicy/"+nsp.Name] = true
+	}
+	for _, vsvip := range vsNode.VSVIPRefs {
+		referred["vsvip/"+vsvip.Name] = true
+	}
+	for _, poolGroup := range vsNode.PoolGroupRefs {
+		referred["poolgroup/"+poolGroup.Name] = true
+	}
+	for _, pool := range vsNode.PoolRefs {
+		referred["pool/"+pool.Name] = true
+	}
+	for _, hm := range vsNode.HealthMonitorNodeRefs {
+		referred["healthmonitor/"+hm.Name] = true
+	}
+	for _, cert := range append(vsNode.SSLKeyCertRefs, vsNode.CACertRefs...) {
+		referred["sslkeyandcertificate/"+cert.Name] = true
+	}
+	for _, childNode := range append(vsNode.SniNodes, vsNode.PassthroughChildNodes...) {
+		addVsNodeReferences(childNode, referred)
+	}
+}
+
+func addEvhNodeReferences(evhNode *nodes.AviEvhVsNode, referred map[string]bool) {
+	for _, httpPolicy := range evhNode.HttpPolicyRefs {
+		referred["httppolicyset/"+httpPolicy.Name] = true
+	}
+	for _, dataScript := range evhNode.HTTPDSrefs {
+		referred["vsdatascriptset/"+dataScript.Name] = true
+	}
+	for _, nsp := range evhNode.NetworkSecurityPolicyRefs {
+		referred["networksecuritypolicy/"+nsp.Name] = true
+	}
+	for _, vsvip := range evhNode.VSVIPRefs {
+		referred["vsvip/"+vsvip.Name] = true
+	}
+	for _, poolGroup := range evhNode.PoolGroupRefs {
+		referred["poolgroup/"+poolGroup.Name] = true
+	}
+	for _, pool := range evhNode.PoolRefs {
+		referred["pool/"+pool.Name] = true
+	}
+	for _, cert := range append(evhNode.SSLKeyCertRefs, evhNode.CACertRefs...) {
+		referred["sslkeyandcertificate/"+cert.Name] = true
+	}
+	for _, childNode := range evhNode.EvhNodes {
+		addEvhNodeReferences(childNode, referred)
+	}
+}
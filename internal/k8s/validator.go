@@ -180,6 +180,14 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 			return err
 		}
 	}
+	for _, sourceRange := range hostrule.Spec.VirtualHost.SourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			err = fmt.Errorf("sourceRanges %s is not a valid CIDR", sourceRange)
+			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+			return err
+		}
+	}
+
 	if len(hostrule.Spec.VirtualHost.ICAPProfile) > 1 {
 		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: "Can only have 1 ICAP profile associated with VS"})
 		return fmt.Errorf("Can only have 1 ICAP profile associated with VS")
@@ -751,24 +759,31 @@ func (f *follower) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.
 	utils.AviLog.Debugf("key: %s, AKO is not a leader, not validating AviInfraSetting object", key)
 	// During AKO bootup as leader is not set, crd validation is not done.
 	// This creates problem in vip network and pool network population.
-	if infraSetting.Status.Status == lib.StatusAccepted {
-		segMgmtNetworK := ""
-		if infraSetting.Spec.SeGroup.Name != "" {
-			addSeGroupLabel(key, infraSetting.Spec.SeGroup.Name)
-			if lib.GetCloudType() == lib.CLOUD_VCENTER {
-				segMgmtNetworK = GetSEGManagementNetwork(infraSetting.Spec.SeGroup.Name)
-			}
-		}
+	PopulateAviInfraSettingNetworks(key, infraSetting)
+	return nil
+}
 
-		if len(infraSetting.Spec.Network.VipNetworks) > 0 {
-			SetAviInfrasettingVIPNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.VipNetworks)
+// PopulateAviInfraSettingNetworks populates the VIP and the node networks of an accepted AviInfraSetting,
+// without validating it. This is used by the AKO instances which don't own the AviInfraSetting status.
+func PopulateAviInfraSettingNetworks(key string, infraSetting *akov1beta1.AviInfraSetting) {
+	if infraSetting.Status.Status != lib.StatusAccepted {
+		return
+	}
+	segMgmtNetworK := ""
+	if infraSetting.Spec.SeGroup.Name != "" {
+		addSeGroupLabel(key, infraSetting.Spec.SeGroup.Name)
+		if lib.GetCloudType() == lib.CLOUD_VCENTER {
+			segMgmtNetworK = GetSEGManagementNetwork(infraSetting.Spec.SeGroup.Name)
 		}
+	}
 
-		if len(infraSetting.Spec.Network.NodeNetworks) > 0 {
-			SetAviInfrasettingNodeNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.NodeNetworks)
-		}
+	if len(infraSetting.Spec.Network.VipNetworks) > 0 {
+		SetAviInfrasettingVIPNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.VipNetworks)
+	}
+
+	if len(infraSetting.Spec.Network.NodeNetworks) > 0 {
+		SetAviInfrasettingNodeNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.NodeNetworks)
 	}
-	return nil
 }
 
 func (f *follower) ValidateMultiClusterIngressObj(key string, multiClusterIngress *akov1alpha1.MultiClusterIngress) error {
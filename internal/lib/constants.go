@@ -68,6 +68,7 @@ const (
 	EnableEvents                               = "enableEvents"
 	LAYER7_ONLY                                = "layer7Only"
 	NO_PG_FOR_SNI                              = "noPGForSNI"
+	ALLOW_MASS_DELETION                        = "allowMassDeletion"
 	SERVICE_TYPE                               = "SERVICE_TYPE"
 	NODE_PORT                                  = "NodePort"
 	NODE_KEY                                   = "NODE_KEY"
@@ -110,11 +111,25 @@ const (
 	AllowedTCPProxyNetworkProfileType          = "PROTOCOL_TYPE_TCP_PROXY"
 	TypeTLSReencrypt                           = "reencrypt"
 	DefaultPoolSSLProfile                      = "System-Standard"
+	AppProtocolH2C                             = "kubernetes.io/h2c"
+	AppProtocolWS                              = "kubernetes.io/ws"
+	AppProtocolWSS                             = "kubernetes.io/wss"
+	AppProtocolHTTPS                           = "https"
+	AppProtocolGRPC                            = "grpc"
+	DriftPolicyDisabled                        = "disabled"
+	DriftPolicyReport                          = "report"
+	DriftPolicyRevert                          = "revert"
 	LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER = "LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER"
 	LB_ALGORITHM_CONSISTENT_HASH               = "LB_ALGORITHM_CONSISTENT_HASH"
 	Gateway                                    = "Gateway"
 	GatewayClass                               = "GatewayClass"
 	HTTPRoute                                  = "HTTPRoute"
+	TLSRoute                                   = "TLSRoute"
+	GRPCRoute                                  = "GRPCRoute"
+	TCPRoute                                   = "TCPRoute"
+	UDPRoute                                   = "UDPRoute"
+	ReferenceGrant                             = "ReferenceGrant"
+	BackendTLSPolicy                           = "BackendTLSPolicy"
 	DuplicateBackends                          = "MultipleBackendsWithSameServiceError"
 	DummyVSForStaleData                        = "DummyVSForStaleData"
 	ControllerReqWaitTime                      = 300
@@ -127,8 +142,25 @@ const (
 	ObjectDeletionStartStatus                  = "Started"
 	ObjectDeletionDoneStatus                   = "Done"
 	ObjectDeletionTimeoutStatus                = "Timeout"
+	ObjectDeletionHaltedStatus                 = "Halted"
 	DefaultRouteCert                           = "router-certs-default"
 	autoAnnotateService                        = "AUTO_ANNOTATE_SERVICE"
+	endpointSliceEnabled                       = "ENDPOINTSLICE_ENABLED"
+	endpointSliceZone                          = "ENDPOINTSLICE_ZONE"
+	dryRunEnabled                              = "DRY_RUN"
+	introspectionEnabled                       = "INTROSPECTION_ENABLED"
+	loadBalancerClass                          = "LOAD_BALANCER_CLASS"
+	claimUnsetLoadBalancerClass                = "CLAIM_UNSET_LOAD_BALANCER_CLASS"
+	useHealthCheckNodePort                     = "USE_HEALTH_CHECK_NODE_PORT"
+	podReadinessGateEnabled                    = "POD_READINESS_GATE_ENABLED"
+	driftDetectionPolicy                       = "DRIFT_DETECTION_POLICY"
+	orphanGCEnabled                            = "ORPHAN_GC_ENABLED"
+	orphanGCGracePeriod                        = "ORPHAN_GC_GRACE_PERIOD"
+	DefaultOrphanGCGracePeriod                 = 3600
+	massDeletionThresholdCount                 = "MASS_DELETION_THRESHOLD_COUNT"
+	massDeletionThresholdPercent               = "MASS_DELETION_THRESHOLD_PERCENT"
+	cacheSnapshotEnabled                       = "CACHE_SNAPSHOT_ENABLED"
+	cacheSnapshotFileName                      = "ako-cache-snapshot.json"
 	ClusterNameLabelKey                        = "clustername"
 	UpdateStatus                               = "UpdateStatus"
 	DeleteStatus                               = "DeleteStatus"
@@ -153,6 +185,8 @@ const (
 	L4AdvPool                                  = "L4 Advance Pool"
 	L4PS                                       = "L4 Policyset"
 	L4PSRule                                   = "L4 Policyset Rule"
+	NetworkSecurityPolicy                      = "Network Security Policy"
+	HealthMonitor                              = "Health Monitor"
 	SNIVS                                      = "SNI VirtualService"
 	VIP                                        = "VS VIP"
 	PG                                         = "Poolgroup"
@@ -209,6 +243,8 @@ const (
 	AKOPause                 = "AKOPause"
 	DuplicateHostPath        = "DuplicateHostPath"
 	DuplicateHost            = "DuplicateHost"
+	DuplicateDefaultBackend  = "DuplicateDefaultBackend"
+	NoCommonSourceRange      = "NoCommonSourceRange"
 	Removed                  = "Removed"
 	Synced                   = "Synced"
 	Attached                 = "Attached"
@@ -218,6 +254,10 @@ const (
 	AKODeleteConfigDone      = "AKODeleteConfigDone"
 	AKODeleteConfigTimeout   = "AKODeleteConfigTimeout"
 	AKOGatewayEventComponent = "avi-kubernetes-operator-gateway-api"
+	AviObjectDrifted         = "AviObjectDrifted"
+	AviObjectDriftReverted   = "AviObjectDriftReverted"
+	MassDeletionHalted       = "MassDeletionHalted"
+	MassDeletionAllowed      = "MassDeletionAllowed"
 
 	DefaultIngressClassAnnotation  = "ingressclass.kubernetes.io/is-default-class"
 	ExternalDNSAnnotation          = "external-dns.alpha.kubernetes.io/hostname"
@@ -225,6 +265,7 @@ const (
 	IngressFinalizer               = "ingress.ako.vmware.com/finalizer"
 	AkoGroup                       = "ako.vmware.com"
 	AviIngressController           = "ako.vmware.com/avi-lb"
+	AviLoadBalancerClass           = AviIngressController // the default loadBalancerClass is the controller name of AKO
 	AKOConditionType               = "ako.vmware.com/ObjectDeletionInProgress"
 	DefaultSecretEnabled           = "ako.vmware.com/enable-tls"
 	GatewayNameLabelKey            = "service.route.lbapi.run.tanzu.vmware.com/gateway-name"
@@ -235,6 +276,7 @@ const (
 	SvcApiGatewayNamespaceLabelKey = "ako.vmware.com/gateway-namespace"
 	SvcApiAviGatewayController     = "ako.vmware.com/avi-lb"
 	NPLPodAnnotation               = "nodeportlocal.antrea.io"
+	PoolMemberReadyConditionType   = "ako.vmware.com/pool-member-ready"
 	NPLSvcAnnotation               = "nodeportlocal.antrea.io/enabled"
 	InfraSettingNameAnnotation     = "aviinfrasetting.ako.vmware.com/name"
 	SkipNodePortAnnotation         = "skipnodeport.ako.vmware.com/enabled"
@@ -248,7 +290,9 @@ const (
 	SharedVipSvcLBAnnotation       = "ako.vmware.com/enable-shared-vip"
 	LoadBalancerIP                 = "ako.vmware.com/load-balancer-ip"
 	LBSvcAppProfileAnnotation      = "ako.vmware.com/application-profile"
+	L7AppProtocolAnnotation        = "ako.vmware.com/enable-l7-app-protocol"
 	L4RuleAnnotation               = "ako.vmware.com/l4rule"
+	DriftPolicyAnnotation          = "ako.vmware.com/drift-policy"
 
 	// Specifies command used in namespace event handler
 	NsFilterAdd                    = "ADD"
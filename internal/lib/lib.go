@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
@@ -45,6 +46,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -136,6 +138,7 @@ func (c ServiceMetadataObj) ServiceMetadataMapping(objType string) ServiceMetada
 var RestOpPerKeyType *prometheus.CounterVec
 var TotalRestOp prometheus.Counter
 var ObjectsInQueue *prometheus.GaugeVec
+var DriftedObjects *prometheus.GaugeVec
 var reg *prometheus.Registry
 
 func SetPrometheusRegistry() {
@@ -187,6 +190,20 @@ func RegisterPromMetrics() *prometheus.Registry {
 		},
 	)
 	reg.MustRegister(ObjectsInQueue)
+
+	DriftedObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "drifted_objects",
+			Help:      "Number of Avi objects managed by AKO, which are modified or deleted out of band.",
+		},
+		[]string{
+			// Avi object type
+			"type",
+		},
+	)
+	reg.MustRegister(DriftedObjects)
 	return reg
 }
 
@@ -200,6 +217,11 @@ func DecrementQueueCounter(queueName string) {
 		ObjectsInQueue.With(prometheus.Labels{"queuename": queueName}).Dec()
 	}
 }
+func SetDriftedObjectsGauge(objType string, count int) {
+	if IsPrometheusEnabled() {
+		DriftedObjects.With(prometheus.Labels{"type": objType}).Set(float64(count))
+	}
+}
 func IncrementRestOpCouter(restOpMethod, objName string) {
 	if IsPrometheusEnabled() {
 		TotalRestOp.Inc()
@@ -256,6 +278,7 @@ func IsNameEncoded(name string) bool {
 var DisableSync bool
 var layer7Only bool
 var noPGForSNI bool
+var allowMassDeletion bool
 var NsxTTzType string
 var deleteConfigMap bool
 
@@ -331,6 +354,12 @@ func SetNoPGForSNI(val string) {
 	utils.AviLog.Infof("Setting the value for the noPGForSNI flag %v", noPGForSNI)
 }
 
+func SetAllowMassDeletion(val string) {
+	// The override is disabled unless it is explicitly set to true.
+	allowMassDeletion, _ = strconv.ParseBool(val)
+	utils.AviLog.Infof("Setting the value for the allowMassDeletion flag %v", allowMassDeletion)
+}
+
 func IsShardVS(vsName string) bool {
 	if deleteConfigMap {
 		//delete configmap is set, do not save anything
@@ -357,6 +386,10 @@ func GetNoPGForSNI() bool {
 	return noPGForSNI
 }
 
+func IsMassDeletionAllowed() bool {
+	return allowMassDeletion
+}
+
 func GetLayer7Only() bool {
 	return layer7Only
 }
@@ -465,6 +498,16 @@ func GetL7SharedPGName(vsName string) string {
 	return l7PGName
 }
 
+// GetL7DefaultBackendPoolName returns the name of the pool which serves the defaultBackend
+// of an ingress without hosts on a shared VS.
+func GetL7DefaultBackendPoolName(vsName string) string {
+	return Encode(vsName+"--default-backend", Pool)
+}
+
+func GetL7DefaultBackendPGName(vsName string) string {
+	return Encode(vsName+"--default-backend", PG)
+}
+
 func GetPassthroughPGName(hostname, infrasettingName string) string {
 	var pgName string
 	if infrasettingName != "" {
@@ -1354,18 +1397,24 @@ func PopulatePassthroughPoolMarkers(host, svcName, infrasettingName string) util
 
 func InformersToRegister(kclient *kubernetes.Clientset, oclient *oshiftclient.Clientset) ([]string, error) {
 	var isOshift bool
+	// Watch over EndpointSlices instead of Endpoints when the EndpointSlice based discovery is enabled.
+	endpointInformer := utils.EndpointInformer
+	if IsEndpointSliceEnabled() {
+		endpointInformer = utils.EndpointSliceInformer
+	}
 	// Initialize the following informers in all AKO deployments. Provide AKO the ability to watch over
 	// Services, Endpoints, Secrets, ConfigMaps and Namespaces.
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
+		endpointInformer,
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
 		utils.NSInformer,
 	}
 
-	// AKO must watch over Pods in case of NodePortLocal, to get Antrea annotation values.
-	if GetServiceType() == NodePortLocal {
+	// AKO must watch over Pods in case of NodePortLocal, to get Antrea annotation values,
+	// and to set the pool member readiness condition of the Pods.
+	if GetServiceType() == NodePortLocal || IsPodReadinessGateEnabled() {
 		allInformers = append(allInformers, utils.PodInformer)
 	}
 
@@ -1465,6 +1514,37 @@ func L4PolicyChecksum(ports []int64, protocols []string, ingestionMarkers utils.
 	return checksum
 }
 
+// NetworkSecurityPolicyChecksum computes the checksum of the source ranges of the
+// NetworkSecurityPolicy which AKO generates for a virtualservice.
+func NetworkSecurityPolicyChecksum(sourceRanges []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	ranges := make([]string, len(sourceRanges))
+	copy(ranges, sourceRanges)
+	sort.Strings(ranges)
+	checksum := utils.Hash(utils.Stringify(ranges))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+// HealthMonitorChecksum computes the checksum of the health monitor which AKO generates
+// to probe the healthCheckNodePort of a Service.
+func HealthMonitorChecksum(monitorPort int32, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(strconv.Itoa(int(monitorPort)))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
 func IsNodePortMode() bool {
 	nodePortType := os.Getenv(SERVICE_TYPE)
 	if nodePortType == NODE_PORT {
@@ -1488,6 +1568,155 @@ func AutoAnnotateNPLSvc() bool {
 	return false
 }
 
+// IsEndpointSliceEnabled returns true if the discovery/v1 EndpointSlices of the Services are watched instead of the
+// core/v1 Endpoints. In ClusterIP mode the pool servers are discovered from the EndpointSlices, and in NodePort mode
+// the nodes hosting the ready endpoints. Not applicable in NodePortLocal mode.
+func IsEndpointSliceEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(endpointSliceEnabled)); !ok {
+		return false
+	}
+	return GetServiceType() != NodePortLocal
+}
+
+// GetEndpointSliceServiceKey returns the namespace/name key of the Service owning the EndpointSlice,
+// and false for the EndpointSlices not managed for a Service.
+func GetEndpointSliceServiceKey(epSlice *discoveryv1.EndpointSlice) (string, bool) {
+	svcName, ok := epSlice.Labels[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return "", false
+	}
+	return epSlice.Namespace + "/" + svcName, true
+}
+
+// GetEndpointSliceZone returns the zone of the Service Engines, used to honor the topology
+// hints of the EndpointSlices.
+func GetEndpointSliceZone() string {
+	return os.Getenv(endpointSliceZone)
+}
+
+// IsDryRunEnabled returns true if AKO has to compute the rest operations for the models
+// without sending them to the Avi controller.
+func IsDryRunEnabled() bool {
+	ok, _ := strconv.ParseBool(os.Getenv(dryRunEnabled))
+	return ok
+}
+
+// IsIntrospectionEnabled returns true if the models and the cache of AKO have to be exposed
+// on the introspection APIs of the API server.
+func IsIntrospectionEnabled() bool {
+	ok, _ := strconv.ParseBool(os.Getenv(introspectionEnabled))
+	return ok
+}
+
+// GetLoadBalancerClass returns the spec.loadBalancerClass of the Services of type LoadBalancer
+// handled by AKO.
+func GetLoadBalancerClass() string {
+	if lbClass := os.Getenv(loadBalancerClass); lbClass != "" {
+		return lbClass
+	}
+	return AviLoadBalancerClass
+}
+
+// ClaimUnsetLoadBalancerClass returns true if AKO has to handle the Services of type LoadBalancer
+// which do not specify a spec.loadBalancerClass.
+func ClaimUnsetLoadBalancerClass() bool {
+	if ok, err := strconv.ParseBool(os.Getenv(claimUnsetLoadBalancerClass)); err == nil {
+		return ok
+	}
+	return true
+}
+
+// IsLoadBalancerClassAccepted returns true if the spec.loadBalancerClass of the Service
+// is handled by AKO, Services of other classes are left to the other LB controllers.
+func IsLoadBalancerClassAccepted(svcObj *corev1.Service) bool {
+	if svcObj.Spec.LoadBalancerClass == nil || *svcObj.Spec.LoadBalancerClass == "" {
+		return ClaimUnsetLoadBalancerClass()
+	}
+	return *svcObj.Spec.LoadBalancerClass == GetLoadBalancerClass()
+}
+
+// UseHealthCheckNodePort returns true if the pools of the Services with externalTrafficPolicy Local
+// have to be monitored on the healthCheckNodePort of the Service, applicable only in NodePort mode.
+func UseHealthCheckNodePort() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(useHealthCheckNodePort)); !ok {
+		return false
+	}
+	return IsNodePortMode()
+}
+
+// IsPodReadinessGateEnabled returns true if the pool member readiness condition of the Pods carrying the
+// readiness gate has to be set by AKO. Not applicable in NodePort mode, where the pool servers are the nodes.
+func IsPodReadinessGateEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(podReadinessGateEnabled)); !ok {
+		return false
+	}
+	return !IsNodePortMode()
+}
+
+// GetDriftDetectionPolicy returns the default policy applied by AKO to the Avi objects modified or deleted
+// out of band, either report or revert. An empty string is returned if the drift detection is disabled.
+func GetDriftDetectionPolicy() string {
+	return ParseDriftPolicy(os.Getenv(driftDetectionPolicy))
+}
+
+// ParseDriftPolicy returns the drift policy set in the input string, or an empty string if the drift
+// detection is disabled or the input is not a valid drift policy.
+func ParseDriftPolicy(policy string) string {
+	switch policy = strings.ToLower(strings.TrimSpace(policy)); policy {
+	case DriftPolicyReport, DriftPolicyRevert:
+		return policy
+	}
+	return ""
+}
+
+// IsOrphanGCEnabled returns true if AKO has to report the orphan Avi objects created for the cluster, and
+// delete them once they are orphan for the grace period.
+func IsOrphanGCEnabled() bool {
+	ok, _ := strconv.ParseBool(os.Getenv(orphanGCEnabled))
+	return ok
+}
+
+// GetOrphanGCGracePeriod returns the duration for which an Avi object has to stay orphan before it is deleted.
+func GetOrphanGCGracePeriod() time.Duration {
+	gracePeriod, err := strconv.Atoi(os.Getenv(orphanGCGracePeriod))
+	if err != nil || gracePeriod <= 0 {
+		gracePeriod = DefaultOrphanGCGracePeriod
+	}
+	return time.Duration(gracePeriod) * time.Second
+}
+
+// GetMassDeletionThresholdCount returns the number of virtualservices above which the deletions of a sync are
+// halted. The threshold is disabled if it is not set to a positive number.
+func GetMassDeletionThresholdCount() int {
+	threshold, err := strconv.Atoi(os.Getenv(massDeletionThresholdCount))
+	if err != nil || threshold < 0 {
+		return 0
+	}
+	return threshold
+}
+
+// GetMassDeletionThresholdPercent returns the percentage of the cached virtualservices above which the deletions
+// of a sync are halted. The threshold is disabled if it is not set to a number between 1 and 100.
+func GetMassDeletionThresholdPercent() int {
+	threshold, err := strconv.Atoi(os.Getenv(massDeletionThresholdPercent))
+	if err != nil || threshold < 0 || threshold > 100 {
+		return 0
+	}
+	return threshold
+}
+
+// IsCacheSnapshotEnabled returns true if the Avi object cache has to be persisted on the PVC mounted for AKO, to
+// warm start the cache on the restarts of AKO.
+func IsCacheSnapshotEnabled() bool {
+	ok, _ := strconv.ParseBool(os.Getenv(cacheSnapshotEnabled))
+	return ok && os.Getenv("USE_PVC") == "true"
+}
+
+// GetCacheSnapshotFilePath returns the path of the file on the PVC, in which the Avi object cache is persisted.
+func GetCacheSnapshotFilePath() string {
+	return filepath.Join(os.Getenv("LOG_FILE_PATH"), strings.TrimLeft(os.Getenv("POD_NAME")+".", ".")+cacheSnapshotFileName)
+}
+
 func GetNodePortsSelector() map[string]string {
 	nodePortsSelectorLabels := make(map[string]string)
 	if IsNodePortMode() {
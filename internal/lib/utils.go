@@ -53,8 +53,9 @@ func ExtractTypeNameNamespace(key string) (string, string, string) {
 
 func isServiceLBType(svcObj *corev1.Service) bool {
 	// If we don't find a service or it is not of type loadbalancer - return false.
+	// Services of type loadbalancer with a loadBalancerClass of another LB controller are skipped as well.
 	if svcObj.Spec.Type == "LoadBalancer" {
-		return true
+		return IsLoadBalancerClassAccepted(svcObj)
 	}
 	return false
 }
@@ -177,6 +178,10 @@ func GetServicesForPod(pod *corev1.Pod) ([]string, []string) {
 		if !matchSvcSelectorPodLabels(svc.Spec.Selector, pod.GetLabels()) {
 			continue
 		}
+		// The Services of type loadbalancer with a loadBalancerClass of another LB controller are not handled by AKO.
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && !IsLoadBalancerClassAccepted(svc) {
+			continue
+		}
 		svcKey := svc.Namespace + "/" + svc.Name
 		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
 			lbList = append(lbList, svcKey)
@@ -188,6 +193,40 @@ func GetServicesForPod(pod *corev1.Pod) ([]string, []string) {
 	return svcList, lbList
 }
 
+// HasPoolMemberReadinessGate returns true if the Pod carries the readiness gate for the pool member readiness condition.
+func HasPoolMemberReadinessGate(pod *corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == PoolMemberReadyConditionType {
+			return true
+		}
+	}
+	return false
+}
+
+func IsPoolMemberReadyConditionTrue(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == PoolMemberReadyConditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsPodPendingPoolMemberReadiness returns true if the containers of a Pod carrying the readiness gate are ready,
+// and the Pod waits only for its pool member readiness condition. Such Pods are added as the pool servers even
+// though their endpoints are not ready yet.
+func IsPodPendingPoolMemberReadiness(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || !HasPoolMemberReadinessGate(pod) || IsPoolMemberReadyConditionTrue(pod) {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.ContainersReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func matchSvcSelectorPodLabels(svcSelector, podLabel map[string]string) bool {
 	if len(svcSelector) == 0 {
 		return false
@@ -16,6 +16,7 @@ package nodes
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -132,7 +133,7 @@ func (o *AviObjectGraph) ConstructAdvL4VsNode(gatewayName, namespace, key string
 	avi_vs_meta.PortProto = portProtocols
 	avi_vs_meta.ApplicationProfile = utils.DEFAULT_L4_APP_PROFILE
 
-	avi_vs_meta.NetworkProfile = getNetworkProfile(isSCTP, isTCP, isUDP)
+	avi_vs_meta.NetworkProfile = GetNetworkProfile(isSCTP, isTCP, isUDP)
 
 	vsVipNode := &AviVSVIPNode{
 		Name:        lib.GetL4VSVipName(gatewayName, namespace),
@@ -262,7 +263,7 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 	avi_vs_meta.PortProto = portProtocols
 	avi_vs_meta.ApplicationProfile = utils.DEFAULT_L4_APP_PROFILE
 
-	avi_vs_meta.NetworkProfile = getNetworkProfile(isSCTP, isTCP, isUDP)
+	avi_vs_meta.NetworkProfile = GetNetworkProfile(isSCTP, isTCP, isUDP)
 
 	vsVipNode := &AviVSVIPNode{
 		Name:        lib.GetL4VSVipName(gatewayName, namespace),
@@ -488,6 +489,7 @@ func (o *AviObjectGraph) ConstructSharedVipSvcLBNode(sharedVipKey, namespace, ke
 	avi_vs_meta.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(namespace, sharedVipKey)
 	var portProtocols []AviPortHostProtocol
 	var sharedPreferredVIP string
+	var svcObjs []*v1.Service
 	var serviceObject *v1.Service
 	for i, serviceNSName := range serviceNSNames {
 		svcNSName := strings.Split(serviceNSName, "/")
@@ -511,6 +513,8 @@ func (o *AviObjectGraph) ConstructSharedVipSvcLBNode(sharedVipKey, namespace, ke
 			}
 		}
 
+		svcObjs = append(svcObjs, svcObj)
+
 		for _, listener := range svcObj.Spec.Ports {
 			protocol := string(listener.Protocol)
 			pp := AviPortHostProtocol{Port: listener.Port, Protocol: protocol}
@@ -533,7 +537,7 @@ func (o *AviObjectGraph) ConstructSharedVipSvcLBNode(sharedVipKey, namespace, ke
 	avi_vs_meta.PortProto = portProtocols
 	avi_vs_meta.ApplicationProfile = utils.DEFAULT_L4_APP_PROFILE
 
-	avi_vs_meta.NetworkProfile = getNetworkProfile(isSCTP, isTCP, isUDP)
+	avi_vs_meta.NetworkProfile = GetNetworkProfile(isSCTP, isTCP, isUDP)
 
 	vsVipNode := &AviVSVIPNode{
 		Name:        lib.GetL4VSVipName(sharedVipKey, namespace),
@@ -573,12 +577,87 @@ func (o *AviObjectGraph) ConstructSharedVipSvcLBNode(sharedVipKey, namespace, ke
 		}
 	}
 
+	// The networkSecurityPolicyRef of the L4Rule takes precedence over the loadBalancerSourceRanges.
+	// The Services without common source ranges are rejected by the shared vip validation.
+	if sourceRanges, restricted := getSharedVipSourceRanges(svcObjs); avi_vs_meta.NetworkSecurityPolicyRef == nil && restricted {
+		var ranges []string
+		for _, sourceRange := range sourceRanges {
+			ranges = append(ranges, sourceRange.String())
+		}
+		if nspNode := BuildNetworkSecurityPolicyNode(vsName, ranges, avi_vs_meta.AviMarkers, key); nspNode != nil {
+			avi_vs_meta.NetworkSecurityPolicyRefs = []*AviNetworkSecurityPolicyNode{nspNode}
+		}
+	}
+
 	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
 
 	o.ConstructSharedVipPolPoolNodes(avi_vs_meta, sharedVipKey, namespace, key)
 	return avi_vs_meta
 }
 
+// getSharedVipSourceRanges returns the source ranges which are allowed by all the Services of a shared vip.
+// The Services without valid loadBalancerSourceRanges do not restrict the clients, restricted is false when
+// none of the Services restricts the clients.
+func getSharedVipSourceRanges(svcObjs []*v1.Service) (sourceRanges []*net.IPNet, restricted bool) {
+	for _, svcObj := range svcObjs {
+		svcRanges := parseSourceRanges(svcObj.Spec.LoadBalancerSourceRanges)
+		if len(svcRanges) == 0 {
+			continue
+		}
+		if !restricted {
+			sourceRanges = svcRanges
+		} else {
+			sourceRanges = intersectSourceRanges(sourceRanges, svcRanges)
+		}
+		restricted = true
+	}
+	return sourceRanges, restricted
+}
+
+// parseSourceRanges returns the valid CIDRs of the loadBalancerSourceRanges, the invalid ones are ignored.
+func parseSourceRanges(sourceRanges []string) []*net.IPNet {
+	var ipNets []*net.IPNet
+	for _, sourceRange := range sourceRanges {
+		if _, ipNet, err := net.ParseCIDR(strings.TrimSpace(sourceRange)); err == nil {
+			ipNets = append(ipNets, ipNet)
+		}
+	}
+	return ipNets
+}
+
+// intersectSourceRanges returns the CIDRs which are covered by both the lists. When a CIDR of
+// one list contains a CIDR of the other list, the narrower CIDR is kept.
+func intersectSourceRanges(ranges, otherRanges []*net.IPNet) []*net.IPNet {
+	var intersection []*net.IPNet
+	for _, ipNet := range ranges {
+		ones, bits := ipNet.Mask.Size()
+		for _, otherIPNet := range otherRanges {
+			otherOnes, otherBits := otherIPNet.Mask.Size()
+			if bits != otherBits {
+				continue
+			}
+			narrower := ipNet
+			if otherOnes > ones {
+				narrower = otherIPNet
+			}
+			if !ipNet.Contains(narrower.IP) || !otherIPNet.Contains(narrower.IP) {
+				continue
+			}
+			found := false
+			for _, n := range intersection {
+				if n.String() == narrower.String() {
+					found = true
+					break
+				}
+			}
+			if !found {
+				intersection = append(intersection, narrower)
+			}
+		}
+	}
+	return intersection
+}
+
 func (o *AviObjectGraph) ConstructSharedVipPolPoolNodes(vsNode *AviVsNode, sharedVipKey, namespace, key string) {
 	namespacedShareVipKey := namespace + "/" + sharedVipKey
 	found, serviceNSNames := objects.SharedlbLister().GetSharedVipKeyToServices(namespacedShareVipKey)
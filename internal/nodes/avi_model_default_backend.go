@@ -0,0 +1,295 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+var defaultBackendListerInstance *DefaultBackendLister
+var dbonce sync.Once
+
+// SharedDefaultBackendLister keeps the ingresses without hosts which declare a defaultBackend,
+// along with the shared VSes serving them, to resolve the conflicts between such ingresses.
+func SharedDefaultBackendLister() *DefaultBackendLister {
+	dbonce.Do(func() {
+		defaultBackendListerInstance = &DefaultBackendLister{
+			ingressToVSStore: objects.NewObjectMapStore(),
+			vsToIngressStore: objects.NewObjectMapStore(),
+		}
+	})
+	return defaultBackendListerInstance
+}
+
+type DefaultBackendLister struct {
+	sync.RWMutex
+
+	// namespace/ingress -> shared VS names
+	ingressToVSStore *objects.ObjectMapStore
+
+	// shared VS name -> namespace/ingresses
+	vsToIngressStore *objects.ObjectMapStore
+}
+
+func (d *DefaultBackendLister) GetIngressToVS(ingress string) []string {
+	d.RLock()
+	defer d.RUnlock()
+	found, vsNames := d.ingressToVSStore.Get(ingress)
+	if !found {
+		return nil
+	}
+	return vsNames.([]string)
+}
+
+func (d *DefaultBackendLister) GetVSToIngresses(vsName string) []string {
+	d.RLock()
+	defer d.RUnlock()
+	found, ingresses := d.vsToIngressStore.Get(vsName)
+	if !found {
+		return nil
+	}
+	return ingresses.([]string)
+}
+
+// UpdateIngressToVS replaces the shared VSes serving the defaultBackend of an ingress.
+func (d *DefaultBackendLister) UpdateIngressToVS(ingress string, vsNames []string) {
+	d.Lock()
+	defer d.Unlock()
+	if found, oldVSNames := d.ingressToVSStore.Get(ingress); found {
+		for _, vsName := range oldVSNames.([]string) {
+			if found, ingresses := d.vsToIngressStore.Get(vsName); found {
+				ingresses := utils.Remove(ingresses.([]string), ingress)
+				if len(ingresses) == 0 {
+					d.vsToIngressStore.Delete(vsName)
+				} else {
+					d.vsToIngressStore.AddOrUpdate(vsName, ingresses)
+				}
+			}
+		}
+	}
+	if len(vsNames) == 0 {
+		d.ingressToVSStore.Delete(ingress)
+		return
+	}
+	d.ingressToVSStore.AddOrUpdate(ingress, vsNames)
+	for _, vsName := range vsNames {
+		var ingresses []string
+		if found, obj := d.vsToIngressStore.Get(vsName); found {
+			ingresses = obj.([]string)
+		}
+		if !utils.HasElem(ingresses, ingress) {
+			ingresses = append(ingresses, ingress)
+		}
+		d.vsToIngressStore.AddOrUpdate(vsName, ingresses)
+	}
+}
+
+// ProcessDefaultBackend sets the defaultBackend of an ingress without hosts as the default pool group of
+// the shared VSes, to serve the requests which don't match any host of the shared VSes. The hosts of
+// an ingress get its defaultBackend as a path, while parsing the ingress.
+func ProcessDefaultBackend(routeIgrObj RouteIngressModel, key string, parsedIng IngressConfig, hostsMap map[string]*objects.RouteIngrhost, modelList *[]string) {
+	if routeIgrObj.GetType() != utils.Ingress {
+		return
+	}
+	ingress := routeIgrObj.GetNamespace() + "/" + routeIgrObj.GetName()
+	oldVSNames := SharedDefaultBackendLister().GetIngressToVS(ingress)
+
+	var vsNames []string
+	if routeIgrObj.Exists() && parsedIng.DefaultBackend != nil && len(hostsMap) == 0 {
+		vsNames = getSharedVSNamesForDefaultBackend(routeIgrObj, key)
+	}
+	if len(oldVSNames) == 0 && len(vsNames) == 0 {
+		return
+	}
+	SharedDefaultBackendLister().UpdateIngressToVS(ingress, vsNames)
+
+	vsNamesToProcess := append(lib.Difference(oldVSNames, vsNames), vsNames...)
+	for _, vsName := range vsNamesToProcess {
+		modelName := lib.GetModelName(lib.GetTenant(), vsName)
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		defaultBackendIngress := getDefaultBackendIngress(vsName)
+		if !found || aviModel == nil {
+			if defaultBackendIngress == nil || !routeIgrObj.Exists() {
+				continue
+			}
+			utils.AviLog.Infof("key: %s, msg: model not found, generating new model with name: %s", key, modelName)
+			aviModel = NewAviObjectGraph()
+			if lib.IsEvhEnabled() {
+				aviModel.(*AviObjectGraph).ConstructAviL7SharedVsNodeForEvh(vsName, key, routeIgrObj, false, false)
+			} else {
+				aviModel.(*AviObjectGraph).ConstructAviL7VsNode(vsName, key, routeIgrObj, false, false)
+			}
+		}
+		if defaultBackendIngress != nil && defaultBackendIngress.Namespace+"/"+defaultBackendIngress.Name != ingress && utils.HasElem(vsNames, vsName) {
+			utils.AviLog.Warnf("key: %s, msg: defaultBackend of the ingress %s is not applied to the VS %s, the defaultBackend of the ingress %s/%s is used",
+				key, ingress, vsName, defaultBackendIngress.Namespace, defaultBackendIngress.Name)
+			if ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(routeIgrObj.GetNamespace()).Get(routeIgrObj.GetName()); err == nil {
+				lib.AKOControlConfig().EventRecorder().Eventf(ingObj, corev1.EventTypeWarning, lib.DuplicateDefaultBackend,
+					"defaultBackend is not applied to the VS %s, the defaultBackend of the ingress %s/%s is used", vsName, defaultBackendIngress.Namespace, defaultBackendIngress.Name)
+			}
+		}
+		aviModel.(*AviObjectGraph).BuildDefaultBackendPoolGroup(vsName, defaultBackendIngress, key)
+		changedModel := saveAviModel(modelName, aviModel.(*AviObjectGraph), key)
+		if !utils.HasElem(modelList, modelName) && changedModel {
+			*modelList = append(*modelList, modelName)
+		}
+	}
+}
+
+// getSharedVSNamesForDefaultBackend returns all the shared VSes of the shard group of the ingress.
+// The dedicated VSes only serve their own hosts, hence no VS is returned for a shard size of 0.
+func getSharedVSNamesForDefaultBackend(routeIgrObj RouteIngressModel, key string) []string {
+	infraSetting := routeIgrObj.GetAviInfraSetting()
+	shardSize := lib.GetShardSizeFromAviInfraSetting(infraSetting)
+	if shardSize == 0 {
+		utils.AviLog.Warnf("key: %s, msg: defaultBackend of an ingress without hosts is not supported with dedicated VSes", key)
+		return nil
+	}
+
+	var shardVsPrefix string
+	if lib.IsEvhEnabled() {
+		shardVsPrefix = lib.GetNamePrefix() + lib.GetAKOIDPrefix() + lib.ShardEVHVSPrefix
+	} else {
+		shardVsPrefix = GetShardVSPrefix(key)
+	}
+	if infraSetting != nil {
+		shardVsPrefix += infraSetting.Name + "-"
+	}
+	if lib.IsEvhEnabled() && lib.VIPPerNamespace() {
+		return []string{shardVsPrefix + "NS-" + routeIgrObj.GetNamespace()}
+	}
+
+	vsNames := make([]string, 0, shardSize)
+	for i := 0; i < int(shardSize); i++ {
+		vsNames = append(vsNames, shardVsPrefix+strconv.Itoa(i))
+	}
+	return vsNames
+}
+
+// getDefaultBackendIngress returns the ingress whose defaultBackend is applied to a shared VS,
+// the oldest ingress wins when several ingresses declare one.
+func getDefaultBackendIngress(vsName string) *networkingv1.Ingress {
+	var defaultBackendIngress *networkingv1.Ingress
+	for _, ingress := range SharedDefaultBackendLister().GetVSToIngresses(vsName) {
+		namespace, name, found := strings.Cut(ingress, "/")
+		if !found {
+			continue
+		}
+		ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(name)
+		if err != nil || ingObj.GetDeletionTimestamp() != nil ||
+			ingObj.Spec.DefaultBackend == nil || ingObj.Spec.DefaultBackend.Service == nil {
+			continue
+		}
+		if defaultBackendIngress == nil || isOlderIngress(ingObj, defaultBackendIngress) {
+			defaultBackendIngress = ingObj
+		}
+	}
+	return defaultBackendIngress
+}
+
+func isOlderIngress(ingress, otherIngress *networkingv1.Ingress) bool {
+	if !ingress.CreationTimestamp.Equal(&otherIngress.CreationTimestamp) {
+		return ingress.CreationTimestamp.Before(&otherIngress.CreationTimestamp)
+	}
+	return ingress.Namespace+"/"+ingress.Name < otherIngress.Namespace+"/"+otherIngress.Name
+}
+
+// BuildDefaultBackendPoolGroup sets the defaultBackend of the ingress as the default pool group of the
+// shared VS, the default pool group is removed when the ingress is nil.
+func (o *AviObjectGraph) BuildDefaultBackendPoolGroup(vsName string, ingress *networkingv1.Ingress, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+
+	var vsNode AviVsEvhSniModel
+	if lib.IsEvhEnabled() {
+		if evhVsNode := o.GetAviEvhVS(); len(evhVsNode) == 1 {
+			vsNode = evhVsNode[0]
+		}
+	} else if l7VsNode := o.GetAviVS(); len(l7VsNode) == 1 {
+		vsNode = l7VsNode[0]
+	}
+	if vsNode == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find the shared VS %s in the model", key, vsName)
+		return
+	}
+
+	poolName := lib.GetL7DefaultBackendPoolName(vsName)
+	pgName := lib.GetL7DefaultBackendPGName(vsName)
+	var poolRefs []*AviPoolNode
+	for _, poolNode := range vsNode.GetPoolRefs() {
+		if poolNode.Name != poolName {
+			poolRefs = append(poolRefs, poolNode)
+		}
+	}
+	var poolGroupRefs []*AviPoolGroupNode
+	for _, pgNode := range vsNode.GetPoolGroupRefs() {
+		if pgNode.Name != pgName {
+			poolGroupRefs = append(poolGroupRefs, pgNode)
+		}
+	}
+	setDefaultPoolGroup(vsNode, "")
+
+	if ingress != nil {
+		validator := NewNodesValidator()
+		backend := validator.parseIngressServiceBackend(ingress.Namespace, "/", networkingv1.PathTypePrefix, ingress.Spec.DefaultBackend.Service, key)
+		infraSetting, err := getL7IngressInfraSetting(key, utils.String(ingress.Spec.IngressClassName), ingress.Namespace)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error while fetching the AviInfraSetting of the ingress %s/%s: %v", key, ingress.Namespace, ingress.Name, err)
+		}
+		poolNode := buildPoolNode(key, poolName, ingress.Name, ingress.Namespace, "", "", infraSetting, "", nil, false, backend)
+		poolNode.AttachedWithSharedVS = true
+		poolRefs = append(poolRefs, poolNode)
+
+		poolRef := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+		ratio := poolNode.ServiceMetadata.PoolRatio
+		pgNode := &AviPoolGroupNode{
+			Name:               pgName,
+			Tenant:             lib.GetTenant(),
+			AttachedToSharedVS: true,
+			Members:            []*avimodels.PoolGroupMember{{PoolRef: &poolRef, Ratio: &ratio}},
+		}
+		poolGroupRefs = append(poolGroupRefs, pgNode)
+		setDefaultPoolGroup(vsNode, pgName)
+		utils.AviLog.Infof("key: %s, msg: defaultBackend of the ingress %s/%s is set as the default pool group of the VS %s", key, ingress.Namespace, ingress.Name, vsName)
+	}
+	vsNode.SetPoolRefs(poolRefs)
+	vsNode.SetPoolGroupRefs(poolGroupRefs)
+}
+
+func setDefaultPoolGroup(vsNode AviVsEvhSniModel, pgName string) {
+	switch node := vsNode.(type) {
+	case *AviEvhVsNode:
+		node.DefaultPoolGroup = pgName
+	case *AviVsNode:
+		node.DefaultPoolGroup = pgName
+	}
+}
+
+// isDefaultBackendPool checks if the pool serves the defaultBackend of an ingress on the shared VS,
+// such pools are not members of the shared pool group selected by the priority labels.
+func isDefaultBackendPool(vsName string, poolNode *AviPoolNode) bool {
+	return poolNode.Name == lib.GetL7DefaultBackendPoolName(vsName)
+}
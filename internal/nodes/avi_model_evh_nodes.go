@@ -100,6 +100,9 @@ type AviVsEvhSniModel interface {
 	GetVHDomainNames() []string
 	SetVHDomainNames([]string)
 
+	GetNetworkSecurityPolicyRefs() []*AviNetworkSecurityPolicyNode
+	SetNetworkSecurityPolicyRefs([]*AviNetworkSecurityPolicyNode)
+
 	GetGeneratedFields() *AviVsNodeGeneratedFields
 	GetCommonFields() *AviVsNodeCommonFields
 }
@@ -112,37 +115,40 @@ type AviEvhVsNode struct {
 	EvhHostName   string
 	AviMarkers    utils.AviObjectMarkers
 	// props from avi vs node
-	Name                string
-	Tenant              string
-	ServiceEngineGroup  string
-	ApplicationProfile  string
-	NetworkProfile      string
-	EnableRhi           *bool
-	Enabled             *bool
-	PortProto           []AviPortHostProtocol // for listeners
-	DefaultPool         string
-	CloudConfigCksum    uint32
-	DefaultPoolGroup    string
-	HTTPChecksum        uint32
-	PoolGroupRefs       []*AviPoolGroupNode
-	PoolRefs            []*AviPoolNode
-	HTTPDSrefs          []*AviHTTPDataScriptNode
-	SharedVS            bool
-	CACertRefs          []*AviTLSKeyCertNode
-	SSLKeyCertRefs      []*AviTLSKeyCertNode
-	HttpPolicyRefs      []*AviHttpPolicySetNode
-	VSVIPRefs           []*AviVSVIPNode
-	TLSType             string
-	ServiceMetadata     lib.ServiceMetadataObj
-	VrfContext          string
-	ICAPProfileRefs     []string
-	ErrorPageProfileRef string
-	HttpPolicySetRefs   []string
-	Paths               []string
-	IngressNames        []string
-	Dedicated           bool
-	VHMatches           []*avimodels.VHMatch
-	Secure              bool
+	Name                      string
+	Tenant                    string
+	ServiceEngineGroup        string
+	ApplicationProfile        string
+	NetworkProfile            string
+	EnableRhi                 *bool
+	Enabled                   *bool
+	PortProto                 []AviPortHostProtocol // for listeners
+	DefaultPool               string
+	CloudConfigCksum          uint32
+	DefaultPoolGroup          string
+	HTTPChecksum              uint32
+	PoolGroupRefs             []*AviPoolGroupNode
+	PoolRefs                  []*AviPoolNode
+	HTTPDSrefs                []*AviHTTPDataScriptNode
+	SharedVS                  bool
+	CACertRefs                []*AviTLSKeyCertNode
+	SSLKeyCertRefs            []*AviTLSKeyCertNode
+	HttpPolicyRefs            []*AviHttpPolicySetNode
+	VSVIPRefs                 []*AviVSVIPNode
+	NetworkSecurityPolicyRefs []*AviNetworkSecurityPolicyNode
+	TLSType                   string
+	ServiceMetadata           lib.ServiceMetadataObj
+	VrfContext                string
+	ICAPProfileRefs           []string
+	ErrorPageProfileRef       string
+	HttpPolicySetRefs         []string
+	Paths                     []string
+	IngressNames              []string
+	Dedicated                 bool
+	VHMatches                 []*avimodels.VHMatch
+	Secure                    bool
+	// SidebandProfile sends a copy of the requests of the child VS to the sideband servers.
+	SidebandProfile *avimodels.SidebandProfile
 
 	AviVsNodeCommonFields
 
@@ -328,6 +334,14 @@ func (v *AviEvhVsNode) SetVHDomainNames(domainNames []string) {
 	v.VHDomainNames = domainNames
 }
 
+func (v *AviEvhVsNode) GetNetworkSecurityPolicyRefs() []*AviNetworkSecurityPolicyNode {
+	return v.NetworkSecurityPolicyRefs
+}
+
+func (v *AviEvhVsNode) SetNetworkSecurityPolicyRefs(nspRefs []*AviNetworkSecurityPolicyNode) {
+	v.NetworkSecurityPolicyRefs = nspRefs
+}
+
 func (v *AviEvhVsNode) GetGeneratedFields() *AviVsNodeGeneratedFields {
 	return &v.AviVsNodeGeneratedFields
 }
@@ -621,6 +635,9 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 	for _, vsvipref := range v.VSVIPRefs {
 		checksumStringSlice = append(checksumStringSlice, "VSVIP"+vsvipref.Name)
 	}
+	for _, nsp := range v.NetworkSecurityPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, "NetworkSecurityPolicy"+nsp.Name)
+	}
 	for _, vhdomain := range v.VHDomainNames {
 		checksumStringSlice = append(checksumStringSlice, "VHDomain"+vhdomain)
 	}
@@ -678,6 +695,10 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 		vsRefs += utils.Stringify(sslKeyAndCertificateRefs)
 	}
 
+	if v.SidebandProfile != nil {
+		vsRefs += utils.Stringify(v.SidebandProfile)
+	}
+
 	sort.Strings(checksumStringSlice)
 	checksum := utils.Hash(strings.Join(checksumStringSlice, delim) +
 		v.ApplicationProfile +
@@ -934,6 +955,8 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 		if tlsSettings != nil && tlsSettings.reencrypt {
 			o.BuildPoolSecurity(poolNode, *tlsSettings, key, poolNode.AviMarkers)
 		}
+		appProtocol := getServicePortAppProtocol(namespace, path.ServiceName, path.PortName, path.Port, path.TargetPort)
+		buildPoolWithAppProtocol(key, poolNode, appProtocol)
 
 		serviceType := lib.GetServiceType()
 		if serviceType == lib.NodePortLocal {
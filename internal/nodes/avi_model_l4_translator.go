@@ -34,7 +34,9 @@ import (
 	"github.com/vmware/alb-sdk/go/models"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -116,7 +118,7 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		avi_vs_meta.ApplicationProfile = utils.DEFAULT_L4_APP_PROFILE
 	}
 
-	avi_vs_meta.NetworkProfile = getNetworkProfile(isSCTP, isTCP, isUDP)
+	avi_vs_meta.NetworkProfile = GetNetworkProfile(isSCTP, isTCP, isUDP)
 
 	vsVipName := lib.GetL4VSVipName(svcObj.ObjectMeta.Name, svcObj.ObjectMeta.Namespace)
 	vsVipNode := &AviVSVIPNode{
@@ -142,6 +144,13 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		buildWithL4Rule(key, avi_vs_meta, l4Rule)
 	}
 
+	// The networkSecurityPolicyRef of the L4Rule takes precedence over the loadBalancerSourceRanges.
+	if avi_vs_meta.NetworkSecurityPolicyRef == nil && len(svcObj.Spec.LoadBalancerSourceRanges) > 0 {
+		if nspNode := BuildNetworkSecurityPolicyNode(vsName, svcObj.Spec.LoadBalancerSourceRanges, avi_vs_meta.AviMarkers, key); nspNode != nil {
+			avi_vs_meta.NetworkSecurityPolicyRefs = []*AviNetworkSecurityPolicyNode{nspNode}
+		}
+	}
+
 	if lib.HasSpecLoadBalancerIP(svcObj) {
 		vsVipNode.IPAddress = svcObj.Spec.LoadBalancerIP
 	} else if lib.HasLoadBalancerIPAnnotation(svcObj) {
@@ -170,6 +179,8 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 		}
 	}
 
+	appProtocol := buildL4VsWithAppProtocol(key, svcObj, vsNode, isSSLEnabled)
+
 	protocolSet := sets.NewString()
 	for _, portProto := range vsNode.PortProto {
 		filterPort := portProto.Port
@@ -183,6 +194,11 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 			VrfContext: lib.GetVrf(),
 		}
 
+		// The TLS of https and wss is passed through by the L4 virtualservice, so the traffic is not re-encrypted
+		// towards the servers. HTTP/2 is enabled only if the virtualservice is served with the HTTP application profile.
+		if appProtocol != "" {
+			poolNode.EnableHttp2 = true
+		}
 		buildPoolWithL4Rule(key, poolNode, l4Rule)
 
 		if lib.IsIstioEnabled() {
@@ -209,6 +225,7 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 		}
 
 		serviceType := lib.GetServiceType()
+		nodePortServers := false
 		if serviceType == lib.NodePortLocal {
 			if svcObj.Spec.Type == "NodePort" {
 				utils.AviLog.Warnf("key: %s, msg: Service of type NodePort is not supported when `serviceType` is NodePortLocal.", key)
@@ -226,6 +243,7 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 			if servers := PopulateServersForNodePort(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key); servers != nil {
 				poolNode.Servers = servers
 			}
+			nodePortServers = true
 		} else {
 			if servers := PopulateServers(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key); servers != nil {
 				poolNode.Servers = servers
@@ -239,14 +257,20 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 
 		buildPoolWithInfraSetting(key, poolNode, infraSetting)
 
-		if isSSLEnabled {
+		if nodePortServers {
+			if hmNode := buildHealthCheckNodePortMonitor(key, svcObj, poolNode); hmNode != nil {
+				vsNode.HealthMonitorNodeRefs = append(vsNode.HealthMonitorNodeRefs, hmNode)
+			}
+		}
+
+		if isSSLEnabled || appProtocol != "" {
 			vsNode.DefaultPool = poolNode.Name
 		}
 		vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
 		utils.AviLog.Infof("key: %s, msg: evaluated L4 pool values :%v", key, utils.Stringify(poolNode))
 	}
 
-	if !isSSLEnabled {
+	if !isSSLEnabled && appProtocol == "" {
 		l4policyNode := &AviL4PolicyNode{Name: vsNode.Name, Tenant: lib.GetTenant(), PortPool: portPoolSet}
 		sort.Strings(protocolSet.List())
 		protocols := strings.Join(protocolSet.List(), ",")
@@ -261,6 +285,41 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 	}
 }
 
+// buildL4VsWithAppProtocol selects the application profile of the L4 virtualservice as per the appProtocol of the
+// Service port, and returns the appProtocol if the virtualservice is served with the HTTP application profile.
+// The HTTP application profile, which turns the virtualservice into an L7 virtualservice, is used only for the
+// Services which opt in with the enable-l7-app-protocol annotation, and have a single TCP port speaking HTTP/2
+// over cleartext or gRPC, over the TCP proxy network profile. Such virtualservices have a default pool, as the L4
+// policies apply only to the L4 application profile. All the other Services keep the L4 application profile, which
+// passes the traffic through as is. The application profile annotation and the L4Rule take precedence.
+func buildL4VsWithAppProtocol(key string, svcObj *corev1.Service, vsNode *AviVsNode, isSSLEnabled bool) string {
+	if enabled, _ := strconv.ParseBool(svcObj.GetAnnotations()[lib.L7AppProtocolAnnotation]); !enabled {
+		return ""
+	}
+	if appProfile := svcObj.GetAnnotations()[lib.LBSvcAppProfileAnnotation]; appProfile != "" {
+		return ""
+	}
+	if isSSLEnabled || vsNode.ApplicationProfileRef != nil || vsNode.NetworkProfile != utils.DEFAULT_TCP_NW_PROFILE {
+		return ""
+	}
+	if len(svcObj.Spec.Ports) != 1 || len(vsNode.PortProto) != 1 || vsNode.PortProto[0].Protocol != utils.TCP {
+		return ""
+	}
+	if svcObj.Spec.Ports[0].AppProtocol == nil {
+		return ""
+	}
+	appProtocol := *svcObj.Spec.Ports[0].AppProtocol
+	switch appProtocol {
+	case lib.AppProtocolH2C, lib.AppProtocolGRPC:
+		vsNode.PortProto[0].EnableHTTP2 = true
+	default:
+		return ""
+	}
+	vsNode.ApplicationProfile = utils.DEFAULT_L7_APP_PROFILE
+	utils.AviLog.Infof("key: %s, msg: applied appProtocol %s over virtualservice %s", key, appProtocol, vsNode.Name)
+	return appProtocol
+}
+
 func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string, ingress bool, key string) []AviPoolMetaServer {
 	ipFamily := lib.GetIPFamily()
 	if ingress {
@@ -277,6 +336,7 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 	}
 
 	var poolMeta []AviPoolMetaServer
+	var poolMembers []objects.PoolMember
 
 	for _, pod := range pods {
 		var annotations []lib.NPLAnnotation
@@ -285,6 +345,11 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 			continue
 		}
 		annotations = obj.([]lib.NPLAnnotation)
+		// The servers of a terminating Pod are disabled, to drain the existing connections.
+		var terminating bool
+		if podObj, err := utils.GetInformers().PodInformer.Lister().Pods(ns).Get(pod.Name); err == nil {
+			terminating = podObj.DeletionTimestamp != nil
+		}
 		for _, a := range annotations {
 			var atype string
 			if utils.IsV4(a.NodeIP) {
@@ -307,11 +372,15 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 					Ip: models.IPAddr{
 						Addr: &a.NodeIP,
 						Type: &atype,
-					}}
+					},
+					Disabled: terminating,
+				}
 				poolMeta = append(poolMeta, server)
+				poolMembers = append(poolMembers, objects.PoolMember{Pod: ns + "/" + pod.Name, IP: a.NodeIP, Port: int32(a.NodePort)})
 			}
 		}
 	}
+	savePoolMembers(poolNode, poolMembers)
 	utils.AviLog.Infof("key: %s, msg: servers for port: %v (%v), are: %v", key, poolNode.Port, poolNode.PortName, utils.Stringify(poolMeta))
 	return poolMeta
 }
@@ -340,6 +409,12 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 		utils.AviLog.Debugf("key: %s, msg: ClusterIP is not processed in NodePort: %s", key, serviceName)
 		return poolMeta
 	}
+	// With externalTrafficPolicy Local, kube-proxy drops the traffic on the nodes which do not host
+	// a ready endpoint of the service, so only those nodes are added as pool servers.
+	var localEndpointNodes map[string]struct{}
+	if svcObj.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+		localEndpointNodes = getNodesWithReadyEndpoints(ns, serviceName, key)
+	}
 	for _, port := range svcObj.Spec.Ports {
 		if port.Name != poolNode.PortName && len(svcObj.Spec.Ports) != 1 {
 			// continue only if port name does not match and its multiport svcobj
@@ -364,6 +439,11 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 				}
 
 			}
+			if localEndpointNodes != nil {
+				if _, ok := localEndpointNodes[node.Name]; !ok {
+					continue
+				}
+			}
 			nodeIP, nodeIP6 := lib.GetIPFromNode(node)
 			var atype string
 			var serverIP avimodels.IPAddr
@@ -393,6 +473,82 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 	return poolMeta
 }
 
+// getNodesWithReadyEndpoints returns the names of the nodes hosting the ready endpoints of the service.
+func getNodesWithReadyEndpoints(ns, serviceName, key string) map[string]struct{} {
+	if lib.IsEndpointSliceEnabled() {
+		return getNodesWithReadyEndpointSlices(ns, serviceName, key)
+	}
+	if utils.GetInformers().EpInformer == nil {
+		return nil
+	}
+	nodeNames := make(map[string]struct{})
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+	if err != nil {
+		utils.AviLog.Infof("key: %s, msg: error in obtaining the endpoints for service: %s, err: %v", key, serviceName, err)
+		return nodeNames
+	}
+	for _, subset := range epObj.Subsets {
+		for _, addr := range subset.Addresses {
+			if addr.NodeName != nil && *addr.NodeName != "" {
+				nodeNames[*addr.NodeName] = struct{}{}
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: nodes with ready endpoints for service %s: %v", key, serviceName, nodeNames)
+	return nodeNames
+}
+
+// getNodesWithReadyEndpointSlices returns the names of the nodes hosting the ready endpoints of the service, from all
+// the EndpointSlices of the service.
+func getNodesWithReadyEndpointSlices(ns, serviceName, key string) map[string]struct{} {
+	if utils.GetInformers().EpSliceInformer == nil {
+		return nil
+	}
+	nodeNames := make(map[string]struct{})
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+	epSlices, err := utils.GetInformers().EpSliceInformer.Lister().EndpointSlices(ns).List(selector)
+	if err != nil {
+		utils.AviLog.Infof("key: %s, msg: error in obtaining the endpointslices for service: %s, err: %v", key, serviceName, err)
+		return nodeNames
+	}
+	for _, epSlice := range epSlices {
+		for _, ep := range epSlice.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			if ep.NodeName != nil && *ep.NodeName != "" {
+				nodeNames[*ep.NodeName] = struct{}{}
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: nodes with ready endpoints for service %s: %v", key, serviceName, nodeNames)
+	return nodeNames
+}
+
+// buildHealthCheckNodePortMonitor returns the health monitor probing the healthCheckNodePort of a Service
+// with externalTrafficPolicy Local, and sets it as the health monitor of the pool. kube-proxy answers the
+// probe with a success only on the nodes which host a ready endpoint of the Service. The health monitors
+// set through the L4Rule take precedence.
+func buildHealthCheckNodePortMonitor(key string, svcObj *corev1.Service, poolNode *AviPoolNode) *AviHealthMonitorNode {
+	if !lib.UseHealthCheckNodePort() ||
+		svcObj.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal ||
+		svcObj.Spec.HealthCheckNodePort == 0 {
+		return nil
+	}
+	if len(poolNode.HealthMonitorRefs) > 0 {
+		utils.AviLog.Debugf("key: %s, msg: pool %s already has health monitors, not using the healthCheckNodePort", key, poolNode.Name)
+		return nil
+	}
+	hmNode := &AviHealthMonitorNode{
+		Name:        poolNode.Name,
+		Tenant:      poolNode.Tenant,
+		MonitorPort: svcObj.Spec.HealthCheckNodePort,
+		AviMarkers:  poolNode.AviMarkers,
+	}
+	poolNode.HealthMonitorRefs = []string{"/api/healthmonitor?name=" + hmNode.Name}
+	return hmNode
+}
+
 func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingress bool, key string) []AviPoolMetaServer {
 
 	ipFamily := lib.GetIPFamily()
@@ -405,12 +561,16 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			return nil
 		}
 	}
+	if lib.IsEndpointSliceEnabled() {
+		return populateServersFromEndpointSlices(poolNode, ns, serviceName, key)
+	}
 	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
 	if err != nil {
 		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
 		return nil
 	}
 	var pool_meta []AviPoolMetaServer
+	var poolMembers []objects.PoolMember
 	for _, ss := range epObj.Subsets {
 		port_match := false
 		for _, epp := range ss.Ports {
@@ -428,7 +588,11 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 		if port_match {
 			var atype string
 			utils.AviLog.Infof("key: %s, msg: found port match for port %v", key, poolNode.Port)
-			for _, addr := range ss.Addresses {
+			addresses := ss.Addresses
+			if lib.IsPodReadinessGateEnabled() {
+				addresses = withPendingPoolMemberAddresses(ss.Addresses, ss.NotReadyAddresses)
+			}
+			for _, addr := range addresses {
 
 				ip := addr.IP
 				if utils.IsV4(addr.IP) {
@@ -450,13 +614,262 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 					server.ServerNode = *addr.NodeName
 				}
 				pool_meta = append(pool_meta, server)
+				if addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+					poolMembers = append(poolMembers, objects.PoolMember{Pod: addr.TargetRef.Namespace + "/" + addr.TargetRef.Name, IP: ip})
+				}
 			}
 		}
 	}
+	savePoolMembers(poolNode, poolMembers)
 	utils.AviLog.Infof("key: %s, msg: servers for port: %v, are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
 	return pool_meta
 }
 
+// withPendingPoolMemberAddresses adds to the ready addresses, the not ready addresses of the Pods which wait
+// only for their pool member readiness condition. These Pods can become ready only once they are pool servers.
+func withPendingPoolMemberAddresses(addresses, notReadyAddresses []corev1.EndpointAddress) []corev1.EndpointAddress {
+	allAddresses := make([]corev1.EndpointAddress, len(addresses))
+	copy(allAddresses, addresses)
+	for _, addr := range notReadyAddresses {
+		if isPodPendingPoolMemberReadiness(addr.TargetRef) {
+			allAddresses = append(allAddresses, addr)
+		}
+	}
+	return allAddresses
+}
+
+func isPodPendingPoolMemberReadiness(targetRef *corev1.ObjectReference) bool {
+	if targetRef == nil || targetRef.Kind != "Pod" {
+		return false
+	}
+	pod, err := utils.GetInformers().PodInformer.Lister().Pods(targetRef.Namespace).Get(targetRef.Name)
+	if err != nil {
+		return false
+	}
+	return lib.IsPodPendingPoolMemberReadiness(pod)
+}
+
+// savePoolMembers records the Pods added as the servers of the pool, for setting their pool member
+// readiness condition. The servers without a port use the port of the pool, which is recorded for them.
+func savePoolMembers(poolNode *AviPoolNode, poolMembers []objects.PoolMember) {
+	if !lib.IsPodReadinessGateEnabled() {
+		return
+	}
+	for i := range poolMembers {
+		if poolMembers[i].Port == 0 {
+			poolMembers[i].Port = poolNode.Port
+		}
+	}
+	tenant := poolNode.Tenant
+	if tenant == "" {
+		tenant = lib.GetTenant()
+	}
+	objects.SharedPoolMemberLister().Save(tenant+"/"+poolNode.Name, poolMembers)
+}
+
+// populateServersFromEndpointSlices builds the servers from all the EndpointSlices of the Service.
+// The ready endpoints are used, and the serving endpoints which are terminating are used only when
+// no endpoint is ready. The other terminating endpoints are added as disabled servers, so that their
+// connections are drained gracefully. The endpoints of the Pods waiting only for their pool member
+// readiness condition are used as the ready ones. The topology hints are honored when the zone of the Service
+// Engines is set.
+func populateServersFromEndpointSlices(poolNode *AviPoolNode, ns string, serviceName string, key string) []AviPoolMetaServer {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+	epSlices, err := utils.GetInformers().EpSliceInformer.Lister().EndpointSlices(ns).List(selector)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+		return nil
+	}
+	sort.Slice(epSlices, func(i, j int) bool {
+		return epSlices[i].Name < epSlices[j].Name
+	})
+
+	addressType := discoveryv1.AddressTypeIPv4
+	atype := "V4"
+	if lib.GetIPFamily() == "V6" {
+		addressType = discoveryv1.AddressTypeIPv6
+		atype = "V6"
+	}
+
+	// If the Service exposes a single port, that port is used as the server port.
+	slicePorts := sets.NewString()
+	for _, epSlice := range epSlices {
+		for _, epp := range epSlice.Ports {
+			if epp.Port != nil {
+				slicePorts.Insert(fmt.Sprintf("%s/%d", utils.String(epp.Name), *epp.Port))
+			}
+		}
+	}
+	singlePort := slicePorts.Len() == 1
+
+	var ready, serving, terminating []discoveryv1.Endpoint
+	for _, epSlice := range epSlices {
+		if epSlice.AddressType != addressType {
+			utils.AviLog.Debugf("key: %s, msg: skipping endpointslice %s of address type %s", key, epSlice.Name, epSlice.AddressType)
+			continue
+		}
+		portMatch := false
+		for _, epp := range epSlice.Ports {
+			if epp.Port == nil {
+				continue
+			}
+			if poolNode.PortName == utils.String(epp.Name) || int32(poolNode.TargetPort.IntValue()) == *epp.Port || singlePort {
+				portMatch = true
+				poolNode.Port = *epp.Port
+				break
+			}
+		}
+		if !portMatch {
+			continue
+		}
+		for _, ep := range epSlice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				ready = append(ready, ep)
+			} else if ep.Conditions.Terminating != nil && *ep.Conditions.Terminating {
+				if ep.Conditions.Serving != nil && *ep.Conditions.Serving {
+					serving = append(serving, ep)
+				} else {
+					terminating = append(terminating, ep)
+				}
+			} else if lib.IsPodReadinessGateEnabled() && isPodPendingPoolMemberReadiness(ep.TargetRef) {
+				ready = append(ready, ep)
+			}
+		}
+	}
+
+	endpoints := ready
+	if len(endpoints) == 0 {
+		endpoints = serving
+	} else {
+		terminating = append(serving, terminating...)
+	}
+	zone := lib.GetEndpointSliceZone()
+	endpoints = filterEndpointsByZone(endpoints, zone)
+	terminating = filterEndpointsByZone(terminating, zone)
+
+	var poolMeta []AviPoolMetaServer
+	var poolMembers []objects.PoolMember
+	addedIPs := sets.NewString()
+	addServers := func(endpoints []discoveryv1.Endpoint, disabled bool) {
+		for _, ep := range endpoints {
+			for _, addr := range ep.Addresses {
+				if addedIPs.Has(addr) {
+					continue
+				}
+				addedIPs.Insert(addr)
+				ip := addr
+				server := AviPoolMetaServer{Ip: avimodels.IPAddr{Type: &atype, Addr: &ip}, Disabled: disabled}
+				if ep.NodeName != nil {
+					server.ServerNode = *ep.NodeName
+				}
+				poolMeta = append(poolMeta, server)
+				if !disabled && ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+					poolMembers = append(poolMembers, objects.PoolMember{Pod: ep.TargetRef.Namespace + "/" + ep.TargetRef.Name, IP: ip})
+				}
+			}
+		}
+	}
+	addServers(endpoints, false)
+	addServers(terminating, true)
+	savePoolMembers(poolNode, poolMembers)
+	utils.AviLog.Infof("key: %s, msg: servers for port: %v, are: %v", key, poolNode.Port, utils.Stringify(poolMeta))
+	return poolMeta
+}
+
+// GetReadyEndpointAddresses returns the addresses of the ready endpoints of the Service port, keyed by the port
+// of the endpoints. The endpoint ports are matched by the name or the target port of the Service port, or as the
+// single port of the Service. Unlike PopulateServers, the addresses are not recorded as pool members.
+func GetReadyEndpointAddresses(ns, serviceName string, svcPort corev1.ServicePort, key string) map[int32][]string {
+	ipFamily := lib.GetIPFamily()
+	addresses := make(map[int32][]string)
+	isPortMatched := func(name string, port int32, singlePort bool) bool {
+		return singlePort || name == svcPort.Name ||
+			(svcPort.TargetPort.Type == intstr.Int && port == int32(svcPort.TargetPort.IntValue()))
+	}
+	addAddress := func(port int32, addr string) {
+		if utils.IsV4(addr) != (ipFamily == "V4") || utils.HasElem(addresses[port], addr) {
+			return
+		}
+		addresses[port] = append(addresses[port], addr)
+	}
+
+	if lib.IsEndpointSliceEnabled() {
+		selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+		epSlices, err := utils.GetInformers().EpSliceInformer.Lister().EndpointSlices(ns).List(selector)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+			return addresses
+		}
+		slicePorts := sets.NewString()
+		for _, epSlice := range epSlices {
+			for _, epp := range epSlice.Ports {
+				if epp.Port != nil {
+					slicePorts.Insert(fmt.Sprintf("%s/%d", utils.String(epp.Name), *epp.Port))
+				}
+			}
+		}
+		for _, epSlice := range epSlices {
+			for _, epp := range epSlice.Ports {
+				if epp.Port == nil || !isPortMatched(utils.String(epp.Name), *epp.Port, slicePorts.Len() == 1) {
+					continue
+				}
+				for _, ep := range epSlice.Endpoints {
+					if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+						continue
+					}
+					for _, addr := range ep.Addresses {
+						addAddress(*epp.Port, addr)
+					}
+				}
+				break
+			}
+		}
+		return addresses
+	}
+
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
+		return addresses
+	}
+	for _, ss := range epObj.Subsets {
+		for _, epp := range ss.Ports {
+			if !isPortMatched(epp.Name, epp.Port, len(ss.Ports) == 1 && len(epObj.Subsets) == 1) {
+				continue
+			}
+			for _, addr := range ss.Addresses {
+				addAddress(epp.Port, addr.IP)
+			}
+			break
+		}
+	}
+	return addresses
+}
+
+// filterEndpointsByZone returns the endpoints hinted for the zone. As done by kube-proxy, the hints
+// are ignored if any endpoint has no hint or if no endpoint is hinted for the zone.
+func filterEndpointsByZone(endpoints []discoveryv1.Endpoint, zone string) []discoveryv1.Endpoint {
+	if zone == "" {
+		return endpoints
+	}
+	var zoneEndpoints []discoveryv1.Endpoint
+	for _, ep := range endpoints {
+		if ep.Hints == nil || len(ep.Hints.ForZones) == 0 {
+			return endpoints
+		}
+		for _, forZone := range ep.Hints.ForZones {
+			if forZone.Name == zone {
+				zoneEndpoints = append(zoneEndpoints, ep)
+				break
+			}
+		}
+	}
+	if len(zoneEndpoints) == 0 {
+		return endpoints
+	}
+	return zoneEndpoints
+}
+
 func PopulateServersForMultiClusterIngress(poolNode *AviPoolNode, ns, cluster, serviceNamespace, serviceName string, key string) []AviPoolMetaServer {
 
 	ipFamily := lib.GetIPFamily()
@@ -735,7 +1148,7 @@ func buildPoolWithL4Rule(key string, pool *AviPoolNode, l4Rule *akov1alpha2.L4Ru
 // and override required services with UDP Fast Path or SCTP proxy. Having a separate
 // internally used network profile (MIXED_NET_PROFILE) helps ensure PUT calls
 // on existing VSes.
-func getNetworkProfile(isSCTP, isTCP, isUDP bool) string {
+func GetNetworkProfile(isSCTP, isTCP, isUDP bool) string {
 	if isSCTP && !isTCP && !isUDP {
 		return utils.SYSTEM_SCTP_PROXY
 	}
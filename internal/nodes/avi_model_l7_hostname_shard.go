@@ -289,6 +289,9 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 	// Reset the PG Node members and rebuild them
 	pgNode.Members = nil
 	for _, poolNode := range vsNode[0].PoolRefs {
+		if isDefaultBackendPool(vsName, poolNode) {
+			continue
+		}
 		ratio := poolNode.ServiceMetadata.PoolRatio
 		pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 		pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, PriorityLabel: &poolNode.PriorityLabel, Ratio: &ratio})
@@ -327,6 +330,9 @@ func buildPoolNode(key, poolName, ingName, namespace, priorityLabel, hostname st
 		poolNode.VrfContext = ""
 	}
 
+	appProtocol := getServicePortAppProtocol(namespace, obj.ServiceName, obj.PortName, obj.Port, obj.TargetPort)
+	buildPoolWithAppProtocol(key, poolNode, appProtocol)
+
 	serviceType := lib.GetServiceType()
 	if serviceType == lib.NodePortLocal {
 		if servers := PopulateServersForNPL(poolNode, namespace, obj.ServiceName, true, key); servers != nil {
@@ -396,6 +402,9 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 		if pgNode != nil {
 			pgNode.Members = nil
 			for _, poolNode := range vsNode[0].PoolRefs {
+				if isDefaultBackendPool(vsName, poolNode) {
+					continue
+				}
 				ratio := poolNode.ServiceMetadata.PoolRatio
 				pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 				pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, PriorityLabel: &poolNode.PriorityLabel, Ratio: &ratio})
@@ -25,7 +25,9 @@ import (
 
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -438,6 +440,8 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 			if hostpath.reencrypt {
 				o.BuildPoolSecurity(poolNode, hostpath, key, poolNode.AviMarkers)
 			}
+			appProtocol := getServicePortAppProtocol(namespace, path.ServiceName, path.PortName, path.Port, path.TargetPort)
+			buildPoolWithAppProtocol(key, poolNode, appProtocol)
 
 			serviceType := lib.GetServiceType()
 			if serviceType == lib.NodePortLocal {
@@ -516,6 +520,55 @@ func (o *AviObjectGraph) BuildPoolSecurity(poolNode *AviPoolNode, tlsData TlsSet
 	poolNode.PkiProfile = &pkiProfile
 }
 
+// buildPoolWithAppProtocol configures the pool of an L7 virtualservice as per the appProtocol of the Service port
+// backing it. HTTP/2 is enabled towards the backends speaking HTTP/2 over cleartext or gRPC, and the traffic is
+// re-encrypted towards the backends expecting TLS. The websockets need no pool setting, the upgrade is allowed by
+// the HTTP application profile. The TLS settings of the Route and the HTTPRule take precedence.
+func buildPoolWithAppProtocol(key string, poolNode *AviPoolNode, appProtocol string) {
+	switch appProtocol {
+	case lib.AppProtocolH2C, lib.AppProtocolGRPC:
+		poolNode.EnableHttp2 = true
+	case lib.AppProtocolHTTPS, lib.AppProtocolWSS:
+		if poolNode.SslProfileRef != nil {
+			return
+		}
+		poolNode.SniEnabled = true
+		poolNode.SslProfileRef = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile))
+	case lib.AppProtocolWS:
+		return
+	default:
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: applied appProtocol %s over pool %s", key, appProtocol, poolNode.Name)
+}
+
+// getServicePortAppProtocol returns the appProtocol of the Service port matching the port name, the port or the
+// target port, in that order.
+func getServicePortAppProtocol(namespace, serviceName, portName string, port int32, targetPort intstr.IntOrString) string {
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(serviceName)
+	if err != nil {
+		return ""
+	}
+	var matchedPort *corev1.ServicePort
+	for i, svcPort := range svcObj.Spec.Ports {
+		if portName != "" && svcPort.Name == portName {
+			matchedPort = &svcObj.Spec.Ports[i]
+			break
+		}
+		if matchedPort == nil && ((port != 0 && svcPort.Port == port) ||
+			(targetPort.IntValue() != 0 && svcPort.TargetPort.IntValue() == targetPort.IntValue())) {
+			matchedPort = &svcObj.Spec.Ports[i]
+		}
+	}
+	if matchedPort == nil && len(svcObj.Spec.Ports) == 1 {
+		matchedPort = &svcObj.Spec.Ports[0]
+	}
+	if matchedPort == nil || matchedPort.AppProtocol == nil {
+		return ""
+	}
+	return *matchedPort.AppProtocol
+}
+
 func (o *AviObjectGraph) BuildPolicyRedirectForVS(vsNode []*AviVsNode, hostnames []string, namespace, infrasettingName, host, key string) {
 	policyname := lib.GetL7HttpRedirPolicy(vsNode[0].Name)
 	myHppMap := AviRedirectPort{
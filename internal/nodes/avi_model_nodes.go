@@ -17,6 +17,7 @@ package nodes
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -180,6 +181,12 @@ func (v *AviVsNode) CalculateForGraphChecksum() uint32 {
 	for _, l4pol := range v.L4PolicyRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(l4pol.GetCheckSum()))
 	}
+	for _, nsp := range v.NetworkSecurityPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(nsp.GetCheckSum()))
+	}
+	for _, hm := range v.HealthMonitorNodeRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(hm.GetCheckSum()))
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -210,6 +217,9 @@ func (v *AviEvhVsNode) CalculateForGraphChecksum() uint32 {
 	for _, vsvip := range v.VSVIPRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(vsvip.GetCheckSum()))
 	}
+	for _, nsp := range v.NetworkSecurityPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(nsp.GetCheckSum()))
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -374,45 +384,47 @@ func (o *AviObjectGraph) GetIstioNodes() (*AviPkiProfileNode, *AviTLSKeyCertNode
 }
 
 type AviVsNode struct {
-	Name                  string
-	Tenant                string
-	ServiceEngineGroup    string
-	ApplicationProfile    string
-	NetworkProfile        string
-	Enabled               *bool
-	EnableRhi             *bool
-	PortProto             []AviPortHostProtocol // for listeners
-	DefaultPool           string
-	CloudConfigCksum      uint32
-	DefaultPoolGroup      string
-	HTTPChecksum          uint32
-	SNIParent             bool
-	PoolGroupRefs         []*AviPoolGroupNode
-	PoolRefs              []*AviPoolNode
-	HTTPDSrefs            []*AviHTTPDataScriptNode
-	SniNodes              []*AviVsNode
-	PassthroughChildNodes []*AviVsNode
-	SharedVS              bool
-	CACertRefs            []*AviTLSKeyCertNode
-	SSLKeyCertRefs        []*AviTLSKeyCertNode
-	HttpPolicyRefs        []*AviHttpPolicySetNode
-	VSVIPRefs             []*AviVSVIPNode
-	L4PolicyRefs          []*AviL4PolicyNode
-	VHParentName          string
-	VHDomainNames         []string
-	TLSType               string
-	IsSNIChild            bool
-	ServiceMetadata       lib.ServiceMetadataObj
-	VrfContext            string
-	ICAPProfileRefs       []string
-	ErrorPageProfileRef   string
-	HttpPolicySetRefs     []string
-	AviMarkers            utils.AviObjectMarkers
-	Paths                 []string
-	IngressNames          []string
-	Dedicated             bool
-	IsL4VS                bool
-	Secure                bool
+	Name                      string
+	Tenant                    string
+	ServiceEngineGroup        string
+	ApplicationProfile        string
+	NetworkProfile            string
+	Enabled                   *bool
+	EnableRhi                 *bool
+	PortProto                 []AviPortHostProtocol // for listeners
+	DefaultPool               string
+	CloudConfigCksum          uint32
+	DefaultPoolGroup          string
+	HTTPChecksum              uint32
+	SNIParent                 bool
+	PoolGroupRefs             []*AviPoolGroupNode
+	PoolRefs                  []*AviPoolNode
+	HTTPDSrefs                []*AviHTTPDataScriptNode
+	SniNodes                  []*AviVsNode
+	PassthroughChildNodes     []*AviVsNode
+	SharedVS                  bool
+	CACertRefs                []*AviTLSKeyCertNode
+	SSLKeyCertRefs            []*AviTLSKeyCertNode
+	HttpPolicyRefs            []*AviHttpPolicySetNode
+	VSVIPRefs                 []*AviVSVIPNode
+	L4PolicyRefs              []*AviL4PolicyNode
+	NetworkSecurityPolicyRefs []*AviNetworkSecurityPolicyNode
+	HealthMonitorNodeRefs     []*AviHealthMonitorNode
+	VHParentName              string
+	VHDomainNames             []string
+	TLSType                   string
+	IsSNIChild                bool
+	ServiceMetadata           lib.ServiceMetadataObj
+	VrfContext                string
+	ICAPProfileRefs           []string
+	ErrorPageProfileRef       string
+	HttpPolicySetRefs         []string
+	AviMarkers                utils.AviObjectMarkers
+	Paths                     []string
+	IngressNames              []string
+	Dedicated                 bool
+	IsL4VS                    bool
+	Secure                    bool
 
 	AviVsNodeCommonFields
 
@@ -614,6 +626,22 @@ func (v *AviVsNode) SetVHDomainNames(domainNames []string) {
 	v.VHDomainNames = domainNames
 }
 
+func (v *AviVsNode) GetNetworkSecurityPolicyRefs() []*AviNetworkSecurityPolicyNode {
+	return v.NetworkSecurityPolicyRefs
+}
+
+func (v *AviVsNode) SetNetworkSecurityPolicyRefs(nspRefs []*AviNetworkSecurityPolicyNode) {
+	v.NetworkSecurityPolicyRefs = nspRefs
+}
+
+func (v *AviVsNode) GetHealthMonitorNodeRefs() []*AviHealthMonitorNode {
+	return v.HealthMonitorNodeRefs
+}
+
+func (v *AviVsNode) SetHealthMonitorNodeRefs(hmRefs []*AviHealthMonitorNode) {
+	v.HealthMonitorNodeRefs = hmRefs
+}
+
 func (v *AviVsNode) GetGeneratedFields() *AviVsNodeGeneratedFields {
 	return &v.AviVsNodeGeneratedFields
 }
@@ -933,6 +961,10 @@ func (v *AviVsNode) CalculateCheckSum() {
 		checksumStringSlice = append(checksumStringSlice, "L4Policy"+l4policy.Name)
 	}
 
+	for _, nsp := range v.NetworkSecurityPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, "NetworkSecurityPolicy"+nsp.Name)
+	}
+
 	for _, vhdomain := range v.VHDomainNames {
 		checksumStringSlice = append(checksumStringSlice, "VHDomain"+vhdomain)
 	}
@@ -1023,6 +1055,10 @@ func (v *AviVsNode) CalculateCheckSum() {
 
 	checksum += v.AviVsNodeGeneratedFields.CalculateCheckSumOfGeneratedCode()
 
+	if v.DefaultPoolGroup != "" {
+		checksum += utils.Hash(v.DefaultPoolGroup)
+	}
+
 	v.CloudConfigCksum = checksum
 }
 
@@ -1091,6 +1127,103 @@ func (v *AviL4PolicyNode) CopyNode() AviModelNode {
 	return &newNode
 }
 
+type AviNetworkSecurityPolicyNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	SourceRanges     []string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviNetworkSecurityPolicyNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.NetworkSecurityPolicyChecksum(v.SourceRanges, v.AviMarkers, nil, false)
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetNodeType() string {
+	return "AviNetworkSecurityPolicyNode"
+}
+
+func (v *AviNetworkSecurityPolicyNode) CopyNode() AviModelNode {
+	newNode := AviNetworkSecurityPolicyNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	return &newNode
+}
+
+// BuildNetworkSecurityPolicyNode returns the NetworkSecurityPolicy which allows only the
+// clients from the given CIDRs to reach the virtualservice. The CIDRs are normalized, and
+// the invalid ones are skipped. A nil node is returned when no valid CIDR is left.
+func BuildNetworkSecurityPolicyNode(vsName string, sourceRanges []string, markers utils.AviObjectMarkers, key string) *AviNetworkSecurityPolicyNode {
+	var ranges []string
+	for _, sourceRange := range sourceRanges {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(sourceRange))
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: skipping invalid source range %s for virtualservice %s", key, sourceRange, vsName)
+			continue
+		}
+		if !utils.HasElem(ranges, ipNet.String()) {
+			ranges = append(ranges, ipNet.String())
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Strings(ranges)
+	return &AviNetworkSecurityPolicyNode{
+		Name:         vsName,
+		Tenant:       lib.GetTenant(),
+		SourceRanges: ranges,
+		AviMarkers:   markers,
+	}
+}
+
+type AviHealthMonitorNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	MonitorPort      int32
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviHealthMonitorNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviHealthMonitorNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.HealthMonitorChecksum(v.MonitorPort, v.AviMarkers, nil, false)
+}
+
+func (v *AviHealthMonitorNode) GetNodeType() string {
+	return "AviHealthMonitorNode"
+}
+
+func (v *AviHealthMonitorNode) CopyNode() AviModelNode {
+	newNode := AviHealthMonitorNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviHealthMonitorNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviHealthMonitorNode: %s", err)
+	}
+	return &newNode
+}
+
 type AviHttpPolicySetNode struct {
 	Name               string
 	Tenant             string
@@ -1510,6 +1643,9 @@ type AviPoolNode struct {
 	PriorityLabel            string
 	ServiceMetadata          lib.ServiceMetadataObj
 	SniEnabled               bool
+	ServerName               string // hostname sent in the TLS SNI extension to the servers, when SNI is enabled
+	EnableHttp2              bool
+	ServerTimeout            *int32 // server response timeout in milliseconds, 0 uses the Avi default
 	PkiProfile               *AviPkiProfileNode
 	NetworkPlacementSettings map[string]lib.NodeNetworkMap
 	VrfContext               string
@@ -1530,6 +1666,7 @@ type AviPoolNode struct {
 // existing struct fields.
 type AviPoolCommonFields struct {
 	ApplicationPersistenceProfileRef *string
+	GracefulDisableTimeout           *int32
 	HealthMonitorRefs                []string
 	LbAlgorithm                      *string
 	LbAlgorithmHash                  *string
@@ -1573,6 +1710,18 @@ func (v *AviPoolNode) CalculateCheckSum() {
 
 	checksumStringSlice = append(checksumStringSlice, utils.Stringify(v.SniEnabled))
 
+	if v.ServerName != "" {
+		checksumStringSlice = append(checksumStringSlice, v.ServerName)
+	}
+
+	if v.EnableHttp2 {
+		checksumStringSlice = append(checksumStringSlice, utils.Stringify(v.EnableHttp2))
+	}
+
+	if v.ServerTimeout != nil {
+		checksumStringSlice = append(checksumStringSlice, utils.Stringify(*v.ServerTimeout))
+	}
+
 	if v.SslProfileRef != nil {
 		checksumStringSlice = append(checksumStringSlice, *v.SslProfileRef)
 	}
@@ -1615,6 +1764,10 @@ func (v *AviPoolNode) CalculateCheckSum() {
 		checksum += utils.Hash(*v.ApplicationPersistenceProfileRef)
 	}
 
+	if v.GracefulDisableTimeout != nil {
+		checksum += utils.Hash(strconv.Itoa(int(*v.GracefulDisableTimeout)))
+	}
+
 	checksum += lib.GetMarkersChecksum(v.AviMarkers)
 
 	if v.T1Lr != "" {
@@ -1680,6 +1833,10 @@ type AviPoolMetaServer struct {
 	Ip         avimodels.IPAddr
 	ServerNode string
 	Port       int32
+	// Disabled is set for the servers of the terminating endpoints, which are gracefully
+	// disabled in the pool to drain the existing connections before they are removed.
+	// It is omitted when false, to keep the checksum of the pools with enabled servers.
+	Disabled bool `json:",omitempty"`
 }
 
 type IngressHostPathSvc struct {
@@ -1692,6 +1849,7 @@ type IngressHostPathSvc struct {
 	TargetPort     intstr.IntOrString
 	clusterContext string // required for Multi-cluster ingress
 	svcNamespace   string // required for Multi-cluster ingress
+	defaultBackend bool   // path added for the defaultBackend of the ingress
 }
 
 type IngressHostMap map[string]HostMetadata
@@ -1727,6 +1885,7 @@ type IngressConfig struct {
 	TlsCollection         []TlsSettings
 	IngressHostMap
 	InsecureEdgeTermAllow bool
+	DefaultBackend        *IngressHostPathSvc
 }
 
 type SecureHostNameMapProp struct {
@@ -81,6 +81,13 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 			} else {
 				RouteIngrDeletePoolsByHostname(routeIgrObj, namespace, objname, key, fullsync, sharedQueue)
 			}
+			var modelList []string
+			ProcessDefaultBackend(routeIgrObj, key, IngressConfig{}, nil, &modelList)
+			if !fullsync {
+				for _, modelName := range modelList {
+					PublishKeyToRestLayer(modelName, key, sharedQueue)
+				}
+			}
 		}
 		return
 	}
@@ -103,6 +110,7 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 		// process secure hosts
 		ProcessSecureHostsForEVH(routeIgrObj, key, parsedIng, &modelList, Storedhosts, hostsMap, fullsync, sharedQueue)
 		ProcessPassthroughHosts(routeIgrObj, key, parsedIng, &modelList, Storedhosts, hostsMap)
+		ProcessDefaultBackend(routeIgrObj, key, parsedIng, hostsMap, &modelList)
 		// delete stale data
 		DeleteStaleDataForEvh(routeIgrObj, key, &modelList, Storedhosts, hostsMap)
 		// hostNamePathStore cache operation
@@ -128,6 +136,8 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 
 	ProcessPassthroughHosts(routeIgrObj, key, parsedIng, &modelList, Storedhosts, hostsMap)
 
+	ProcessDefaultBackend(routeIgrObj, key, parsedIng, hostsMap, &modelList)
+
 	utils.AviLog.Debugf("key: %s, msg: Stored hosts: %v, hosts map: %v", key, Storedhosts, hostsMap)
 	DeleteStaleData(routeIgrObj, key, &modelList, Storedhosts, hostsMap)
 
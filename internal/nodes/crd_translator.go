@@ -64,6 +64,7 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	var vsEnabled *bool
 	var crdStatus lib.CRDMetadata
 	var vsICAPProfile []string
+	var vsNetworkSecurityPolicies []*AviNetworkSecurityPolicyNode
 
 	// Initializing the values of vsHTTPPolicySets and vsDatascripts, using a nil value would impact the value of VS checksum
 	vsHTTPPolicySets := []string{}
@@ -176,6 +177,20 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 			}
 		}
 
+		if len(hostrule.Spec.VirtualHost.SourceRanges) > 0 {
+			// The network security policy is evaluated on the connections to the parent VS, so it is not attached
+			// to the SNI and EVH child VSes.
+			if vsNode.IsSharedVS() || vsNode.IsDedicatedVS() {
+				markers := lib.PopulateVSNodeMarkers(hostrule.Namespace, host, "")
+				if nspNode := BuildNetworkSecurityPolicyNode(vsNode.GetName(), hostrule.Spec.VirtualHost.SourceRanges, markers, key); nspNode != nil {
+					vsNetworkSecurityPolicies = append(vsNetworkSecurityPolicies, nspNode)
+				}
+			} else {
+				utils.AviLog.Warnf("key: %s, msg: sourceRanges of hostrule %s is applicable only to a parent VS, not applying it on child VS %s",
+					key, hrNamespaceName, vsNode.GetName())
+			}
+		}
+
 		for _, alias := range hostrule.Spec.VirtualHost.Aliases {
 			if !utils.HasElem(VHDomainNames, alias) {
 				VHDomainNames = append(VHDomainNames, alias)
@@ -209,6 +224,7 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	}
 	vsNode.SetVSVIPLoadBalancerIP(lbIP)
 	vsNode.SetVHDomainNames(VHDomainNames)
+	vsNode.SetNetworkSecurityPolicyRefs(vsNetworkSecurityPolicies)
 
 	serviceMetadataObj := vsNode.GetServiceMetadata()
 	serviceMetadataObj.CRDStatus = crdStatus
@@ -262,11 +278,6 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 		}
 
 		for _, pool := range vsNode.GetPoolRefs() {
-			isPathSniEnabled := pool.SniEnabled
-			pathSslProfile := pool.SslProfileRef
-			pathPkiProfile := pool.PkiProfileRef
-			destinationCertNode := pool.PkiProfile
-			pathHMs := pool.HealthMonitorRefs
 			if poolPath == "" && path == "/" {
 				// In case of openfhit Route, the path could be empty, in that case, treat
 				// httprule targt path / as that of empty path, to match the pool appropriately.
@@ -295,82 +306,144 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 			}
 			if (secureRgx.MatchString(poolName) && isSNI) || (insecureRgx.MatchString(poolName) && !isSNI) {
 				utils.AviLog.Debugf("key: %s, msg: computing poolNode %s for httprule.paths.target %s", key, poolName, path)
-				// pool tls
-				if httpRulePath.TLS.Type != "" {
-					isPathSniEnabled = true
-					if httpRulePath.TLS.SSLProfile != "" {
-						pathSslProfile = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", httpRulePath.TLS.SSLProfile))
-					} else {
-						pathSslProfile = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile))
-					}
+				pkiProfileMarkers := lib.PopulatePoolNodeMarkers(namespace, host, "", pool.AviMarkers.ServiceName, []string{ingName}, []string{path})
+				applyHTTPRulePath(key, rule, path, poolName, httpRulePath, pool, pkiProfileMarkers)
+			}
+		}
+	}
 
-					if httpRulePath.TLS.DestinationCA != "" {
-						destinationCertNode = &AviPkiProfileNode{
-							Name:   lib.GetPoolPKIProfileName(poolName),
-							Tenant: lib.GetTenant(),
-							CACert: httpRulePath.TLS.DestinationCA,
-						}
-						destinationCertNode.AviMarkers = lib.PopulatePoolNodeMarkers(namespace, host, "", pool.AviMarkers.ServiceName, []string{ingName}, []string{path})
-					} else {
-						destinationCertNode = nil
-					}
+}
 
-					if httpRulePath.TLS.PKIProfile != "" {
-						pathPkiProfile = proto.String(fmt.Sprintf("/api/pkiprofile?name=%s", httpRulePath.TLS.PKIProfile))
-					}
+// BuildPoolHTTPRuleForPaths applies the HTTPRule of the hosts on a pool which serves the paths, for the
+// pools whose names aren't derived from the host and the path, such as the pools of the Gateway API routes.
+// The hosts are evaluated in order, and the HTTPRule path whose target is the longest prefix of the
+// paths of the first matching host is applied on the pool.
+func BuildPoolHTTPRuleForPaths(hosts, paths []string, key string, pool *AviPoolNode) {
+	for _, host := range hosts {
+		found, pathRules := objects.SharedCRDLister().GetFqdnHTTPRulesMapping(host)
+		if !found {
+			continue
+		}
+		var target string
+		for pathRuleTarget := range pathRules {
+			for _, path := range paths {
+				if strings.HasPrefix(path, pathRuleTarget) && len(pathRuleTarget) > len(target) {
+					target = pathRuleTarget
 				}
+			}
+		}
+		if target == "" {
+			continue
+		}
 
-				var persistenceProfile *string
-				if httpRulePath.ApplicationPersistence != "" {
-					persistenceProfile = proto.String(fmt.Sprintf("/api/applicationpersistenceprofile?name=%s", httpRulePath.ApplicationPersistence))
-				}
+		rule := pathRules[target]
+		pathNSName := strings.Split(rule, "/")
+		httpRuleObj, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(pathNSName[0]).Get(pathNSName[1])
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: httprule not found err: %+v", key, err)
+			return
+		} else if httpRuleObj.Status.Status == lib.StatusRejected {
+			return
+		}
+		for _, httpRulePath := range httpRuleObj.Spec.Paths {
+			if httpRulePath.Target != target {
+				continue
+			}
+			if httpRulePath.TLS.Type != "" && httpRulePath.TLS.Type != lib.TypeTLSReencrypt {
+				return
+			}
+			utils.AviLog.Debugf("key: %s, msg: computing poolNode %s for httprule.paths.target %s", key, pool.Name, target)
+			applyHTTPRulePath(key, rule, target, pool.Name, httpRulePath, pool, pool.AviMarkers)
+			return
+		}
+		return
+	}
+}
 
-				for _, hm := range httpRulePath.HealthMonitors {
-					if !utils.HasElem(pathHMs, fmt.Sprintf("/api/healthmonitor?name=%s", hm)) {
-						pathHMs = append(pathHMs, fmt.Sprintf("/api/healthmonitor?name=%s", hm))
-					}
-				}
+// applyHTTPRulePath applies the settings of an HTTPRule path on the pool, the markers are set on
+// the PKI profile which is created from the destination CA of the path.
+func applyHTTPRulePath(key, rule, path, poolName string, httpRulePath akov1beta1.HTTPRulePaths, pool *AviPoolNode, pkiProfileMarkers utils.AviObjectMarkers) {
+	isPathSniEnabled := pool.SniEnabled
+	pathSslProfile := pool.SslProfileRef
+	pathPkiProfile := pool.PkiProfileRef
+	destinationCertNode := pool.PkiProfile
+	pathHMs := pool.HealthMonitorRefs
+
+	// pool tls
+	if httpRulePath.TLS.Type != "" {
+		isPathSniEnabled = true
+		if httpRulePath.TLS.SSLProfile != "" {
+			pathSslProfile = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", httpRulePath.TLS.SSLProfile))
+		} else {
+			pathSslProfile = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile))
+		}
 
-				pool.SniEnabled = isPathSniEnabled
-				pool.SslProfileRef = pathSslProfile
-				pool.PkiProfileRef = pathPkiProfile
-				pool.PkiProfile = destinationCertNode
-				pool.HealthMonitorRefs = pathHMs
-				pool.ApplicationPersistenceProfileRef = persistenceProfile
+		if httpRulePath.TLS.DestinationCA != "" {
+			destinationCertNode = &AviPkiProfileNode{
+				Name:   lib.GetPoolPKIProfileName(poolName),
+				Tenant: lib.GetTenant(),
+				CACert: httpRulePath.TLS.DestinationCA,
+			}
+			destinationCertNode.AviMarkers = pkiProfileMarkers
+		} else {
+			destinationCertNode = nil
+		}
 
-				// from this path, generate refs to this pool node
-				if httpRulePath.LoadBalancerPolicy.Algorithm != "" {
-					pool.LbAlgorithm = proto.String(httpRulePath.LoadBalancerPolicy.Algorithm)
-				}
-				if pool.LbAlgorithm != nil &&
-					*pool.LbAlgorithm == lib.LB_ALGORITHM_CONSISTENT_HASH {
-					pool.LbAlgorithmHash = proto.String(httpRulePath.LoadBalancerPolicy.Hash)
-					if pool.LbAlgorithmHash != nil &&
-						*pool.LbAlgorithmHash == lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER {
-						if httpRulePath.LoadBalancerPolicy.HostHeader != "" {
-							pool.LbAlgorithmConsistentHashHdr = proto.String(httpRulePath.LoadBalancerPolicy.HostHeader)
-						} else {
-							utils.AviLog.Warnf("key: %s, HostHeader is not provided for LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER", key)
-						}
-					} else if httpRulePath.LoadBalancerPolicy.HostHeader != "" {
-						utils.AviLog.Warnf("key: %s, HostHeader is only applicable for LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER", key)
-					}
-				}
+		if httpRulePath.TLS.PKIProfile != "" {
+			pathPkiProfile = proto.String(fmt.Sprintf("/api/pkiprofile?name=%s", httpRulePath.TLS.PKIProfile))
+		}
+	}
 
-				// There is no need to convert the servicemetadata CRDStatus to INACTIVE, in case
-				// no appropriate HttpRule is found, since we build the PoolNodes from scrach every time
-				// while building the graph. If no HttpRule is found, the CRDStatus will remain empty.
-				// For the same reason, we cannot track ACTIVE -> INACTIVE transitions in HTTPRule.
-				pool.ServiceMetadata.CRDStatus = lib.CRDMetadata{
-					Type:   "HTTPRule",
-					Value:  rule + "/" + path,
-					Status: lib.CRDActive,
-				}
-				utils.AviLog.Infof("key: %s, Attached httprule %s on pool %s", key, rule, pool.Name)
+	var persistenceProfile *string
+	if httpRulePath.ApplicationPersistence != "" {
+		persistenceProfile = proto.String(fmt.Sprintf("/api/applicationpersistenceprofile?name=%s", httpRulePath.ApplicationPersistence))
+	}
+
+	for _, hm := range httpRulePath.HealthMonitors {
+		if !utils.HasElem(pathHMs, fmt.Sprintf("/api/healthmonitor?name=%s", hm)) {
+			pathHMs = append(pathHMs, fmt.Sprintf("/api/healthmonitor?name=%s", hm))
+		}
+	}
+
+	pool.SniEnabled = isPathSniEnabled
+	pool.SslProfileRef = pathSslProfile
+	pool.PkiProfileRef = pathPkiProfile
+	pool.PkiProfile = destinationCertNode
+	pool.HealthMonitorRefs = pathHMs
+	pool.ApplicationPersistenceProfileRef = persistenceProfile
+	if httpRulePath.GracefulDisableTimeout != nil {
+		pool.GracefulDisableTimeout = httpRulePath.GracefulDisableTimeout
+	}
+
+	// from this path, generate refs to this pool node
+	if httpRulePath.LoadBalancerPolicy.Algorithm != "" {
+		pool.LbAlgorithm = proto.String(httpRulePath.LoadBalancerPolicy.Algorithm)
+	}
+	if pool.LbAlgorithm != nil &&
+		*pool.LbAlgorithm == lib.LB_ALGORITHM_CONSISTENT_HASH {
+		pool.LbAlgorithmHash = proto.String(httpRulePath.LoadBalancerPolicy.Hash)
+		if pool.LbAlgorithmHash != nil &&
+			*pool.LbAlgorithmHash == lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER {
+			if httpRulePath.LoadBalancerPolicy.HostHeader != "" {
+				pool.LbAlgorithmConsistentHashHdr = proto.String(httpRulePath.LoadBalancerPolicy.HostHeader)
+			} else {
+				utils.AviLog.Warnf("key: %s, HostHeader is not provided for LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER", key)
 			}
+		} else if httpRulePath.LoadBalancerPolicy.HostHeader != "" {
+			utils.AviLog.Warnf("key: %s, HostHeader is only applicable for LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER", key)
 		}
 	}
 
+	// There is no need to convert the servicemetadata CRDStatus to INACTIVE, in case
+	// no appropriate HttpRule is found, since we build the PoolNodes from scrach every time
+	// while building the graph. If no HttpRule is found, the CRDStatus will remain empty.
+	// For the same reason, we cannot track ACTIVE -> INACTIVE transitions in HTTPRule.
+	pool.ServiceMetadata.CRDStatus = lib.CRDMetadata{
+		Type:   "HTTPRule",
+		Value:  rule + "/" + path,
+		Status: lib.CRDActive,
+	}
+	utils.AviLog.Infof("key: %s, Attached httprule %s on pool %s", key, rule, pool.Name)
 }
 
 func BuildL7SSORule(host, key string, vsNode AviVsEvhSniModel) {
@@ -26,6 +26,7 @@ import (
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 )
@@ -212,7 +213,7 @@ func DequeueIngestion(key string, fullsync bool) {
 			}
 
 			// Do not handle service update if it belongs to unaccepted namespace
-			if svcObj.Spec.Type == utils.LoadBalancer && !lib.GetLayer7Only() && utils.CheckIfNamespaceAccepted(namespace) {
+			if svcObj.Spec.Type == utils.LoadBalancer && lib.IsLoadBalancerClassAccepted(svcObj) && !lib.GetLayer7Only() && utils.CheckIfNamespaceAccepted(namespace) {
 				// This endpoint update affects a LB service.
 				aviModelGraph := NewAviObjectGraph()
 				if sharedVipKey, ok := svcObj.Annotations[lib.SharedVipSvcLBAnnotation]; ok && sharedVipKey != "" {
@@ -568,6 +569,7 @@ func handleL4SharedVipService(namespacedVipKey, key string, fullsync bool) {
 	var sharedVipLBIP string
 	var sharedVipInfraSetting string
 	var sharedL4Rule string
+	var svcObjs []*corev1.Service
 	for i, serviceNSName := range serviceNSNames {
 		svcNSName := strings.Split(serviceNSName, "/")
 		svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(svcNSName[0]).Get(svcNSName[1])
@@ -576,6 +578,7 @@ func handleL4SharedVipService(namespacedVipKey, key string, fullsync bool) {
 			isShareVipKeyDelete = true
 			break
 		}
+		svcObjs = append(svcObjs, svcObj)
 
 		// Initializing the preferred VIP from the first Service we get, so any other Service
 		// that wishes for static IP allocation differently conflicts with this.
@@ -621,6 +624,17 @@ func handleL4SharedVipService(namespacedVipKey, key string, fullsync bool) {
 		}
 	}
 
+	// The shared vip allows only the clients which are allowed by all the Services, the Services
+	// must have at least one common source range.
+	if sourceRanges, restricted := getSharedVipSourceRanges(svcObjs); !isShareVipKeyDelete && restricted && len(sourceRanges) == 0 {
+		utils.AviLog.Errorf("Service loadBalancerSourceRanges have no common range in the Services grouped using shared-vip annotation %s", namespacedVipKey)
+		for _, svcObj := range svcObjs {
+			lib.AKOControlConfig().EventRecorder().Eventf(svcObj, corev1.EventTypeWarning, lib.NoCommonSourceRange,
+				"loadBalancerSourceRanges have no common range in the Services grouped using shared-vip annotation %s", namespacedVipKey)
+		}
+		isShareVipKeyDelete = true
+	}
+
 	if isShareVipKeyDelete {
 		// Check if a model corresponding to the gateway exists or not in memory.
 		if found, _ := objects.SharedAviGraphLister().Get(modelName); found {
@@ -846,6 +860,12 @@ func isServiceDelete(svcName string, namespace string, key string) bool {
 		return true
 	}
 
+	// The loadBalancerClass of the service might have moved away from AKO, in which case
+	// the L4 dedicated virtual service should be deleted.
+	if svc.Spec.Type == utils.LoadBalancer && !lib.IsLoadBalancerClassAccepted(svc) {
+		return true
+	}
+
 	return false
 }
 
@@ -972,6 +972,9 @@ func parseServicesForIngress(ingSpec networkingv1.IngressSpec, key string) []str
 			}
 		}
 	}
+	if ingSpec.DefaultBackend != nil && ingSpec.DefaultBackend.Service != nil && !utils.HasElem(services, ingSpec.DefaultBackend.Service.Name) {
+		services = append(services, ingSpec.DefaultBackend.Service.Name)
+	}
 	utils.AviLog.Debugf("key: %s, msg: total services retrieved from corev1: %s", key, services)
 	return services
 }
@@ -1186,7 +1189,7 @@ func infraSettingNSToServices(infraSettingName, key string) ([]string, bool) {
 			return allServices, false
 		}
 		for _, svc := range services {
-			if svc.Spec.Type != "LoadBalancer" {
+			if svc.Spec.Type != "LoadBalancer" || !lib.IsLoadBalancerClassAccepted(svc) {
 				continue
 			}
 			key := svc.GetNamespace() + "/" + svc.GetName()
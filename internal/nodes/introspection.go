@@ -0,0 +1,292 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+package nodes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+)
+
+// ListModelViews returns the summary of all the models, sorted by the model name.
+func ListModelViews() []models.ModelSummary {
+	summaries := []models.ModelSummary{}
+	for _, modelName := range objects.SharedAviGraphLister().AviGraphStore.GetAllKeys() {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		summary := models.ModelSummary{Name: modelName}
+		if graph, ok := aviModel.(*AviObjectGraph); ok && graph != nil {
+			graph.Lock.RLock()
+			summary.GraphChecksum = graph.GraphChecksum
+			summary.NodeCount = len(graph.GetOrderedNodes())
+			graph.Lock.RUnlock()
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+// GetModelViews returns the models with the given name. The name can either be the complete model name of the
+// form tenant/name, or the name of the model in any tenant.
+func GetModelViews(name string) []models.ModelGraph {
+	graphs := []models.ModelGraph{}
+	for _, modelName := range objects.SharedAviGraphLister().AviGraphStore.GetAllKeys() {
+		if modelName != name && !strings.HasSuffix(modelName, "/"+name) {
+			continue
+		}
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		graph, ok := aviModel.(*AviObjectGraph)
+		if !ok || graph == nil {
+			// The model is set to nil when the virtualservice is being deleted.
+			graphs = append(graphs, models.ModelGraph{Name: modelName, Nodes: []models.ModelNode{}})
+			continue
+		}
+		graphs = append(graphs, buildModelGraphView(modelName, graph))
+	}
+	sort.Slice(graphs, func(i, j int) bool {
+		return graphs[i].Name < graphs[j].Name
+	})
+	return graphs
+}
+
+func buildModelGraphView(modelName string, graph *AviObjectGraph) models.ModelGraph {
+	graph.Lock.RLock()
+	defer graph.Lock.RUnlock()
+	modelGraph := models.ModelGraph{
+		Name:          modelName,
+		GraphChecksum: graph.GraphChecksum,
+		IsVrf:         graph.IsVrf,
+		RetryCount:    graph.RetryCount,
+		Nodes:         []models.ModelNode{},
+	}
+	for _, node := range graph.GetOrderedNodes() {
+		// The nodes are read while holding the lock of the model, as the graph layer may update them.
+		modelGraph.Nodes = append(modelGraph.Nodes, buildModelNodeView(node))
+	}
+	return modelGraph
+}
+
+// buildModelNodeView returns the view of a node of a model. The checksums are read from the nodes as computed by the
+// graph layer, since computing them would update the nodes.
+func buildModelNodeView(node AviModelNode) models.ModelNode {
+	modelNode := models.ModelNode{NodeType: node.GetNodeType()}
+	switch n := node.(type) {
+	case *AviVsNode:
+		vs := buildVSView(n)
+		modelNode.NodeRef, modelNode.VirtualService = vs.NodeRef, &vs
+	case *AviEvhVsNode:
+		vs := buildEvhVSView(n)
+		modelNode.NodeRef, modelNode.VirtualService = vs.NodeRef, &vs
+	case *AviVrfNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Checksum: n.CloudConfigCksum}
+	case *AviVSVIPNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviPoolNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviPoolGroupNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviTLSKeyCertNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviPkiProfileNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviHTTPDataScriptNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviHttpPolicySetNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviL4PolicyNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviNetworkSecurityPolicyNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	case *AviHealthMonitorNode:
+		modelNode.NodeRef = models.NodeRef{Name: n.Name, Tenant: n.Tenant, Checksum: n.CloudConfigCksum}
+	}
+	return modelNode
+}
+
+func buildVSView(vs *AviVsNode) models.VSNode {
+	vsNode := models.VSNode{
+		NodeRef:                 models.NodeRef{Name: vs.Name, Tenant: vs.Tenant, Checksum: vs.CloudConfigCksum},
+		VrfContext:              vs.VrfContext,
+		ServiceEngineGroup:      vs.ServiceEngineGroup,
+		ApplicationProfile:      vs.ApplicationProfile,
+		NetworkProfile:          vs.NetworkProfile,
+		PortProtocols:           buildPortProtocolViews(vs.PortProto),
+		VHDomainNames:           vs.VHDomainNames,
+		Dedicated:               vs.Dedicated,
+		ServiceMetadata:         vs.ServiceMetadata,
+		VSVIPs:                  buildVSVIPViews(vs.VSVIPRefs),
+		PoolGroups:              buildPoolGroupViews(vs.PoolGroupRefs),
+		Pools:                   buildPoolViews(vs.PoolRefs),
+		HTTPPolicySets:          buildHTTPPolicySetRefs(vs.HttpPolicyRefs),
+		DataScripts:             buildDataScriptRefs(vs.HTTPDSrefs),
+		SSLKeyCerts:             buildSSLKeyCertViews(vs.SSLKeyCertRefs),
+		CACerts:                 buildSSLKeyCertViews(vs.CACertRefs),
+		L4PolicySets:            buildL4PolicyRefs(vs.L4PolicyRefs),
+		NetworkSecurityPolicies: buildNetworkSecurityPolicyRefs(vs.NetworkSecurityPolicyRefs),
+		HealthMonitors:          buildHealthMonitorRefs(vs.HealthMonitorNodeRefs),
+	}
+	for _, child := range vs.SniNodes {
+		vsNode.Children = append(vsNode.Children, buildVSView(child))
+	}
+	for _, child := range vs.PassthroughChildNodes {
+		vsNode.PassthroughChildren = append(vsNode.PassthroughChildren, buildVSView(child))
+	}
+	return vsNode
+}
+
+func buildEvhVSView(vs *AviEvhVsNode) models.VSNode {
+	vsNode := models.VSNode{
+		NodeRef:                 models.NodeRef{Name: vs.Name, Tenant: vs.Tenant, Checksum: vs.CloudConfigCksum},
+		VrfContext:              vs.VrfContext,
+		ServiceEngineGroup:      vs.ServiceEngineGroup,
+		ApplicationProfile:      vs.ApplicationProfile,
+		NetworkProfile:          vs.NetworkProfile,
+		PortProtocols:           buildPortProtocolViews(vs.PortProto),
+		VHDomainNames:           vs.VHDomainNames,
+		Dedicated:               vs.Dedicated,
+		ServiceMetadata:         vs.ServiceMetadata,
+		VSVIPs:                  buildVSVIPViews(vs.VSVIPRefs),
+		PoolGroups:              buildPoolGroupViews(vs.PoolGroupRefs),
+		Pools:                   buildPoolViews(vs.PoolRefs),
+		HTTPPolicySets:          buildHTTPPolicySetRefs(vs.HttpPolicyRefs),
+		DataScripts:             buildDataScriptRefs(vs.HTTPDSrefs),
+		SSLKeyCerts:             buildSSLKeyCertViews(vs.SSLKeyCertRefs),
+		CACerts:                 buildSSLKeyCertViews(vs.CACertRefs),
+		NetworkSecurityPolicies: buildNetworkSecurityPolicyRefs(vs.NetworkSecurityPolicyRefs),
+	}
+	for _, child := range vs.EvhNodes {
+		vsNode.Children = append(vsNode.Children, buildEvhVSView(child))
+	}
+	return vsNode
+}
+
+func buildPortProtocolViews(portProtos []AviPortHostProtocol) []models.PortProtocol {
+	var portProtocols []models.PortProtocol
+	for _, pp := range portProtos {
+		portProtocols = append(portProtocols, models.PortProtocol{Port: pp.Port, Protocol: pp.Protocol, Hosts: pp.Hosts, EnableSSL: pp.EnableSSL})
+	}
+	return portProtocols
+}
+
+func buildVSVIPViews(vsvips []*AviVSVIPNode) []models.VSVIPNode {
+	var vsvipNodes []models.VSVIPNode
+	for _, vsvip := range vsvips {
+		vsvipNodes = append(vsvipNodes, models.VSVIPNode{
+			NodeRef:    models.NodeRef{Name: vsvip.Name, Tenant: vsvip.Tenant, Checksum: vsvip.CloudConfigCksum},
+			FQDNs:      vsvip.FQDNs,
+			IPAddress:  vsvip.IPAddress,
+			VrfContext: vsvip.VrfContext,
+		})
+	}
+	return vsvipNodes
+}
+
+func buildPoolViews(pools []*AviPoolNode) []models.PoolNode {
+	var poolNodes []models.PoolNode
+	for _, pool := range pools {
+		poolNode := models.PoolNode{
+			NodeRef:    models.NodeRef{Name: pool.Name, Tenant: pool.Tenant, Checksum: pool.CloudConfigCksum},
+			Port:       pool.Port,
+			Protocol:   pool.Protocol,
+			TargetPort: pool.TargetPort.String(),
+			SniEnabled: pool.SniEnabled,
+			Servers:    []models.PoolServer{},
+		}
+		if pool.PkiProfile != nil {
+			poolNode.PkiProfile = pool.PkiProfile.Name
+		}
+		for _, server := range pool.Servers {
+			poolServer := models.PoolServer{Port: server.Port, ServerNode: server.ServerNode, Disabled: server.Disabled}
+			if server.Ip.Addr != nil {
+				poolServer.IP = *server.Ip.Addr
+			}
+			poolNode.Servers = append(poolNode.Servers, poolServer)
+		}
+		poolNodes = append(poolNodes, poolNode)
+	}
+	return poolNodes
+}
+
+func buildPoolGroupViews(poolGroups []*AviPoolGroupNode) []models.PoolGroupNode {
+	var pgNodes []models.PoolGroupNode
+	for _, pg := range poolGroups {
+		pgNode := models.PoolGroupNode{
+			NodeRef: models.NodeRef{Name: pg.Name, Tenant: pg.Tenant, Checksum: pg.CloudConfigCksum},
+			Members: []string{},
+		}
+		for _, member := range pg.Members {
+			if member.PoolRef != nil {
+				pgNode.Members = append(pgNode.Members, *member.PoolRef)
+			}
+		}
+		pgNodes = append(pgNodes, pgNode)
+	}
+	return pgNodes
+}
+
+func buildSSLKeyCertViews(sslKeyCerts []*AviTLSKeyCertNode) []models.SSLKeyCertNode {
+	var sslNodes []models.SSLKeyCertNode
+	for _, sslKeyCert := range sslKeyCerts {
+		sslNodes = append(sslNodes, models.SSLKeyCertNode{
+			NodeRef: models.NodeRef{Name: sslKeyCert.Name, Tenant: sslKeyCert.Tenant, Checksum: sslKeyCert.CloudConfigCksum},
+			Type:    sslKeyCert.Type,
+			Port:    sslKeyCert.Port,
+		})
+	}
+	return sslNodes
+}
+
+func buildHTTPPolicySetRefs(httpPolicySets []*AviHttpPolicySetNode) []models.NodeRef {
+	var refs []models.NodeRef
+	for _, node := range httpPolicySets {
+		refs = append(refs, models.NodeRef{Name: node.Name, Tenant: node.Tenant, Checksum: node.CloudConfigCksum})
+	}
+	return refs
+}
+
+func buildDataScriptRefs(dataScripts []*AviHTTPDataScriptNode) []models.NodeRef {
+	var refs []models.NodeRef
+	for _, node := range dataScripts {
+		refs = append(refs, models.NodeRef{Name: node.Name, Tenant: node.Tenant, Checksum: node.CloudConfigCksum})
+	}
+	return refs
+}
+
+func buildL4PolicyRefs(l4Policies []*AviL4PolicyNode) []models.NodeRef {
+	var refs []models.NodeRef
+	for _, node := range l4Policies {
+		refs = append(refs, models.NodeRef{Name: node.Name, Tenant: node.Tenant, Checksum: node.CloudConfigCksum})
+	}
+	return refs
+}
+
+func buildNetworkSecurityPolicyRefs(policies []*AviNetworkSecurityPolicyNode) []models.NodeRef {
+	var refs []models.NodeRef
+	for _, node := range policies {
+		refs = append(refs, models.NodeRef{Name: node.Name, Tenant: node.Tenant, Checksum: node.CloudConfigCksum})
+	}
+	return refs
+}
+
+func buildHealthMonitorRefs(healthMonitors []*AviHealthMonitorNode) []models.NodeRef {
+	var refs []models.NodeRef
+	for _, node := range healthMonitors {
+		refs = append(refs, models.NodeRef{Name: node.Name, Tenant: node.Tenant, Checksum: node.CloudConfigCksum})
+	}
+	return refs
+}
@@ -81,7 +81,7 @@ func validateSpecFromHostnameCache(key string, ingress *networkingv1.Ingress) bo
 					}
 				}
 			}
-		} else {
+		} else if ingress.Spec.DefaultBackend == nil {
 			utils.AviLog.Warnf("key: %s, msg: Found Ingress: %s without service backends. Not going to process.", key, ingress.Name)
 			return false
 		}
@@ -211,6 +211,20 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 		passthroughEnabled = strings.EqualFold(val, "true")
 	}
 
+	// The defaultBackend serves the requests which don't match any path of the hosts of the ingress,
+	// it is added as a Prefix path / to each host.
+	var defaultBackend *IngressHostPathSvc
+	if ingSpec.DefaultBackend != nil {
+		if ingSpec.DefaultBackend.Service != nil {
+			backend := v.parseIngressServiceBackend(ns, "/", networkingv1.PathTypePrefix, ingSpec.DefaultBackend.Service, key)
+			backend.defaultBackend = true
+			defaultBackend = &backend
+		} else {
+			utils.AviLog.Warnf("key: %s, msg: only service backends are supported as defaultBackend of the ingress %s/%s", key, ns, ingName)
+		}
+	}
+	ingressConfig.DefaultBackend = defaultBackend
+
 	var tlsConfigs []TlsSettings
 	for _, rule := range ingSpec.Rules {
 		var hostPathMapSvcList HostMetadata
@@ -261,33 +275,21 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 				secretHostsMap[secretName] = append(secretHostsMap[secretName], hostName)
 			}
 		}
+		// the defaultBackend is added back after the paths of the rule, if the host still has no catch-all path
+		hostPathMapSvcList.ingressHPSvc = removeDefaultBackendPath(hostPathMapSvcList.ingressHPSvc)
 		if rule.IngressRuleValue.HTTP != nil {
 			for _, path := range rule.IngressRuleValue.HTTP.Paths {
 				pathType := networkingv1.PathTypeImplementationSpecific
 				if path.PathType != nil {
 					pathType = *path.PathType
 				}
-				hostPathMapSvc := IngressHostPathSvc{
-					Path:        path.Path,
-					PathType:    pathType,
-					ServiceName: path.Backend.Service.Name,
-					Port:        path.Backend.Service.Port.Number,
-					PortName:    path.Backend.Service.Port.Name,
-					TargetPort:  v.findTargetPort(path.Backend.Service.Name, ns, &path.Backend.Service.Port, key),
-				}
-				if hostPathMapSvc.PortName == "" {
-					// fill the port name as the port name is not given in the ingress
-					hostPathMapSvc.PortName = v.findPortName(path.Backend.Service.Name, ns, path.Backend.Service.Port.Number, key)
-				}
-				if hostPathMapSvc.Port == 0 {
-					// Default to port 80 if not set in the ingress object
-					hostPathMapSvc.Port = 80
-				}
-				// for ingress use 100 as default weight
-				hostPathMapSvc.weight = 100
+				hostPathMapSvc := v.parseIngressServiceBackend(ns, path.Path, pathType, path.Backend.Service, key)
 				hostPathMapSvcList.ingressHPSvc = append(hostPathMapSvcList.ingressHPSvc, hostPathMapSvc)
 			}
 		}
+		if defaultBackend != nil && !passthroughEnabled && !hasCatchAllPath(hostPathMapSvcList.ingressHPSvc) {
+			hostPathMapSvcList.ingressHPSvc = append(hostPathMapSvcList.ingressHPSvc, *defaultBackend)
+		}
 
 		if passthroughEnabled {
 			pass.host = hostName
@@ -402,6 +404,49 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 	return ingressConfig
 }
 
+// parseIngressServiceBackend returns the path and the service port of an ingress backend.
+func (v *Validator) parseIngressServiceBackend(ns, path string, pathType networkingv1.PathType, backend *networkingv1.IngressServiceBackend, key string) IngressHostPathSvc {
+	hostPathMapSvc := IngressHostPathSvc{
+		Path:        path,
+		PathType:    pathType,
+		ServiceName: backend.Name,
+		Port:        backend.Port.Number,
+		PortName:    backend.Port.Name,
+		TargetPort:  v.findTargetPort(backend.Name, ns, &backend.Port, key),
+	}
+	if hostPathMapSvc.PortName == "" {
+		// fill the port name as the port name is not given in the ingress
+		hostPathMapSvc.PortName = v.findPortName(backend.Name, ns, backend.Port.Number, key)
+	}
+	if hostPathMapSvc.Port == 0 {
+		// Default to port 80 if not set in the ingress object
+		hostPathMapSvc.Port = 80
+	}
+	// for ingress use 100 as default weight
+	hostPathMapSvc.weight = 100
+	return hostPathMapSvc
+}
+
+// hasCatchAllPath checks if one of the paths matches all the requests of a host.
+func hasCatchAllPath(paths []IngressHostPathSvc) bool {
+	for _, path := range paths {
+		if (path.Path == "/" || path.Path == "") && path.PathType != networkingv1.PathTypeExact {
+			return true
+		}
+	}
+	return false
+}
+
+func removeDefaultBackendPath(paths []IngressHostPathSvc) []IngressHostPathSvc {
+	filteredPaths := make([]IngressHostPathSvc, 0, len(paths))
+	for _, path := range paths {
+		if !path.defaultBackend {
+			filteredPaths = append(filteredPaths, path)
+		}
+	}
+	return filteredPaths
+}
+
 func (v *Validator) findTargetPort(serviceName, ns string, serviceBackendPort *networkingv1.ServiceBackendPort, key string) intstr.IntOrString {
 	// Query the service and obtain the targetPort
 	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(ns).Get(serviceName)
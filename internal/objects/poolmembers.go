@@ -0,0 +1,80 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package objects
+
+import (
+	"sync"
+)
+
+var poolMemberInstance *PoolMemberLister
+var poolMemberOnce sync.Once
+
+func SharedPoolMemberLister() *PoolMemberLister {
+	poolMemberOnce.Do(func() {
+		store := NewObjectMapStore()
+		poolMemberInstance = &PoolMemberLister{}
+		poolMemberInstance.store = store
+	})
+	return poolMemberInstance
+}
+
+// PoolMember is a Pod added as a server of a pool, with the IP and port of the server.
+type PoolMember struct {
+	Pod  string
+	IP   string
+	Port int32
+}
+
+// PoolMemberLister stores the Pods added as the servers of a pool, keyed by tenant/pool name.
+type PoolMemberLister struct {
+	store *ObjectMapStore
+}
+
+func (a *PoolMemberLister) Save(poolKey string, members []PoolMember) {
+	if len(members) == 0 {
+		a.store.Delete(poolKey)
+		return
+	}
+	a.store.AddOrUpdate(poolKey, members)
+}
+
+func (a *PoolMemberLister) Get(poolKey string) (bool, []PoolMember) {
+	ok, obj := a.store.Get(poolKey)
+	if !ok {
+		return false, nil
+	}
+	return true, obj.([]PoolMember)
+}
+
+// GetPoolsForPods returns the members of the given Pods, keyed by the Pods and then by the pools they belong to.
+func (a *PoolMemberLister) GetPoolsForPods(podKeys map[string]bool) map[string]map[string][]PoolMember {
+	podPools := make(map[string]map[string][]PoolMember)
+	for poolKey, obj := range a.store.CopyAllObjects() {
+		for _, member := range obj.([]PoolMember) {
+			if !podKeys[member.Pod] {
+				continue
+			}
+			if _, ok := podPools[member.Pod]; !ok {
+				podPools[member.Pod] = make(map[string][]PoolMember)
+			}
+			podPools[member.Pod][poolKey] = append(podPools[member.Pod][poolKey], member)
+		}
+	}
+	return podPools
+}
+
+func (a *PoolMemberLister) Delete(poolKey string) {
+	a.store.Delete(poolKey)
+}
@@ -163,6 +163,7 @@ func (rest *RestOperations) AviDSCacheAdd(rest_op *utils.RestOp, vsKey avicache.
 			checksum += utils.Hash(fmt.Sprintf(utils.HTTP_DS_SCRIPT_MODIFIED, ds_cache_obj.PoolGroups[0]))
 		}
 		ds_cache_obj.CloudConfigCksum = checksum
+		ds_cache_obj.LastModified, _ = resp["_last_modified"].(string)
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
 		rest.cache.DSCache.AviCacheAdd(k, &ds_cache_obj)
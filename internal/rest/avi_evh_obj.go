@@ -37,7 +37,9 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 	var sni_to_delete []avicache.NamespaceName
 	var httppol_to_delete []avicache.NamespaceName
 	var l4pol_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
+	var ds_to_delete []avicache.NamespaceName
 	var vsvipErr error
 	var publishKey string
 
@@ -69,6 +71,8 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 		pools_to_delete, rest_ops = rest.PoolCU(aviVsNode.PoolRefs, vs_cache_obj, namespace, rest_ops, key)
 		pgs_to_delete, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, vs_cache_obj, namespace, rest_ops, key)
 		httppol_to_delete, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		ds_to_delete, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, vs_cache_obj, namespace, rest_ops, key)
+		nsp_to_delete, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NetworkSecurityPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 		if vs_cache_obj.CloudConfigCksum == strconv.Itoa(int(aviVsNode.GetCheckSum())) {
 			utils.AviLog.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
@@ -97,6 +101,8 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 		_, rest_ops = rest.PoolCU(aviVsNode.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 
 		// The cache was not found - it's a POST call.
 		restOp := rest.AviVsBuildForEvh(aviVsNode, utils.RestPost, nil, key)
@@ -125,6 +131,8 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 	rest_ops = rest.VSVipDelete(vsvip_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.HTTPPolicyDelete(httppol_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.L4PolicyDelete(l4pol_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.NetworkSecurityPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.DSDelete(ds_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolGroupDelete(pgs_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolDelete(pools_to_delete, namespace, rest_ops, key)
 	if success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, true); !success {
@@ -168,6 +176,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 	var sni_pools_to_delete []avicache.NamespaceName
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
@@ -187,6 +196,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				nsp_to_delete, rest_ops = rest.NetworkSecurityPolicyCU(sni_node.NetworkSecurityPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
 
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
@@ -205,6 +215,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.NetworkSecurityPolicyCU(sni_node.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuildForEvh(sni_node, utils.RestPost, nil, key)
@@ -214,6 +225,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 		}
 		rest_ops = rest.SSLKeyCertDelete(sslkey_cert_delete, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.NetworkSecurityPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the EVH VSes to be deleted are: %s", key, cache_sni_nodes)
@@ -224,6 +236,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NetworkSecurityPolicyCU(sni_node.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuildForEvh(sni_node, utils.RestPost, nil, key)
@@ -360,6 +373,10 @@ func (rest *RestOperations) AviVsBuildForEvh(vs_meta *nodes.AviEvhVsNode, rest_m
 		for i, pp := range vs_meta.PortProto {
 			port := pp.Port
 			svc := avimodels.Service{Port: &port, EnableSsl: &vs_meta.PortProto[i].EnableSSL, EnableHttp2: &vs_meta.PortProto[i].EnableHTTP2}
+			if pp.Passthrough {
+				// The TLS connections on the port are passed through to the pools selected by the datascripts.
+				svc.OverrideApplicationProfileRef = proto.String("/api/applicationprofile/?name=" + utils.DEFAULT_L4_APP_PROFILE)
+			}
 			vs.Services = append(vs.Services, &svc)
 		}
 
@@ -384,10 +401,15 @@ func (rest *RestOperations) AviVsBuildForEvh(vs_meta *nodes.AviEvhVsNode, rest_m
 		}
 		vs.HTTPPolicies = httpPolicyCollection
 
-		// Datascripts from hostrule.
 		var datascriptCollection []*avimodels.VSDataScripts
-		for i, script := range vs_meta.VsDatascriptRefs {
+		for i, ds := range vs_meta.HTTPDSrefs {
 			j := int32(i)
+			dsRef := "/api/vsdatascriptset/?name=" + ds.Name
+			datascriptCollection = append(datascriptCollection, &avimodels.VSDataScripts{Index: &j, VsDatascriptSetRef: &dsRef})
+		}
+		// Datascripts from hostrule.
+		for i, script := range vs_meta.VsDatascriptRefs {
+			j := int32(len(vs_meta.HTTPDSrefs) + i)
 			datascript := script
 			datascripts := &avimodels.VSDataScripts{VsDatascriptSetRef: &datascript, Index: &j}
 			datascriptCollection = append(datascriptCollection, datascripts)
@@ -429,6 +451,10 @@ func (rest *RestOperations) AviVsBuildForEvh(vs_meta *nodes.AviEvhVsNode, rest_m
 			vs.RemoveListeningPortOnVsDown = &vsDownOnPoolDown
 		}
 		vs.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+		if len(vs_meta.NetworkSecurityPolicyRefs) > 0 {
+			nspRef := fmt.Sprintf("/api/networksecuritypolicy/?name=%s", vs_meta.NetworkSecurityPolicyRefs[0].Name)
+			vs.NetworkSecurityPolicyRef = &nspRef
+		}
 
 		if err := copier.CopyWithOption(&vs, &vs_meta.AviVsNodeGeneratedFields, copier.Option{IgnoreEmpty: true}); err != nil {
 			utils.AviLog.Warnf("key: %s, msg: unable to set few parameters in the VS, err: %v", key, err)
@@ -505,6 +531,7 @@ func (rest *RestOperations) AviVsChildEvhBuild(vs_meta *nodes.AviEvhVsNode, rest
 		ErrorPageProfileRef:   &vs_meta.ErrorPageProfileRef,
 		Enabled:               vs_meta.Enabled,
 		VhType:                proto.String(utils.VS_TYPE_VH_ENHANCED),
+		SidebandProfile:       vs_meta.SidebandProfile,
 	}
 
 	if len(vs_meta.ICAPProfileRefs) != 0 {
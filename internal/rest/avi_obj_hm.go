@@ -0,0 +1,205 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviHealthMonitorBuild(hm_meta *nodes.AviHealthMonitorNode, cache_obj *avicache.AviHealthMonitorCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(hm_meta.Name, lib.HealthMonitor) {
+		utils.AviLog.Warnf("key: %s not processing health monitor object", key)
+		return nil
+	}
+	name := hm_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", hm_meta.Tenant)
+	hmType := "HEALTH_MONITOR_HTTP"
+	monitorPort := hm_meta.MonitorPort
+
+	// kube-proxy answers on the healthCheckNodePort with a 200 only on the nodes
+	// which host a ready endpoint of the Service, and with a 503 on the others.
+	httpRequest := "GET /healthz HTTP/1.0"
+	hm := avimodels.HealthMonitor{
+		Name:        &name,
+		TenantRef:   &tenant,
+		Type:        &hmType,
+		MonitorPort: &monitorPort,
+		HTTPMonitor: &avimodels.HealthMonitorHTTP{
+			HTTPRequest:      &httpRequest,
+			HTTPResponseCode: []string{"HTTP_2XX"},
+		},
+	}
+	hm.Markers = lib.GetAllMarkers(hm_meta.AviMarkers)
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/healthmonitor/" + cache_obj.Uuid
+		rest_op = utils.RestOp{
+			ObjName: hm_meta.Name,
+			Path:    path,
+			Method:  utils.RestPut,
+			Obj:     hm,
+			Tenant:  hm_meta.Tenant,
+			Model:   "HealthMonitor",
+		}
+	} else {
+		// Patch an existing health monitor object if it exists in the cache but not associated with this VS.
+		hm_key := avicache.NamespaceName{Namespace: hm_meta.Tenant, Name: hm_meta.Name}
+		hm_cache, ok := rest.cache.HMCache.AviCacheGet(hm_key)
+		if ok {
+			hm_cache_obj, _ := hm_cache.(*avicache.AviHealthMonitorCache)
+			path = "/api/healthmonitor/" + hm_cache_obj.Uuid
+			rest_op = utils.RestOp{
+				ObjName: hm_meta.Name,
+				Path:    path,
+				Method:  utils.RestPut,
+				Obj:     hm,
+				Tenant:  hm_meta.Tenant,
+				Model:   "HealthMonitor",
+			}
+		} else {
+			path = "/api/healthmonitor/"
+			rest_op = utils.RestOp{
+				ObjName: hm_meta.Name,
+				Path:    path,
+				Method:  utils.RestPost,
+				Obj:     hm,
+				Tenant:  hm_meta.Tenant,
+				Model:   "HealthMonitor",
+			}
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("HealthMonitor Restop %v AviHealthMonitorMeta %v",
+		rest_op, utils.Stringify(hm_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviHealthMonitorDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/healthmonitor/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "HealthMonitor",
+	}
+	utils.AviLog.Infof(spew.Sprintf("Health Monitor DELETE Restop %v ",
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviHealthMonitorCacheAdd(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for healthmonitor, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "healthmonitor", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("Unable to find Health Monitor obj in resp %v", rest_op.Response)
+		return errors.New("Health Monitor object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("Name not present in response %v", resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("Uuid not present in response %v", resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var hm avimodels.HealthMonitor
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			hm = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor)
+		case avimodels.HealthMonitor:
+			hm = rest_op.Obj.(avimodels.HealthMonitor)
+		}
+		var monitorPort int32
+		if hm.MonitorPort != nil {
+			monitorPort = *hm.MonitorPort
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		//This is fetching data from response send at avi controller.
+		cksum := lib.HealthMonitorChecksum(monitorPort, emptyIngestionMarkers, hm.Markers, true)
+		hm_cache_obj := avicache.AviHealthMonitorCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: cksum,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.HMCache.AviCacheAdd(k, &hm_cache_obj)
+		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+			if found {
+				vs_cache_obj.AddToHMKeyCollection(k)
+				utils.AviLog.Infof("Modified the VS cache for health monitor object. The cache now is :%v", utils.Stringify(vs_cache_obj))
+			}
+		} else {
+			vs_cache_obj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vs_cache_obj.AddToHMKeyCollection(k)
+			utils.AviLog.Infof(spew.Sprintf("Added VS cache key during health monitor update %v val %v", vsKey,
+				vs_cache_obj))
+		}
+		utils.AviLog.Infof(spew.Sprintf("Added Health Monitor cache k %v val %v", k,
+			hm_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviHealthMonitorCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	hmKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.HMCache.AviCacheDelete(hmKey)
+	vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+		if found {
+			vs_cache_obj.RemoveFromHMKeyCollection(hmKey)
+		}
+	}
+
+	return nil
+}
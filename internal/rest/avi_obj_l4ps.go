@@ -54,8 +54,11 @@ func (rest *RestOperations) AviL4PSBuild(hps_meta *nodes.AviL4PolicyNode, cache_
 	var l4rules []*avimodels.L4Rule
 	for _, hppmap := range hps_meta.PortPool {
 		if hppmap.Port != 0 {
-			// Keep the l4 policy rule name similar to the Pool name it corresponds to.
+			// Keep the l4 policy rule name similar to the Pool or the PoolGroup name it corresponds to.
 			ruleName := hppmap.Pool
+			if hppmap.PoolGroup != "" {
+				ruleName = hppmap.PoolGroup
+			}
 			if lib.CheckObjectNameLength(ruleName, lib.L4PSRule) {
 				utils.AviLog.Warnf("key: %s not adding L4 PolicyRule to Policyset object", key)
 				continue
@@ -67,10 +70,17 @@ func (rest *RestOperations) AviL4PSBuild(hps_meta *nodes.AviL4PolicyNode, cache_
 			ports = append(ports, int64(hppmap.Port))
 			l4action := &avimodels.L4RuleAction{}
 			actionSelect := &avimodels.L4RuleActionSelectPool{}
-			poolName := hppmap.Pool
-			actionSelect.PoolRef = &poolName
-			poolSelect := "L4_RULE_ACTION_SELECT_POOL"
-			actionSelect.ActionType = &poolSelect
+			if hppmap.PoolGroup != "" {
+				pgName := hppmap.PoolGroup
+				actionSelect.PoolGroupRef = &pgName
+				pgSelect := "L4_RULE_ACTION_SELECT_POOLGROUP"
+				actionSelect.ActionType = &pgSelect
+			} else {
+				poolName := hppmap.Pool
+				actionSelect.PoolRef = &poolName
+				poolSelect := "L4_RULE_ACTION_SELECT_POOL"
+				actionSelect.ActionType = &poolSelect
+			}
 			l4action.SelectPool = actionSelect
 			l4rule.Action = l4action
 			j := idx
@@ -202,6 +212,7 @@ func (rest *RestOperations) AviL4PolicyCacheAdd(rest_op *utils.RestOp, vsKey avi
 		var protocols []string
 		var ports []int64
 		var pools []string
+		var pgs []string
 		switch rest_op.Obj.(type) {
 		case utils.AviRestObjMacro:
 			l4policyset = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.L4PolicySet)
@@ -212,8 +223,14 @@ func (rest *RestOperations) AviL4PolicyCacheAdd(rest_op *utils.RestOp, vsKey avi
 			// cannot create an external load balancer with mix protocol - hence just caching the protocol once
 			protocols = append(protocols, *rule.Match.Protocol.Protocol)
 			ports = rule.Match.Port.Ports
-			pool := strings.TrimPrefix(*rule.Action.SelectPool.PoolRef, "/api/pool?name=")
-			pools = append(pools, pool)
+			if rule.Action.SelectPool.PoolRef != nil {
+				pool := strings.TrimPrefix(*rule.Action.SelectPool.PoolRef, "/api/pool?name=")
+				pools = append(pools, pool)
+			}
+			if rule.Action.SelectPool.PoolGroupRef != nil {
+				pg := strings.TrimPrefix(*rule.Action.SelectPool.PoolGroupRef, "/api/poolgroup?name=")
+				pgs = append(pgs, pg)
+			}
 		}
 		emptyIngestionMarkers := utils.AviObjectMarkers{}
 		//This is fetching data from response send at avi controller.
@@ -222,6 +239,7 @@ func (rest *RestOperations) AviL4PolicyCacheAdd(rest_op *utils.RestOp, vsKey avi
 			Uuid:             uuid,
 			LastModified:     lastModifiedStr,
 			Pools:            pools,
+			PoolGroups:       pgs,
 			CloudConfigCksum: cksum,
 		}
 
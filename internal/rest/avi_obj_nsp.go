@@ -0,0 +1,232 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviNetworkSecurityPolicyBuild(nsp_meta *nodes.AviNetworkSecurityPolicyNode, cache_obj *avicache.AviNetworkSecurityPolicyCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(nsp_meta.Name, lib.NetworkSecurityPolicy) {
+		utils.AviLog.Warnf("key: %s not processing network security policy object", key)
+		return nil
+	}
+	name := nsp_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", nsp_meta.Tenant)
+	cr := lib.AKOUser
+
+	nsp := avimodels.NetworkSecurityPolicy{
+		Name:      &name,
+		CreatedBy: &cr,
+		TenantRef: &tenant,
+	}
+	nsp.Markers = lib.GetAllMarkers(nsp_meta.AviMarkers)
+
+	// The clients outside of the source ranges are denied, everything else falls through
+	// to the default behaviour of the virtualservice.
+	var prefixes []*avimodels.IPAddrPrefix
+	for _, sourceRange := range nsp_meta.SourceRanges {
+		_, ipNet, err := net.ParseCIDR(sourceRange)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: skipping invalid source range %s, err: %v", key, sourceRange, err)
+			continue
+		}
+		addr := ipNet.IP.String()
+		addrType := "V4"
+		if ipNet.IP.To4() == nil {
+			addrType = "V6"
+		}
+		ones, _ := ipNet.Mask.Size()
+		mask := int32(ones)
+		prefixes = append(prefixes, &avimodels.IPAddrPrefix{
+			IPAddr: &avimodels.IPAddr{Addr: &addr, Type: &addrType},
+			Mask:   &mask,
+		})
+	}
+	ruleName := name + "-deny"
+	action := "NETWORK_SECURITY_POLICY_ACTION_TYPE_DENY"
+	matchCriteria := "IS_NOT_IN"
+	enable := true
+	var idx int32
+	nsp.Rules = []*avimodels.NetworkSecurityRule{{
+		Name:   &ruleName,
+		Action: &action,
+		Enable: &enable,
+		Index:  &idx,
+		Match: &avimodels.NetworkSecurityMatchTarget{
+			ClientIP: &avimodels.IPAddrMatch{
+				MatchCriteria: &matchCriteria,
+				Prefixes:      prefixes,
+			},
+		},
+	}}
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/networksecuritypolicy/" + cache_obj.Uuid
+		rest_op = utils.RestOp{
+			ObjName: nsp_meta.Name,
+			Path:    path,
+			Method:  utils.RestPut,
+			Obj:     nsp,
+			Tenant:  nsp_meta.Tenant,
+			Model:   "NetworkSecurityPolicy",
+		}
+	} else {
+		// Patch an existing network security policy object if it exists in the cache but not associated with this VS.
+		nsp_key := avicache.NamespaceName{Namespace: nsp_meta.Tenant, Name: nsp_meta.Name}
+		nsp_cache, ok := rest.cache.NSPCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+			path = "/api/networksecuritypolicy/" + nsp_cache_obj.Uuid
+			rest_op = utils.RestOp{
+				ObjName: nsp_meta.Name,
+				Path:    path,
+				Method:  utils.RestPut,
+				Obj:     nsp,
+				Tenant:  nsp_meta.Tenant,
+				Model:   "NetworkSecurityPolicy",
+			}
+		} else {
+			path = "/api/networksecuritypolicy/"
+			rest_op = utils.RestOp{
+				ObjName: nsp_meta.Name,
+				Path:    path,
+				Method:  utils.RestPost,
+				Obj:     nsp,
+				Tenant:  nsp_meta.Tenant,
+				Model:   "NetworkSecurityPolicy",
+			}
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("NetworkSecurityPolicy Restop %v AviNetworkSecurityPolicyMeta %v",
+		rest_op, utils.Stringify(nsp_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/networksecuritypolicy/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "NetworkSecurityPolicy",
+	}
+	utils.AviLog.Infof(spew.Sprintf("Network Security Policy DELETE Restop %v ",
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyCacheAdd(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for networksecuritypolicy, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "networksecuritypolicy", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("Unable to find Network Security Policy obj in resp %v", rest_op.Response)
+		return errors.New("Network Security Policy object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("Name not present in response %v", resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("Uuid not present in response %v", resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var nsp avimodels.NetworkSecurityPolicy
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			nsp = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy)
+		case avimodels.NetworkSecurityPolicy:
+			nsp = rest_op.Obj.(avimodels.NetworkSecurityPolicy)
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		//This is fetching data from response send at avi controller.
+		cksum := lib.NetworkSecurityPolicyChecksum(avicache.NetworkSecurityPolicySourceRanges(nsp), emptyIngestionMarkers, nsp.Markers, true)
+		nsp_cache_obj := avicache.AviNetworkSecurityPolicyCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: cksum,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.NSPCache.AviCacheAdd(k, &nsp_cache_obj)
+		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+			if found {
+				vs_cache_obj.AddToNSPKeyCollection(k)
+				utils.AviLog.Infof("Modified the VS cache for network security policy object. The cache now is :%v", utils.Stringify(vs_cache_obj))
+			}
+		} else {
+			vs_cache_obj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vs_cache_obj.AddToNSPKeyCollection(k)
+			utils.AviLog.Infof(spew.Sprintf("Added VS cache key during network security policy update %v val %v", vsKey,
+				vs_cache_obj))
+		}
+		utils.AviLog.Infof(spew.Sprintf("Added Network Security Policy cache k %v val %v", k,
+			nsp_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	nspKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.NSPCache.AviCacheDelete(nspKey)
+	vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+		if found {
+			vs_cache_obj.RemoveFromNSPKeyCollection(nspKey)
+		}
+	}
+
+	return nil
+}
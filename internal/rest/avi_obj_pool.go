@@ -26,6 +26,7 @@ import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -116,6 +117,18 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 		pool.Tier1Lr = &pool_meta.T1Lr
 	}
 
+	if pool_meta.EnableHttp2 {
+		pool.EnableHttp2 = &pool_meta.EnableHttp2
+	}
+
+	if pool_meta.ServerTimeout != nil {
+		pool.ServerTimeout = pool_meta.ServerTimeout
+	}
+
+	if pool_meta.SniEnabled && pool_meta.ServerName != "" {
+		pool.ServerName = &pool_meta.ServerName
+	}
+
 	if !pool_meta.AttachedWithSharedVS {
 		pool.Markers = lib.GetAllMarkers(pool_meta.AviMarkers)
 	} else {
@@ -142,6 +155,10 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 		pool.ApplicationPersistenceProfileRef = pool_meta.ApplicationPersistenceProfileRef
 	}
 
+	if pool_meta.GracefulDisableTimeout != nil {
+		pool.GracefulDisableTimeout = pool_meta.GracefulDisableTimeout
+	}
+
 	for i, server := range pool_meta.Servers {
 		port := pool_meta.Port
 		sip := server.Ip
@@ -154,6 +171,9 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 			sn := server.ServerNode
 			s.ServerNode = &sn
 		}
+		if server.Disabled {
+			s.Enabled = proto.Bool(false)
+		}
 		pool.Servers = append(pool.Servers, &s)
 	}
 
@@ -393,6 +413,7 @@ func (rest *RestOperations) AviPoolCacheDel(rest_op *utils.RestOp, vsKey avicach
 		}
 	}
 	utils.AviLog.Debugf("key: %s, msg: deleting pool with key: %s", key, poolKey)
+	objects.SharedPoolMemberLister().Delete(poolKey.Namespace + "/" + poolKey.Name)
 	cacheServiceMetadataCRD := lib.CRDMetadata{}
 	if poolCache, ok := rest.cache.PoolCache.AviCacheGet(poolKey); ok {
 		if poolCacheObj, found := poolCache.(*avicache.AviPoolCache); found {
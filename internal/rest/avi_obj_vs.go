@@ -254,6 +254,10 @@ func (rest *RestOperations) AviVsBuild(vs_meta *nodes.AviVsNode, rest_method uti
 			}
 			vs.L4Policies = l4Policies
 		}
+		if len(vs_meta.NetworkSecurityPolicyRefs) > 0 {
+			nspRef := fmt.Sprintf("/api/networksecuritypolicy/?name=%s", vs_meta.NetworkSecurityPolicyRefs[0].Name)
+			vs.NetworkSecurityPolicyRef = &nspRef
+		}
 		if vs_meta.DefaultPool != "" {
 			pool_ref := "/api/pool/?name=" + vs_meta.DefaultPool
 			vs.PoolRef = &pool_ref
@@ -51,6 +51,7 @@ func (rest *RestOperations) CleanupVS(key string, skipVS bool) {
 	namespace, name := utils.ExtractNamespaceObjectName(key)
 	vsKey := avicache.NamespaceName{Namespace: namespace, Name: name}
 	vs_cache_obj := rest.getVsCacheObj(vsKey, key)
+	models.DryRunPlan.ResetModelPlan(key)
 	utils.AviLog.Infof("key: %s, msg: cleanup mode, removing all stale objects", key)
 	rest.DeleteVSOper(vsKey, vs_cache_obj, namespace, key, skipVS, false)
 	utils.AviLog.Infof("key: %s, msg: cleanup mode, stale object removal done", key)
@@ -59,6 +60,8 @@ func (rest *RestOperations) CleanupVS(key string, skipVS bool) {
 func (rest *RestOperations) DequeueNodes(key string) {
 	utils.AviLog.Infof("key: %s, msg: start rest layer sync.", key)
 	lib.DecrementQueueCounter(utils.GraphLayer)
+	// In dry-run mode, the plan of the model is computed afresh against the cache.
+	models.DryRunPlan.ResetModelPlan(key)
 	// Got the key from the Graph Layer - let's fetch the model
 	ok, avimodelIntf := objects.SharedAviGraphLister().Get(key)
 	if !ok {
@@ -82,7 +85,7 @@ func (rest *RestOperations) DequeueNodes(key string) {
 		}
 		if vs_cache_obj != nil {
 			utils.AviLog.Infof("key: %s, msg: nil model found, this is a vs deletion case", key)
-			rest.DeleteVSOper(vsKey, vs_cache_obj, namespace, key, false, false)
+			rest.DeleteVSOper(vsKey, vs_cache_obj, namespace, key, false, vs_cache_obj.ServiceMetadataObj.PassthroughParentRef != "")
 		}
 	} else if ok && avimodelIntf != nil {
 		avimodel := avimodelIntf.(*nodes.AviObjectGraph)
@@ -289,6 +292,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	var sni_to_delete []avicache.NamespaceName
 	var httppol_to_delete []avicache.NamespaceName
 	var l4pol_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
+	var hm_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	var vsvipErr error
 	var publishKey string
@@ -306,12 +311,16 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		}
 	}
 	// Order would be this: 1. Pools 2. PGs  3. DS. 4. SSLKeyCert 5. VS
+	// The vsvip of a VS with a passthrough parent is owned by the parent VS.
+	skipVSVip := aviVsNode.ServiceMetadata.PassthroughParentRef != ""
 	if vs_cache_obj != nil {
 		var rest_ops []*utils.RestOp
-		vsvip_to_delete, rest_ops, vsvipErr = rest.VSVipCU(aviVsNode.VSVIPRefs, vs_cache_obj, namespace, rest_ops, key)
-		if vsvipErr != nil {
-			if rest.CheckAndPublishForRetry(vsvipErr, publishKey, key, avimodel) {
-				return
+		if !skipVSVip {
+			vsvip_to_delete, rest_ops, vsvipErr = rest.VSVipCU(aviVsNode.VSVIPRefs, vs_cache_obj, namespace, rest_ops, key)
+			if vsvipErr != nil {
+				if rest.CheckAndPublishForRetry(vsvipErr, publishKey, key, avimodel) {
+					return
+				}
 			}
 		}
 		if aviVsNode.Dedicated {
@@ -321,11 +330,14 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 			// which shuld be the new SSLKeyCertCollection
 			sslkey_cert_delete, rest_ops = rest.SSLKeyCertCU(aviVsNode.SSLKeyCertRefs, sslkey_cert_delete, namespace, rest_ops, key)
 		}
+		// The health monitors have to be created first, as they are referred by the pools
+		hm_to_delete, rest_ops = rest.HealthMonitorCU(aviVsNode.HealthMonitorNodeRefs, vs_cache_obj, namespace, rest_ops, key)
 		pools_to_delete, rest_ops = rest.PoolCU(aviVsNode.PoolRefs, vs_cache_obj, namespace, rest_ops, key)
 		pgs_to_delete, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, vs_cache_obj, namespace, rest_ops, key)
 		httppol_to_delete, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		ds_to_delete, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, vs_cache_obj, namespace, rest_ops, key)
 		l4pol_to_delete, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		nsp_to_delete, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NetworkSecurityPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 		if vs_cache_obj.CloudConfigCksum == strconv.Itoa(int(aviVsNode.GetCheckSum())) {
 			utils.AviLog.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
@@ -343,20 +355,24 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		}
 	} else {
 		var rest_ops []*utils.RestOp
-		_, rest_ops, vsvipErr = rest.VSVipCU(aviVsNode.VSVIPRefs, nil, namespace, rest_ops, key)
-		if vsvipErr != nil {
-			if rest.CheckAndPublishForRetry(vsvipErr, publishKey, key, avimodel) {
-				return
+		if !skipVSVip {
+			_, rest_ops, vsvipErr = rest.VSVipCU(aviVsNode.VSVIPRefs, nil, namespace, rest_ops, key)
+			if vsvipErr != nil {
+				if rest.CheckAndPublishForRetry(vsvipErr, publishKey, key, avimodel) {
+					return
+				}
 			}
 		}
 		if aviVsNode.Dedicated {
 			_, rest_ops = rest.CACertCU(aviVsNode.CACertRefs, []avicache.NamespaceName{}, namespace, rest_ops, key)
 			_, rest_ops = rest.SSLKeyCertCU(aviVsNode.SSLKeyCertRefs, nil, namespace, rest_ops, key)
 		}
+		_, rest_ops = rest.HealthMonitorCU(aviVsNode.HealthMonitorNodeRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolCU(aviVsNode.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, nil, namespace, rest_ops, key)
 
 		// The cache was not found - it's a POST call.
@@ -388,9 +404,11 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	}
 	rest_ops = rest.HTTPPolicyDelete(httppol_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.L4PolicyDelete(l4pol_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.NetworkSecurityPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.DSDelete(ds_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolGroupDelete(pgs_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolDelete(pools_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.HealthMonitorDelete(hm_to_delete, namespace, rest_ops, key)
 	if success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, false); !success {
 		return
 	}
@@ -525,8 +543,10 @@ func (rest *RestOperations) DeleteVSOper(vsKey avicache.NamespaceName, vs_cache_
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.L4PolicyDelete(vs_cache_obj.L4PolicyCollection, namespace, rest_ops, key)
+		rest_ops = rest.NetworkSecurityPolicyDelete(vs_cache_obj.NSPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
+		rest_ops = rest.HealthMonitorDelete(vs_cache_obj.HMKeyCollection, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, nil, key, false)
 		if success {
 			vsKeysPending := rest.cache.VsCacheMeta.AviGetAllKeys()
@@ -564,6 +584,7 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 		rest_ops = rest.DataScriptDelete(vs_cache_obj.DSKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
+		rest_ops = rest.NetworkSecurityPolicyDelete(vs_cache_obj.NSPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, false)
@@ -580,6 +601,13 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 		shardSize = 8
 	}
 	var retry, fastRetry, processNextObj bool
+	if lib.IsDryRunEnabled() {
+		// The rest operations are only recorded in the plan, and the cache is left untouched,
+		// so that the plan always reflects the difference between the models and the cache.
+		utils.AviLog.Infof("key: %s, msg: dry-run mode, skipping %d rest operations", key, len(rest_ops))
+		models.InitDryRunPlan().AddRestOps(key, rest_ops)
+		return true, true
+	}
 	bkt := utils.Bkt(key, shardSize)
 	if len(rest.aviRestPoolClient.AviClient) > 0 && len(rest_ops) > 0 {
 		utils.AviLog.Infof("key: %s, msg: processing in rest queue number: %v", key, bkt)
@@ -733,6 +761,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLKeyCertAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNetworkSecurityPolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "HealthMonitor" {
+			rest.AviHealthMonitorCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VrfContext" {
 			rest.AviVrfCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
@@ -756,6 +788,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNetworkSecurityPolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "HealthMonitor" {
+			rest.AviHealthMonitorCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
 			rest.AviVsVipCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VSDataScriptSet" {
@@ -934,6 +970,30 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					rest_op.ObjName = L4PolicySet
 				}
 				rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				if NetworkSecurityPolicy != "" {
+					rest_op.ObjName = NetworkSecurityPolicy
+				}
+				rest.AviNetworkSecurityPolicyCacheDel(rest_op, aviObjKey, key)
+			case "HealthMonitor":
+				var HealthMonitor string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					HealthMonitor = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor).Name
+				case avimodels.HealthMonitor:
+					HealthMonitor = *rest_op.Obj.(avimodels.HealthMonitor).Name
+				}
+				if HealthMonitor != "" {
+					rest_op.ObjName = HealthMonitor
+				}
+				rest.AviHealthMonitorCacheDel(rest_op, aviObjKey, key)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -1025,6 +1085,24 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					L4PolicySet = *rest_op.Obj.(avimodels.L4PolicySet).Name
 				}
 				aviObjCache.AviPopulateOneVsL4PolCache(c, utils.CloudName, L4PolicySet)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				aviObjCache.AviPopulateOneVsNSPCache(c, utils.CloudName, NetworkSecurityPolicy)
+			case "HealthMonitor":
+				var HealthMonitor string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					HealthMonitor = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor).Name
+				case avimodels.HealthMonitor:
+					HealthMonitor = *rest_op.Obj.(avimodels.HealthMonitor).Name
+				}
+				aviObjCache.AviPopulateOneVsHMCache(c, utils.CloudName, HealthMonitor)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -1298,6 +1376,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 	var sni_pools_to_delete []avicache.NamespaceName
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
@@ -1317,6 +1396,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				nsp_to_delete, rest_ops = rest.NetworkSecurityPolicyCU(sni_node.NetworkSecurityPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
 					restOp := rest.AviVsBuild(sni_node, utils.RestPut, sni_cache_obj, key)
@@ -1334,6 +1414,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.NetworkSecurityPolicyCU(sni_node.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1343,6 +1424,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		}
 		rest_ops = rest.SSLKeyCertDelete(sslkey_cert_delete, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.NetworkSecurityPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the SNI VSes to be deleted are: %s", key, cache_sni_nodes)
@@ -1353,6 +1435,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NetworkSecurityPolicyCU(sni_node.NetworkSecurityPolicyRefs, nil, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1670,6 +1753,126 @@ func (rest *RestOperations) L4PolicyDelete(l4_to_delete []avicache.NamespaceName
 	return rest_ops
 }
 
+func (rest *RestOperations) NetworkSecurityPolicyCU(nsp_nodes []*nodes.AviNetworkSecurityPolicyNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_nsp_nodes []avicache.NamespaceName
+	// Default is POST
+	if vs_cache_obj != nil {
+		cache_nsp_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.NSPKeyCollection))
+		copy(cache_nsp_nodes, vs_cache_obj.NSPKeyCollection)
+		for _, nsp := range nsp_nodes {
+			nsp_key := avicache.NamespaceName{Namespace: namespace, Name: nsp.Name}
+			found := utils.HasElem(cache_nsp_nodes, nsp_key)
+			if found {
+				nsp_cache, ok := rest.cache.NSPCache.AviCacheGet(nsp_key)
+				if ok {
+					cache_nsp_nodes = avicache.RemoveNamespaceName(cache_nsp_nodes, nsp_key)
+					nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+					// Cache found. Let's compare the checksums
+					if nsp_cache_obj.CloudConfigCksum == nsp.GetCheckSum() {
+						utils.AviLog.Debugf("The checksums are same for network security policy cache obj %s, not doing anything", nsp_cache_obj.Name)
+					} else {
+						// The checksums are different, so it should be a PUT call.
+						restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nsp_cache_obj, key)
+						if restOp != nil {
+							rest_ops = append(rest_ops, restOp)
+						}
+					}
+				}
+			} else {
+				// Not found - it should be a POST call.
+				restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nil, key)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		}
+	} else {
+		// Everything is a POST call
+		for _, nsp := range nsp_nodes {
+			restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the network security policies to be deleted are: %s", key, cache_nsp_nodes)
+	return cache_nsp_nodes, rest_ops
+}
+
+func (rest *RestOperations) NetworkSecurityPolicyDelete(nsp_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_nsp := range nsp_to_delete {
+		nsp_key := avicache.NamespaceName{Namespace: namespace, Name: del_nsp.Name}
+		nsp_cache, ok := rest.cache.NSPCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+			restOp := rest.AviNetworkSecurityPolicyDel(nsp_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_nsp.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+func (rest *RestOperations) HealthMonitorCU(hm_nodes []*nodes.AviHealthMonitorNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_hm_nodes []avicache.NamespaceName
+	// Default is POST
+	if vs_cache_obj != nil {
+		cache_hm_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.HMKeyCollection))
+		copy(cache_hm_nodes, vs_cache_obj.HMKeyCollection)
+		for _, hm := range hm_nodes {
+			hm_key := avicache.NamespaceName{Namespace: namespace, Name: hm.Name}
+			found := utils.HasElem(cache_hm_nodes, hm_key)
+			if found {
+				hm_cache, ok := rest.cache.HMCache.AviCacheGet(hm_key)
+				if ok {
+					cache_hm_nodes = avicache.RemoveNamespaceName(cache_hm_nodes, hm_key)
+					hm_cache_obj, _ := hm_cache.(*avicache.AviHealthMonitorCache)
+					// Cache found. Let's compare the checksums
+					if hm_cache_obj.CloudConfigCksum == hm.GetCheckSum() {
+						utils.AviLog.Debugf("The checksums are same for health monitor cache obj %s, not doing anything", hm_cache_obj.Name)
+					} else {
+						// The checksums are different, so it should be a PUT call.
+						restOp := rest.AviHealthMonitorBuild(hm, hm_cache_obj, key)
+						if restOp != nil {
+							rest_ops = append(rest_ops, restOp)
+						}
+					}
+				}
+			} else {
+				// Not found - it should be a POST call.
+				restOp := rest.AviHealthMonitorBuild(hm, nil, key)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		}
+	} else {
+		// Everything is a POST call
+		for _, hm := range hm_nodes {
+			restOp := rest.AviHealthMonitorBuild(hm, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the health monitors to be deleted are: %s", key, cache_hm_nodes)
+	return cache_hm_nodes, rest_ops
+}
+
+func (rest *RestOperations) HealthMonitorDelete(hm_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_hm := range hm_to_delete {
+		hm_key := avicache.NamespaceName{Namespace: namespace, Name: del_hm.Name}
+		hm_cache, ok := rest.cache.HMCache.AviCacheGet(hm_key)
+		if ok {
+			hm_cache_obj, _ := hm_cache.(*avicache.AviHealthMonitorCache)
+			restOp := rest.AviHealthMonitorDel(hm_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_hm.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
 func (rest *RestOperations) KeyCertCU(sslkey_nodes []*nodes.AviTLSKeyCertNode, certKeys []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
 	// Default is POST
 	var cache_ssl_nodes []avicache.NamespaceName
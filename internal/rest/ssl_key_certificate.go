@@ -171,6 +171,7 @@ func (rest *RestOperations) AviSSLKeyCertAdd(rest_op *utils.RestOp, vsKey avicac
 			CloudConfigCksum: lib.SSLKeyCertChecksum(name, cert, cacert, emptyIngestionMarkers, SSLKeyAndCertificate.Markers, true),
 			HasCARef:         hasCA,
 		}
+		ssl_cache_obj.LastModified, _ = resp["_last_modified"].(string)
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
 		rest.cache.SSLKeyCache.AviCacheAdd(k, &ssl_cache_obj)
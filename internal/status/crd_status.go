@@ -314,6 +314,11 @@ func UpdateSSORuleStatus(key string, sr *akov1alpha2.SSORule, updateStatus Updat
 
 // SSORuleEventBroadcast is responsible for broadcasting SSORule specific events when the VS Cache is Added/Updated/Deleted.
 func SSORuleEventBroadcast(vsName string, vsCacheMetadataOld, vsMetadataNew lib.CRDMetadata) {
+	// The SSORules are not watched by the Gateway API container, which also programs the HostRules.
+	crdInformers := lib.AKOControlConfig().CRDInformers()
+	if crdInformers == nil || crdInformers.SSORuleInformer == nil {
+		return
+	}
 	if vsCacheMetadataOld.Value != vsMetadataNew.Value {
 		oldSRNamespaceName := strings.Split(vsCacheMetadataOld.Value, "/")
 		newSRNamespaceName := strings.Split(vsMetadataNew.Value, "/")
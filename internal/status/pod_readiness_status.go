@@ -0,0 +1,163 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+const (
+	PoolMemberOperUp = "OPER_UP"
+	// PoolMemberReadinessSyncInterval is the interval at which the server runtime of the pools is checked
+	// for the Pods waiting for their pool member readiness condition.
+	PoolMemberReadinessSyncInterval = 10 * time.Second
+)
+
+// poolServerRuntime is the runtime of a pool server, as reported by the Avi controller.
+type poolServerRuntime struct {
+	IPAddr     *models.IPAddr            `json:"ip_addr,omitempty"`
+	Port       int32                     `json:"port,omitempty"`
+	OperStatus *models.OperationalStatus `json:"oper_status,omitempty"`
+}
+
+// UpdatePoolMemberReadiness sets the pool member readiness condition of the Pods waiting for it, once the
+// Pods are servers of all their pools and the Avi controller reports all these servers up.
+func UpdatePoolMemberReadiness() {
+	if !lib.IsPodReadinessGateEnabled() || !lib.AKOControlConfig().IsLeader() {
+		return
+	}
+	pods, err := utils.GetInformers().PodInformer.Lister().Pods(metav1.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Error in listing the Pods for pool member readiness: %v", err)
+		return
+	}
+
+	pendingPods := make(map[string]*corev1.Pod)
+	podKeys := make(map[string]bool)
+	for _, pod := range pods {
+		if lib.IsPodPendingPoolMemberReadiness(pod) {
+			podKey := pod.Namespace + "/" + pod.Name
+			pendingPods[podKey] = pod
+			podKeys[podKey] = true
+		}
+	}
+	if len(pendingPods) == 0 {
+		return
+	}
+
+	// Only the pools having a member pending its readiness condition are polled, once per sync.
+	podPools := objects.SharedPoolMemberLister().GetPoolsForPods(podKeys)
+	serverRuntimes := make(map[string][]poolServerRuntime)
+	for podKey, pod := range pendingPods {
+		pools := podPools[podKey]
+		if len(pools) == 0 {
+			utils.AviLog.Debugf("Pod %s is not a server of any pool yet", podKey)
+			continue
+		}
+		allUp := true
+		for poolKey, members := range pools {
+			runtimes, ok := serverRuntimes[poolKey]
+			if !ok {
+				runtimes = getPoolServerRuntimes(poolKey)
+				serverRuntimes[poolKey] = runtimes
+			}
+			for _, member := range members {
+				if !isPoolServerUp(runtimes, member) {
+					utils.AviLog.Debugf("Server %s:%d of Pod %s is not up in pool %s", member.IP, member.Port, podKey, poolKey)
+					allUp = false
+					break
+				}
+			}
+			if !allUp {
+				break
+			}
+		}
+		if allUp {
+			setPoolMemberReadyCondition(pod)
+		}
+	}
+}
+
+func getPoolServerRuntimes(poolKey string) []poolServerRuntime {
+	var runtimes []poolServerRuntime
+	tenantPool := strings.SplitN(poolKey, "/", 2)
+	if len(tenantPool) != 2 {
+		return runtimes
+	}
+	poolCache, ok := avicache.SharedAviObjCache().PoolCache.AviCacheGet(avicache.NamespaceName{Namespace: tenantPool[0], Name: tenantPool[1]})
+	if !ok {
+		utils.AviLog.Debugf("Pool %s not found in the cache", poolKey)
+		return runtimes
+	}
+	poolCacheObj, ok := poolCache.(*avicache.AviPoolCache)
+	if !ok || poolCacheObj.Uuid == "" {
+		return runtimes
+	}
+	clients := avicache.SharedAVIClients()
+	if clients == nil || len(clients.AviClient) == 0 {
+		return runtimes
+	}
+	uri := "/api/pool/" + poolCacheObj.Uuid + "/runtime/server/"
+	if err := lib.AviGet(clients.AviClient[0], uri, &runtimes); err != nil {
+		utils.AviLog.Warnf("Error in getting the server runtime of pool %s: %v", poolKey, err)
+		return nil
+	}
+	return runtimes
+}
+
+func isPoolServerUp(runtimes []poolServerRuntime, member objects.PoolMember) bool {
+	for _, runtime := range runtimes {
+		if runtime.IPAddr == nil || runtime.IPAddr.Addr == nil || *runtime.IPAddr.Addr != member.IP || runtime.Port != member.Port {
+			continue
+		}
+		return runtime.OperStatus != nil && runtime.OperStatus.State != nil && *runtime.OperStatus.State == PoolMemberOperUp
+	}
+	return false
+}
+
+func setPoolMemberReadyCondition(pod *corev1.Pod) {
+	patchPayload := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []corev1.PodCondition{{
+				Type:               lib.PoolMemberReadyConditionType,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "PoolMemberUp",
+				Message:            "Pod is up in all the Avi pools",
+			}},
+		},
+	}
+	payloadBytes, _ := json.Marshal(patchPayload)
+	_, err := utils.GetInformers().ClientSet.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.StrategicMergePatchType, payloadBytes, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("Error in setting the pool member readiness condition of Pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	utils.AviLog.Infof("Set the pool member readiness condition of Pod %s/%s", pod.Namespace, pod.Name)
+}
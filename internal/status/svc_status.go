@@ -163,6 +163,17 @@ func (l *leader) DeleteL4LBStatus(svc_mdata_obj lib.ServiceMetadataObj, vsName,
 			"status": nil,
 		})
 
+		if serviceObj := serviceMap[service]; serviceObj != nil && !lib.IsLoadBalancerClassAccepted(serviceObj) {
+			// The loadBalancerClass of the service moved away from AKO, the loadbalancer status
+			// is owned by the other LB controller now, so only the AKO annotations are removed.
+			if serviceObj.Annotations[lib.VSAnnotation] != "" {
+				if err := deleteSvcAnnotation(serviceObj); err != nil {
+					utils.AviLog.Errorf("key: %s, msg: error in deleting service annotation: %v", key, err)
+				}
+			}
+			continue
+		}
+
 		if serviceObj := serviceMap[service]; serviceObj != nil && (serviceObj.Status.LoadBalancer.Ingress == nil ||
 			(serviceObj.Status.LoadBalancer.Ingress != nil && len(serviceObj.Status.LoadBalancer.Ingress) == 0)) {
 			continue
@@ -0,0 +1,225 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+package models
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The introspection APIs expose the in-memory state of AKO, to debug the sync of the objects without scraping
+// the logs.
+
+// ModelSummary is the summary of a model built by the graph layer.
+type ModelSummary struct {
+	Name          string `json:"name"`
+	GraphChecksum uint32 `json:"graph_checksum"`
+	NodeCount     int    `json:"node_count"`
+}
+
+// ModelNode is a node of a model, along with its type. The virtualservice nodes are exposed along with the nodes
+// they refer to, see VSNode.
+type ModelNode struct {
+	NodeType string `json:"node_type"`
+	NodeRef
+	VirtualService *VSNode `json:"virtualservice,omitempty"`
+}
+
+// ModelGraph is a model built by the graph layer, with all its nodes.
+type ModelGraph struct {
+	Name          string      `json:"name"`
+	GraphChecksum uint32      `json:"graph_checksum"`
+	IsVrf         bool        `json:"is_vrf"`
+	RetryCount    int         `json:"retry_count"`
+	Nodes         []ModelNode `json:"nodes"`
+}
+
+// The nodes of the models are exposed through the views below, which carry only the fields needed to debug the
+// sync. The introspection APIs are not authenticated, so the keys, certificates, CA certificates and datascripts
+// of the nodes must never be added to these views.
+
+// NodeRef identifies a node of a model.
+type NodeRef struct {
+	Name     string `json:"name"`
+	Tenant   string `json:"tenant,omitempty"`
+	Checksum uint32 `json:"checksum"`
+}
+
+// PortProtocol is a listener port of a virtualservice node.
+type PortProtocol struct {
+	Port      int32    `json:"port"`
+	Protocol  string   `json:"protocol"`
+	Hosts     []string `json:"hosts,omitempty"`
+	EnableSSL bool     `json:"enable_ssl,omitempty"`
+}
+
+// VSVIPNode is a vsvip node of a virtualservice.
+type VSVIPNode struct {
+	NodeRef
+	FQDNs      []string `json:"fqdns,omitempty"`
+	IPAddress  string   `json:"ip_address,omitempty"`
+	VrfContext string   `json:"vrf_context,omitempty"`
+}
+
+// PoolServer is a server of a pool node.
+type PoolServer struct {
+	IP         string `json:"ip"`
+	Port       int32  `json:"port,omitempty"`
+	ServerNode string `json:"server_node,omitempty"`
+	Disabled   bool   `json:"disabled,omitempty"`
+}
+
+// PoolNode is a pool node of a virtualservice.
+type PoolNode struct {
+	NodeRef
+	Port       int32        `json:"port"`
+	Protocol   string       `json:"protocol,omitempty"`
+	TargetPort string       `json:"target_port,omitempty"`
+	SniEnabled bool         `json:"sni_enabled,omitempty"`
+	PkiProfile string       `json:"pki_profile,omitempty"`
+	Servers    []PoolServer `json:"servers"`
+}
+
+// PoolGroupNode is a poolgroup node of a virtualservice, along with the pools it refers to.
+type PoolGroupNode struct {
+	NodeRef
+	Members []string `json:"members"`
+}
+
+// SSLKeyCertNode is an sslkeyandcertificate node of a virtualservice. The key and the certificates are omitted.
+type SSLKeyCertNode struct {
+	NodeRef
+	Type string `json:"type,omitempty"`
+	Port int32  `json:"port,omitempty"`
+}
+
+// VSNode is a virtualservice node, along with the nodes of the objects it refers to and its child virtualservices.
+type VSNode struct {
+	NodeRef
+	VrfContext              string           `json:"vrf_context,omitempty"`
+	ServiceEngineGroup      string           `json:"service_engine_group,omitempty"`
+	ApplicationProfile      string           `json:"application_profile,omitempty"`
+	NetworkProfile          string           `json:"network_profile,omitempty"`
+	PortProtocols           []PortProtocol   `json:"port_protocols,omitempty"`
+	VHDomainNames           []string         `json:"vh_domain_names,omitempty"`
+	Dedicated               bool             `json:"dedicated,omitempty"`
+	ServiceMetadata         interface{}      `json:"service_metadata"`
+	VSVIPs                  []VSVIPNode      `json:"vsvips,omitempty"`
+	PoolGroups              []PoolGroupNode  `json:"poolgroups,omitempty"`
+	Pools                   []PoolNode       `json:"pools,omitempty"`
+	HTTPPolicySets          []NodeRef        `json:"httppolicysets,omitempty"`
+	DataScripts             []NodeRef        `json:"datascripts,omitempty"`
+	SSLKeyCerts             []SSLKeyCertNode `json:"sslkeyandcertificates,omitempty"`
+	CACerts                 []SSLKeyCertNode `json:"ca_certificates,omitempty"`
+	L4PolicySets            []NodeRef        `json:"l4policysets,omitempty"`
+	NetworkSecurityPolicies []NodeRef        `json:"networksecuritypolicies,omitempty"`
+	HealthMonitors          []NodeRef        `json:"healthmonitors,omitempty"`
+	Children                []VSNode         `json:"children,omitempty"`
+	PassthroughChildren     []VSNode         `json:"passthrough_children,omitempty"`
+}
+
+// CachedObject is an Avi object present in the cache.
+type CachedObject struct {
+	Name     string      `json:"name"`
+	Tenant   string      `json:"tenant"`
+	Uuid     string      `json:"uuid,omitempty"`
+	Checksum interface{} `json:"checksum,omitempty"`
+	Found    bool        `json:"found"`
+}
+
+// CachedVS is a virtualservice present in the cache, along with the cached objects it refers to.
+type CachedVS struct {
+	Name               string                    `json:"name"`
+	Tenant             string                    `json:"tenant"`
+	Uuid               string                    `json:"uuid"`
+	Checksum           string                    `json:"checksum"`
+	ParentVS           string                    `json:"parent_vs,omitempty"`
+	PassthroughParent  string                    `json:"passthrough_parent,omitempty"`
+	SNIChildUuids      []string                  `json:"sni_child_uuids,omitempty"`
+	ServiceMetadataObj interface{}               `json:"service_metadata"`
+	LastModified       string                    `json:"last_modified,omitempty"`
+	InvalidData        bool                      `json:"invalid_data"`
+	Objects            map[string][]CachedObject `json:"objects"`
+}
+
+// GraphModel implements ApiModel, and exposes the models stored in the graph lister. The models are read through
+// ListModels and GetModels, which are set by the graph layer.
+type GraphModel struct {
+	ListModels func() []ModelSummary
+	GetModels  func(name string) []ModelGraph
+}
+
+func (a *GraphModel) InitModel() {}
+
+func (a *GraphModel) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []OperationMap {
+	var operationMapList []OperationMap
+
+	list := OperationMap{
+		Route:  "/api/models",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			utils.Respond(w, a.ListModels())
+		},
+	}
+	operationMapList = append(operationMapList, list)
+
+	get := OperationMap{
+		Route:  "/api/models/{name}",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			name := mux.Vars(r)["name"]
+			graphs := a.GetModels(name)
+			if len(graphs) == 0 {
+				utils.RespondWithError(w, http.StatusNotFound, fmt.Sprintf("model %s not found", name))
+				return
+			}
+			utils.Respond(w, graphs)
+		},
+	}
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
+
+// CacheModel implements ApiModel, and exposes the virtualservices stored in the Avi object cache. The virtualservices
+// are read through GetCachedVS, which is set by the cache layer.
+type CacheModel struct {
+	GetCachedVS func(name string) []CachedVS
+}
+
+func (a *CacheModel) InitModel() {}
+
+func (a *CacheModel) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/api/cache/vs/{name}",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			name := mux.Vars(r)["name"]
+			vsList := a.GetCachedVS(name)
+			if len(vsList) == 0 {
+				utils.RespondWithError(w, http.StatusNotFound, fmt.Sprintf("virtualservice %s not found in cache", name))
+				return
+			}
+			utils.Respond(w, vsList)
+		},
+	}
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
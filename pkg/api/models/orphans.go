@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OrphanObject is an Avi object created for the cluster, which is not referred by any model or virtualservice.
+type OrphanObject struct {
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	Uuid        string    `json:"uuid"`
+	FirstSeen   time.Time `json:"first_seen"`
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+var OrphanReport *OrphanReportModel
+var orphanreportonce sync.Once
+
+// OrphanReportModel implements ApiModel, and holds the orphan objects found during the last garbage collection.
+type OrphanReportModel struct {
+	Objects    []OrphanObject `json:"objects"`
+	LastRun    time.Time      `json:"last_run"`
+	reportLock sync.RWMutex
+}
+
+func (a *OrphanReportModel) InitModel() {
+	orphanreportonce.Do(func() {
+		OrphanReport = &OrphanReportModel{
+			Objects: []OrphanObject{},
+		}
+	})
+}
+
+func (a *OrphanReportModel) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/api/orphans",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			OrphanReport.reportLock.RLock()
+			defer OrphanReport.reportLock.RUnlock()
+			utils.Respond(w, OrphanReport)
+		},
+	}
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
+
+// SetOrphans replaces the orphan objects reported by the API server.
+func (a *OrphanReportModel) SetOrphans(objects []OrphanObject) {
+	// The model is initialized only when the garbage collection is enabled. Return if the model is not inited.
+	if a == nil {
+		return
+	}
+	a.reportLock.Lock()
+	defer a.reportLock.Unlock()
+	a.Objects = append([]OrphanObject{}, objects...)
+	a.LastRun = time.Now()
+}
+
+// GetOrphans returns a copy of the orphan objects reported by the API server.
+func (a *OrphanReportModel) GetOrphans() []OrphanObject {
+	if a == nil {
+		return nil
+	}
+	a.reportLock.RLock()
+	defer a.reportLock.RUnlock()
+	return append([]OrphanObject{}, a.Objects...)
+}
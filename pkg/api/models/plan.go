@@ -0,0 +1,174 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PlannedRestOp is a rest operation which would have been sent to the Avi controller. The object of the rest
+// operation is not exposed as is, as the API is not authenticated. Only the names of the fields set in the object,
+// and a checksum of these fields, are exposed, and the fields holding secrets are left out of both.
+type PlannedRestOp struct {
+	Method     utils.RestMethod `json:"method"`
+	Path       string           `json:"path"`
+	ObjectType string           `json:"object_type"`
+	Name       string           `json:"name,omitempty"`
+	Tenant     string           `json:"tenant"`
+	Fields     []string         `json:"fields,omitempty"`
+	Checksum   uint32           `json:"checksum,omitempty"`
+}
+
+// redactedFields are the fields of the Avi objects holding the private keys, the datascripts and the credentials,
+// which are left out of the plan.
+var redactedFields = map[string]struct{}{
+	"key":            {},
+	"key_passphrase": {},
+	"datascript":     {},
+	"authentication": {},
+}
+
+// newPlannedRestOp returns the view of the rest operation exposed in the plan.
+func newPlannedRestOp(restOp *utils.RestOp) PlannedRestOp {
+	plannedRestOp := PlannedRestOp{
+		Method:     restOp.Method,
+		Path:       restOp.Path,
+		ObjectType: restOp.Model,
+		Name:       restOp.ObjName,
+		Tenant:     restOp.Tenant,
+	}
+	if restOp.Obj == nil {
+		return plannedRestOp
+	}
+	data, err := json.Marshal(restOp.Obj)
+	if err != nil {
+		return plannedRestOp
+	}
+	var obj map[string]json.RawMessage
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return plannedRestOp
+	}
+	for field := range redactedFields {
+		delete(obj, field)
+	}
+	if name, ok := obj["name"]; ok && plannedRestOp.Name == "" {
+		json.Unmarshal(name, &plannedRestOp.Name)
+	}
+	for field := range obj {
+		plannedRestOp.Fields = append(plannedRestOp.Fields, field)
+	}
+	sort.Strings(plannedRestOp.Fields)
+	// The fields are marshalled in the sorted order of the keys, so the checksum does not depend on the map order.
+	if data, err = json.Marshal(obj); err == nil {
+		plannedRestOp.Checksum = utils.Hash(string(data))
+	}
+	return plannedRestOp
+}
+
+// ModelPlan holds the rest operations computed for a model during the last sync of the model.
+type ModelPlan struct {
+	RestOps   []PlannedRestOp `json:"rest_ops"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+var DryRunPlan *PlanModel
+var dryrunplanonce sync.Once
+
+// PlanModel implements ApiModel, and holds the rest operations computed in dry-run mode for every model.
+type PlanModel struct {
+	Models   map[string]*ModelPlan `json:"models"`
+	planLock sync.RWMutex
+}
+
+// InitDryRunPlan initializes and returns the plan. The plan is computed whenever AKO runs in dry-run mode,
+// whether or not it is registered with the API server.
+func InitDryRunPlan() *PlanModel {
+	dryrunplanonce.Do(func() {
+		DryRunPlan = &PlanModel{
+			Models: make(map[string]*ModelPlan),
+		}
+	})
+	return DryRunPlan
+}
+
+func (a *PlanModel) InitModel() {
+	InitDryRunPlan()
+}
+
+func (a *PlanModel) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/api/plan",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			DryRunPlan.planLock.RLock()
+			defer DryRunPlan.planLock.RUnlock()
+			utils.Respond(w, DryRunPlan)
+		},
+	}
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
+
+// ResetModelPlan removes the rest operations computed earlier for the model, before the model is synced again.
+func (a *PlanModel) ResetModelPlan(key string) {
+	if a == nil {
+		return
+	}
+	a.planLock.Lock()
+	defer a.planLock.Unlock()
+	delete(a.Models, key)
+}
+
+// AddRestOps adds the rest operations computed for the model to the plan.
+func (a *PlanModel) AddRestOps(key string, restOps []*utils.RestOp) {
+	if a == nil || len(restOps) == 0 {
+		return
+	}
+	a.planLock.Lock()
+	defer a.planLock.Unlock()
+	modelPlan, ok := a.Models[key]
+	if !ok {
+		modelPlan = &ModelPlan{}
+		a.Models[key] = modelPlan
+	}
+	for _, restOp := range restOps {
+		modelPlan.RestOps = append(modelPlan.RestOps, newPlannedRestOp(restOp))
+	}
+	modelPlan.Timestamp = time.Now()
+}
+
+// GetModelPlan returns a copy of the rest operations computed for the model.
+func (a *PlanModel) GetModelPlan(key string) []PlannedRestOp {
+	if a == nil {
+		return nil
+	}
+	a.planLock.RLock()
+	defer a.planLock.RUnlock()
+	modelPlan, ok := a.Models[key]
+	if !ok {
+		return nil
+	}
+	return append([]PlannedRestOp{}, modelPlan.RestOps...)
+}
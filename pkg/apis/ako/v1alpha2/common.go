@@ -24,6 +24,7 @@ type BackendProperties struct {
 	AnalyticsPolicy                  *PoolAnalyticsPolicy `json:"analyticsPolicy,omitempty"`
 	ApplicationPersistenceProfileRef *string              `json:"applicationPersistenceProfileRef,omitempty"`
 	Enabled                          *bool                `json:"enabled,omitempty"`
+	GracefulDisableTimeout           *int32               `json:"gracefulDisableTimeout,omitempty"`
 	HealthMonitorRefs                []string             `json:"healthMonitorRefs,omitempty"`
 	LbAlgorithm                      *string              `json:"lbAlgorithm,omitempty"`
 	LbAlgorithmConsistentHashHdr     *string              `json:"lbAlgorithmConsistentHashHdr,omitempty"`
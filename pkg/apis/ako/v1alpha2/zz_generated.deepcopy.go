@@ -64,6 +64,11 @@ func (in *BackendProperties) DeepCopyInto(out *BackendProperties) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.GracefulDisableTimeout != nil {
+		in, out := &in.GracefulDisableTimeout, &out.GracefulDisableTimeout
+		*out = new(int32)
+		**out = **in
+	}
 	if in.HealthMonitorRefs != nil {
 		in, out := &in.HealthMonitorRefs, &out.HealthMonitorRefs
 		*out = make([]string, len(*in))
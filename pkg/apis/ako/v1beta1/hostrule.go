@@ -75,6 +75,7 @@ type HostRuleVirtualHost struct {
 	TCPSettings        *HostRuleTCPSettings     `json:"tcpSettings,omitempty"`
 	Aliases            []string                 `json:"aliases,omitempty"`
 	ICAPProfile        []string                 `json:"icapProfile,omitempty"`
+	SourceRanges       []string                 `json:"sourceRanges,omitempty"`
 }
 
 // HostRuleTCPSettings allows for customizing TCP settings
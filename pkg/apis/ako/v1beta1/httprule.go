@@ -44,6 +44,7 @@ type HTTPRulePaths struct {
 	TLS                    HTTPRuleTLS      `json:"tls,omitempty"`
 	HealthMonitors         []string         `json:"healthMonitors,omitempty"`
 	ApplicationPersistence string           `json:"applicationPersistence,omitempty"`
+	GracefulDisableTimeout *int32           `json:"gracefulDisableTimeout,omitempty"`
 }
 
 // HTTPRuleLBPolicy holds a path/pool's load balancer policies
@@ -406,6 +406,11 @@ func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.GracefulDisableTimeout != nil {
+		in, out := &in.GracefulDisableTimeout, &out.GracefulDisableTimeout
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -754,6 +759,11 @@ func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SourceRanges != nil {
+		in, out := &in.SourceRanges, &out.SourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
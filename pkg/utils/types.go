@@ -23,6 +23,7 @@ import (
 	oshiftinformers "github.com/openshift/client-go/route/informers/externalversions/route/v1"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
 	netinformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -75,6 +76,7 @@ type Informers struct {
 	ConfigMapInformer           coreinformers.ConfigMapInformer
 	ServiceInformer             coreinformers.ServiceInformer
 	EpInformer                  coreinformers.EndpointsInformer
+	EpSliceInformer             discoveryinformers.EndpointSliceInformer
 	PodInformer                 coreinformers.PodInformer
 	NSInformer                  coreinformers.NamespaceInformer
 	SecretInformer              coreinformers.SecretInformer
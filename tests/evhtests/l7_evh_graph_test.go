@@ -17,6 +17,8 @@ package evhtests
 import (
 	"context"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +31,7 @@ import (
 
 	"github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -2029,3 +2032,90 @@ func TestPortsForInsecureAndSecureEVH(t *testing.T) {
 	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
 	TearDownTestForIngress(t, modelName)
 }
+
+func TestIngressWithDefaultBackendForEvh(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	hostModelName, _ := GetModelName("foo.com", "default")
+	modelNames := []string{hostModelName}
+	if !lib.VIPPerNamespace() {
+		modelNames = nil
+		for i := 0; i < 8; i++ {
+			modelNames = append(modelNames, "admin/cluster--Shared-L7-EVH-"+strconv.Itoa(i))
+		}
+	}
+	SetUpTestForIngress(t, modelNames...)
+
+	defaultBackend := &networking.IngressBackend{
+		Service: &networking.IngressServiceBackend{
+			Name: "avisvc",
+			Port: networking.ServiceBackendPort{Number: 8080},
+		},
+	}
+
+	// the defaultBackend of an ingress with hosts is added as a path of the hosts
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "ingress-db-evh-1",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	ingrFake.Spec.DefaultBackend = defaultBackend
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(hostModelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes) != 1 || len(nodes[0].EvhNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].EvhNodes[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(2))
+
+	// the defaultBackend of an ingress without hosts is the default pool group of the EVH parents
+	ingrNoHosts := &networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "ingress-db-evh-2",
+		},
+		Spec: networking.IngressSpec{
+			DefaultBackend: defaultBackend,
+		},
+	}
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrNoHosts, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	for _, modelName := range modelNames {
+		vsName := strings.TrimPrefix(modelName, "admin/")
+		g.Eventually(func() string {
+			found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+			if !found || aviModel == nil {
+				return ""
+			}
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+			if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 || len(nodes[0].PoolRefs[0].Servers) != 1 {
+				return ""
+			}
+			return nodes[0].DefaultPoolGroup
+		}, 10*time.Second).Should(gomega.Equal(lib.GetL7DefaultBackendPGName(vsName)))
+	}
+
+	for _, ingressName := range []string{"ingress-db-evh-1", "ingress-db-evh-2"} {
+		if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), ingressName, metav1.DeleteOptions{}); err != nil {
+			t.Fatalf("Couldn't DELETE the Ingress %v", err)
+		}
+	}
+	for _, modelName := range modelNames {
+		g.Eventually(func() bool {
+			_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+			return nodes[0].DefaultPoolGroup == "" && len(nodes[0].PoolRefs) == 0 && len(nodes[0].EvhNodes) == 0
+		}, 10*time.Second).Should(gomega.Equal(true))
+	}
+	TearDownTestForIngress(t, modelNames...)
+}
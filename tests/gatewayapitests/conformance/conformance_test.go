@@ -0,0 +1,72 @@
+//go:build conformance
+
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// Package conformance runs the upstream Gateway API conformance suite against ako-gateway-api,
+// deployed in the cluster of the current kubeconfig with its GatewayClass. The features are
+// given with the -supported-features flag of the suite. The suite is not vendored, and is built
+// only with the conformance build tag, through the gatewayapi-conformance make target.
+package conformance
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/gateway-api/conformance/tests"
+	"sigs.k8s.io/gateway-api/conformance/utils/flags"
+	"sigs.k8s.io/gateway-api/conformance/utils/suite"
+)
+
+func TestConformance(t *testing.T) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		t.Fatalf("Error loading Kubernetes config: %v", err)
+	}
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		t.Fatalf("Error initializing Kubernetes client: %v", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		t.Fatalf("Error initializing Kubernetes REST client: %v", err)
+	}
+	v1alpha2.AddToScheme(c.Scheme())
+	v1beta1.AddToScheme(c.Scheme())
+	gatewayv1.AddToScheme(c.Scheme())
+
+	t.Logf("Running the conformance tests with the %s GatewayClass, and the supported features: [%v]",
+		*flags.GatewayClassName, *flags.SupportedFeatures)
+
+	cSuite := suite.New(suite.Options{
+		Client:               c,
+		RestConfig:           cfg,
+		Clientset:            clientset,
+		GatewayClassName:     *flags.GatewayClassName,
+		Debug:                *flags.ShowDebug,
+		CleanupBaseResources: *flags.CleanupBaseResources,
+		SupportedFeatures:    suite.ParseSupportedFeatures(*flags.SupportedFeatures),
+		ExemptFeatures:       suite.ParseSupportedFeatures(*flags.ExemptFeatures),
+		SkipTests:            suite.ParseSkipTests(*flags.SkipTests),
+		RunTest:              *flags.RunTest,
+	})
+	cSuite.Setup(t)
+	cSuite.Run(t, tests.ConformanceTests)
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// Package features runs the feature tests of ako-gateway-api. The test cases are modelled on
+// the upstream Gateway API conformance suite, with the same short names and features, but
+// they are not the upstream suite and passing them is not a claim of conformance. The
+// traffic checks of the upstream suite are replaced by checks on the status of the objects
+// and on the Avi objects built by AKO against the Avi mock server. The upstream suite is run
+// against a live cluster by the gatewayapi-conformance make target.
+package features
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+
+	akogatewayapik8s "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/k8s"
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+var ctrl *akogatewayapik8s.GatewayController
+
+// namespaces of the test cases, as in the upstream suite
+const (
+	INFRA_NAMESPACE       = "gateway-features-infra"
+	WEB_BACKEND_NAMESPACE = "gateway-features-web-backend"
+)
+
+// FeatureTest is a test case modelled on the upstream suite. The test is run only if
+// all its Features are supported by AKO, as done by the upstream suite.
+type FeatureTest struct {
+	ShortName   string
+	Description string
+	Features    []gatewayv1.SupportedFeature
+	Test        func(t *testing.T)
+}
+
+// Gateway API features exercised by the feature tests, named as in the upstream conformance suite.
+const (
+	SupportGateway                             gatewayv1.SupportedFeature = "Gateway"
+	SupportReferenceGrant                      gatewayv1.SupportedFeature = "ReferenceGrant"
+	SupportHTTPRoute                           gatewayv1.SupportedFeature = "HTTPRoute"
+	SupportHTTPRouteQueryParamMatching         gatewayv1.SupportedFeature = "HTTPRouteQueryParamMatching"
+	SupportHTTPRouteMethodMatching             gatewayv1.SupportedFeature = "HTTPRouteMethodMatching"
+	SupportHTTPRouteResponseHeaderModification gatewayv1.SupportedFeature = "HTTPRouteResponseHeaderModification"
+	SupportHTTPRouteHostRewrite                gatewayv1.SupportedFeature = "HTTPRouteHostRewrite"
+	SupportHTTPRoutePathRewrite                gatewayv1.SupportedFeature = "HTTPRoutePathRewrite"
+	SupportTLSRoute                            gatewayv1.SupportedFeature = "TLSRoute"
+)
+
+// FeatureTests is the list of the feature test cases.
+var FeatureTests []FeatureTest
+
+func TestMain(m *testing.M) {
+	tests.KubeClient = k8sfake.NewSimpleClientset()
+	tests.GatewayClient = gatewayfake.NewSimpleClientset()
+	integrationtest.KubeClient = tests.KubeClient
+
+	// Sets the environment variables
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("FULL_SYNC_INTERVAL", utils.AKO_DEFAULT_NS)
+	os.Setenv("ENABLE_EVH", "true")
+	os.Setenv("TENANT", "admin")
+	os.Setenv("POD_NAME", "ako-0")
+
+	// Set the user with prefix
+	_ = lib.AKOControlConfig()
+	lib.SetAKOUser(akogatewayapilib.Prefix)
+	lib.SetNamePrefix(akogatewayapilib.Prefix)
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	akoControlConfig := akogatewayapilib.AKOControlConfig()
+	akoControlConfig.SetEventRecorder(lib.AKOGatewayEventComponent, tests.KubeClient, true)
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+	}
+	utils.AviLog.SetLevel("DEBUG")
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: tests.KubeClient}, registeredInformers, make(map[string]interface{}))
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	tests.KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	akoApi := integrationtest.InitializeFakeAKOAPIServer()
+	defer akoApi.ShutDown()
+
+	tests.NewAviFakeClientInstance(tests.KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = akogatewayapik8s.SharedGatewayController()
+	ctrl.DisableSync = false
+	ctrl.InitGatewayAPIInformers(tests.GatewayClient)
+	akoControlConfig.SetGatewayAPIClientset(tests.GatewayClient)
+
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(tests.KubeClient)
+	go ctrl.InitController(k8s.K8sinformers{Cs: tests.KubeClient}, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+// TestFeatures runs the feature test cases.
+func TestFeatures(t *testing.T) {
+	for _, featureTest := range FeatureTests {
+		featureTest := featureTest
+		t.Run(featureTest.ShortName, func(t *testing.T) {
+			t.Logf("Running %s: %s", featureTest.ShortName, featureTest.Description)
+			featureTest.Test(t)
+		})
+	}
+}
+
+func getGateway(name, namespace string) *gatewayv1.Gateway {
+	gateway, err := tests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return gateway
+}
+
+// isGatewayAccepted returns true once the Gateway has the Accepted condition set to True.
+func isGatewayAccepted(name, namespace string) bool {
+	gateway := getGateway(name, namespace)
+	return gateway != nil && apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+}
+
+// getListenerCondition returns the condition of the given type of the first listener of the Gateway.
+func getListenerCondition(name, namespace, conditionType string) *metav1.Condition {
+	gateway := getGateway(name, namespace)
+	if gateway == nil || len(gateway.Status.Listeners) == 0 {
+		return nil
+	}
+	return apimeta.FindStatusCondition(gateway.Status.Listeners[0].Conditions, conditionType)
+}
+
+// getHTTPRouteCondition returns the condition of the given type for the first parent of the HTTPRoute.
+func getHTTPRouteCondition(name, namespace, conditionType string) *metav1.Condition {
+	httpRoute, err := tests.GatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil || len(httpRoute.Status.Parents) == 0 {
+		return nil
+	}
+	return apimeta.FindStatusCondition(httpRoute.Status.Parents[0].Conditions, conditionType)
+}
+
+// getChildNodes returns the child VSes built for the routes attached to the Gateway.
+func getChildNodes(gatewayName, namespace string) []*avinodes.AviEvhVsNode {
+	modelName, _ := tests.GetModelName(namespace, gatewayName)
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0].EvhNodes
+}
+
+// setupHTTPGateway creates the GatewayClass and a Gateway with a HTTP listener on port 8080
+// in the infra namespace, and waits for the Gateway to be accepted.
+func setupHTTPGateway(t *testing.T, gatewayClassName, gatewayName string) {
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	tests.SetupGateway(t, gatewayName, INFRA_NAMESPACE, gatewayClassName, nil, tests.GetListenersV1([]int32{8080}))
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		return isGatewayAccepted(gatewayName, INFRA_NAMESPACE)
+	}, 30*time.Second).Should(gomega.Equal(true))
+}
+
+func teardownHTTPGateway(t *testing.T, gatewayClassName, gatewayName string) {
+	tests.TeardownGateway(t, gatewayName, INFRA_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+func init() {
+	FeatureTests = append(FeatureTests,
+		GatewaySecretMissingReferenceGrant,
+		GatewaySecretInvalidReferenceGrant,
+		GatewaySecretReferenceGrantAllInNamespace,
+		GatewaySecretReferenceGrantSpecific,
+		GatewayWithAttachedRoutes,
+	)
+}
+
+// setupCrossNamespaceTLSGateway creates a Gateway in the infra namespace with a HTTPS listener
+// which refers to a Secret in the web backend namespace.
+func setupCrossNamespaceTLSGateway(t *testing.T, gatewayClassName, gatewayName, secretName string) {
+	integrationtest.AddSecret(secretName, WEB_BACKEND_NAMESPACE, "cert", "key")
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1([]int32{8443})
+	tests.SetListenerTLS(&listeners[0], gatewayv1.TLSModeTerminate, secretName, WEB_BACKEND_NAMESPACE)
+	tests.SetupGateway(t, gatewayName, INFRA_NAMESPACE, gatewayClassName, nil, listeners)
+}
+
+func teardownCrossNamespaceTLSGateway(t *testing.T, gatewayClassName, gatewayName, secretName string) {
+	tests.TeardownGateway(t, gatewayName, INFRA_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DeleteSecret(secretName, WEB_BACKEND_NAMESPACE)
+}
+
+// setupSecretReferenceGrant creates a ReferenceGrant in the web backend namespace which allows the
+// Gateways of the infra namespace to refer to the Secret secretName, or to all the Secrets if empty.
+func setupSecretReferenceGrant(t *testing.T, name, secretName string) {
+	rg := &tests.ReferenceGrant{}
+	rg.ReferenceGrant = rg.ReferenceGrantV1beta1(name, WEB_BACKEND_NAMESPACE, lib.Gateway, INFRA_NAMESPACE, utils.Secret)
+	if secretName != "" {
+		rg.Spec.To[0].Name = (*gatewayv1.ObjectName)(&secretName)
+	}
+	rg.Create(t)
+}
+
+var GatewaySecretMissingReferenceGrant = FeatureTest{
+	ShortName:   "GatewaySecretMissingReferenceGrant",
+	Description: "A Gateway should not be accepted if it has a certificateRef for a Secret in another namespace and a ReferenceGrant granting permission to the Secret does not exist",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportReferenceGrant},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-gw-01"
+		gatewayName := "gateway-cf-gw-01"
+		secretName := "secret-cf-gw-01"
+		setupCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() string {
+			condition := getListenerCondition(gatewayName, INFRA_NAMESPACE, string(gatewayv1.ListenerConditionResolvedRefs))
+			if condition == nil || condition.Status != metav1.ConditionFalse {
+				return ""
+			}
+			return condition.Reason
+		}, 30*time.Second).Should(gomega.Equal(string(gatewayv1.ListenerReasonRefNotPermitted)))
+		g.Expect(isGatewayAccepted(gatewayName, INFRA_NAMESPACE)).To(gomega.BeFalse())
+
+		teardownCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+	},
+}
+
+var GatewaySecretInvalidReferenceGrant = FeatureTest{
+	ShortName:   "GatewaySecretInvalidReferenceGrant",
+	Description: "A Gateway should not be accepted if it has a certificateRef for a Secret in another namespace and a ReferenceGrant exists but does not grant permission to that specific Secret",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportReferenceGrant},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-gw-02"
+		gatewayName := "gateway-cf-gw-02"
+		secretName := "secret-cf-gw-02"
+		referenceGrantName := "referencegrant-cf-gw-02"
+		setupSecretReferenceGrant(t, referenceGrantName, "not-the-secret-cf-gw-02")
+		setupCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() string {
+			condition := getListenerCondition(gatewayName, INFRA_NAMESPACE, string(gatewayv1.ListenerConditionResolvedRefs))
+			if condition == nil || condition.Status != metav1.ConditionFalse {
+				return ""
+			}
+			return condition.Reason
+		}, 30*time.Second).Should(gomega.Equal(string(gatewayv1.ListenerReasonRefNotPermitted)))
+
+		teardownCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+		tests.TeardownReferenceGrant(t, referenceGrantName, WEB_BACKEND_NAMESPACE)
+	},
+}
+
+var GatewaySecretReferenceGrantAllInNamespace = FeatureTest{
+	ShortName:   "GatewaySecretReferenceGrantAllInNamespace",
+	Description: "A Gateway should be accepted if it has a certificateRef for a Secret in another namespace and a ReferenceGrant granting permission to all Secrets in the namespace exists",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportReferenceGrant},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-gw-03"
+		gatewayName := "gateway-cf-gw-03"
+		secretName := "secret-cf-gw-03"
+		referenceGrantName := "referencegrant-cf-gw-03"
+		setupSecretReferenceGrant(t, referenceGrantName, "")
+		setupCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() bool {
+			return isGatewayAccepted(gatewayName, INFRA_NAMESPACE)
+		}, 30*time.Second).Should(gomega.Equal(true))
+
+		teardownCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+		tests.TeardownReferenceGrant(t, referenceGrantName, WEB_BACKEND_NAMESPACE)
+	},
+}
+
+var GatewaySecretReferenceGrantSpecific = FeatureTest{
+	ShortName:   "GatewaySecretReferenceGrantSpecific",
+	Description: "A Gateway should be accepted if it has a certificateRef for a Secret in another namespace and a ReferenceGrant granting permission to the specific Secret exists",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportReferenceGrant},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-gw-04"
+		gatewayName := "gateway-cf-gw-04"
+		secretName := "secret-cf-gw-04"
+		referenceGrantName := "referencegrant-cf-gw-04"
+		setupSecretReferenceGrant(t, referenceGrantName, secretName)
+		setupCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() bool {
+			return isGatewayAccepted(gatewayName, INFRA_NAMESPACE)
+		}, 30*time.Second).Should(gomega.Equal(true))
+
+		teardownCrossNamespaceTLSGateway(t, gatewayClassName, gatewayName, secretName)
+		tests.TeardownReferenceGrant(t, referenceGrantName, WEB_BACKEND_NAMESPACE)
+	},
+}
+
+var GatewayWithAttachedRoutes = FeatureTest{
+	ShortName:   "GatewayWithAttachedRoutes",
+	Description: "A Gateway should report the number of the routes attached to its listeners",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-gw-05"
+		gatewayName := "gateway-cf-gw-05"
+		httpRouteName := "httproute-cf-gw-05"
+		svcName := "infra-backend-cf-gw-05"
+		setupHTTPGateway(t, gatewayClassName, gatewayName)
+		integrationtest.CreateSVC(t, INFRA_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+
+		parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, INFRA_NAMESPACE, []int32{8080})
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, map[string][]string{},
+			[][]string{{svcName, INFRA_NAMESPACE, "8080", "1"}})
+		tests.SetupHTTPRoute(t, httpRouteName, INFRA_NAMESPACE, parentRefs, []gatewayv1.Hostname{"foo-8080.com"}, []gatewayv1.HTTPRouteRule{rule})
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() int32 {
+			gateway := getGateway(gatewayName, INFRA_NAMESPACE)
+			if gateway == nil || len(gateway.Status.Listeners) != 1 {
+				return -1
+			}
+			return gateway.Status.Listeners[0].AttachedRoutes
+		}, 30*time.Second).Should(gomega.Equal(int32(1)))
+
+		tests.TeardownHTTPRoute(t, httpRouteName, INFRA_NAMESPACE)
+		integrationtest.DelSVC(t, INFRA_NAMESPACE, svcName)
+		teardownHTTPGateway(t, gatewayClassName, gatewayName)
+	},
+}
@@ -0,0 +1,386 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+func init() {
+	FeatureTests = append(FeatureTests,
+		HTTPRouteSimpleSameNamespace,
+		HTTPRouteInvalidNonExistentBackendRef,
+		HTTPRouteInvalidBackendRefUnknownKind,
+		HTTPRouteInvalidCrossNamespaceBackendRef,
+		HTTPRouteReferenceGrant,
+		HTTPRouteHeaderMatching,
+		HTTPRouteQueryParamMatching,
+		HTTPRouteMethodMatching,
+		HTTPRouteResponseHeaderModifier,
+		HTTPRouteRewriteHost,
+		HTTPRouteRewritePath,
+	)
+}
+
+// setupHTTPRoute creates the Gateway in the infra namespace along with a HTTPRoute attached to it,
+// with the given rule for the hostname foo-8080.com.
+func setupHTTPRoute(t *testing.T, gatewayClassName, gatewayName, httpRouteName string, rule gatewayv1.HTTPRouteRule) {
+	setupHTTPGateway(t, gatewayClassName, gatewayName)
+	parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, INFRA_NAMESPACE, []int32{8080})
+	tests.SetupHTTPRoute(t, httpRouteName, INFRA_NAMESPACE, parentRefs, []gatewayv1.Hostname{"foo-8080.com"}, []gatewayv1.HTTPRouteRule{rule})
+}
+
+func teardownHTTPRoute(t *testing.T, gatewayClassName, gatewayName, httpRouteName string) {
+	tests.TeardownHTTPRoute(t, httpRouteName, INFRA_NAMESPACE)
+	teardownHTTPGateway(t, gatewayClassName, gatewayName)
+}
+
+// waitForChildNode waits for the single child VS of the Gateway to satisfy the condition and returns it.
+func waitForChildNode(t *testing.T, gatewayName string, condition func(*avinodes.AviEvhVsNode) bool) *avinodes.AviEvhVsNode {
+	var childNode *avinodes.AviEvhVsNode
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		childNodes := getChildNodes(gatewayName, INFRA_NAMESPACE)
+		if len(childNodes) != 1 || !condition(childNodes[0]) {
+			return false
+		}
+		childNode = childNodes[0]
+		return true
+	}, 30*time.Second).Should(gomega.Equal(true))
+	return childNode
+}
+
+// resolvedRefsReason returns the reason of the ResolvedRefs condition of the HTTPRoute.
+func resolvedRefsReason(httpRouteName string) func() string {
+	return func() string {
+		condition := getHTTPRouteCondition(httpRouteName, INFRA_NAMESPACE, string(gatewayv1.RouteConditionResolvedRefs))
+		if condition == nil {
+			return ""
+		}
+		return condition.Reason
+	}
+}
+
+var HTTPRouteSimpleSameNamespace = FeatureTest{
+	ShortName:   "HTTPRouteSimpleSameNamespace",
+	Description: "A single HTTPRoute in the infra namespace attaches to a Gateway in the same namespace",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-01"
+		gatewayName := "gateway-cf-hr-01"
+		httpRouteName := "httproute-cf-hr-01"
+		svcName := "infra-backend-cf-hr-01"
+		integrationtest.CreateSVC(t, INFRA_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+		integrationtest.CreateEP(t, INFRA_NAMESPACE, svcName, false, false, "1.2.3")
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, map[string][]string{},
+			[][]string{{svcName, INFRA_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() bool {
+			condition := getHTTPRouteCondition(httpRouteName, INFRA_NAMESPACE, string(gatewayv1.RouteConditionAccepted))
+			return condition != nil && condition.Status == metav1.ConditionTrue
+		}, 30*time.Second).Should(gomega.Equal(true))
+		g.Eventually(resolvedRefsReason(httpRouteName), 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonResolvedRefs)))
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.PoolRefs) == 1 && len(childNode.PoolRefs[0].Servers) == 1
+		})
+		g.Expect(*childNode.VHMatches[0].Host).To(gomega.Equal("foo-8080.com"))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+		integrationtest.DelSVC(t, INFRA_NAMESPACE, svcName)
+		integrationtest.DelEP(t, INFRA_NAMESPACE, svcName)
+	},
+}
+
+var HTTPRouteInvalidNonExistentBackendRef = FeatureTest{
+	ShortName:   "HTTPRouteInvalidNonExistentBackendRef",
+	Description: "A HTTPRoute should set the ResolvedRefs condition to False with reason BackendNotFound if it has a backendRef Service that does not exist",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-02"
+		gatewayName := "gateway-cf-hr-02"
+		httpRouteName := "httproute-cf-hr-02"
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, map[string][]string{},
+			[][]string{{"nonexistent-backend-cf-hr-02", INFRA_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(resolvedRefsReason(httpRouteName), 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonBackendNotFound)))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteInvalidBackendRefUnknownKind = FeatureTest{
+	ShortName:   "HTTPRouteInvalidBackendRefUnknownKind",
+	Description: "A HTTPRoute should set the ResolvedRefs condition to False with reason InvalidKind if it has a backendRef that points to an unknown Kind",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-03"
+		gatewayName := "gateway-cf-hr-03"
+		httpRouteName := "httproute-cf-hr-03"
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, map[string][]string{},
+			[][]string{{"infra-backend-cf-hr-03", INFRA_NAMESPACE, "8080", "1"}})
+		unknownKind := gatewayv1.Kind("UnknownKind")
+		rule.BackendRefs[0].Kind = &unknownKind
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(resolvedRefsReason(httpRouteName), 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonInvalidKind)))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteInvalidCrossNamespaceBackendRef = FeatureTest{
+	ShortName:   "HTTPRouteInvalidCrossNamespaceBackendRef",
+	Description: "A HTTPRoute should set the ResolvedRefs condition to False with reason RefNotPermitted if it has a backendRef Service in another namespace and a ReferenceGrant granting permission to the Service does not exist",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportReferenceGrant},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-04"
+		gatewayName := "gateway-cf-hr-04"
+		httpRouteName := "httproute-cf-hr-04"
+		svcName := "web-backend-cf-hr-04"
+		integrationtest.CreateSVC(t, WEB_BACKEND_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, map[string][]string{},
+			[][]string{{svcName, WEB_BACKEND_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(resolvedRefsReason(httpRouteName), 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonRefNotPermitted)))
+		// no traffic is forwarded to the backend
+		g.Expect(getChildNodes(gatewayName, INFRA_NAMESPACE)).To(gomega.BeEmpty())
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+		integrationtest.DelSVC(t, WEB_BACKEND_NAMESPACE, svcName)
+	},
+}
+
+var HTTPRouteReferenceGrant = FeatureTest{
+	ShortName:   "HTTPRouteReferenceGrant",
+	Description: "A HTTPRoute with a backendRef in another namespace should be resolved when a ReferenceGrant granting permission to the Service exists",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportReferenceGrant},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-05"
+		gatewayName := "gateway-cf-hr-05"
+		httpRouteName := "httproute-cf-hr-05"
+		referenceGrantName := "referencegrant-cf-hr-05"
+		svcName := "web-backend-cf-hr-05"
+		integrationtest.CreateSVC(t, WEB_BACKEND_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+		integrationtest.CreateEP(t, WEB_BACKEND_NAMESPACE, svcName, false, false, "1.2.3")
+		tests.SetupReferenceGrant(t, referenceGrantName, WEB_BACKEND_NAMESPACE, lib.HTTPRoute, INFRA_NAMESPACE, utils.Service)
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, map[string][]string{},
+			[][]string{{svcName, WEB_BACKEND_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(resolvedRefsReason(httpRouteName), 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonResolvedRefs)))
+		waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.PoolRefs) == 1 && len(childNode.PoolRefs[0].Servers) == 1
+		})
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+		tests.TeardownReferenceGrant(t, referenceGrantName, WEB_BACKEND_NAMESPACE)
+		integrationtest.DelSVC(t, WEB_BACKEND_NAMESPACE, svcName)
+		integrationtest.DelEP(t, WEB_BACKEND_NAMESPACE, svcName)
+	},
+}
+
+var HTTPRouteHeaderMatching = FeatureTest{
+	ShortName:   "HTTPRouteHeaderMatching",
+	Description: "A single HTTPRoute with header matching",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-06"
+		gatewayName := "gateway-cf-hr-06"
+		httpRouteName := "httproute-cf-hr-06"
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{"version"}, map[string][]string{},
+			[][]string{{"infra-backend-cf-hr-06", INFRA_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.VHMatches) == 1 && len(childNode.VHMatches[0].Rules) == 1
+		})
+		g := gomega.NewGomegaWithT(t)
+		hdrs := childNode.VHMatches[0].Rules[0].Matches.Hdrs
+		g.Expect(hdrs).To(gomega.HaveLen(1))
+		g.Expect(*hdrs[0].Hdr).To(gomega.Equal("version"))
+		g.Expect(*hdrs[0].MatchCriteria).To(gomega.Equal("HDR_EQUALS"))
+		g.Expect(hdrs[0].Value).To(gomega.ConsistOf("some-value"))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteQueryParamMatching = FeatureTest{
+	ShortName:   "HTTPRouteQueryParamMatching",
+	Description: "A single HTTPRoute with query param matching",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportHTTPRouteQueryParamMatching},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-07"
+		gatewayName := "gateway-cf-hr-07"
+		httpRouteName := "httproute-cf-hr-07"
+		rule := tests.GetHTTPRouteRuleV1([]string{}, []string{}, map[string][]string{},
+			[][]string{{"infra-backend-cf-hr-07", INFRA_NAMESPACE, "8080", "1"}})
+		rule.Matches = []gatewayv1.HTTPRouteMatch{tests.GetHTTPRouteQueryMethodMatchV1("/", "animal", "whale", "")}
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.VHMatches) == 1 && len(childNode.VHMatches[0].Rules) == 1
+		})
+		g := gomega.NewGomegaWithT(t)
+		query := childNode.VHMatches[0].Rules[0].Matches.Query
+		g.Expect(query).ShouldNot(gomega.BeNil())
+		g.Expect(*query.MatchCriteria).To(gomega.Equal("QUERY_MATCH_REGEX_MATCH"))
+		g.Expect(query.MatchStr).To(gomega.ConsistOf("(^|&)animal=whale(&|$)"))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteMethodMatching = FeatureTest{
+	ShortName:   "HTTPRouteMethodMatching",
+	Description: "A single HTTPRoute with method matching",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportHTTPRouteMethodMatching},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-08"
+		gatewayName := "gateway-cf-hr-08"
+		httpRouteName := "httproute-cf-hr-08"
+		rule := tests.GetHTTPRouteRuleV1([]string{}, []string{}, map[string][]string{},
+			[][]string{{"infra-backend-cf-hr-08", INFRA_NAMESPACE, "8080", "1"}})
+		rule.Matches = []gatewayv1.HTTPRouteMatch{tests.GetHTTPRouteQueryMethodMatchV1("/", "", "", "POST")}
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.VHMatches) == 1 && len(childNode.VHMatches[0].Rules) == 1
+		})
+		g := gomega.NewGomegaWithT(t)
+		method := childNode.VHMatches[0].Rules[0].Matches.Method
+		g.Expect(method).ShouldNot(gomega.BeNil())
+		g.Expect(*method.MatchCriteria).To(gomega.Equal("IS_IN"))
+		g.Expect(method.Methods).To(gomega.ConsistOf("HTTP_METHOD_POST"))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteResponseHeaderModifier = FeatureTest{
+	ShortName:   "HTTPRouteResponseHeaderModifier",
+	Description: "A HTTPRoute has the response header modifier filters applied",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportHTTPRouteResponseHeaderModification},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-09"
+		gatewayName := "gateway-cf-hr-09"
+		httpRouteName := "httproute-cf-hr-09"
+		rule := tests.GetHTTPRouteRuleV1([]string{"/"}, []string{},
+			map[string][]string{"ResponseHeaderModifier": {"add", "replace", "remove"}},
+			[][]string{{"infra-backend-cf-hr-09", INFRA_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.HttpPolicyRefs) == 1 && len(childNode.HttpPolicyRefs[0].ResponseRules) == 1
+		})
+		g := gomega.NewGomegaWithT(t)
+		hdrActions := childNode.HttpPolicyRefs[0].ResponseRules[0].HdrAction
+		g.Expect(hdrActions).To(gomega.HaveLen(3))
+		g.Expect(*hdrActions[0].Action).To(gomega.Equal("HTTP_ADD_HDR"))
+		g.Expect(*hdrActions[1].Action).To(gomega.Equal("HTTP_REPLACE_HDR"))
+		g.Expect(*hdrActions[2].Action).To(gomega.Equal("HTTP_REMOVE_HDR"))
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteRewriteHost = FeatureTest{
+	ShortName:   "HTTPRouteRewriteHost",
+	Description: "A HTTPRoute with hostname rewrite filter",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportHTTPRouteHostRewrite},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-10"
+		gatewayName := "gateway-cf-hr-10"
+		httpRouteName := "httproute-cf-hr-10"
+		rule := tests.GetHTTPRouteRuleV1([]string{"/one"}, []string{},
+			map[string][]string{"URLRewrite": {"hostname"}},
+			[][]string{{"infra-backend-cf-hr-10", INFRA_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.HttpPolicyRefs) == 1 && len(childNode.HttpPolicyRefs[0].RequestRules) == 1
+		})
+		g := gomega.NewGomegaWithT(t)
+		rewriteAction := childNode.HttpPolicyRefs[0].RequestRules[0].RewriteURLAction
+		g.Expect(rewriteAction).ShouldNot(gomega.BeNil())
+		g.Expect(*rewriteAction.HostHdr.Tokens[0].StrValue).To(gomega.Equal("rewrite.com"))
+		g.Expect(rewriteAction.Path).To(gomega.BeNil())
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
+
+var HTTPRouteRewritePath = FeatureTest{
+	ShortName:   "HTTPRouteRewritePath",
+	Description: "A HTTPRoute with path rewrite filter",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportHTTPRoute, SupportHTTPRoutePathRewrite},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-hr-11"
+		gatewayName := "gateway-cf-hr-11"
+		httpRouteName := "httproute-cf-hr-11"
+		rule := tests.GetHTTPRouteRuleV1([]string{"/prefix/one"}, []string{},
+			map[string][]string{"URLRewrite": {"prefix"}},
+			[][]string{{"infra-backend-cf-hr-11", INFRA_NAMESPACE, "8080", "1"}})
+		setupHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName, rule)
+
+		childNode := waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			return len(childNode.HttpPolicyRefs) == 1 && len(childNode.HttpPolicyRefs[0].RequestRules) == 1
+		})
+		g := gomega.NewGomegaWithT(t)
+		requestRule := childNode.HttpPolicyRefs[0].RequestRules[0]
+		g.Expect(requestRule.Match.Path.MatchStr).To(gomega.ConsistOf("/prefix/one"))
+		g.Expect(requestRule.RewriteURLAction.Path.Tokens).To(gomega.HaveLen(2))
+		g.Expect(*requestRule.RewriteURLAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+		g.Expect(*requestRule.RewriteURLAction.Path.Tokens[1].StartIndex).To(gomega.Equal(int32(2)))
+
+		// the full path is replaced
+		rule = tests.GetHTTPRouteRuleV1([]string{"/full/one"}, []string{},
+			map[string][]string{"URLRewrite": {"fullpath"}},
+			[][]string{{"infra-backend-cf-hr-11", INFRA_NAMESPACE, "8080", "1"}})
+		parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, INFRA_NAMESPACE, []int32{8080})
+		tests.UpdateHTTPRoute(t, httpRouteName, INFRA_NAMESPACE, parentRefs, []gatewayv1.Hostname{"foo-8080.com"}, []gatewayv1.HTTPRouteRule{rule})
+		waitForChildNode(t, gatewayName, func(childNode *avinodes.AviEvhVsNode) bool {
+			if len(childNode.HttpPolicyRefs) != 1 || len(childNode.HttpPolicyRefs[0].RequestRules) != 1 {
+				return false
+			}
+			rewriteAction := childNode.HttpPolicyRefs[0].RequestRules[0].RewriteURLAction
+			return rewriteAction != nil && rewriteAction.Path != nil && len(rewriteAction.Path.Tokens) == 1 &&
+				*rewriteAction.Path.Tokens[0].StrValue == "bar"
+		})
+
+		teardownHTTPRoute(t, gatewayClassName, gatewayName, httpRouteName)
+	},
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package features
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+func init() {
+	FeatureTests = append(FeatureTests,
+		TLSRouteSimpleSameNamespace,
+		TLSRouteWildcardHostnames,
+	)
+}
+
+var TLSRouteSimpleSameNamespace = FeatureTest{
+	ShortName:   "TLSRouteSimpleSameNamespace",
+	Description: "A single TLSRoute in the infra namespace attaches to a Gateway in the same namespace",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportTLSRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-tr-01"
+		gatewayName := "gateway-cf-tr-01"
+		tlsRouteName := "tlsroute-cf-tr-01"
+		svcName := "tls-backend-cf-tr-01"
+		ports := []int32{8443}
+		modelName, _ := tests.GetModelName(INFRA_NAMESPACE, gatewayName)
+
+		integrationtest.CreateSVC(t, INFRA_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+		integrationtest.CreateEP(t, INFRA_NAMESPACE, svcName, false, false, "1.2.3")
+		tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+		tests.SetupGateway(t, gatewayName, INFRA_NAMESPACE, gatewayClassName, nil, tests.GetPassthroughListenersV1(ports))
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() bool {
+			return isGatewayAccepted(gatewayName, INFRA_NAMESPACE)
+		}, 30*time.Second).Should(gomega.Equal(true))
+
+		parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, INFRA_NAMESPACE, ports)
+		rule := tests.GetTLSRouteRuleV1alpha2([][]string{{svcName, INFRA_NAMESPACE, "8080", "1"}})
+		tests.SetupTLSRoute(t, tlsRouteName, INFRA_NAMESPACE, parentRefs, []gatewayv1.Hostname{"foo-8443.com"}, []gatewayv1alpha2.TLSRouteRule{rule})
+
+		g.Eventually(func() bool {
+			tlsRoute, err := tests.GatewayClient.GatewayV1alpha2().TLSRoutes(INFRA_NAMESPACE).Get(context.TODO(), tlsRouteName, metav1.GetOptions{})
+			if err != nil || len(tlsRoute.Status.Parents) != 1 {
+				return false
+			}
+			return apimeta.IsStatusConditionTrue(tlsRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+		}, 30*time.Second).Should(gomega.Equal(true))
+
+		// the connections for the SNI hostname are switched to the poolgroup of the backend
+		g.Eventually(func() int {
+			found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+			if !found || aviModel == nil {
+				return -1
+			}
+			vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+			if len(vsNode.PoolGroupRefs) != 1 || len(vsNode.PoolRefs) != 1 {
+				return -1
+			}
+			return len(vsNode.PoolRefs[0].Servers)
+		}, 30*time.Second).Should(gomega.Equal(1))
+
+		tests.TeardownTLSRoute(t, tlsRouteName, INFRA_NAMESPACE)
+		tests.TeardownGateway(t, gatewayName, INFRA_NAMESPACE)
+		tests.TeardownGatewayClass(t, gatewayClassName)
+		integrationtest.DelSVC(t, INFRA_NAMESPACE, svcName)
+		integrationtest.DelEP(t, INFRA_NAMESPACE, svcName)
+	},
+}
+
+var TLSRouteWildcardHostnames = FeatureTest{
+	ShortName:   "TLSRouteWildcardHostnames",
+	Description: "A TLSRoute with wildcard hostnames attaches to a Gateway listener with a wildcard hostname",
+	Features:    []gatewayv1.SupportedFeature{SupportGateway, SupportTLSRoute},
+	Test: func(t *testing.T) {
+		gatewayClassName := "gateway-class-cf-tr-02"
+		gatewayName := "gateway-cf-tr-02"
+		tlsRouteName := "tlsroute-cf-tr-02"
+		svcName := "tls-backend-cf-tr-02"
+		ports := []int32{8443}
+		modelName, _ := tests.GetModelName(INFRA_NAMESPACE, gatewayName)
+
+		integrationtest.CreateSVC(t, INFRA_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+		integrationtest.CreateEP(t, INFRA_NAMESPACE, svcName, false, false, "1.2.3")
+		tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+		listeners := tests.GetPassthroughListenersV1(ports)
+		wildcardHostname := gatewayv1.Hostname("*.example.com")
+		listeners[0].Hostname = &wildcardHostname
+		tests.SetupGateway(t, gatewayName, INFRA_NAMESPACE, gatewayClassName, nil, listeners)
+
+		g := gomega.NewGomegaWithT(t)
+		g.Eventually(func() bool {
+			return isGatewayAccepted(gatewayName, INFRA_NAMESPACE)
+		}, 30*time.Second).Should(gomega.Equal(true))
+
+		parentRefs := tests.GetParentReferencesV1([]string{gatewayName}, INFRA_NAMESPACE, ports)
+		rule := tests.GetTLSRouteRuleV1alpha2([][]string{{svcName, INFRA_NAMESPACE, "8080", "1"}})
+		tests.SetupTLSRoute(t, tlsRouteName, INFRA_NAMESPACE, parentRefs, []gatewayv1.Hostname{"*.foo.example.com"}, []gatewayv1alpha2.TLSRouteRule{rule})
+
+		g.Eventually(func() bool {
+			tlsRoute, err := tests.GatewayClient.GatewayV1alpha2().TLSRoutes(INFRA_NAMESPACE).Get(context.TODO(), tlsRouteName, metav1.GetOptions{})
+			if err != nil || len(tlsRoute.Status.Parents) != 1 {
+				return false
+			}
+			return apimeta.IsStatusConditionTrue(tlsRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+		}, 30*time.Second).Should(gomega.Equal(true))
+
+		// the connections for the SNI hostnames matching the wildcard are switched to the poolgroup of the backend
+		pgName := akogatewayapilib.GetPassthroughPGName(INFRA_NAMESPACE, gatewayName, "*.foo.example.com")
+		g.Eventually(func() []string {
+			found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+			if !found || aviModel == nil {
+				return nil
+			}
+			vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+			if len(vsNode.HTTPDSrefs) != 1 {
+				return nil
+			}
+			return vsNode.HTTPDSrefs[0].PoolGroupRefs
+		}, 30*time.Second).Should(gomega.ConsistOf(pgName))
+
+		tests.TeardownTLSRoute(t, tlsRouteName, INFRA_NAMESPACE)
+		tests.TeardownGateway(t, gatewayName, INFRA_NAMESPACE)
+		tests.TeardownGatewayClass(t, gatewayClassName)
+		integrationtest.DelSVC(t, INFRA_NAMESPACE, svcName)
+		integrationtest.DelEP(t, INFRA_NAMESPACE, svcName)
+	},
+}
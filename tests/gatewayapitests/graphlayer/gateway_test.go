@@ -35,6 +35,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	v1beta1crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1beta1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
@@ -68,11 +69,14 @@ func TestMain(m *testing.M) {
 	lib.AKOControlConfig().SetIsLeaderFlag(true)
 	akoControlConfig := akogatewayapilib.AKOControlConfig()
 	akoControlConfig.SetEventRecorder(lib.AKOGatewayEventComponent, tests.KubeClient, true)
+	lib.AKOControlConfig().SetCRDClientsetAndEnableInfraSettingParam(v1beta1crdfake.NewSimpleClientset())
+	k8s.NewGatewayCRDInformers()
 	registeredInformers := []string{
 		utils.ServiceInformer,
 		utils.EndpointInformer,
 		utils.SecretInformer,
 		utils.NSInformer,
+		utils.ConfigMapInformer,
 	}
 	utils.AviLog.SetLevel("DEBUG")
 	utils.NewInformers(utils.KubeClientIntf{ClientSet: tests.KubeClient}, registeredInformers, make(map[string]interface{}))
@@ -456,3 +460,85 @@ func TestSecretCreateDelete(t *testing.T) {
 	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
 	tests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestGatewayWithAviInfraSetting(t *testing.T) {
+
+	gatewayName := "gateway-infra-01"
+	gatewayClassName := "gateway-class-infra-01"
+	infraSettingName := "infra-gw-01"
+	ports := []int32{8080}
+
+	setting := integrationtest.FakeAviInfraSetting{
+		Name:          infraSettingName,
+		SeGroupName:   "thisisaviref-" + infraSettingName + "-seGroup",
+		Networks:      []string{"thisisaviref-" + infraSettingName + "-networkName"},
+		EnableRhi:     true,
+		BGPPeerLabels: []string{"peer1", "peer2"},
+	}
+	infraSetting := setting.AviInfraSetting()
+	infraSetting.Status.Status = lib.StatusAccepted
+	if _, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings().Create(context.TODO(), infraSetting, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding AviInfraSetting: %v", err)
+	}
+
+	gc := &tests.FakeGatewayClass{
+		Name:           gatewayClassName,
+		ControllerName: akogatewayapilib.GatewayController,
+		ParametersRef: &gatewayv1.ParametersReference{
+			Group: lib.AkoGroup,
+			Kind:  lib.AviInfraSetting,
+			Name:  infraSettingName,
+		},
+	}
+	gc.Create(t)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, tests.GetListenersV1(ports))
+
+	g := gomega.NewGomegaWithT(t)
+	modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(DEFAULT_NAMESPACE, gatewayName))
+
+	g.Eventually(func() string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return ""
+		}
+		return aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].ServiceEngineGroup
+	}, 30*time.Second).Should(gomega.Equal("thisisaviref-" + infraSettingName + "-seGroup"))
+	// the networks of the AviInfraSetting are populated by its event handler, which builds the gateway again
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].VSVIPRefs[0].VipNetworks)
+	}, 30*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(*nodes[0].EnableRhi).To(gomega.Equal(true))
+	g.Expect(nodes[0].VSVIPRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs[0].BGPPeerLabels).To(gomega.ContainElements("peer1", "peer2"))
+	g.Expect(nodes[0].VSVIPRefs[0].VipNetworks).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs[0].VipNetworks[0].NetworkName).To(gomega.Equal("thisisaviref-" + infraSettingName + "-networkName"))
+
+	// the gateways are built again once the AviInfraSetting is updated
+	setting.SeGroupName = "thisisaviref-" + infraSettingName + "-seGroup-updated"
+	infraSetting = setting.AviInfraSetting()
+	infraSetting.Status.Status = lib.StatusAccepted
+	infraSetting.ResourceVersion = "2"
+	if _, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings().Update(context.TODO(), infraSetting, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating AviInfraSetting: %v", err)
+	}
+	g.Eventually(func() string {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].ServiceEngineGroup
+	}, 30*time.Second).Should(gomega.Equal("thisisaviref-" + infraSettingName + "-seGroup-updated"))
+
+	// the global defaults are used once the parametersRef is removed from the GatewayClass
+	gc.ParametersRef = nil
+	gc.Update(t)
+	g.Eventually(func() string {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].ServiceEngineGroup
+	}, 30*time.Second).Should(gomega.Equal(lib.GetSEGName()))
+
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.TeardownAviInfraSetting(t, infraSettingName)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+func TestGRPCRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-gr-01"
+	gatewayClassName := "gateway-class-gr-01"
+	grpcRouteName := "grpc-route-gr-01"
+	svcName := "avisvc-gr-01"
+	ports := []int32{8080}
+	modelName, parentVSName := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rules := []gatewayv1alpha2.GRPCRouteRule{
+		akogatewayapitests.GetGRPCRouteRuleV1alpha2([][]string{{"foo.Bar", "Get"}}, [][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	childNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes[0]
+	g.Expect(childNode.VHParentName).To(gomega.Equal(parentVSName))
+	g.Expect(*childNode.VHMatches[0].Host).To(gomega.Equal("foo-8080.com"))
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Path.MatchStr).To(gomega.ContainElement("/foo.Bar/Get"))
+	g.Expect(*childNode.VHMatches[0].Rules[0].Matches.Path.MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(childNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolRefs[0].EnableHttp2).To(gomega.Equal(true))
+	g.Expect(childNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+
+	// service match
+	rules = []gatewayv1alpha2.GRPCRouteRule{
+		akogatewayapitests.GetGRPCRouteRuleV1alpha2([][]string{{"foo.Bar", ""}}, [][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.UpdateGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+	g.Eventually(func() string {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return ""
+		}
+		return *nodes[0].EvhNodes[0].VHMatches[0].Rules[0].Matches.Path.MatchCriteria
+	}, 25*time.Second).Should(gomega.Equal("BEGINS_WITH"))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	childNode = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes[0]
+	g.Expect(childNode.VHMatches[0].Rules[0].Matches.Path.MatchStr).To(gomega.ContainElement("/foo.Bar/"))
+
+	// method match
+	rules = []gatewayv1alpha2.GRPCRouteRule{
+		akogatewayapitests.GetGRPCRouteRuleV1alpha2([][]string{{"", "Get"}}, [][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.UpdateGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+	g.Eventually(func() string {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return ""
+		}
+		return *nodes[0].EvhNodes[0].VHMatches[0].Rules[0].Matches.Path.MatchCriteria
+	}, 25*time.Second).Should(gomega.Equal("ENDS_WITH"))
+
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGRPCRouteWithHTTPRouteOfSameName(t *testing.T) {
+
+	gatewayName := "gateway-gr-02"
+	gatewayClassName := "gateway-class-gr-02"
+	routeName := "route-gr-02"
+	svcName := "avisvc-gr-02"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	httpRules := []gatewayv1.HTTPRouteRule{
+		akogatewayapitests.GetHTTPRouteRuleV1([]string{"/"}, []string{}, nil, [][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupHTTPRoute(t, routeName, DEFAULT_NAMESPACE, parentRefs, hostnames, httpRules)
+	// rule without matches, matches all the requests
+	grpcRules := []gatewayv1alpha2.GRPCRouteRule{
+		akogatewayapitests.GetGRPCRouteRuleV1alpha2(nil, [][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupGRPCRoute(t, routeName, DEFAULT_NAMESPACE, parentRefs, hostnames, grpcRules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	childNodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes
+	g.Expect(childNodes[0].Name).NotTo(gomega.Equal(childNodes[1].Name))
+	g.Expect(childNodes[0].PoolRefs[0].EnableHttp2).NotTo(gomega.Equal(childNodes[1].PoolRefs[0].EnableHttp2))
+
+	akogatewayapitests.TeardownGRPCRoute(t, routeName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(1))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	childNodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes
+	g.Expect(childNodes[0].PoolRefs[0].EnableHttp2).To(gomega.Equal(false))
+
+	akogatewayapitests.TeardownHTTPRoute(t, routeName, DEFAULT_NAMESPACE)
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
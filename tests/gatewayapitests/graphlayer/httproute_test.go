@@ -30,6 +30,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 )
@@ -530,6 +531,213 @@ func TestHTTPRouteFilterWithRequestRedirect(t *testing.T) {
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
 
+func TestHTTPRouteFilterWithURLRewrite(t *testing.T) {
+
+	gatewayName := "gateway-hrf-05"
+	gatewayClassName := "gateway-class-hrf-05"
+	httpRouteName := "http-route-hrf-05"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo/v1"}, []string{},
+		map[string][]string{"URLRewrite": {"hostname", "prefix"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	requestRule := nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0]
+	g.Expect(requestRule.RewriteURLAction).ShouldNot(gomega.BeNil())
+	g.Expect(*requestRule.Match.Path.MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(requestRule.Match.Path.MatchStr).To(gomega.ConsistOf("/foo/v1"))
+	g.Expect(*requestRule.RewriteURLAction.HostHdr.Tokens[0].StrValue).To(gomega.Equal("rewrite.com"))
+	g.Expect(requestRule.RewriteURLAction.Path.Tokens).To(gomega.HaveLen(2))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[1].Type).To(gomega.Equal("URI_TOKEN_TYPE_PATH"))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[1].StartIndex).To(gomega.Equal(int32(2)))
+
+	// update httproute to replace the full path
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo/v1"}, []string{},
+		map[string][]string{"URLRewrite": {"fullpath"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		childVS := nodes[0].EvhNodes[0]
+		if len(childVS.HttpPolicyRefs) != 1 || len(childVS.HttpPolicyRefs[0].RequestRules) != 1 {
+			return false
+		}
+		requestRule := childVS.HttpPolicyRefs[0].RequestRules[0]
+		return requestRule.Match == nil && requestRule.RewriteURLAction.HostHdr == nil &&
+			len(requestRule.RewriteURLAction.Path.Tokens) == 1 &&
+			*requestRule.RewriteURLAction.Path.Tokens[0].StrValue == "bar"
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteFilterWithRequestMirror(t *testing.T) {
+
+	gatewayName := "gateway-hrf-06"
+	gatewayClassName := "gateway-class-hrf-06"
+	httpRouteName := "http-route-hrf-06"
+	mirrorSvcName := "mirrorsvc"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, mirrorSvcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, mirrorSvcName, false, false, "1.2.6")
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestMirror": {}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || nodes[0].EvhNodes[0].SidebandProfile == nil {
+			return 0
+		}
+		return len(nodes[0].EvhNodes[0].SidebandProfile.IP)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childVS := nodes[0].EvhNodes[0]
+	g.Expect(*childVS.SidebandProfile.IP[0].Addr).To(gomega.Equal("1.2.6.1"))
+	g.Expect(childVS.HttpPolicyRefs).To(gomega.HaveLen(0))
+
+	// the servers of the mirror backend are updated with its endpoints
+	integrationtest.ScaleCreateEP(t, DEFAULT_NAMESPACE, mirrorSvcName)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if nodes[0].EvhNodes[0].SidebandProfile == nil {
+			return 0
+		}
+		return len(nodes[0].EvhNodes[0].SidebandProfile.IP)
+	}, 25*time.Second).Should(gomega.Equal(2))
+
+	// update httproute
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return nodes[0].EvhNodes[0].SidebandProfile == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, mirrorSvcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, mirrorSvcName)
+}
+
+func TestHTTPRouteWithQueryParamAndMethodMatch(t *testing.T) {
+
+	gatewayName := "gateway-hr-qm-01"
+	gatewayClassName := "gateway-class-hr-qm-01"
+	httpRouteName := "http-route-hr-qm-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{}, []string{}, map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rule.Matches = []gatewayv1.HTTPRouteMatch{akogatewayapitests.GetHTTPRouteQueryMethodMatchV1("/foo", "version", "v1", "GET")}
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	matchTarget := nodes[0].EvhNodes[0].VHMatches[0].Rules[0].Matches
+	g.Expect(matchTarget.Query).ShouldNot(gomega.BeNil())
+	g.Expect(*matchTarget.Query.MatchCriteria).To(gomega.Equal("QUERY_MATCH_REGEX_MATCH"))
+	g.Expect(matchTarget.Query.MatchStr).To(gomega.ConsistOf("(^|&)version=v1(&|$)"))
+	g.Expect(matchTarget.Method).ShouldNot(gomega.BeNil())
+	g.Expect(*matchTarget.Method.MatchCriteria).To(gomega.Equal("IS_IN"))
+	g.Expect(matchTarget.Method.Methods).To(gomega.ConsistOf("HTTP_METHOD_GET"))
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
 func TestHTTPRouteWithValidConfig(t *testing.T) {
 	gatewayClassName := "gateway-class-hr-01"
 	gatewayName := "gateway-hr-01"
@@ -878,3 +1086,384 @@ func TestHTTPRouteBackendServiceUpdate(t *testing.T) {
 	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestHTTPRouteWithCrossNamespaceBackendRef(t *testing.T) {
+
+	gatewayName := "gateway-hr-rg-01"
+	gatewayClassName := "gateway-class-hr-rg-01"
+	httpRouteName := "http-route-hr-rg-01"
+	referenceGrantName := "referencegrant-hr-rg-01"
+	svcName := "avisvc-hr-rg-01"
+	svcNamespace := "svc-ns-hr-rg-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	integrationtest.CreateSVC(t, svcNamespace, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, svcNamespace, svcName, false, false, "1.2.3")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, svcNamespace, "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	poolCount := func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		var count int
+		for _, childNode := range nodes[0].EvhNodes {
+			count += len(childNode.PoolRefs)
+		}
+		return count
+	}
+	// the backend in the other namespace is skipped without a ReferenceGrant
+	g.Eventually(poolCount, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.SetupReferenceGrant(t, referenceGrantName, svcNamespace, lib.HTTPRoute, DEFAULT_NAMESPACE, utils.Service)
+	g.Eventually(poolCount, 25*time.Second).Should(gomega.Equal(1))
+
+	akogatewayapitests.TeardownReferenceGrant(t, referenceGrantName, svcNamespace)
+	g.Eventually(poolCount, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, svcNamespace, svcName)
+	integrationtest.DelEP(t, svcNamespace, svcName)
+}
+
+func TestHTTPRouteWithListenerNamespaceSelector(t *testing.T) {
+
+	gatewayName := "gateway-hr-ns-01"
+	gatewayClassName := "gateway-class-hr-ns-01"
+	httpRouteName := "http-route-hr-ns-01"
+	svcName := "avisvc-hr-ns-01"
+	routeNamespace := "route-ns-hr-ns-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	integrationtest.AddNamespace(t, routeNamespace, map[string]string{"app": "foo"})
+	integrationtest.CreateSVC(t, routeNamespace, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, routeNamespace, svcName, false, false, "1.2.3")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetListenerAllowedRoutesSelector(&listeners[0], map[string]string{"gateway-access": "true"})
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, routeNamespace, "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, routeNamespace, parentRefs, hostnames, rules)
+
+	childCount := func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}
+	// the route isn't programmed until its namespace matches the selector of the listener
+	g.Consistently(childCount, 5*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.UpdateNamespace(t, routeNamespace, map[string]string{"app": "foo", "gateway-access": "true"})
+	g.Eventually(childCount, 25*time.Second).Should(gomega.Equal(1))
+
+	integrationtest.UpdateNamespace(t, routeNamespace, map[string]string{"app": "foo"})
+	g.Eventually(childCount, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, routeNamespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, routeNamespace, svcName)
+	integrationtest.DelEP(t, routeNamespace, svcName)
+	integrationtest.DeleteNamespace(routeNamespace)
+}
+
+func TestHTTPRouteWithBackendTLSPolicy(t *testing.T) {
+
+	gatewayName := "gateway-hr-btls-01"
+	gatewayClassName := "gateway-class-hr-btls-01"
+	httpRouteName := "http-route-hr-btls-01"
+	policyName := "backendtlspolicy-hr-btls-01"
+	caSecretName := "ca-hr-btls-01"
+	svcName := "avisvc-hr-btls-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+	akogatewayapitests.AddCACertSecret(t, caSecretName, DEFAULT_NAMESPACE, "ca-cert-01")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	getPool := func() *avinodes.AviPoolNode {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return nil
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].PoolRefs) != 1 {
+			return nil
+		}
+		return nodes[0].EvhNodes[0].PoolRefs[0]
+	}
+	g.Eventually(func() bool {
+		pool := getPool()
+		return pool != nil && !pool.SniEnabled && pool.PkiProfile == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// the connections to the servers are re-encrypted once the policy targets the Service
+	akogatewayapitests.SetupBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE, svcName, "backend.example.com", caSecretName)
+	g.Eventually(func() bool {
+		pool := getPool()
+		return pool != nil && pool.SniEnabled && pool.PkiProfile != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool := getPool()
+	g.Expect(pool.ServerName).To(gomega.Equal("backend.example.com"))
+	g.Expect(*pool.SslProfileRef).To(gomega.Equal("/api/sslprofile?name=" + lib.DefaultPoolSSLProfile))
+	g.Expect(pool.PkiProfile.CACert).To(gomega.Equal("ca-cert-01"))
+
+	// the pki profile is updated along with the CA certificate
+	akogatewayapitests.AddCACertSecret(t, caSecretName, DEFAULT_NAMESPACE, "ca-cert-02")
+	g.Eventually(func() string {
+		pool := getPool()
+		if pool == nil || pool.PkiProfile == nil {
+			return ""
+		}
+		return pool.PkiProfile.CACert
+	}, 25*time.Second).Should(gomega.Equal("ca-cert-02"))
+
+	akogatewayapitests.TeardownBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		pool := getPool()
+		return pool != nil && !pool.SniEnabled && pool.PkiProfile == nil && pool.ServerName == ""
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+}
+
+func TestHTTPRouteWithHostRuleAndHTTPRule(t *testing.T) {
+
+	gatewayName := "gateway-hr-crd-01"
+	gatewayClassName := "gateway-class-hr-crd-01"
+	httpRouteName := "http-route-hr-crd-01"
+	hostRuleName := "hostrule-hr-crd-01"
+	httpRuleName := "httprule-hr-crd-01"
+	svcName := "avisvc-hr-crd-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	getChildVS := func() *avinodes.AviEvhVsNode {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return nil
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].PoolRefs) != 1 {
+			return nil
+		}
+		return nodes[0].EvhNodes[0]
+	}
+	g.Eventually(func() bool {
+		return getChildVS() != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// the HostRule of the hostname of the route is applied on the child VS once accepted
+	hostRule := integrationtest.FakeHostRule{
+		Name:             hostRuleName,
+		Namespace:        DEFAULT_NAMESPACE,
+		Fqdn:             "foo-8080.com",
+		WafPolicy:        "thisisaviref-waf",
+		AnalyticsProfile: "thisisaviref-analyticsprof",
+	}.HostRule()
+	hostRule.Status.Status = lib.StatusAccepted
+	if _, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(DEFAULT_NAMESPACE).Create(context.TODO(), hostRule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	g.Eventually(func() bool {
+		childVS := getChildVS()
+		return childVS != nil && childVS.WafPolicyRef != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	childVS := getChildVS()
+	g.Expect(*childVS.WafPolicyRef).To(gomega.Equal("/api/wafpolicy?name=thisisaviref-waf"))
+	g.Expect(*childVS.AnalyticsProfileRef).To(gomega.Equal("/api/analyticsprofile?name=thisisaviref-analyticsprof"))
+	g.Expect(childVS.ServiceMetadata.CRDStatus.Value).To(gomega.Equal(DEFAULT_NAMESPACE + "/" + hostRuleName))
+
+	// the HTTPRule path which is the longest prefix of the path of the rule is applied on the pool
+	httpRule := integrationtest.FakeHTTPRule{
+		Name:      httpRuleName,
+		Namespace: DEFAULT_NAMESPACE,
+		Fqdn:      "foo-8080.com",
+		PathProperties: []integrationtest.FakeHTTPRulePath{{
+			Path:        "/",
+			LbAlgorithm: "LB_ALGORITHM_ROUND_ROBIN",
+		}, {
+			Path:           "/foo",
+			LbAlgorithm:    "LB_ALGORITHM_CONSISTENT_HASH",
+			Hash:           "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS",
+			HealthMonitors: []string{"thisisaviref-hm1"},
+		}},
+	}.HTTPRule()
+	httpRule.Status.Status = lib.StatusAccepted
+	if _, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(DEFAULT_NAMESPACE).Create(context.TODO(), httpRule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	g.Eventually(func() bool {
+		childVS := getChildVS()
+		return childVS != nil && childVS.PoolRefs[0].LbAlgorithm != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool := getChildVS().PoolRefs[0]
+	g.Expect(*pool.LbAlgorithm).To(gomega.Equal("LB_ALGORITHM_CONSISTENT_HASH"))
+	g.Expect(*pool.LbAlgorithmHash).To(gomega.Equal("LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS"))
+	g.Expect(pool.HealthMonitorRefs).To(gomega.ContainElement("/api/healthmonitor?name=thisisaviref-hm1"))
+	g.Expect(pool.SniEnabled).To(gomega.Equal(true))
+
+	// the settings are removed from the child VS and the pool once the rules are deleted
+	if err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(DEFAULT_NAMESPACE).Delete(context.TODO(), httpRuleName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting HTTPRule: %v", err)
+	}
+	g.Eventually(func() bool {
+		childVS := getChildVS()
+		return childVS != nil && childVS.PoolRefs[0].LbAlgorithm == nil && len(childVS.PoolRefs[0].HealthMonitorRefs) == 0
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	if err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(DEFAULT_NAMESPACE).Delete(context.TODO(), hostRuleName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting HostRule: %v", err)
+	}
+	g.Eventually(func() bool {
+		childVS := getChildVS()
+		return childVS != nil && childVS.WafPolicyRef == nil && childVS.AnalyticsProfileRef == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+}
+
+func TestHTTPRouteWithTimeouts(t *testing.T) {
+
+	gatewayName := "gateway-hr-to-01"
+	gatewayClassName := "gateway-class-hr-to-01"
+	httpRouteName := "http-route-hr-to-01"
+	svcName := "avisvc-hr-to-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	rule.Timeouts = akogatewayapitests.GetHTTPRouteTimeoutsV1("30s", "10s")
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	getServerTimeout := func() int32 {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].PoolRefs) != 1 {
+			return -1
+		}
+		pool := nodes[0].EvhNodes[0].PoolRefs[0]
+		if pool.ServerTimeout == nil {
+			return -2
+		}
+		return *pool.ServerTimeout
+	}
+
+	// the backendRequest timeout is preferred over the request timeout
+	g.Eventually(getServerTimeout, 25*time.Second).Should(gomega.Equal(int32(10000)))
+
+	rule.Timeouts = akogatewayapitests.GetHTTPRouteTimeoutsV1("1m30s", "")
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+	g.Eventually(getServerTimeout, 25*time.Second).Should(gomega.Equal(int32(90000)))
+
+	// a zero timeout falls back to the default server timeout of the pool
+	rule.Timeouts = akogatewayapitests.GetHTTPRouteTimeoutsV1("0s", "")
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+	g.Eventually(getServerTimeout, 25*time.Second).Should(gomega.Equal(int32(0)))
+
+	rule.Timeouts = nil
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+	g.Eventually(getServerTimeout, 25*time.Second).Should(gomega.Equal(int32(-2)))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+}
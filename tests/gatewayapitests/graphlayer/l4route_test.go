@@ -0,0 +1,184 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+func TestTCPRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-l4-01"
+	gatewayClassName := "gateway-class-l4-01"
+	tcpRouteName := "tcp-route-l4-01"
+	svcName := "avisvc-l4-01"
+	l4ModelName, l4VSName := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetL4ListenersV1([]int32{9000}, gatewayv1.TCPProtocolType)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return found && aviModel != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// the L4 VS owns the vsvip when the gateway has only TCP/UDP listeners
+	_, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+	vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	g.Expect(vsNode.Name).To(gomega.Equal(l4VSName))
+	g.Expect(vsNode.ApplicationProfile).To(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(9000)))
+	g.Expect(vsNode.ServiceMetadata.PassthroughParentRef).To(gomega.Equal(""))
+	g.Expect(vsNode.ServiceMetadata.Gateway).To(gomega.Equal(DEFAULT_NAMESPACE + "/" + gatewayName))
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(0))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{9000})
+	rules := []gatewayv1alpha2.TCPRouteRule{
+		akogatewayapitests.GetTCPRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE, parentRefs, rules)
+
+	pgName := akogatewayapilib.GetL4PoolGroupName(DEFAULT_NAMESPACE, gatewayName, "listener-9000")
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].L4PolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(l4ModelName)
+	vsNode = aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool).To(gomega.HaveLen(1))
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(9000)))
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool[0].PoolGroup).To(gomega.ContainSubstring(pgName))
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolGroupRefs[0].Name).To(gomega.Equal(pgName))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs[0].Port).To(gomega.Equal(int32(8443)))
+	g.Expect(vsNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+
+	// the listener is served by the oldest route
+	dupRules := []gatewayv1alpha2.TCPRouteRule{
+		akogatewayapitests.GetTCPRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}, {svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupTCPRoute(t, tcpRouteName+"-dup", DEFAULT_NAMESPACE, parentRefs, dupRules)
+	g.Consistently(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolGroupRefs[0].Members)
+	}, 5*time.Second).Should(gomega.Equal(1))
+	akogatewayapitests.TeardownTCPRoute(t, tcpRouteName+"-dup", DEFAULT_NAMESPACE)
+
+	akogatewayapitests.TeardownTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return found && aviModel != nil
+	}, 25*time.Second).Should(gomega.Equal(false))
+
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestUDPRouteWithHTTPListener(t *testing.T) {
+
+	gatewayName := "gateway-l4-02"
+	gatewayClassName := "gateway-class-l4-02"
+	udpRouteName := "udp-route-l4-02"
+	svcName := "avisvc-l4-02"
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+	l4ModelName, l4VSName := akogatewayapitests.GetL4ModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1([]int32{8080})
+	listeners = append(listeners, akogatewayapitests.GetL4ListenersV1([]int32{5353}, gatewayv1.UDPProtocolType)...)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return found && aviModel != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// the UDP listener is not served by the EVH parent, which owns the vsvip
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	parentNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+	g.Expect(parentNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PortProto[0].Port).To(gomega.Equal(int32(8080)))
+	g.Expect(parentNode.ServiceMetadata.PassthroughChildRef).To(gomega.Equal(l4VSName))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{5353})
+	rules := []gatewayv1alpha2.UDPRouteRule{
+		akogatewayapitests.GetUDPRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE, parentRefs, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].L4PolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(l4ModelName)
+	vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	g.Expect(vsNode.NetworkProfile).To(gomega.Equal(utils.SYSTEM_UDP_FAST_PATH))
+	g.Expect(vsNode.ServiceMetadata.PassthroughParentRef).To(gomega.Equal(parentNode.Name))
+	g.Expect(vsNode.VSVIPRefs[0].Name).To(gomega.Equal(parentNode.VSVIPRefs[0].Name))
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool[0].Protocol).To(gomega.Equal(utils.UDP))
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+
+	akogatewayapitests.TeardownUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].L4PolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(l4ModelName)
+		return found && aviModel != nil
+	}, 25*time.Second).Should(gomega.Equal(false))
+
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
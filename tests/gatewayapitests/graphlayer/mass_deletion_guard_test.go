@@ -0,0 +1,113 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+func updateAllowMassDeletion(t *testing.T, value, resourceVersion string) {
+	cm, err := tests.KubeClient.CoreV1().ConfigMaps(utils.GetAKONamespace()).Get(context.TODO(), lib.AviConfigMap, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error in getting the configmap: %v", err)
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[lib.ALLOW_MASS_DELETION] = value
+	cm.ResourceVersion = resourceVersion
+	if _, err = tests.KubeClient.CoreV1().ConfigMaps(utils.GetAKONamespace()).Update(context.TODO(), cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating the configmap: %v", err)
+	}
+}
+
+func TestGatewayMassDeletionGuardHaltAndResume(t *testing.T) {
+	gatewayName := "gateway-mdg-01"
+	gatewayClassName := "gateway-class-mdg-01"
+	ports := []int32{8080}
+	modelName, _ := tests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	// the Gateway model makes sure that the controller has synced during the bootup
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	os.Setenv("MASS_DELETION_THRESHOLD_COUNT", "1")
+	defer os.Unsetenv("MASS_DELETION_THRESHOLD_COUNT")
+
+	// parent VSes of Gateways which are not present in the cluster
+	var vsKeys []cache.NamespaceName
+	for i := 0; i < 2; i++ {
+		_, vsName := tests.GetModelName(DEFAULT_NAMESPACE, fmt.Sprintf("gateway-mass-deletion-%d", i))
+		vsKey := cache.NamespaceName{Namespace: "admin", Name: vsName}
+		cache.SharedAviObjCache().VsCacheMeta.AviCacheAdd(vsKey, &cache.AviVsCache{
+			Name:   vsName,
+			Tenant: "admin",
+			Uuid:   "virtualservice-" + vsName + "-random-uuid",
+		})
+		vsKeys = append(vsKeys, vsKey)
+	}
+
+	// The deletion of the virtualservices exceeding the threshold is halted during the full sync.
+	g.Expect(ctrl.FullSyncK8s(true)).To(gomega.Succeed())
+	g.Consistently(func() bool {
+		for _, vsKey := range vsKeys {
+			if _, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey); !found {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second).Should(gomega.BeTrue())
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.ContainElements(
+		lib.GetModelName("admin", vsKeys[0].Name), lib.GetModelName("admin", vsKeys[1].Name)))
+
+	// The halted deletions are resumed once the override is set in the configmap.
+	updateAllowMassDeletion(t, "true", "gateway-mass-deletion-1")
+	defer updateAllowMassDeletion(t, "false", "gateway-mass-deletion-2")
+	g.Eventually(func() int {
+		var found int
+		for _, vsKey := range vsKeys {
+			if _, ok := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey); ok {
+				found++
+			}
+		}
+		return found
+	}, 15*time.Second).Should(gomega.Equal(0))
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.BeEmpty())
+
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+func setupPassthroughBackend(t *testing.T, name string) {
+	svcExample := (integrationtest.FakeService{
+		Name:         name,
+		Namespace:    DEFAULT_NAMESPACE,
+		Type:         corev1.ServiceTypeClusterIP,
+		ServicePorts: []integrationtest.Serviceport{{PortName: "https", Protocol: "TCP", PortNumber: 8443, TargetPort: intstr.FromInt(8443)}},
+	}).Service()
+	if _, err := akogatewayapitests.KubeClient.CoreV1().Services(DEFAULT_NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: DEFAULT_NAMESPACE,
+			Name:      name,
+		},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.2.3.4"}},
+			Ports:     []corev1.EndpointPort{{Name: "https", Port: 8443, Protocol: "TCP"}},
+		}},
+	}
+	if _, err := akogatewayapitests.KubeClient.CoreV1().Endpoints(DEFAULT_NAMESPACE).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+}
+
+func teardownPassthroughBackend(t *testing.T, name string) {
+	akogatewayapitests.KubeClient.CoreV1().Endpoints(DEFAULT_NAMESPACE).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	akogatewayapitests.KubeClient.CoreV1().Services(DEFAULT_NAMESPACE).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
+// getParentVsNode returns the EVH parent VS of the gateway, or nil if the model isn't built.
+func getParentVsNode(modelName string) *avinodes.AviEvhVsNode {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+func TestTLSRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-tr-01"
+	gatewayClassName := "gateway-class-tr-01"
+	tlsRouteName := "tls-route-tr-01"
+	svcName := "avisvc-tr-01"
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1([]int32{8080})
+	listeners = append(listeners, akogatewayapitests.GetPassthroughListenersV1([]int32{8443})...)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		return getParentVsNode(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	// the passthrough listener is served by the EVH parent, with the L4 application profile on its port
+	parentNode := getParentVsNode(modelName)
+	g.Expect(parentNode.PortProto).To(gomega.HaveLen(2))
+	for _, pp := range parentNode.PortProto {
+		if pp.Port == 8443 {
+			g.Expect(pp.Passthrough).To(gomega.BeTrue())
+			g.Expect(pp.Protocol).To(gomega.Equal(utils.TCP))
+		} else {
+			g.Expect(pp.Port).To(gomega.Equal(int32(8080)))
+			g.Expect(pp.Passthrough).To(gomega.BeFalse())
+		}
+	}
+	g.Expect(parentNode.ServiceMetadata.PassthroughChildRef).To(gomega.Equal(""))
+	g.Expect(parentNode.HTTPDSrefs).To(gomega.HaveLen(1))
+	g.Expect(parentNode.HTTPDSrefs[0].Name).To(gomega.Equal(akogatewayapilib.GetPassthroughDSName(DEFAULT_NAMESPACE, gatewayName)))
+	g.Expect(parentNode.HTTPDSrefs[0].Evt).To(gomega.Equal("VS_DATASCRIPT_EVT_L4_REQUEST"))
+	g.Expect(parentNode.PoolGroupRefs).To(gomega.HaveLen(0))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{8443})
+	rules := []gatewayv1alpha2.TLSRouteRule{
+		akogatewayapitests.GetTLSRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, nil, rules)
+
+	pgName := akogatewayapilib.GetPassthroughPGName(DEFAULT_NAMESPACE, gatewayName, "foo-8443.com")
+	g.Eventually(func() int {
+		parentNode := getParentVsNode(modelName)
+		if parentNode == nil {
+			return 0
+		}
+		return len(parentNode.PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	parentNode = getParentVsNode(modelName)
+	g.Expect(parentNode.PoolGroupRefs[0].Name).To(gomega.Equal(pgName))
+	g.Expect(parentNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(parentNode.HTTPDSrefs[0].PoolGroupRefs).To(gomega.ContainElement(pgName))
+	g.Expect(parentNode.HTTPDSrefs[0].Script).To(gomega.ContainSubstring(`{["foo-8443.com"] = "` + pgName + `"}`))
+	g.Expect(parentNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PoolRefs[0].Port).To(gomega.Equal(int32(8443)))
+	g.Expect(parentNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(parentNode.VSVIPRefs[0].FQDNs).To(gomega.ContainElement("foo-8443.com"))
+
+	// the first TLSRoute claiming the hostname wins
+	hostnames := []gatewayv1.Hostname{"foo-8443.com"}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName+"-dup", DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+	g.Consistently(func() int {
+		return len(getParentVsNode(modelName).PoolRefs)
+	}, 5*time.Second).Should(gomega.Equal(1))
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName+"-dup", DEFAULT_NAMESPACE)
+
+	// hostname of the TLSRoute doesn't match the listener
+	hostnames = []gatewayv1.Hostname{"bar-8443.com"}
+	akogatewayapitests.UpdateTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+	g.Eventually(func() int {
+		return len(getParentVsNode(modelName).PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+	g.Expect(getParentVsNode(modelName).HTTPDSrefs[0].Script).To(gomega.ContainSubstring("local poolgroups = {}"))
+
+	hostnames = []gatewayv1.Hostname{"foo-8443.com"}
+	akogatewayapitests.UpdateTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+	g.Eventually(func() int {
+		return len(getParentVsNode(modelName).PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		return len(getParentVsNode(modelName).PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		return getParentVsNode(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(false))
+
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTLSRouteWithPassthroughOnlyGateway(t *testing.T) {
+
+	gatewayName := "gateway-tr-02"
+	gatewayClassName := "gateway-class-tr-02"
+	tlsRouteName := "tls-route-tr-02"
+	svcName := "avisvc-tr-02"
+	modelName, vsName := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetPassthroughListenersV1([]int32{8443})
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		return getParentVsNode(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{8443})
+	rules := []gatewayv1alpha2.TLSRouteRule{
+		akogatewayapitests.GetTLSRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, nil, rules)
+
+	g.Eventually(func() int {
+		parentNode := getParentVsNode(modelName)
+		if parentNode == nil {
+			return 0
+		}
+		return len(parentNode.PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	// the EVH parent is built for the passthrough listener alone, and owns the vsvip
+	parentNode := getParentVsNode(modelName)
+	g.Expect(parentNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PortProto[0].Passthrough).To(gomega.BeTrue())
+	g.Expect(parentNode.ServiceMetadata.Gateway).To(gomega.Equal(DEFAULT_NAMESPACE + "/" + gatewayName))
+	g.Expect(parentNode.VSVIPRefs[0].Name).To(gomega.ContainSubstring(vsName))
+	g.Expect(parentNode.VSVIPRefs[0].FQDNs).To(gomega.ContainElement("foo-8443.com"))
+
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		return getParentVsNode(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(false))
+
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTLSRouteWithWildcardHostnames(t *testing.T) {
+
+	gatewayName := "gateway-tr-03"
+	gatewayClassName := "gateway-class-tr-03"
+	tlsRouteName := "tls-route-tr-03"
+	svcName := "avisvc-tr-03"
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetPassthroughListenersV1([]int32{8443})
+	wildcardHostname := gatewayv1.Hostname("*.example.com")
+	listeners[0].Hostname = &wildcardHostname
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	setupPassthroughBackend(t, svcName)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		return getParentVsNode(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{8443})
+	rules := []gatewayv1alpha2.TLSRouteRule{
+		akogatewayapitests.GetTLSRouteRuleV1alpha2([][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}}),
+	}
+	hostnames := []gatewayv1.Hostname{"*.foo.example.com", "bar.example.com", "*.other.com"}
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	// the hostnames matching the wildcard listener hostname get a poolgroup each
+	g.Eventually(func() int {
+		return len(getParentVsNode(modelName).PoolGroupRefs)
+	}, 25*time.Second).Should(gomega.Equal(2))
+
+	parentNode := getParentVsNode(modelName)
+	wildcardPGName := akogatewayapilib.GetPassthroughPGName(DEFAULT_NAMESPACE, gatewayName, "*.foo.example.com")
+	exactPGName := akogatewayapilib.GetPassthroughPGName(DEFAULT_NAMESPACE, gatewayName, "bar.example.com")
+	g.Expect(parentNode.HTTPDSrefs[0].PoolGroupRefs).To(gomega.ConsistOf(wildcardPGName, exactPGName))
+	g.Expect(parentNode.HTTPDSrefs[0].Script).To(gomega.ContainSubstring(`{["*.foo.example.com"] = "` + wildcardPGName + `", ["bar.example.com"] = "` + exactPGName + `"}`))
+	// the wildcard hostnames are not added to the vsvip
+	g.Expect(parentNode.VSVIPRefs[0].FQDNs).To(gomega.ConsistOf("bar.example.com"))
+
+	// a TLSRoute without hostnames inherits the wildcard hostname of the listener
+	akogatewayapitests.UpdateTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, nil, rules)
+	wildcardPGName = akogatewayapilib.GetPassthroughPGName(DEFAULT_NAMESPACE, gatewayName, "*.example.com")
+	g.Eventually(func() []string {
+		return getParentVsNode(modelName).HTTPDSrefs[0].PoolGroupRefs
+	}, 25*time.Second).Should(gomega.ConsistOf(wildcardPGName))
+	g.Expect(getParentVsNode(modelName).VSVIPRefs[0].FQDNs).To(gomega.BeEmpty())
+
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		return getParentVsNode(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(false))
+
+	teardownPassthroughBackend(t, svcName)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -342,7 +342,7 @@ func TestGatewayInvalidListenerProtocol(t *testing.T) {
 		Status: gatewayv1.GatewayStatus{},
 	}
 	akogatewayapitests.SetGatewayGatewayClass(&gateway, gwClassName)
-	akogatewayapitests.AddGatewayListener(&gateway, "listener-example", 80, gatewayv1.TCPProtocolType, false)
+	akogatewayapitests.AddGatewayListener(&gateway, "listener-example", 80, gatewayv1.ProtocolType("SCTP"), false)
 	akogatewayapitests.SetListenerHostname(&gateway.Spec.Listeners[0], "*.example.com")
 
 	//create
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+// getBackendTLSPolicyReason returns the reason of the Accepted condition published by AKO for the Gateway
+// gatewayName, or an empty string if the Gateway is not an ancestor of the BackendTLSPolicy.
+func getBackendTLSPolicyReason(name, gatewayName string) string {
+	policy, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().BackendTLSPolicies(DEFAULT_NAMESPACE).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, ancestor := range policy.Status.Ancestors {
+		if ancestor.ControllerName != akogatewayapilib.GatewayController || string(ancestor.AncestorRef.Name) != gatewayName {
+			continue
+		}
+		condition := apimeta.FindStatusCondition(ancestor.Conditions, string(gatewayv1alpha2.PolicyConditionAccepted))
+		if condition == nil {
+			return ""
+		}
+		return condition.Reason
+	}
+	return ""
+}
+
+func TestBackendTLSPolicyStatus(t *testing.T) {
+	gatewayClassName := "gateway-class-btls-01"
+	gatewayName := "gateway-btls-01"
+	httpRouteName := "httproute-btls-01"
+	policyName := "backendtlspolicy-btls-01"
+	conflictedPolicyName := "backendtlspolicy-btls-02"
+	caSecretName := "ca-btls-01"
+	svcName := "avisvc-btls-01"
+	ports := []int32{8080}
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, svcName, false, false, "1.2.3")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, []gatewayv1.Hostname{"foo-8080.com"}, []gatewayv1.HTTPRouteRule{rule})
+
+	// the policy isn't accepted until its CA certificate is found
+	akogatewayapitests.SetupBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE, svcName, "backend.example.com", caSecretName)
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() string {
+		return getBackendTLSPolicyReason(policyName, gatewayName)
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1alpha2.PolicyReasonInvalid)))
+
+	akogatewayapitests.AddCACertSecret(t, caSecretName, DEFAULT_NAMESPACE, "ca-cert")
+	g.Eventually(func() string {
+		return getBackendTLSPolicyReason(policyName, gatewayName)
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1alpha2.PolicyReasonAccepted)))
+
+	// the policies created later for the same Service are conflicted
+	bp := &akogatewayapitests.BackendTLSPolicy{}
+	bp.BackendTLSPolicy = bp.BackendTLSPolicyV1alpha2(conflictedPolicyName, DEFAULT_NAMESPACE, svcName, "backend.example.com", caSecretName)
+	bp.CreationTimestamp = metav1.NewTime(time.Now().Add(time.Minute))
+	bp.Create(t)
+	g.Eventually(func() string {
+		return getBackendTLSPolicyReason(conflictedPolicyName, gatewayName)
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1alpha2.PolicyReasonConflicted)))
+	g.Expect(getBackendTLSPolicyReason(policyName, gatewayName)).To(gomega.Equal(string(gatewayv1alpha2.PolicyReasonAccepted)))
+
+	// the ancestor is removed along with the route
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() string {
+		return getBackendTLSPolicyReason(policyName, gatewayName)
+	}, 30*time.Second).Should(gomega.Equal(""))
+
+	akogatewayapitests.TeardownBackendTLSPolicy(t, conflictedPolicyName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, svcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, svcName)
+}
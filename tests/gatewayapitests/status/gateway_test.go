@@ -772,3 +772,46 @@ func TestGatewayWithInvalidTLSConfigInListeners(t *testing.T) {
 		integrationtest.DeleteSecret(secret, DEFAULT_NAMESPACE)
 	}
 }
+
+func TestGatewayWithConflictingPortsInListeners(t *testing.T) {
+
+	gatewayName := "gateway-neg-06"
+	gatewayClassName := "gateway-class-neg-06"
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1([]int32{8443})
+	passthroughListeners := tests.GetPassthroughListenersV1([]int32{8443})
+	passthroughListeners[0].Name = "listener-passthrough-8443"
+	listeners = append(listeners, passthroughListeners...)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	if err != nil || gateway == nil {
+		t.Fatalf("Couldn't get the gateway, err: %+v", err)
+	}
+
+	// the port can't use both the HTTP and the L4 application profiles, so both listeners are conflicted
+	condition := apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	g.Expect(condition.Status).To(gomega.Equal(metav1.ConditionFalse))
+	g.Expect(condition.Message).To(gomega.Equal("Gateway contains 2 invalid listener(s)"))
+	g.Expect(gateway.Status.Listeners).To(gomega.HaveLen(2))
+	for _, listenerStatus := range gateway.Status.Listeners {
+		condition = apimeta.FindStatusCondition(listenerStatus.Conditions, string(gatewayv1.ListenerConditionConflicted))
+		g.Expect(condition).NotTo(gomega.BeNil())
+		g.Expect(condition.Status).To(gomega.Equal(metav1.ConditionTrue))
+		g.Expect(condition.Reason).To(gomega.Equal(string(gatewayv1.ListenerReasonProtocolConflict)))
+	}
+
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -81,3 +81,34 @@ func TestGatewayClassWithNonAKOController(t *testing.T) {
 
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestGatewayClassWithInvalidParametersRef(t *testing.T) {
+
+	gatewayClassName := "gateway-class-02"
+	gc := &akogatewayapitests.FakeGatewayClass{
+		Name:           gatewayClassName,
+		ControllerName: akogatewayapilib.GatewayController,
+		ParametersRef: &gatewayv1.ParametersReference{
+			Group: "",
+			Kind:  "ConfigMap",
+			Name:  "gateway-params",
+		},
+	}
+	gc.Create(t)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() string {
+		gatewayClass, err := akogatewayapitests.GatewayClient.GatewayV1().GatewayClasses().Get(context.TODO(), gatewayClassName, metav1.GetOptions{})
+		if err != nil || gatewayClass == nil {
+			t.Logf("Couldn't get the GatewayClass, err: %+v", err)
+			return ""
+		}
+		condition := apimeta.FindStatusCondition(gatewayClass.Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted))
+		if condition == nil || condition.Status != metav1.ConditionFalse {
+			return ""
+		}
+		return condition.Reason
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1.GatewayClassReasonInvalidParameters)))
+
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
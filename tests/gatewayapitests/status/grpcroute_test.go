@@ -0,0 +1,123 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+func TestGRPCRouteWithValidConfig(t *testing.T) {
+	gatewayClassName := "gateway-class-gr-01"
+	gatewayName := "gateway-gr-01"
+	grpcRouteName := "grpcroute-01"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	rules := []gatewayv1alpha2.GRPCRouteRule{
+		akogatewayapitests.GetGRPCRouteRuleV1alpha2([][]string{{"foo.Bar", "Get"}}, nil),
+	}
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, namespace, parentRefs, hostnames, rules)
+
+	g.Eventually(func() bool {
+		grpcRoute, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+		if err != nil || grpcRoute == nil {
+			t.Logf("Couldn't get the GRPCRoute, err: %+v", err)
+			return false
+		}
+		if len(grpcRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(grpcRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted)) &&
+			apimeta.IsStatusConditionTrue(grpcRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, namespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGRPCRouteWithRegularExpressionMethodMatch(t *testing.T) {
+	gatewayClassName := "gateway-class-gr-02"
+	gatewayName := "gateway-gr-02"
+	grpcRouteName := "grpcroute-02"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	rule := akogatewayapitests.GetGRPCRouteRuleV1alpha2([][]string{{"foo.Bar", "Get.*"}}, nil)
+	regexMatchType := gatewayv1alpha2.GRPCMethodMatchRegularExpression
+	rule.Matches[0].Method.Type = &regexMatchType
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, namespace, parentRefs, hostnames, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() bool {
+		grpcRoute, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+		if err != nil || grpcRoute == nil {
+			t.Logf("Couldn't get the GRPCRoute, err: %+v", err)
+			return false
+		}
+		if len(grpcRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		condition := apimeta.FindStatusCondition(grpcRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+		return condition != nil && condition.Status == metav1.ConditionFalse &&
+			condition.Reason == string(gatewayv1.RouteReasonUnsupportedValue)
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, namespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
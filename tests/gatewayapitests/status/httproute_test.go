@@ -27,6 +27,7 @@ import (
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 )
 
 /* Positive test cases
@@ -664,3 +665,173 @@ func TestHTTPRouteWithNoHostnames(t *testing.T) {
 	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestHTTPRouteWithRequestMirrorFilter(t *testing.T) {
+	gatewayClassName := "gateway-class-hr-11"
+	gatewayName := "gateway-hr-11"
+	httpRouteName := "httproute-11"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestMirror": {}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, namespace, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	g.Eventually(func() bool {
+		httpRoute, err := akogatewayapitests.GatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), httpRouteName, metav1.GetOptions{})
+		if err != nil || httpRoute == nil {
+			t.Logf("Couldn't get the HTTPRoute, err: %+v", err)
+			return false
+		}
+		if len(httpRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(httpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, namespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteWithListenerNamespaceSelector(t *testing.T) {
+	gatewayClassName := "gateway-class-hr-12"
+	gatewayName := "gateway-hr-12"
+	httpRouteName := "httproute-12"
+	namespace := "default"
+	routeNamespace := "route-ns-hr-12"
+	ports := []int32{8080}
+
+	integrationtest.AddNamespace(t, routeNamespace, map[string]string{"app": "foo"})
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetListenerAllowedRoutesSelector(&listeners[0], map[string]string{"gateway-access": "true"})
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, routeNamespace, parentRefs, hostnames, nil)
+
+	acceptedCondition := func() *metav1.Condition {
+		httpRoute, err := akogatewayapitests.GatewayClient.GatewayV1().HTTPRoutes(routeNamespace).Get(context.TODO(), httpRouteName, metav1.GetOptions{})
+		if err != nil || httpRoute == nil || len(httpRoute.Status.Parents) != len(ports) {
+			return nil
+		}
+		return apimeta.FindStatusCondition(httpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+	}
+	// the namespace of the route doesn't match the selector of the listener
+	g.Eventually(func() string {
+		if condition := acceptedCondition(); condition != nil && condition.Status == metav1.ConditionFalse {
+			return condition.Reason
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonNotAllowedByListeners)))
+	g.Expect(acceptedCondition().Message).To(gomega.Equal("No listener in the Gateway allows HTTPRoute from namespace " + routeNamespace))
+
+	// the route is accepted once the namespace is labelled
+	integrationtest.UpdateNamespace(t, routeNamespace, map[string]string{"app": "foo", "gateway-access": "true"})
+	g.Eventually(func() bool {
+		condition := acceptedCondition()
+		return condition != nil && condition.Status == metav1.ConditionTrue
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	// and it is rejected again once the label is removed
+	integrationtest.UpdateNamespace(t, routeNamespace, map[string]string{"app": "foo"})
+	g.Eventually(func() string {
+		if condition := acceptedCondition(); condition != nil && condition.Status == metav1.ConditionFalse {
+			return condition.Reason
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonNotAllowedByListeners)))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, routeNamespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DeleteNamespace(routeNamespace)
+}
+
+func TestHTTPRouteWithUnsupportedTimeout(t *testing.T) {
+	gatewayClassName := "gateway-class-hr-13"
+	gatewayName := "gateway-hr-13"
+	httpRouteName := "httproute-13"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	// the timeout exceeds the maximum server timeout of the pools
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rule.Timeouts = akogatewayapitests.GetHTTPRouteTimeoutsV1("7h", "")
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, namespace, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	g.Eventually(func() bool {
+		httpRoute, err := akogatewayapitests.GatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), httpRouteName, metav1.GetOptions{})
+		if err != nil || httpRoute == nil {
+			t.Logf("Couldn't get the HTTPRoute, err: %+v", err)
+			return false
+		}
+		if len(httpRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		condition := apimeta.FindStatusCondition(httpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+		return condition != nil && condition.Status == metav1.ConditionFalse &&
+			condition.Reason == string(gatewayv1.RouteReasonUnsupportedValue)
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	// the route is accepted once the timeout is supported
+	rule.Timeouts = akogatewayapitests.GetHTTPRouteTimeoutsV1("6h", "")
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, namespace, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+	g.Eventually(func() bool {
+		httpRoute, err := akogatewayapitests.GatewayClient.GatewayV1().HTTPRoutes(namespace).Get(context.TODO(), httpRouteName, metav1.GetOptions{})
+		if err != nil || httpRoute == nil || len(httpRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(httpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, namespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
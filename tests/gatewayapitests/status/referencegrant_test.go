@@ -0,0 +1,131 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+func TestGatewayWithCrossNamespaceCertificateRef(t *testing.T) {
+	gatewayClassName := "gateway-class-rg-01"
+	gatewayName := "gateway-rg-01"
+	referenceGrantName := "referencegrant-01"
+	secretName := "secret-rg-01"
+	secretNamespace := "secret-ns-rg-01"
+	ports := []int32{8080}
+
+	integrationtest.AddSecret(secretName, secretNamespace, "cert", "key")
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetListenerTLS(&listeners[0], gatewayv1.TLSModeTerminate, secretName, secretNamespace)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil || len(gateway.Status.Listeners) != len(ports) {
+			return false
+		}
+		condition := apimeta.FindStatusCondition(gateway.Status.Listeners[0].Conditions, string(gatewayv1.ListenerConditionResolvedRefs))
+		return condition != nil && condition.Status == metav1.ConditionFalse &&
+			condition.Reason == string(gatewayv1.ListenerReasonRefNotPermitted)
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	// the listener is valid once the ReferenceGrant allows the reference to the Secret
+	akogatewayapitests.SetupReferenceGrant(t, referenceGrantName, secretNamespace, lib.Gateway, DEFAULT_NAMESPACE, utils.Secret)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownReferenceGrant(t, referenceGrantName, secretNamespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DeleteSecret(secretName, secretNamespace)
+}
+
+func TestHTTPRouteWithCrossNamespaceBackendRef(t *testing.T) {
+	gatewayClassName := "gateway-class-rg-02"
+	gatewayName := "gateway-rg-02"
+	httpRouteName := "httproute-rg-02"
+	referenceGrantName := "referencegrant-02"
+	svcName := "avisvc-rg-02"
+	svcNamespace := "svc-ns-rg-02"
+	ports := []int32{8080}
+
+	integrationtest.CreateSVC(t, svcNamespace, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, map[string][]string{},
+		[][]string{{svcName, svcNamespace, "8080", "1"}})
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	resolvedRefsReason := func() string {
+		httpRoute, err := akogatewayapitests.GatewayClient.GatewayV1().HTTPRoutes(DEFAULT_NAMESPACE).Get(context.TODO(), httpRouteName, metav1.GetOptions{})
+		if err != nil || httpRoute == nil || len(httpRoute.Status.Parents) != len(ports) {
+			return ""
+		}
+		condition := apimeta.FindStatusCondition(httpRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+		if condition == nil {
+			return ""
+		}
+		return condition.Reason
+	}
+	g.Eventually(resolvedRefsReason, 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonRefNotPermitted)))
+
+	// the backend is resolved once the ReferenceGrant allows the reference to the Service
+	akogatewayapitests.SetupReferenceGrant(t, referenceGrantName, svcNamespace, lib.HTTPRoute, DEFAULT_NAMESPACE, utils.Service)
+	g.Eventually(resolvedRefsReason, 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonResolvedRefs)))
+
+	// and it is not permitted again once the ReferenceGrant is deleted
+	akogatewayapitests.TeardownReferenceGrant(t, referenceGrantName, svcNamespace)
+	g.Eventually(resolvedRefsReason, 30*time.Second).Should(gomega.Equal(string(gatewayv1.RouteReasonRefNotPermitted)))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DelSVC(t, svcNamespace, svcName)
+}
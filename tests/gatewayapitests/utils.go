@@ -27,9 +27,12 @@ import (
 
 	"github.com/onsi/gomega"
 	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
@@ -73,6 +76,11 @@ func GetModelName(namespace, name string) (string, string) {
 	return "admin/" + vsName, vsName
 }
 
+func GetL4ModelName(namespace, name string) (string, string) {
+	vsName := akogatewayapilib.Prefix + "cluster--" + namespace + "-" + name + "-L4"
+	return "admin/" + vsName, vsName
+}
+
 func SetGatewayName(gw *gatewayv1.Gateway, name string) {
 	gw.Name = name
 }
@@ -116,6 +124,16 @@ func UnsetListenerTLS(l *gatewayv1.Listener) {
 	l.TLS = &gatewayv1.GatewayTLSConfig{}
 }
 
+func SetListenerAllowedRoutesSelector(l *gatewayv1.Listener, matchLabels map[string]string) {
+	from := gatewayv1.NamespacesFromSelector
+	l.AllowedRoutes = &gatewayv1.AllowedRoutes{
+		Namespaces: &gatewayv1.RouteNamespaces{
+			From:     &from,
+			Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+		},
+	}
+}
+
 func SetListenerHostname(l *gatewayv1.Listener, hostname string) {
 	l.Hostname = (*gatewayv1.Hostname)(&hostname)
 }
@@ -153,6 +171,38 @@ func GetListenersV1(ports []int32, secrets ...string) []gatewayv1.Listener {
 	return listeners
 }
 
+func GetPassthroughListenersV1(ports []int32) []gatewayv1.Listener {
+	listeners := make([]gatewayv1.Listener, 0, len(ports))
+	for _, port := range ports {
+		hostname := fmt.Sprintf("foo-%d.com", port)
+		tlsMode := gatewayv1.TLSModePassthrough
+		listener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("listener-%d", port)),
+			Port:     gatewayv1.PortNumber(port),
+			Protocol: gatewayv1.TLSProtocolType,
+			Hostname: (*gatewayv1.Hostname)(&hostname),
+			TLS: &gatewayv1.GatewayTLSConfig{
+				Mode: &tlsMode,
+			},
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+func GetL4ListenersV1(ports []int32, protocol gatewayv1.ProtocolType) []gatewayv1.Listener {
+	listeners := make([]gatewayv1.Listener, 0, len(ports))
+	for _, port := range ports {
+		listener := gatewayv1.Listener{
+			Name:     gatewayv1.SectionName(fmt.Sprintf("listener-%d", port)),
+			Port:     gatewayv1.PortNumber(port),
+			Protocol: protocol,
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
 func GetListenerStatusV1(ports []int32, attachedRoutes []int32) []gatewayv1.ListenerStatus {
 	listeners := make([]gatewayv1.ListenerStatus, 0, len(ports))
 	for i, port := range ports {
@@ -241,6 +291,7 @@ func TeardownGateway(t *testing.T, name, namespace string) {
 type FakeGatewayClass struct {
 	Name           string
 	ControllerName string
+	ParametersRef  *gatewayv1.ParametersReference
 }
 
 func (gc *FakeGatewayClass) GatewayClassV1() *gatewayv1.GatewayClass {
@@ -250,6 +301,7 @@ func (gc *FakeGatewayClass) GatewayClassV1() *gatewayv1.GatewayClass {
 		},
 		Spec: gatewayv1.GatewayClassSpec{
 			ControllerName: gatewayv1.GatewayController(gc.ControllerName),
+			ParametersRef:  gc.ParametersRef,
 		},
 	}
 }
@@ -411,10 +463,51 @@ func GetHTTPRouteFilterV1(filterType string, actions []string) gatewayv1.HTTPRou
 			Hostname:   (*gatewayv1.PreciseHostname)(&host),
 			StatusCode: &statusCode302,
 		}
+	case "URLRewrite":
+		routeFilter.URLRewrite = &gatewayv1.HTTPURLRewriteFilter{}
+		for _, action := range actions {
+			switch action {
+			case "hostname":
+				host := "rewrite.com"
+				routeFilter.URLRewrite.Hostname = (*gatewayv1.PreciseHostname)(&host)
+			case "prefix":
+				routeFilter.URLRewrite.Path = &gatewayv1.HTTPPathModifier{
+					Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: proto.String("/bar"),
+				}
+			case "fullpath":
+				routeFilter.URLRewrite.Path = &gatewayv1.HTTPPathModifier{
+					Type:            gatewayv1.FullPathHTTPPathModifier,
+					ReplaceFullPath: proto.String("/bar"),
+				}
+			}
+		}
+	case "RequestMirror":
+		mirrorPort := gatewayv1.PortNumber(8080)
+		routeFilter.RequestMirror = &gatewayv1.HTTPRequestMirrorFilter{
+			BackendRef: gatewayv1.BackendObjectReference{Name: "mirrorsvc", Port: &mirrorPort},
+		}
 	}
 	return routeFilter
 }
 
+// GetHTTPRouteQueryMethodMatchV1 returns a PathPrefix match on the path along with a
+// match on the query param name=value and the method, if these are not empty.
+func GetHTTPRouteQueryMethodMatchV1(path, queryParamName, queryParamValue, method string) gatewayv1.HTTPRouteMatch {
+	routeMatch := GetHTTPRouteMatchV1(path, "PathPrefix", []string{})
+	if queryParamName != "" {
+		routeMatch.QueryParams = []gatewayv1.HTTPQueryParamMatch{{
+			Type:  (*gatewayv1.QueryParamMatchType)(proto.String("Exact")),
+			Name:  gatewayv1.HTTPHeaderName(queryParamName),
+			Value: queryParamValue,
+		}}
+	}
+	if method != "" {
+		routeMatch.Method = (*gatewayv1.HTTPMethod)(proto.String(method))
+	}
+	return routeMatch
+}
+
 func GetHTTPRouteBackendV1(backendRefs []string) gatewayv1.HTTPBackendRef {
 	serviceKind := gatewayv1.Kind("Service")
 	port, _ := strconv.Atoi(backendRefs[2])
@@ -456,6 +549,20 @@ func GetHTTPRouteRuleV1(paths []string, matchHeaders []string, filterActionMap m
 	rule.BackendRefs = backends
 	return rule
 }
+
+// GetHTTPRouteTimeoutsV1 returns the timeouts of a HTTPRoute rule, an empty value leaves the timeout unset.
+func GetHTTPRouteTimeoutsV1(request, backendRequest string) *gatewayv1.HTTPRouteTimeouts {
+	timeouts := &gatewayv1.HTTPRouteTimeouts{}
+	if request != "" {
+		requestTimeout := gatewayv1.Duration(request)
+		timeouts.Request = &requestTimeout
+	}
+	if backendRequest != "" {
+		backendRequestTimeout := gatewayv1.Duration(backendRequest)
+		timeouts.BackendRequest = &backendRequestTimeout
+	}
+	return timeouts
+}
 func GetHTTPRouteRulesV1Login() []gatewayv1.HTTPRouteRule {
 	rules := make([]gatewayv1.HTTPRouteRule, 0)
 	// TODO: add few rules
@@ -533,6 +640,335 @@ func TeardownHTTPRoute(t *testing.T, name, namespace string) {
 	hr.Delete(t)
 }
 
+type GRPCRoute struct {
+	*gatewayv1alpha2.GRPCRoute
+}
+
+func (gr *GRPCRoute) GRPCRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) *gatewayv1alpha2.GRPCRoute {
+	grpcRoute := &gatewayv1alpha2.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gatewayv1alpha2.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Hostnames: hostnames,
+			Rules:     rules,
+		},
+	}
+	return grpcRoute
+}
+
+// GetGRPCRouteRuleV1alpha2 returns a rule with a method match per service/method pair,
+// an empty service or method is not set in the match.
+func GetGRPCRouteRuleV1alpha2(methods [][]string, backendRefs [][]string) gatewayv1alpha2.GRPCRouteRule {
+	rule := gatewayv1alpha2.GRPCRouteRule{}
+	for _, method := range methods {
+		methodMatch := &gatewayv1alpha2.GRPCMethodMatch{}
+		if method[0] != "" {
+			methodMatch.Service = proto.String(method[0])
+		}
+		if method[1] != "" {
+			methodMatch.Method = proto.String(method[1])
+		}
+		rule.Matches = append(rule.Matches, gatewayv1alpha2.GRPCRouteMatch{Method: methodMatch})
+	}
+	for _, backend := range GetTLSRouteRuleV1alpha2(backendRefs).BackendRefs {
+		rule.BackendRefs = append(rule.BackendRefs, gatewayv1alpha2.GRPCBackendRef{BackendRef: backend})
+	}
+	return rule
+}
+
+func (gr *GRPCRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().GRPCRoutes(gr.Namespace).Create(context.TODO(), gr.GRPCRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Created GRPCRoute %s", gr.Name)
+}
+
+func (gr *GRPCRoute) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().GRPCRoutes(gr.Namespace).Update(context.TODO(), gr.GRPCRoute, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Updated GRPCRoute %s", gr.Name)
+}
+
+func (gr *GRPCRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().GRPCRoutes(gr.Namespace).Delete(context.TODO(), gr.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Deleted GRPCRoute %s", gr.Name)
+}
+
+func SetupGRPCRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) {
+	gr := &GRPCRoute{}
+	gr.GRPCRoute = gr.GRPCRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	gr.Create(t)
+}
+
+func UpdateGRPCRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) {
+	gr := &GRPCRoute{}
+	gr.GRPCRoute = gr.GRPCRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	gr.Update(t)
+}
+
+func TeardownGRPCRoute(t *testing.T, name, namespace string) {
+	gr := &GRPCRoute{}
+	gr.GRPCRoute = gr.GRPCRouteV1alpha2(name, namespace, nil, nil, nil)
+	gr.Delete(t)
+}
+
+type TLSRoute struct {
+	*gatewayv1alpha2.TLSRoute
+}
+
+func (tr *TLSRoute) TLSRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.TLSRouteRule) *gatewayv1alpha2.TLSRoute {
+	tlsRoute := &gatewayv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Hostnames: hostnames,
+			Rules:     rules,
+		},
+	}
+	return tlsRoute
+}
+
+func GetTLSRouteRuleV1alpha2(backendRefs [][]string) gatewayv1alpha2.TLSRouteRule {
+	backends := make([]gatewayv1.BackendRef, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		backend := GetHTTPRouteBackendV1(backendRef)
+		backends = append(backends, backend.BackendRef)
+	}
+	return gatewayv1alpha2.TLSRouteRule{BackendRefs: backends}
+}
+
+func (tr *TLSRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TLSRoutes(tr.Namespace).Create(context.TODO(), tr.TLSRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Created TLSRoute %s", tr.Name)
+}
+
+func (tr *TLSRoute) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TLSRoutes(tr.Namespace).Update(context.TODO(), tr.TLSRoute, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Updated TLSRoute %s", tr.Name)
+}
+
+func (tr *TLSRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().TLSRoutes(tr.Namespace).Delete(context.TODO(), tr.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Deleted TLSRoute %s", tr.Name)
+}
+
+func SetupTLSRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.TLSRouteRule) {
+	tr := &TLSRoute{}
+	tr.TLSRoute = tr.TLSRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	tr.Create(t)
+}
+
+func UpdateTLSRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.TLSRouteRule) {
+	tr := &TLSRoute{}
+	tr.TLSRoute = tr.TLSRouteV1alpha2(name, namespace, parentRefs, hostnames, rules)
+	tr.Update(t)
+}
+
+func TeardownTLSRoute(t *testing.T, name, namespace string) {
+	tr := &TLSRoute{}
+	tr.TLSRoute = tr.TLSRouteV1alpha2(name, namespace, nil, nil, nil)
+	tr.Delete(t)
+}
+
+type TCPRoute struct {
+	*gatewayv1alpha2.TCPRoute
+}
+
+func (tr *TCPRoute) TCPRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) *gatewayv1alpha2.TCPRoute {
+	tcpRoute := &gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gatewayv1alpha2.TCPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Rules: rules,
+		},
+	}
+	return tcpRoute
+}
+
+func GetTCPRouteRuleV1alpha2(backendRefs [][]string) gatewayv1alpha2.TCPRouteRule {
+	return gatewayv1alpha2.TCPRouteRule{BackendRefs: GetTLSRouteRuleV1alpha2(backendRefs).BackendRefs}
+}
+
+func (tr *TCPRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TCPRoutes(tr.Namespace).Create(context.TODO(), tr.TCPRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Created TCPRoute %s", tr.Name)
+}
+
+func (tr *TCPRoute) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().TCPRoutes(tr.Namespace).Update(context.TODO(), tr.TCPRoute, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Updated TCPRoute %s", tr.Name)
+}
+
+func (tr *TCPRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().TCPRoutes(tr.Namespace).Delete(context.TODO(), tr.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Deleted TCPRoute %s", tr.Name)
+}
+
+func SetupTCPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) {
+	tr := &TCPRoute{}
+	tr.TCPRoute = tr.TCPRouteV1alpha2(name, namespace, parentRefs, rules)
+	tr.Create(t)
+}
+
+func UpdateTCPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.TCPRouteRule) {
+	tr := &TCPRoute{}
+	tr.TCPRoute = tr.TCPRouteV1alpha2(name, namespace, parentRefs, rules)
+	tr.Update(t)
+}
+
+func TeardownTCPRoute(t *testing.T, name, namespace string) {
+	tr := &TCPRoute{}
+	tr.TCPRoute = tr.TCPRouteV1alpha2(name, namespace, nil, nil)
+	tr.Delete(t)
+}
+
+type UDPRoute struct {
+	*gatewayv1alpha2.UDPRoute
+}
+
+func (ur *UDPRoute) UDPRouteV1alpha2(name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.UDPRouteRule) *gatewayv1alpha2.UDPRoute {
+	udpRoute := &gatewayv1alpha2.UDPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: gatewayv1alpha2.UDPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: parentRefs,
+			},
+			Rules: rules,
+		},
+	}
+	return udpRoute
+}
+
+func GetUDPRouteRuleV1alpha2(backendRefs [][]string) gatewayv1alpha2.UDPRouteRule {
+	return gatewayv1alpha2.UDPRouteRule{BackendRefs: GetTLSRouteRuleV1alpha2(backendRefs).BackendRefs}
+}
+
+func (ur *UDPRoute) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().UDPRoutes(ur.Namespace).Create(context.TODO(), ur.UDPRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the UDPRoute, err: %+v", err)
+	}
+	t.Logf("Created UDPRoute %s", ur.Name)
+}
+
+func (ur *UDPRoute) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().UDPRoutes(ur.Namespace).Delete(context.TODO(), ur.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the UDPRoute, err: %+v", err)
+	}
+	t.Logf("Deleted UDPRoute %s", ur.Name)
+}
+
+func SetupUDPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, rules []gatewayv1alpha2.UDPRouteRule) {
+	ur := &UDPRoute{}
+	ur.UDPRoute = ur.UDPRouteV1alpha2(name, namespace, parentRefs, rules)
+	ur.Create(t)
+}
+
+func TeardownUDPRoute(t *testing.T, name, namespace string) {
+	ur := &UDPRoute{}
+	ur.UDPRoute = ur.UDPRouteV1alpha2(name, namespace, nil, nil)
+	ur.Delete(t)
+}
+
+type ReferenceGrant struct {
+	*gatewayv1beta1.ReferenceGrant
+}
+
+// ReferenceGrantV1beta1 returns a ReferenceGrant which allows the references from the objects of
+// kind fromKind in the namespace fromNamespace to all the core objects of kind toKind in the namespace.
+func (rg *ReferenceGrant) ReferenceGrantV1beta1(name, namespace, fromKind, fromNamespace, toKind string) *gatewayv1beta1.ReferenceGrant {
+	referenceGrant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayv1.GroupName,
+				Kind:      gatewayv1.Kind(fromKind),
+				Namespace: gatewayv1.Namespace(fromNamespace),
+			}},
+			To: []gatewayv1beta1.ReferenceGrantTo{{
+				Kind: gatewayv1.Kind(toKind),
+			}},
+		},
+	}
+	return referenceGrant
+}
+
+func (rg *ReferenceGrant) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1beta1().ReferenceGrants(rg.Namespace).Create(context.TODO(), rg.ReferenceGrant, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the ReferenceGrant, err: %+v", err)
+	}
+	t.Logf("Created ReferenceGrant %s", rg.Name)
+}
+
+func (rg *ReferenceGrant) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1beta1().ReferenceGrants(rg.Namespace).Delete(context.TODO(), rg.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the ReferenceGrant, err: %+v", err)
+	}
+	t.Logf("Deleted ReferenceGrant %s", rg.Name)
+}
+
+func SetupReferenceGrant(t *testing.T, name, namespace, fromKind, fromNamespace, toKind string) {
+	rg := &ReferenceGrant{}
+	rg.ReferenceGrant = rg.ReferenceGrantV1beta1(name, namespace, fromKind, fromNamespace, toKind)
+	rg.Create(t)
+}
+
+func TeardownReferenceGrant(t *testing.T, name, namespace string) {
+	rg := &ReferenceGrant{}
+	rg.ReferenceGrant = rg.ReferenceGrantV1beta1(name, namespace, "", "", "")
+	rg.Delete(t)
+}
+
 func ValidateGatewayStatus(t *testing.T, actualStatus, expectedStatus *gatewayv1.GatewayStatus) {
 
 	g := gomega.NewGomegaWithT(t)
@@ -588,3 +1024,98 @@ func ValidateHTTPRouteStatus(t *testing.T, actualStatus, expectedStatus *gateway
 		ValidateConditions(t, actualRouteParentStatus.Conditions, expectedRouteParentStatus.Conditions)
 	}
 }
+
+type BackendTLSPolicy struct {
+	*gatewayv1alpha2.BackendTLSPolicy
+}
+
+// BackendTLSPolicyV1alpha2 returns a BackendTLSPolicy targeting the Service svcName, which validates
+// the servers against the CA certificates in the Secrets caSecrets.
+func (bp *BackendTLSPolicy) BackendTLSPolicyV1alpha2(name, namespace, svcName, hostname string, caSecrets ...string) *gatewayv1alpha2.BackendTLSPolicy {
+	policy := &gatewayv1alpha2.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.BackendTLSPolicySpec{
+			TargetRef: gatewayv1alpha2.PolicyTargetReferenceWithSectionName{
+				PolicyTargetReference: gatewayv1alpha2.PolicyTargetReference{
+					Group: "",
+					Kind:  "Service",
+					Name:  gatewayv1.ObjectName(svcName),
+				},
+			},
+			TLS: gatewayv1alpha2.BackendTLSPolicyConfig{
+				Hostname: gatewayv1beta1.PreciseHostname(hostname),
+			},
+		},
+	}
+	for _, caSecret := range caSecrets {
+		policy.Spec.TLS.CACertRefs = append(policy.Spec.TLS.CACertRefs, gatewayv1beta1.LocalObjectReference{
+			Group: "",
+			Kind:  "Secret",
+			Name:  gatewayv1.ObjectName(caSecret),
+		})
+	}
+	return policy
+}
+
+func (bp *BackendTLSPolicy) Create(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().BackendTLSPolicies(bp.Namespace).Create(context.TODO(), bp.BackendTLSPolicy, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the BackendTLSPolicy, err: %+v", err)
+	}
+	t.Logf("Created BackendTLSPolicy %s", bp.Name)
+}
+
+func (bp *BackendTLSPolicy) Update(t *testing.T) {
+	_, err := GatewayClient.GatewayV1alpha2().BackendTLSPolicies(bp.Namespace).Update(context.TODO(), bp.BackendTLSPolicy, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the BackendTLSPolicy, err: %+v", err)
+	}
+	t.Logf("Updated BackendTLSPolicy %s", bp.Name)
+}
+
+func (bp *BackendTLSPolicy) Delete(t *testing.T) {
+	err := GatewayClient.GatewayV1alpha2().BackendTLSPolicies(bp.Namespace).Delete(context.TODO(), bp.Name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the BackendTLSPolicy, err: %+v", err)
+	}
+	t.Logf("Deleted BackendTLSPolicy %s", bp.Name)
+}
+
+func SetupBackendTLSPolicy(t *testing.T, name, namespace, svcName, hostname string, caSecrets ...string) {
+	bp := &BackendTLSPolicy{}
+	bp.BackendTLSPolicy = bp.BackendTLSPolicyV1alpha2(name, namespace, svcName, hostname, caSecrets...)
+	bp.Create(t)
+}
+
+func TeardownBackendTLSPolicy(t *testing.T, name, namespace string) {
+	bp := &BackendTLSPolicy{}
+	bp.BackendTLSPolicy = bp.BackendTLSPolicyV1alpha2(name, namespace, "", "")
+	bp.Delete(t)
+}
+
+// AddCACertSecret creates or updates the Secret with the CA certificate under the ca.crt key.
+func AddCACertSecret(t *testing.T, name, namespace, caCert string) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Data: map[string][]byte{
+			"ca.crt": []byte(caCert),
+		},
+	}
+	if _, err := KubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+		if _, err := KubeClient.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("Couldn't update the Secret, err: %+v", err)
+		}
+		return
+	}
+	if _, err := KubeClient.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Couldn't create the Secret, err: %+v", err)
+	}
+}
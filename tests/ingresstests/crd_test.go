@@ -15,8 +15,13 @@
 package ingresstests
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"net/http"
 	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -935,6 +940,109 @@ func TestValidationsOfHostruleFQDNAliases(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHostruleSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "source-ranges-hr-foo"
+	sharedHrname := "source-ranges-hr-shared"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	integrationtest.SetupHostRule(t, hrname, "foo.com", false)
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: lib.Encode("cluster--foo.com", lib.EVHVS)}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/source-ranges-hr-foo", true)
+
+	var childNSPRefs int32
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == "PUT" || r.Method == "POST") && strings.Contains(r.URL.EscapedPath(), "/api/virtualservice") {
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if strings.Contains(string(body), sniVSKey.Name) && strings.Contains(string(body), "network_security_policy_ref") {
+				atomic.AddInt32(&childNSPRefs, 1)
+			}
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	updateHostRule := func(name, fqdn string, sourceRanges []string, resourceVersion, status string) {
+		hrUpdate := integrationtest.FakeHostRule{
+			Name:      name,
+			Namespace: "default",
+			Fqdn:      fqdn,
+		}.HostRule()
+		hrUpdate.Spec.VirtualHost.SourceRanges = sourceRanges
+		hrUpdate.ResourceVersion = resourceVersion
+		if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("error in updating HostRule: %v", err)
+		}
+		g.Eventually(func() string {
+			hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), name, metav1.GetOptions{})
+			return hostrule.Status.Status
+		}, 10*time.Second).Should(gomega.Equal(status))
+	}
+	getNSPKeys := func(key cache.NamespaceName) []cache.NamespaceName {
+		vsCache, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(key)
+		if !found {
+			return nil
+		}
+		return vsCache.(*cache.AviVsCache).NSPKeyCollection
+	}
+	getChildNSPRefs := func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		var nspRefs int
+		for _, sniNode := range aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes {
+			nspRefs += len(sniNode.NetworkSecurityPolicyRefs)
+		}
+		return nspRefs
+	}
+
+	// The network security policy is not attached to the SNI child VS.
+	updateHostRule(hrname, "foo.com", []string{"10.10.0.0/16", "192.168.1.0/24"}, "2", lib.StatusAccepted)
+	g.Consistently(func() int {
+		return getChildNSPRefs() + len(getNSPKeys(sniVSKey)) + int(atomic.LoadInt32(&childNSPRefs))
+	}, 3*time.Second).Should(gomega.Equal(0))
+
+	// An invalid CIDR rejects the HostRule.
+	updateHostRule(hrname, "foo.com", []string{"10.10.0.0/33"}, "3", lib.StatusRejected)
+
+	// The network security policy is attached to the parent VS through the HostRule of the Shared VS FQDN.
+	hrCreate := integrationtest.FakeHostRule{
+		Name:      sharedHrname,
+		Namespace: "default",
+		Fqdn:      "cluster--Shared-L7-0.admin.com",
+	}.HostRule()
+	hrCreate.Spec.VirtualHost.SourceRanges = []string{"10.10.0.0/16", "192.168.1.0/24"}
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Create(context.TODO(), hrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	integrationtest.VerifyMetadataHostRule(t, g, vsKey, "default/"+sharedHrname, true)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nspNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRefs[0]
+	g.Expect(nspNode.Name).To(gomega.Equal(vsKey.Name))
+	g.Expect(nspNode.SourceRanges).To(gomega.Equal([]string{"10.10.0.0/16", "192.168.1.0/24"}))
+	g.Eventually(func() int {
+		return len(getNSPKeys(vsKey))
+	}, 15*time.Second).Should(gomega.Equal(1))
+	g.Expect(getChildNSPRefs()).To(gomega.Equal(0))
+	g.Expect(getNSPKeys(sniVSKey)).To(gomega.BeEmpty())
+	g.Expect(atomic.LoadInt32(&childNSPRefs)).To(gomega.Equal(int32(0)))
+
+	// Removing the sourceRanges deletes the network security policy of the parent VS.
+	updateHostRule(sharedHrname, "cluster--Shared-L7-0.admin.com", nil, "2", lib.StatusAccepted)
+	g.Eventually(func() int {
+		return len(getNSPKeys(vsKey))
+	}, 15*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.TeardownHostRule(t, g, vsKey, sharedHrname)
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestHostruleFQDNAliasesForMultiPathIngress(t *testing.T) {
 
 	g := gomega.NewGomegaWithT(t)
@@ -1247,6 +1355,7 @@ func TestHTTPRuleCreateDelete(t *testing.T) {
 	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].HealthMonitorRefs).To(gomega.HaveLen(2))
 	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].HealthMonitorRefs[0]).To(gomega.ContainSubstring("thisisaviref-hm2"))
 	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].HealthMonitorRefs[1]).To(gomega.ContainSubstring("thisisaviref-hm1"))
+	g.Expect(*nodes[0].SniNodes[0].PoolRefs[0].GracefulDisableTimeout).To(gomega.Equal(int32(10)))
 
 	// delete httprule deletes refs as well
 	integrationtest.TeardownHTTPRule(t, rrname)
@@ -1258,6 +1367,7 @@ func TestHTTPRuleCreateDelete(t *testing.T) {
 	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].SslProfileRef).To(gomega.BeNil())
 	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].PkiProfile).To(gomega.BeNil())
 	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].HealthMonitorRefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].SniNodes[0].PoolRefs[0].GracefulDisableTimeout).To(gomega.BeNil())
 
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -39,7 +40,9 @@ import (
 
 	"github.com/onsi/gomega"
 	"github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
@@ -2782,3 +2785,222 @@ func TestPortsForInsecureAndSecureSNI(t *testing.T) {
 
 	TearDownTestForIngress(t, modelName)
 }
+
+func getDefaultBackend(serviceName string) *networking.IngressBackend {
+	return &networking.IngressBackend{
+		Service: &networking.IngressServiceBackend{
+			Name: serviceName,
+			Port: networking.ServiceBackendPort{Number: 8080},
+		},
+	}
+}
+
+func TestIngressWithDefaultBackend(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "ingress-default-backend",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	ingrFake.Spec.DefaultBackend = getDefaultBackend("avisvc")
+
+	_, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	// the defaultBackend serves the requests of the host which don't match any path
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(2))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	for _, pool := range nodes[0].PoolRefs {
+		if pool.Name == "cluster--foo.com_foo-default-ingress-default-backend" {
+			g.Expect(pool.PriorityLabel).To(gomega.Equal("foo.com/foo"))
+		} else if pool.Name == "cluster--foo.com_-default-ingress-default-backend" {
+			g.Expect(pool.PriorityLabel).To(gomega.Equal("foo.com/"))
+		} else {
+			t.Fatalf("unexpected pool: %s", pool.Name)
+		}
+		g.Expect(pool.Servers).To(gomega.HaveLen(1))
+	}
+	g.Expect(nodes[0].PoolGroupRefs[0].Members).To(gomega.HaveLen(2))
+
+	// an explicit catch-all path of the host replaces the defaultBackend
+	ingrFake.Spec.Rules[0].HTTP.Paths[0].Path = "/"
+	ingrFake.ResourceVersion = "2"
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingrFake, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	g.Eventually(func() []*avinodes.AviPoolNode {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs
+	}, 10*time.Second).Should(gomega.HaveLen(1))
+
+	err = KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "ingress-default-backend", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	VerifyIngressDeletion(t, g, aviModel, 0)
+
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestIngressWithoutHostsWithDefaultBackend(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	var modelNames []string
+	for i := 0; i < 8; i++ {
+		modelNames = append(modelNames, "admin/cluster--Shared-L7-"+strconv.Itoa(i))
+	}
+	SetUpTestForIngress(t, modelNames...)
+	integrationtest.CreateSVC(t, "default", "avisvc-db", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc-db", false, false, "2.2.2")
+
+	createIngress := func(name, serviceName string, age time.Duration) {
+		ingress := &networking.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec: networking.IngressSpec{
+				DefaultBackend: getDefaultBackend(serviceName),
+			},
+		}
+		if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingress, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding Ingress: %v", err)
+		}
+	}
+	// verifyDefaultBackend checks that the defaultBackend of the ingress is the default pool group of all the shared VSes
+	verifyDefaultBackend := func(ingressName string) {
+		for _, modelName := range modelNames {
+			vsName := strings.TrimPrefix(modelName, "admin/")
+			g.Eventually(func() string {
+				found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+				if !found || aviModel == nil {
+					return ""
+				}
+				nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+				if len(nodes) != 1 || nodes[0].DefaultPoolGroup == "" {
+					return ""
+				}
+				for _, pool := range nodes[0].PoolRefs {
+					if pool.Name == lib.GetL7DefaultBackendPoolName(vsName) && len(pool.Servers) == 1 {
+						return nodes[0].DefaultPoolGroup + "/" + pool.IngressName
+					}
+				}
+				return ""
+			}, 10*time.Second).Should(gomega.Equal(lib.GetL7DefaultBackendPGName(vsName) + "/" + ingressName))
+		}
+	}
+
+	createIngress("ingress-db-1", "avisvc", time.Hour)
+	verifyDefaultBackend("ingress-db-1")
+	_, aviModel := objects.SharedAviGraphLister().Get(modelNames[0])
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	// the default pool is not a member of the shared pool group selected by the priority labels
+	g.Expect(nodes[0].PoolGroupRefs).To(gomega.HaveLen(2))
+	g.Expect(nodes[0].PoolGroupRefs[0].Members).To(gomega.BeEmpty())
+
+	// the oldest ingress wins
+	createIngress("ingress-db-2", "avisvc-db", 2*time.Hour)
+	verifyDefaultBackend("ingress-db-2")
+	createIngress("ingress-db-3", "avisvc-db", time.Minute)
+	verifyDefaultBackend("ingress-db-2")
+
+	err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "ingress-db-2", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	verifyDefaultBackend("ingress-db-1")
+
+	for _, ingressName := range []string{"ingress-db-1", "ingress-db-3"} {
+		err = KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), ingressName, metav1.DeleteOptions{})
+		if err != nil {
+			t.Fatalf("Couldn't DELETE the Ingress %v", err)
+		}
+	}
+	for _, modelName := range modelNames {
+		g.Eventually(func() bool {
+			_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			return nodes[0].DefaultPoolGroup == "" && len(nodes[0].PoolRefs) == 0 && len(nodes[0].PoolGroupRefs) == 1
+		}, 10*time.Second).Should(gomega.Equal(true))
+	}
+
+	integrationtest.DelSVC(t, "default", "avisvc-db")
+	integrationtest.DelEP(t, "default", "avisvc-db")
+	TearDownTestForIngress(t, modelNames...)
+}
+
+func TestIngressWithServiceAppProtocol(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+	svcExample := integrationtest.ConstructService("default", "avisvc", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false, make(map[string]string))
+	svcExample.Spec.Ports[0].AppProtocol = proto.String("kubernetes.io/h2c")
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services("default").Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "ingress-app-protocol",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	// kubernetes.io/h2c enables HTTP/2 towards the servers
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return false
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs) == 1 && nodes[0].PoolRefs[0].EnableHttp2
+	}, 10*time.Second).Should(gomega.BeTrue())
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	g.Expect(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0].SslProfileRef).To(gomega.BeNil())
+
+	// https re-encrypts the traffic towards the servers
+	svcExample.Spec.Ports[0].AppProtocol = proto.String("https")
+	svcExample.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services("default").Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() *string {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0].SslProfileRef
+	}, 10*time.Second).Should(gomega.Equal(proto.String("/api/sslprofile?name=System-Standard")))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	pool := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0]
+	g.Expect(pool.SniEnabled).To(gomega.BeTrue())
+	g.Expect(pool.EnableHttp2).To(gomega.BeFalse())
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "ingress-app-protocol", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	VerifyIngressDeletion(t, g, aviModel, 0)
+
+	TearDownTestForIngress(t, modelName)
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -27,6 +29,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
 	"github.com/onsi/gomega"
@@ -1016,3 +1020,58 @@ func TestProfilesAttachedToVS(t *testing.T) {
 
 	integrationtest.ResetMiddleware()
 }
+
+func TestDryRunPlanForSecureIngress(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	tlsKey, tlsCert := "dry-run-plan-private-key", "dry-run-plan-certificate"
+
+	os.Setenv("DRY_RUN", "true")
+	models.DryRunPlan.InitModel()
+	SetupDomain()
+	SetUpTestForIngress(t, modelName)
+	integrationtest.AddSecret("my-secret", "default", tlsCert, tlsKey)
+	ingrFake := (integrationtest.FakeIngress{
+		Name:         "foo-with-targets",
+		Namespace:    "default",
+		DnsNames:     []string{"foo.com"},
+		Ips:          []string{"8.8.8.8"},
+		HostNames:    []string{"v1"},
+		Paths:        []string{"/foo"},
+		ServiceName:  "avisvc",
+		TlsSecretDNS: map[string][]string{"my-secret": {"foo.com"}},
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+
+	var sslKeyCertOp *models.PlannedRestOp
+	g.Eventually(func() bool {
+		for _, restOp := range models.DryRunPlan.GetModelPlan(modelName) {
+			if restOp.ObjectType == "SSLKeyAndCertificate" {
+				sslKeyCertOp = &restOp
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Expect(sslKeyCertOp.Fields).To(gomega.ContainElement("certificate"))
+	g.Expect(sslKeyCertOp.Fields).NotTo(gomega.ContainElements("key", "key_passphrase"))
+	g.Expect(sslKeyCertOp.Checksum).NotTo(gomega.BeZero())
+
+	akoApi := &api.ApiServer{
+		Models: []models.ApiModel{models.DryRunPlan},
+	}
+	recorder := httptest.NewRecorder()
+	akoApi.SetRouter(false, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/plan", nil))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	body := recorder.Body.String()
+	g.Expect(body).To(gomega.ContainSubstring("SSLKeyAndCertificate"))
+	g.Expect(body).NotTo(gomega.ContainSubstring(tlsKey))
+	g.Expect(body).NotTo(gomega.ContainSubstring(tlsCert))
+	g.Expect(body).NotTo(gomega.ContainSubstring(`"key"`))
+
+	os.Unsetenv("DRY_RUN")
+	models.DryRunPlan.ResetModelPlan(modelName)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+)
+
+func setUpCacheSnapshotEnv(t *testing.T) {
+	os.Setenv("CACHE_SNAPSHOT_ENABLED", "true")
+	os.Setenv("USE_PVC", "true")
+	os.Setenv("LOG_FILE_PATH", t.TempDir())
+	t.Cleanup(func() {
+		os.Unsetenv("CACHE_SNAPSHOT_ENABLED")
+		os.Unsetenv("USE_PVC")
+		os.Unsetenv("LOG_FILE_PATH")
+	})
+}
+
+func snapshotKey(name string) cache.NamespaceName {
+	return cache.NamespaceName{Namespace: lib.GetTenant(), Name: name}
+}
+
+// addSnapshotObjects adds two pools, two poolgroups and three virtualservices to the given cache, all last modified
+// at "100".
+func addSnapshotObjects(c *cache.AviObjCache) {
+	for _, i := range []string{"1", "2", "3"} {
+		c.PoolCache.AviCacheAdd(snapshotKey("cluster--snap-pool-"+i), &cache.AviPoolCache{
+			Name: "cluster--snap-pool-" + i, Uuid: "pool-snap-" + i, CloudConfigCksum: "1", LastModified: "100"})
+		c.VsCacheMeta.AviCacheAdd(snapshotKey("cluster--snap-vs-"+i), &cache.AviVsCache{
+			Name: "cluster--snap-vs-" + i, Uuid: "virtualservice-snap-" + i, CloudConfigCksum: "1", LastModified: "100",
+			PGKeyCollection:   []cache.NamespaceName{snapshotKey("cluster--snap-pg-" + i)},
+			PoolKeyCollection: []cache.NamespaceName{snapshotKey("cluster--snap-pool-" + i)},
+		})
+	}
+	for _, i := range []string{"1", "2"} {
+		c.PgCache.AviCacheAdd(snapshotKey("cluster--snap-pg-"+i), &cache.AviPGCache{
+			Name: "cluster--snap-pg-" + i, Uuid: "poolgroup-snap-" + i, CloudConfigCksum: "1", LastModified: "100",
+			Members: []string{"cluster--snap-pool-" + i}})
+	}
+}
+
+// setUpSnapshotController makes the Avi controller list the objects of the snapshot, with pool-1 modified without
+// any change to its references, pg-2 modified to refer to another pool, and pool-3 and vs-3 deleted. It returns the
+// names filtered by the GETs of the modified objects, per object type.
+func setUpSnapshotController() (map[string][]string, *sync.Mutex) {
+	var lock sync.Mutex
+	fetched := make(map[string][]string)
+	versions := map[string][]map[string]interface{}{
+		"pool": {
+			{"name": "cluster--snap-pool-1", "uuid": "pool-snap-1", "_last_modified": "200"},
+			{"name": "cluster--snap-pool-2", "uuid": "pool-snap-2", "_last_modified": "100"},
+		},
+		"poolgroup": {
+			{"name": "cluster--snap-pg-1", "uuid": "poolgroup-snap-1", "_last_modified": "100"},
+			{"name": "cluster--snap-pg-2", "uuid": "poolgroup-snap-2", "_last_modified": "200"},
+		},
+		"virtualservice": {
+			{"name": "cluster--snap-vs-1", "uuid": "virtualservice-snap-1", "_last_modified": "100"},
+			{"name": "cluster--snap-vs-2", "uuid": "virtualservice-snap-2", "_last_modified": "100"},
+		},
+	}
+	objs := map[string]map[string]interface{}{
+		"cluster--snap-pool-1": {"name": "cluster--snap-pool-1", "uuid": "pool-snap-1", "cloud_config_cksum": "2",
+			"service_metadata": "{}", "_last_modified": "200"},
+		"cluster--snap-pg-2": {"name": "cluster--snap-pg-2", "uuid": "poolgroup-snap-2", "cloud_config_cksum": "2", "_last_modified": "200",
+			"members": []map[string]interface{}{{"pool_ref": "https://localhost/api/pool/pool-snap-1#cluster--snap-pool-1"}}},
+		"cluster--snap-vs-2": {"name": "cluster--snap-vs-2", "uuid": "virtualservice-snap-2", "cloud_config_cksum": "1", "_last_modified": "100",
+			"service_pool_select": []map[string]interface{}{{"service_pool_group_ref": "https://localhost/api/poolgroup/poolgroup-snap-2#cluster--snap-pg-2"}}},
+	}
+
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		objType := strings.Trim(strings.TrimPrefix(strings.TrimLeft(url, "/"), "api/"), "/")
+		if r.Method == "GET" && strings.Contains(r.URL.RawQuery, "fields=name,uuid,_last_modified") {
+			results := versions[objType]
+			collection, _ := json.Marshal(map[string]interface{}{"count": len(results), "results": results})
+			w.WriteHeader(http.StatusOK)
+			w.Write(collection)
+			return
+		}
+		if r.Method == "GET" && r.URL.Query().Get("name.in") != "" {
+			results := []map[string]interface{}{}
+			names := strings.Split(r.URL.Query().Get("name.in"), ",")
+			for _, name := range names {
+				if obj, found := objs[name]; found {
+					results = append(results, obj)
+				}
+			}
+			lock.Lock()
+			fetched[objType] = append(fetched[objType], names...)
+			lock.Unlock()
+			collection, _ := json.Marshal(map[string]interface{}{"count": len(results), "results": results})
+			w.WriteHeader(http.StatusOK)
+			w.Write(collection)
+			return
+		}
+		NormalControllerServer(w, r)
+	})
+	return fetched, &lock
+}
+
+func TestCacheSnapshotWarmStart(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-cache-snapshot-01"
+	setUpSyncedService(t, g, svcName)
+	defer tearDownSvcWithLBClass(t, g, svcName)
+	setUpCacheSnapshotEnv(t)
+	defer ResetMiddleware()
+	controllerVersion := lib.AKOControlConfig().ControllerVersion()
+
+	snapshotCache := cache.NewAviObjCache()
+	addSnapshotObjects(snapshotCache)
+	g.Expect(snapshotCache.SaveCacheSnapshot(controllerVersion, utils.CloudName)).To(gomega.Succeed())
+	snapshotInfo, err := os.Stat(lib.GetCacheSnapshotFilePath())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(snapshotInfo.Mode().Perm()).To(gomega.Equal(os.FileMode(0600)))
+
+	fetched, lock := setUpSnapshotController()
+	snapshot, err := cache.LoadCacheSnapshot(controllerVersion, utils.CloudName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	warmCache := cache.NewAviObjCache()
+	g.Expect(warmCache.AviObjCacheWarmStart(cache.SharedAVIClients().AviClient, utils.CloudName, snapshot)).To(gomega.Succeed())
+
+	// Only the modified objects, and the virtualservice referring to the poolgroup whose members changed, are fetched.
+	lock.Lock()
+	g.Expect(fetched["pool"]).To(gomega.ConsistOf("cluster--snap-pool-1"))
+	g.Expect(fetched["poolgroup"]).To(gomega.ConsistOf("cluster--snap-pg-2"))
+	g.Expect(fetched["virtualservice"]).To(gomega.ConsistOf("cluster--snap-vs-2"))
+	lock.Unlock()
+
+	poolIntf, found := warmCache.PoolCache.AviCacheGet(snapshotKey("cluster--snap-pool-1"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(poolIntf.(*cache.AviPoolCache).LastModified).To(gomega.Equal("200"))
+	g.Expect(poolIntf.(*cache.AviPoolCache).CloudConfigCksum).To(gomega.Equal("2"))
+	poolIntf, found = warmCache.PoolCache.AviCacheGet(snapshotKey("cluster--snap-pool-2"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(poolIntf.(*cache.AviPoolCache).LastModified).To(gomega.Equal("100"))
+	_, found = warmCache.PoolCache.AviCacheGet(snapshotKey("cluster--snap-pool-3"))
+	g.Expect(found).To(gomega.BeFalse())
+
+	pgIntf, found := warmCache.PgCache.AviCacheGet(snapshotKey("cluster--snap-pg-2"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(pgIntf.(*cache.AviPGCache).Members).To(gomega.ConsistOf("cluster--snap-pool-1"))
+
+	vsIntf, found := warmCache.VsCacheMeta.AviCacheGet(snapshotKey("cluster--snap-vs-1"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(vsIntf.(*cache.AviVsCache).Uuid).To(gomega.Equal("virtualservice-snap-1"))
+	g.Expect(vsIntf.(*cache.AviVsCache).PoolKeyCollection).To(gomega.ConsistOf(snapshotKey("cluster--snap-pool-1")))
+	vsIntf, found = warmCache.VsCacheMeta.AviCacheGet(snapshotKey("cluster--snap-vs-2"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(vsIntf.(*cache.AviVsCache).PGKeyCollection).To(gomega.ConsistOf(snapshotKey("cluster--snap-pg-2")))
+	g.Expect(vsIntf.(*cache.AviVsCache).PoolKeyCollection).To(gomega.ConsistOf(snapshotKey("cluster--snap-pool-1")))
+	_, found = warmCache.VsCacheMeta.AviCacheGet(snapshotKey("cluster--snap-vs-3"))
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestCacheSnapshotMismatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-cache-snapshot-02"
+	setUpSyncedService(t, g, svcName)
+	defer tearDownSvcWithLBClass(t, g, svcName)
+	setUpCacheSnapshotEnv(t)
+	controllerVersion := lib.AKOControlConfig().ControllerVersion()
+
+	_, err := cache.LoadCacheSnapshot(controllerVersion, utils.CloudName)
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	snapshotCache := cache.NewAviObjCache()
+	addSnapshotObjects(snapshotCache)
+	g.Expect(snapshotCache.SaveCacheSnapshot(controllerVersion, utils.CloudName)).To(gomega.Succeed())
+
+	// The snapshot is not used for another controller version or cloud.
+	_, err = cache.LoadCacheSnapshot(controllerVersion+"-other", utils.CloudName)
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = cache.LoadCacheSnapshot(controllerVersion, utils.CloudName+"-other")
+	g.Expect(err).To(gomega.HaveOccurred())
+	snapshot, err := cache.LoadCacheSnapshot(controllerVersion, utils.CloudName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(snapshot.VirtualServices).To(gomega.HaveLen(3))
+	g.Expect(snapshot.Pools).To(gomega.HaveLen(3))
+	g.Expect(snapshot.PoolGroups).To(gomega.HaveLen(2))
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getCachedObjectVersions returns the collection of the objects of the given type present in the cache,
+// with the last modification time of the drifted object changed.
+func getCachedObjectVersions(objType, driftedUuid string) []byte {
+	mcache := cache.SharedAviObjCache()
+	objCache := map[string]*cache.AviCache{
+		"virtualservice": mcache.VsCacheMeta,
+		"pool":           mcache.PoolCache,
+		"poolgroup":      mcache.PgCache,
+		"httppolicyset":  mcache.HTTPPolicyCache,
+	}[objType]
+	var results []map[string]string
+	for _, objIntf := range objCache.ShallowCopy() {
+		var name, uuid, cksum, lastModified string
+		switch obj := objIntf.(type) {
+		case *cache.AviVsCache:
+			name, uuid, cksum, lastModified = obj.Name, obj.Uuid, obj.CloudConfigCksum, obj.LastModified
+		case *cache.AviPoolCache:
+			name, uuid, cksum, lastModified = obj.Name, obj.Uuid, obj.CloudConfigCksum, obj.LastModified
+		case *cache.AviPGCache:
+			name, uuid, cksum, lastModified = obj.Name, obj.Uuid, obj.CloudConfigCksum, obj.LastModified
+		case *cache.AviHTTPPolicyCache:
+			name, uuid, cksum, lastModified = obj.Name, obj.Uuid, obj.CloudConfigCksum, obj.LastModified
+		}
+		if uuid != "" && uuid == driftedUuid {
+			lastModified = "1700000000000002"
+		}
+		results = append(results, map[string]string{"name": name, "uuid": uuid, "cloud_config_cksum": cksum, "_last_modified": lastModified})
+	}
+	collection, _ := json.Marshal(map[string]interface{}{"count": len(results), "results": results})
+	return collection
+}
+
+// setUpDriftedPool makes the Avi controller report the pool of the Service as modified out of band, until
+// the pool is updated by AKO, and returns the number of updates of the pool received by the Avi controller.
+func setUpDriftedPool(t *testing.T, g *gomega.GomegaWithT, svcName string, annotations map[string]string) (cache.NamespaceName, *int32) {
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	mcache := cache.SharedAviObjCache()
+
+	objects.SharedAviGraphLister().Delete(fmt.Sprintf("%s/%s", AVINAMESPACE, vsKey.Name))
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Annotations = annotations
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	var poolKey cache.NamespaceName
+	g.Eventually(func() int {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found || vsCache.(*cache.AviVsCache).Uuid == "" {
+			return 0
+		}
+		poolKeys := vsCache.(*cache.AviVsCache).PoolKeyCollection
+		if len(poolKeys) == 1 {
+			poolKey = poolKeys[0]
+		}
+		return len(poolKeys)
+	}, 15*time.Second).Should(gomega.Equal(1))
+	poolCache, found := mcache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(found).To(gomega.BeTrue())
+	poolCacheObj := poolCache.(*cache.AviPoolCache)
+	poolUuid := poolCacheObj.Uuid
+	// The mock controller does not return the last modification time of the pool, which marks it invalid.
+	poolCacheObj.LastModified = "1700000000000001"
+	poolCacheObj.InvalidData = false
+
+	var poolUpdates int32
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Contains(r.URL.RawQuery, "_last_modified") {
+			w.WriteHeader(http.StatusOK)
+			driftedUuid := poolUuid
+			if atomic.LoadInt32(&poolUpdates) > 0 {
+				driftedUuid = ""
+			}
+			w.Write(getCachedObjectVersions(strings.Split(strings.Trim(url, "/"), "/")[1], driftedUuid))
+			return
+		}
+		if r.Method == "PUT" && strings.Contains(url, "/api/pool/"+poolUuid) {
+			atomic.AddInt32(&poolUpdates, 1)
+			// The pool updated by AKO has a last modification time, unlike the pools of the mock controller.
+			recorder := httptest.NewRecorder()
+			NormalControllerServer(recorder, r)
+			var resp map[string]interface{}
+			json.Unmarshal(recorder.Body.Bytes(), &resp)
+			resp["_last_modified"] = "1700000000000003"
+			finalResponse, _ := json.Marshal(resp)
+			w.WriteHeader(recorder.Code)
+			w.Write(finalResponse)
+			return
+		}
+		NormalControllerServer(w, r)
+	})
+	return poolKey, &poolUpdates
+}
+
+func TestDriftDetectionRevertPool(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("DRIFT_DETECTION_POLICY", lib.DriftPolicyRevert)
+	defer os.Unsetenv("DRIFT_DETECTION_POLICY")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	defer ResetMiddleware()
+
+	svcName := "testsvc-drift-01"
+	poolKey, poolUpdates := setUpDriftedPool(t, g, svcName, nil)
+	poolCache, _ := cache.SharedAviObjCache().PoolCache.AviCacheGet(poolKey)
+	poolCksum := poolCache.(*cache.AviPoolCache).CloudConfigCksum
+
+	// The drifted pool is updated with the configuration of AKO.
+	ctrl.FullSync()
+	g.Eventually(func() int32 {
+		return atomic.LoadInt32(poolUpdates)
+	}, 15*time.Second).Should(gomega.BeNumerically(">=", 1))
+	g.Eventually(func() string {
+		poolCache, _ := cache.SharedAviObjCache().PoolCache.AviCacheGet(poolKey)
+		return poolCache.(*cache.AviPoolCache).LastModified
+	}, 15*time.Second).Should(gomega.Equal("1700000000000003"))
+	poolCache, _ = cache.SharedAviObjCache().PoolCache.AviCacheGet(poolKey)
+	g.Expect(poolCache.(*cache.AviPoolCache).CloudConfigCksum).To(gomega.Equal(poolCksum))
+
+	// The reverted pool is not updated again.
+	g.Expect(cache.SharedDriftDetector().DetectDrift(cache.SharedAVIClients().AviClient[0])).To(gomega.BeEmpty())
+
+	ResetMiddleware()
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestDriftDetectionReportAnnotation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("DRIFT_DETECTION_POLICY", lib.DriftPolicyRevert)
+	defer os.Unsetenv("DRIFT_DETECTION_POLICY")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	defer ResetMiddleware()
+
+	// The report policy of the Service overrides the default revert policy.
+	svcName := "testsvc-drift-02"
+	poolKey, poolUpdates := setUpDriftedPool(t, g, svcName, map[string]string{lib.DriftPolicyAnnotation: lib.DriftPolicyReport})
+	poolCache, _ := cache.SharedAviObjCache().PoolCache.AviCacheGet(poolKey)
+	poolCksum := poolCache.(*cache.AviPoolCache).CloudConfigCksum
+
+	g.Expect(cache.SharedDriftDetector().DetectDrift(cache.SharedAVIClients().AviClient[0])).To(gomega.BeEmpty())
+	g.Expect(poolCache.(*cache.AviPoolCache).CloudConfigCksum).To(gomega.Equal(poolCksum))
+	ctrl.FullSync()
+	g.Consistently(func() int32 {
+		return atomic.LoadInt32(poolUpdates)
+	}, 3*time.Second).Should(gomega.Equal(int32(0)))
+
+	ResetMiddleware()
+	tearDownSvcWithLBClass(t, g, svcName)
+}
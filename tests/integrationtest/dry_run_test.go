@@ -0,0 +1,109 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getPlannedRestOps returns the method and the object type of the rest operations planned for the model.
+func getPlannedRestOps(modelName string) []string {
+	var restOps []string
+	for _, restOp := range models.DryRunPlan.GetModelPlan(modelName) {
+		restOps = append(restOps, fmt.Sprintf("%s %s", restOp.Method, restOp.ObjectType))
+	}
+	return restOps
+}
+
+// TestDryRunPlanWithIntrospectionDisabled verifies that the plan is computed in dry-run mode, without the
+// introspection APIs and without the plan being registered with the API server.
+func TestDryRunPlanWithIntrospectionDisabled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-dryrun-nointrospection"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+
+	os.Setenv("DRY_RUN", "true")
+	os.Setenv("INTROSPECTION_ENABLED", "false")
+	defer os.Unsetenv("INTROSPECTION_ENABLED")
+	objects.SharedAviGraphLister().Delete(modelName)
+	CreateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	g.Eventually(func() []string {
+		return getPlannedRestOps(modelName)
+	}, 10*time.Second).Should(gomega.ContainElements("POST VsVip", "POST Pool", "POST VirtualService"))
+	_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeFalse())
+
+	// the plan of the model is removed along with the model
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	g.Eventually(func() []string {
+		return getPlannedRestOps(modelName)
+	}, 10*time.Second).Should(gomega.BeEmpty())
+	os.Unsetenv("DRY_RUN")
+	objects.SharedAviGraphLister().Delete(modelName)
+}
+
+func TestDryRunForSvcLB(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-dryrun"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	mcache := cache.SharedAviObjCache()
+
+	os.Setenv("DRY_RUN", "true")
+	objects.SharedAviGraphLister().Delete(modelName)
+	CreateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	// the rest operations are planned, but not applied
+	g.Eventually(func() []string {
+		return getPlannedRestOps(modelName)
+	}, 10*time.Second).Should(gomega.ContainElements("POST VsVip", "POST Pool", "POST VirtualService"))
+	g.Consistently(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 5*time.Second).Should(gomega.Equal(false))
+
+	// the rest operations are applied once the dry-run mode is disabled
+	os.Unsetenv("DRY_RUN")
+	DelEP(t, NAMESPACE, svcName)
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.2")
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	g.Expect(getPlannedRestOps(modelName)).To(gomega.BeEmpty())
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
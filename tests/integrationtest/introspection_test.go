@@ -0,0 +1,138 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+
+	"github.com/onsi/gomega"
+)
+
+// getIntrospectionAPI sends the GET request to the introspection APIs and decodes the response.
+func getIntrospectionAPI(t *testing.T, route string, response interface{}) int {
+	akoApi := &api.ApiServer{
+		Models: []models.ApiModel{
+			&models.GraphModel{ListModels: nodes.ListModelViews, GetModels: nodes.GetModelViews},
+			&models.CacheModel{GetCachedVS: cache.GetCachedVSViews},
+		},
+	}
+	router := akoApi.SetRouter(false, nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, route, nil))
+	if recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatalf("error in decoding the response of %s: %v", route, err)
+		}
+	}
+	return recorder.Code
+}
+
+func TestIntrospectionAPIForSvcLB(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	SetUpTestForSvcLB(t)
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName})
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	var summaries []models.ModelSummary
+	g.Expect(getIntrospectionAPI(t, "/api/models", &summaries)).To(gomega.Equal(http.StatusOK))
+	var modelNames []string
+	for _, summary := range summaries {
+		modelNames = append(modelNames, summary.Name)
+	}
+	g.Expect(modelNames).To(gomega.ContainElement(SINGLEPORTMODEL))
+
+	// the model can be fetched both with and without the tenant
+	var graphs []models.ModelGraph
+	g.Expect(getIntrospectionAPI(t, "/api/models/"+vsName, &graphs)).To(gomega.Equal(http.StatusOK))
+	g.Expect(graphs).To(gomega.HaveLen(1))
+	g.Expect(graphs[0].Name).To(gomega.Equal(SINGLEPORTMODEL))
+	g.Expect(graphs[0].Nodes).To(gomega.HaveLen(1))
+	g.Expect(graphs[0].Nodes[0].NodeType).To(gomega.Equal("VirtualServiceNode"))
+	g.Expect(graphs[0].Nodes[0].Name).To(gomega.Equal(vsName))
+	g.Expect(graphs[0].Nodes[0].VirtualService).NotTo(gomega.BeNil())
+	g.Expect(graphs[0].Nodes[0].VirtualService.Pools).To(gomega.HaveLen(1))
+	g.Expect(graphs[0].Nodes[0].VirtualService.VSVIPs).To(gomega.HaveLen(1))
+
+	var cachedVS []models.CachedVS
+	g.Expect(getIntrospectionAPI(t, "/api/cache/vs/"+vsName, &cachedVS)).To(gomega.Equal(http.StatusOK))
+	g.Expect(cachedVS).To(gomega.HaveLen(1))
+	g.Expect(cachedVS[0].Tenant).To(gomega.Equal(AVINAMESPACE))
+	g.Expect(cachedVS[0].Uuid).NotTo(gomega.BeEmpty())
+	g.Expect(cachedVS[0].Objects["pools"]).To(gomega.HaveLen(1))
+	g.Expect(cachedVS[0].Objects["pools"][0].Found).To(gomega.BeTrue())
+	g.Expect(cachedVS[0].Objects["pools"][0].Uuid).NotTo(gomega.BeEmpty())
+	g.Expect(cachedVS[0].Objects["vsvips"]).To(gomega.HaveLen(1))
+
+	g.Expect(getIntrospectionAPI(t, "/api/models/cluster--unknown", &graphs)).To(gomega.Equal(http.StatusNotFound))
+	g.Expect(getIntrospectionAPI(t, "/api/cache/vs/cluster--unknown", &cachedVS)).To(gomega.Equal(http.StatusNotFound))
+
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestIntrospectionAPIOmitsSecrets(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--introspection-secure"
+	key, cert, caCert := "introspection-test-private-key", "introspection-test-certificate", "introspection-test-ca-certificate"
+
+	aviModel := nodes.NewAviObjectGraph()
+	vsNode := &nodes.AviVsNode{
+		Name:   "cluster--introspection-secure",
+		Tenant: "admin",
+		SSLKeyCertRefs: []*nodes.AviTLSKeyCertNode{{
+			Name:   "cluster--introspection-secure-cert",
+			Tenant: "admin",
+			Key:    []byte(key),
+			Cert:   []byte(cert),
+			CACert: caCert,
+		}},
+		PoolRefs: []*nodes.AviPoolNode{{
+			Name:       "cluster--introspection-secure-pool",
+			Tenant:     "admin",
+			PkiProfile: &nodes.AviPkiProfileNode{Name: "cluster--introspection-secure-pki", Tenant: "admin", CACert: caCert},
+		}},
+	}
+	aviModel.AddModelNode(vsNode)
+	objects.SharedAviGraphLister().Save(modelName, aviModel)
+	defer objects.SharedAviGraphLister().Delete(modelName)
+
+	akoApi := &api.ApiServer{
+		Models: []models.ApiModel{&models.GraphModel{ListModels: nodes.ListModelViews, GetModels: nodes.GetModelViews}},
+	}
+	recorder := httptest.NewRecorder()
+	akoApi.SetRouter(false, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/models/cluster--introspection-secure", nil))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	body := recorder.Body.String()
+	g.Expect(body).To(gomega.ContainSubstring("cluster--introspection-secure-cert"))
+	g.Expect(body).To(gomega.ContainSubstring("cluster--introspection-secure-pki"))
+	for _, secret := range []string{key, cert, caCert} {
+		g.Expect(strings.Contains(body, secret)).To(gomega.BeFalse(), "response contains %s", secret)
+	}
+}
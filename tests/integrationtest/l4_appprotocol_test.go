@@ -0,0 +1,176 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setUpLicense sets the license of the mock controller, which selects the network profile of the L4 virtualservices.
+func setUpLicense(license string) {
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.EscapedPath(), "/api/systemconfiguration") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"default_license_tier": "` + license + `"}`))
+			return
+		}
+		NormalControllerServer(w, r)
+	})
+	lib.AKOControlConfig().SetLicenseType(cache.SharedAVIClients().AviClient[0])
+}
+
+// getL4VsNode returns the virtualservice of the L4 model, once its pool has the given number of servers.
+func getL4VsNode(g *gomega.GomegaWithT, modelName string, servers int) *avinodes.AviVsNode {
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		vsNodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(vsNodes) == 0 || len(vsNodes[0].PoolRefs) == 0 {
+			return 0
+		}
+		return len(vsNodes[0].PoolRefs[0].Servers)
+	}, 10*time.Second).Should(gomega.Equal(servers))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+}
+
+func updateL4SvcAppProtocol(t *testing.T, svc *corev1.Service, appProtocol, resourceVersion string) {
+	svc.Spec.Ports[0].AppProtocol = proto.String(appProtocol)
+	svc.ResourceVersion = resourceVersion
+	if _, err := KubeClient.CoreV1().Services(svc.Namespace).Update(context.TODO(), svc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+}
+
+func TestL4SvcWithAppProtocol(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-appprotocol-01"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+
+	setUpLicense(lib.LicenseTypeEnterprise)
+	defer ResetMiddleware()
+	defer setUpLicense("BASIC")
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.Ports[0].AppProtocol = proto.String("https")
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	// The L4 virtualservice passes the TLS through, so https must not re-encrypt the traffic towards the servers.
+	vsNode := getL4VsNode(g, modelName, 1)
+	g.Expect(vsNode.PoolRefs[0].SslProfileRef).To(gomega.BeNil())
+	g.Expect(vsNode.PoolRefs[0].SniEnabled).To(gomega.BeFalse())
+	g.Expect(vsNode.PoolRefs[0].PkiProfile).To(gomega.BeNil())
+	g.Expect(vsNode.PoolRefs[0].EnableHttp2).To(gomega.BeFalse())
+	g.Expect(vsNode.ApplicationProfile).To(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(1))
+
+	// Without the opt-in annotation, HTTP/2 over cleartext keeps the L4 application profile.
+	updateL4SvcAppProtocol(t, svcExample, "kubernetes.io/h2c", "2")
+	g.Consistently(func() string {
+		return getL4VsNode(g, modelName, 1).ApplicationProfile
+	}, 3*time.Second).Should(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	vsNode = getL4VsNode(g, modelName, 1)
+	g.Expect(vsNode.PortProto[0].EnableHTTP2).To(gomega.BeFalse())
+	g.Expect(vsNode.PoolRefs[0].EnableHttp2).To(gomega.BeFalse())
+	g.Expect(vsNode.DefaultPool).To(gomega.BeEmpty())
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(1))
+
+	// With the opt-in annotation, HTTP/2 over cleartext is served with the HTTP application profile, and HTTP/2 is
+	// enabled towards the servers.
+	svcExample.Annotations = map[string]string{lib.L7AppProtocolAnnotation: "true"}
+	updateL4SvcAppProtocol(t, svcExample, "kubernetes.io/h2c", "3")
+	g.Eventually(func() string {
+		return getL4VsNode(g, modelName, 1).ApplicationProfile
+	}, 10*time.Second).Should(gomega.Equal(utils.DEFAULT_L7_APP_PROFILE))
+	vsNode = getL4VsNode(g, modelName, 1)
+	g.Expect(vsNode.PortProto[0].EnableHTTP2).To(gomega.BeTrue())
+	g.Expect(vsNode.PoolRefs[0].EnableHttp2).To(gomega.BeTrue())
+	g.Expect(vsNode.PoolRefs[0].SslProfileRef).To(gomega.BeNil())
+	g.Expect(vsNode.DefaultPool).To(gomega.Equal(vsNode.PoolRefs[0].Name))
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.BeEmpty())
+
+	// The websockets are passed through by the L4 virtualservice, which is not turned into an L7 virtualservice.
+	updateL4SvcAppProtocol(t, svcExample, "kubernetes.io/ws", "4")
+	g.Eventually(func() string {
+		return getL4VsNode(g, modelName, 1).ApplicationProfile
+	}, 10*time.Second).Should(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	vsNode = getL4VsNode(g, modelName, 1)
+	g.Expect(vsNode.PortProto[0].EnableHTTP2).To(gomega.BeFalse())
+	g.Expect(vsNode.PoolRefs[0].EnableHttp2).To(gomega.BeFalse())
+	g.Expect(vsNode.DefaultPool).To(gomega.BeEmpty())
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(1))
+
+	// The application profile annotation takes precedence over the appProtocol.
+	svcExample.Annotations = map[string]string{
+		lib.L7AppProtocolAnnotation:   "true",
+		lib.LBSvcAppProfileAnnotation: utils.DEFAULT_L4_APP_PROFILE,
+	}
+	updateL4SvcAppProtocol(t, svcExample, "grpc", "5")
+	g.Consistently(func() string {
+		return getL4VsNode(g, modelName, 1).ApplicationProfile
+	}, 3*time.Second).Should(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	vsNode = getL4VsNode(g, modelName, 1)
+	g.Expect(vsNode.PoolRefs[0].EnableHttp2).To(gomega.BeFalse())
+	g.Expect(vsNode.DefaultPool).To(gomega.BeEmpty())
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(1))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestL4SvcWithAppProtocolOverFastPath(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-appprotocol-02"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Annotations = map[string]string{lib.L7AppProtocolAnnotation: "true"}
+	svcExample.Spec.Ports[0].AppProtocol = proto.String("grpc")
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	// The HTTP application profile needs the TCP proxy network profile, so the virtualservice stays at L4.
+	vsNode := getL4VsNode(g, modelName, 1)
+	g.Expect(vsNode.NetworkProfile).To(gomega.Equal(utils.TCP_NW_FAST_PATH))
+	g.Expect(vsNode.ApplicationProfile).To(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	g.Expect(vsNode.PoolRefs[0].EnableHttp2).To(gomega.BeFalse())
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(1))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+
+	"github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+func SetUpTestForSvcLBWithEndpointSlice(t *testing.T, svcName string) string {
+	os.Setenv("ENDPOINTSLICE_ENABLED", "true")
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	objects.SharedAviGraphLister().Delete(modelName)
+	CreateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	return modelName
+}
+
+func TearDownTestForSvcLBWithEndpointSlice(t *testing.T, g *gomega.GomegaWithT, svcName string, epSliceNames ...string) {
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	objects.SharedAviGraphLister().Delete(modelName)
+	DelSVC(t, NAMESPACE, svcName)
+	for _, epSliceName := range epSliceNames {
+		DelEPS(t, NAMESPACE, epSliceName)
+	}
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+	os.Unsetenv("ENDPOINTSLICE_ENABLED")
+}
+
+// getEndpointSliceServers returns the sorted IPs of the enabled servers of the pool of the L4 VS.
+func getEndpointSliceServers(modelName string) []string {
+	return getEndpointSlicePoolServers(modelName, false)
+}
+
+// getEndpointSliceDisabledServers returns the sorted IPs of the servers being drained.
+func getEndpointSliceDisabledServers(modelName string) []string {
+	return getEndpointSlicePoolServers(modelName, true)
+}
+
+func getEndpointSlicePoolServers(modelName string, disabled bool) []string {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+		return nil
+	}
+	var servers []string
+	for _, server := range nodes[0].PoolRefs[0].Servers {
+		if server.Disabled == disabled {
+			servers = append(servers, *server.Ip.Addr)
+		}
+	}
+	sort.Strings(servers)
+	return servers
+}
+
+func TestAviSvcWithMultipleEndpointSlices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-eps-01"
+	modelName := SetUpTestForSvcLBWithEndpointSlice(t, svcName)
+
+	CreateEPS(t, ConstructEPS(NAMESPACE, svcName+"-a", svcName, "1.2.1.1", "1.2.1.2"))
+	CreateEPS(t, ConstructEPS(NAMESPACE, svcName+"-b", svcName, "1.2.1.3"))
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.1.1", "1.2.1.2", "1.2.1.3"}))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	g.Expect(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0].Port).To(gomega.Equal(int32(8080)))
+
+	// the servers of a slice are updated without affecting the other slices
+	UpdateEPS(t, ConstructEPS(NAMESPACE, svcName+"-b", svcName, "1.2.1.3", "1.2.1.4"))
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.1.1", "1.2.1.2", "1.2.1.3", "1.2.1.4"}))
+
+	DelEPS(t, NAMESPACE, svcName+"-a")
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.1.3", "1.2.1.4"}))
+
+	// slices of other services and of other address types are not considered
+	CreateEPS(t, ConstructEPS(NAMESPACE, svcName+"-other", "othersvc-eps-01", "1.2.1.5"))
+	ipv6Slice := ConstructEPS(NAMESPACE, svcName+"-v6", svcName, "2001::1")
+	ipv6Slice.AddressType = discoveryv1.AddressTypeIPv6
+	CreateEPS(t, ipv6Slice)
+	g.Consistently(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 5*time.Second).Should(gomega.Equal([]string{"1.2.1.3", "1.2.1.4"}))
+
+	TearDownTestForSvcLBWithEndpointSlice(t, g, svcName, svcName+"-b", svcName+"-other", svcName+"-v6")
+}
+
+func TestAviSvcWithTerminatingEndpointSlice(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-eps-02"
+	modelName := SetUpTestForSvcLBWithEndpointSlice(t, svcName)
+
+	// the terminating endpoints are drained while a ready endpoint exists
+	epSlice := ConstructEPS(NAMESPACE, svcName, svcName, "1.2.2.1", "1.2.2.2", "1.2.2.3")
+	epSlice.Endpoints[1].Conditions = discoveryv1.EndpointConditions{Ready: proto.Bool(false), Serving: proto.Bool(true), Terminating: proto.Bool(true)}
+	epSlice.Endpoints[2].Conditions = discoveryv1.EndpointConditions{Ready: proto.Bool(false), Serving: proto.Bool(false), Terminating: proto.Bool(true)}
+	CreateEPS(t, epSlice)
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.2.1"}))
+	g.Expect(getEndpointSliceDisabledServers(modelName)).To(gomega.Equal([]string{"1.2.2.2", "1.2.2.3"}))
+
+	// the serving terminating endpoints are used when no endpoint is ready
+	epSlice.Endpoints[0].Conditions = discoveryv1.EndpointConditions{Ready: proto.Bool(false), Serving: proto.Bool(true), Terminating: proto.Bool(true)}
+	UpdateEPS(t, epSlice)
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.2.1", "1.2.2.2"}))
+	g.Expect(getEndpointSliceDisabledServers(modelName)).To(gomega.Equal([]string{"1.2.2.3"}))
+
+	// the servers are removed once the endpoints are removed from the slice
+	epSlice.Endpoints = epSlice.Endpoints[:2]
+	UpdateEPS(t, epSlice)
+	g.Eventually(func() []string {
+		return getEndpointSliceDisabledServers(modelName)
+	}, 10*time.Second).Should(gomega.BeEmpty())
+
+	// the endpoints which are not ready and not terminating are never used
+	epSlice.Endpoints[0].Conditions = discoveryv1.EndpointConditions{Ready: proto.Bool(false)}
+	epSlice.Endpoints[1].Conditions = discoveryv1.EndpointConditions{Ready: proto.Bool(false)}
+	UpdateEPS(t, epSlice)
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.BeEmpty())
+	g.Expect(getEndpointSliceDisabledServers(modelName)).To(gomega.BeEmpty())
+
+	TearDownTestForSvcLBWithEndpointSlice(t, g, svcName, svcName)
+}
+
+func TestAviSvcWithEndpointSliceTopologyHints(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-eps-03"
+	os.Setenv("ENDPOINTSLICE_ZONE", "zone-a")
+	defer os.Unsetenv("ENDPOINTSLICE_ZONE")
+	modelName := SetUpTestForSvcLBWithEndpointSlice(t, svcName)
+
+	epSlice := ConstructEPS(NAMESPACE, svcName, svcName, "1.2.3.1", "1.2.3.2", "1.2.3.3")
+	epSlice.Endpoints[0].Hints = &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "zone-a"}}}
+	epSlice.Endpoints[1].Hints = &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "zone-b"}}}
+	epSlice.Endpoints[2].Hints = &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "zone-a"}, {Name: "zone-b"}}}
+	CreateEPS(t, epSlice)
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.3.1", "1.2.3.3"}))
+
+	// the hints are ignored when an endpoint has no hint
+	epSlice.Endpoints[2].Hints = nil
+	UpdateEPS(t, epSlice)
+	g.Eventually(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.2.3.1", "1.2.3.2", "1.2.3.3"}))
+
+	// the hints are ignored when no endpoint is hinted for the zone
+	epSlice.Endpoints[0].Hints = &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "zone-b"}}}
+	epSlice.Endpoints[2].Hints = &discoveryv1.EndpointHints{ForZones: []discoveryv1.ForZone{{Name: "zone-c"}}}
+	UpdateEPS(t, epSlice)
+	g.Consistently(func() []string {
+		return getEndpointSliceServers(modelName)
+	}, 5*time.Second).Should(gomega.Equal([]string{"1.2.3.1", "1.2.3.2", "1.2.3.3"}))
+
+	TearDownTestForSvcLBWithEndpointSlice(t, g, svcName, svcName)
+}
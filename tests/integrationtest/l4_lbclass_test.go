@@ -0,0 +1,192 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func createSvcWithLBClass(t *testing.T, svcName string, lbClass *string) string {
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.LoadBalancerClass = lbClass
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+	return modelName
+}
+
+func tearDownSvcWithLBClass(t *testing.T, g *gomega.GomegaWithT, svcName string) {
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	objects.SharedAviGraphLister().Delete(modelName)
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
+func TestAviSvcWithAKOLoadBalancerClass(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-lbclass-01"
+	lbClass := lib.AviLoadBalancerClass
+	modelName := createSvcWithLBClass(t, svcName, &lbClass)
+
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestAviSvcWithOtherLoadBalancerClass(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-lbclass-02"
+	lbClass := "metallb.io/metallb"
+	modelName := createSvcWithLBClass(t, svcName, &lbClass)
+
+	g.Consistently(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 5*time.Second).Should(gomega.Equal(false))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestAviSvcWithCustomLoadBalancerClass(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("LOAD_BALANCER_CLASS", "example.com/avi")
+	defer os.Unsetenv("LOAD_BALANCER_CLASS")
+
+	svcName := "testsvc-lbclass-03"
+	lbClass := lib.AviLoadBalancerClass
+	modelName := createSvcWithLBClass(t, svcName, &lbClass)
+	g.Consistently(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 5*time.Second).Should(gomega.Equal(false))
+	tearDownSvcWithLBClass(t, g, svcName)
+
+	svcName = "testsvc-lbclass-04"
+	lbClass = "example.com/avi"
+	modelName = createSvcWithLBClass(t, svcName, &lbClass)
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 10*time.Second).Should(gomega.Equal(true))
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestAviSvcWithUnsetLoadBalancerClassNotClaimed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("CLAIM_UNSET_LOAD_BALANCER_CLASS", "false")
+	defer os.Unsetenv("CLAIM_UNSET_LOAD_BALANCER_CLASS")
+
+	svcName := "testsvc-lbclass-05"
+	modelName := createSvcWithLBClass(t, svcName, nil)
+	g.Consistently(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 5*time.Second).Should(gomega.Equal(false))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestAviSvcLoadBalancerClassMovesAway(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-lbclass-06"
+	modelName := createSvcWithLBClass(t, svcName, nil)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+
+	// The service is handed over to another LB controller, the VS should get deleted.
+	lbClass := "metallb.io/metallb"
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.LoadBalancerClass = &lbClass
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel == nil
+	}, 15*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(false))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestServicesForPodWithOtherLoadBalancerClass(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	selector := map[string]string{"app": "lbclass-pod"}
+	akoLBClass, otherLBClass := lib.AviLoadBalancerClass, "metallb.io/metallb"
+	svcClasses := map[string]*string{"testsvc-lbclass-pod-01": &akoLBClass, "testsvc-lbclass-pod-02": &otherLBClass}
+	for svcName, lbClass := range svcClasses {
+		svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+		svcExample.Spec.LoadBalancerClass = lbClass
+		svcExample.Spec.Selector = selector
+		if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding Service: %v", err)
+		}
+	}
+	g.Eventually(func() int {
+		services, _ := utils.GetInformers().ServiceInformer.Lister().Services(NAMESPACE).List(labels.SelectorFromSet(nil))
+		var found int
+		for _, svc := range services {
+			if svcClasses[svc.Name] != nil {
+				found++
+			}
+		}
+		return found
+	}, 10*time.Second).Should(gomega.Equal(2))
+
+	// Only the Service of AKO is returned for the Pod, the Service of another LB controller is skipped.
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: "lbclass-pod", Labels: selector}}
+	svcList, lbList := lib.GetServicesForPod(pod)
+	g.Expect(svcList).To(gomega.Equal([]string{NAMESPACE + "/testsvc-lbclass-pod-01"}))
+	g.Expect(lbList).To(gomega.Equal([]string{NAMESPACE + "/testsvc-lbclass-pod-01"}))
+
+	for svcName := range svcClasses {
+		tearDownSvcWithLBClass(t, g, svcName)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+
+	"github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func constructSvcWithTrafficPolicy(svcName string, trafficPolicy corev1.ServiceExternalTrafficPolicyType, healthCheckNodePort int32) *corev1.Service {
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.ExternalTrafficPolicy = trafficPolicy
+	svcExample.Spec.HealthCheckNodePort = healthCheckNodePort
+	return svcExample
+}
+
+func constructEPOnNode(svcName, nodeName string) *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: svcName},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.1.1.1", NodeName: &nodeName}},
+			Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+}
+
+// constructEPSOnNodes returns an EndpointSlice of the Service, with an endpoint on each of the given nodes, ready as per
+// the given readiness.
+func constructEPSOnNodes(svcName string, nodeReadiness map[string]bool) *discoveryv1.EndpointSlice {
+	epSlice := ConstructEPS(NAMESPACE, svcName+"-etp", svcName)
+	nodeNames := make([]string, 0, len(nodeReadiness))
+	for nodeName := range nodeReadiness {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for i, nodeName := range nodeNames {
+		epSlice.Endpoints = append(epSlice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{fmt.Sprintf("1.1.1.%d", i+1)},
+			Conditions: discoveryv1.EndpointConditions{Ready: proto.Bool(nodeReadiness[nodeName])},
+			NodeName:   proto.String(nodeName),
+		})
+	}
+	return epSlice
+}
+
+func getPoolServerIPs(modelName string) []string {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	vsNodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(vsNodes) == 0 || len(vsNodes[0].PoolRefs) == 0 {
+		return nil
+	}
+	var serverIPs []string
+	for _, server := range vsNodes[0].PoolRefs[0].Servers {
+		serverIPs = append(serverIPs, *server.Ip.Addr)
+	}
+	sort.Strings(serverIPs)
+	return serverIPs
+}
+
+func setUpNodesForTrafficPolicy(t *testing.T) {
+	SetNodePortMode()
+	CreateNode(t, "testNode1", "10.1.1.2")
+	CreateNode(t, "testNode2", "10.1.1.3")
+}
+
+func tearDownNodesForTrafficPolicy(t *testing.T) {
+	DeleteNode(t, "testNode1")
+	DeleteNode(t, "testNode2")
+	SetClusterIPMode()
+}
+
+func TestL4SvcNodePortWithExternalTrafficPolicyLocal(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	setUpNodesForTrafficPolicy(t)
+	defer tearDownNodesForTrafficPolicy(t)
+
+	svcName := "testsvc-etp-01"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := constructSvcWithTrafficPolicy(svcName, corev1.ServiceExternalTrafficPolicyTypeLocal, 32000)
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Create(context.TODO(), constructEPOnNode(svcName, "testNode1"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+
+	// Only the node hosting the endpoint is added as the pool server.
+	g.Eventually(func() []string {
+		return getPoolServerIPs(modelName)
+	}, 15*time.Second).Should(gomega.Equal([]string{"10.1.1.2"}))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	g.Expect(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].HealthMonitorNodeRefs).To(gomega.BeEmpty())
+
+	// The pool servers follow the endpoint when it moves to another node.
+	epExample := constructEPOnNode(svcName, "testNode2")
+	epExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Endpoint: %v", err)
+	}
+	g.Eventually(func() []string {
+		return getPoolServerIPs(modelName)
+	}, 15*time.Second).Should(gomega.Equal([]string{"10.1.1.3"}))
+
+	// With externalTrafficPolicy Cluster, all the nodes are added as the pool servers.
+	svcExample = constructSvcWithTrafficPolicy(svcName, corev1.ServiceExternalTrafficPolicyTypeCluster, 0)
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() []string {
+		return getPoolServerIPs(modelName)
+	}, 15*time.Second).Should(gomega.Equal([]string{"10.1.1.2", "10.1.1.3"}))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestL4SvcNodePortWithHealthCheckNodePort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("USE_HEALTH_CHECK_NODE_PORT", "true")
+	defer os.Unsetenv("USE_HEALTH_CHECK_NODE_PORT")
+	setUpNodesForTrafficPolicy(t)
+	defer tearDownNodesForTrafficPolicy(t)
+
+	svcName := "testsvc-etp-02"
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)
+	modelName := fmt.Sprintf("%s/%s", AVINAMESPACE, vsName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	mcache := cache.SharedAviObjCache()
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := constructSvcWithTrafficPolicy(svcName, corev1.ServiceExternalTrafficPolicyTypeLocal, 32000)
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Create(context.TODO(), constructEPOnNode(svcName, "testNode1"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].HealthMonitorNodeRefs)
+	}, 15*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	hmNode := vsNode.HealthMonitorNodeRefs[0]
+	g.Expect(hmNode.Name).To(gomega.Equal(vsNode.PoolRefs[0].Name))
+	g.Expect(hmNode.MonitorPort).To(gomega.Equal(int32(32000)))
+	g.Expect(vsNode.PoolRefs[0].HealthMonitorRefs).To(gomega.Equal([]string{"/api/healthmonitor?name=" + hmNode.Name}))
+
+	hmKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: hmNode.Name}
+	g.Eventually(func() []cache.NamespaceName {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return nil
+		}
+		return vsCache.(*cache.AviVsCache).HMKeyCollection
+	}, 15*time.Second).Should(gomega.ConsistOf(hmKey))
+	_, found := mcache.HMCache.AviCacheGet(hmKey)
+	g.Expect(found).To(gomega.BeTrue())
+
+	// Switching to externalTrafficPolicy Cluster deletes the health monitor.
+	svcExample = constructSvcWithTrafficPolicy(svcName, corev1.ServiceExternalTrafficPolicyTypeCluster, 0)
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := mcache.HMCache.AviCacheGet(hmKey)
+		return found
+	}, 15*time.Second).Should(gomega.BeFalse())
+	vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(vsCache.(*cache.AviVsCache).HMKeyCollection).To(gomega.BeEmpty())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestL4SvcNodePortWithExternalTrafficPolicyLocalAndEndpointSlices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("ENDPOINTSLICE_ENABLED", "true")
+	defer os.Unsetenv("ENDPOINTSLICE_ENABLED")
+	setUpNodesForTrafficPolicy(t)
+	defer tearDownNodesForTrafficPolicy(t)
+
+	svcName := "testsvc-etp-03"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := constructSvcWithTrafficPolicy(svcName, corev1.ServiceExternalTrafficPolicyTypeLocal, 32000)
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	defer DelEPS(t, NAMESPACE, svcName+"-etp")
+
+	// Only the node hosting the ready endpoint is added as the pool server.
+	CreateEPS(t, constructEPSOnNodes(svcName, map[string]bool{"testNode1": true, "testNode2": false}))
+	g.Eventually(func() []string {
+		return getPoolServerIPs(modelName)
+	}, 15*time.Second).Should(gomega.Equal([]string{"10.1.1.2"}))
+
+	// The pool servers follow the readiness of the endpoints.
+	UpdateEPS(t, constructEPSOnNodes(svcName, map[string]bool{"testNode1": false, "testNode2": true}))
+	g.Eventually(func() []string {
+		return getPoolServerIPs(modelName)
+	}, 15*time.Second).Should(gomega.Equal([]string{"10.1.1.3"}))
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
@@ -230,6 +230,7 @@ func TestMain(m *testing.M) {
 	registeredInformers := []string{
 		utils.ServiceInformer,
 		utils.EndpointInformer,
+		utils.EndpointSliceInformer,
 		utils.IngressInformer,
 		utils.IngressClassInformer,
 		utils.SecretInformer,
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func constructSvcWithSourceRanges(svcName string, sourceRanges []string) *corev1.Service {
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.LoadBalancerSourceRanges = sourceRanges
+	return svcExample
+}
+
+func getVSCacheNSPKeys(g *gomega.GomegaWithT, vsKey cache.NamespaceName) []cache.NamespaceName {
+	mcache := cache.SharedAviObjCache()
+	vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	if !found {
+		return nil
+	}
+	vsCacheObj, ok := vsCache.(*cache.AviVsCache)
+	g.Expect(ok).To(gomega.BeTrue())
+	return vsCacheObj.NSPKeyCollection
+}
+
+func TestAviSvcWithLoadBalancerSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-srcranges-01"
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)
+	modelName := fmt.Sprintf("%s/%s", AVINAMESPACE, vsName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	nspKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	mcache := cache.SharedAviObjCache()
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := constructSvcWithSourceRanges(svcName, []string{"192.168.1.5/32", " 10.10.0.0/16", "not-a-cidr"})
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nspNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRefs[0]
+	g.Expect(nspNode.Name).To(gomega.Equal(vsName))
+	g.Expect(nspNode.SourceRanges).To(gomega.Equal([]string{"10.10.0.0/16", "192.168.1.5/32"}))
+
+	g.Eventually(func() []cache.NamespaceName {
+		return getVSCacheNSPKeys(g, vsKey)
+	}, 15*time.Second).Should(gomega.ConsistOf(nspKey))
+	_, found := mcache.NSPCache.AviCacheGet(nspKey)
+	g.Expect(found).To(gomega.BeTrue())
+
+	// Removing the loadBalancerSourceRanges should delete the network security policy.
+	svcExample = constructSvcWithSourceRanges(svcName, nil)
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() int {
+		return len(getVSCacheNSPKeys(g, vsKey))
+	}, 15*time.Second).Should(gomega.Equal(0))
+	g.Eventually(func() bool {
+		_, found := mcache.NSPCache.AviCacheGet(nspKey)
+		return found
+	}, 15*time.Second).Should(gomega.BeFalse())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestAviSvcWithLoadBalancerSourceRangesAndL4RuleNSP(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	svcName := "testsvc-srcranges-02"
+	l4RuleName := "test-l4rule-srcranges"
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)
+	modelName := fmt.Sprintf("%s/%s", AVINAMESPACE, vsName)
+
+	SetupL4Rule(t, l4RuleName, NAMESPACE, []int{8080})
+	g.Eventually(func() string {
+		l4Rule, _ := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(NAMESPACE).Get(context.TODO(), l4RuleName, metav1.GetOptions{})
+		return l4Rule.Status.Status
+	}, 30*time.Second).Should(gomega.Equal("Accepted"))
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := constructSvcWithSourceRanges(svcName, []string{"10.10.0.0/16"})
+	svcExample.Annotations = map[string]string{lib.L4RuleAnnotation: l4RuleName}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	// The networkSecurityPolicyRef of the L4Rule takes precedence over the loadBalancerSourceRanges.
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return false
+		}
+		return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRef != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	g.Expect(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].NetworkSecurityPolicyRefs).To(gomega.BeEmpty())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+	TeardownL4Rule(t, l4RuleName, NAMESPACE)
+}
+
+func TestSharedVIPSvcWithDifferentLoadBalancerSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--red-ns-" + SHAREDVIPKEY
+
+	SetUpTestForSharedVIPSvcLB(t, corev1.ProtocolTCP, corev1.ProtocolUDP)
+	updateSourceRanges := func(svcName string, sourceRanges []string) {
+		svcObj, err := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), svcName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("error in getting Service: %v", err)
+		}
+		svcObj.Spec.LoadBalancerSourceRanges = sourceRanges
+		svcObj.ResourceVersion = fmt.Sprintf("%d", time.Now().UnixNano())
+		if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcObj, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("error in updating Service: %v", err)
+		}
+	}
+	getSourceRanges := func() []string {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return nil
+		}
+		vsNodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(vsNodes) != 1 || len(vsNodes[0].NetworkSecurityPolicyRefs) != 1 {
+			return nil
+		}
+		return vsNodes[0].NetworkSecurityPolicyRefs[0].SourceRanges
+	}
+
+	// The shared VIP allows only the clients which are allowed by both the Services.
+	updateSourceRanges(SHAREDVIPSVC01, []string{"10.10.0.0/16", "192.168.1.0/24"})
+	updateSourceRanges(SHAREDVIPSVC02, []string{"10.10.1.0/24", "172.16.0.0/16"})
+	g.Eventually(getSourceRanges, 10*time.Second).Should(gomega.Equal([]string{"10.10.1.0/24"}))
+
+	// The shared VIP is not built when the Services have no common source range.
+	updateSourceRanges(SHAREDVIPSVC02, []string{"172.16.0.0/16"})
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found || aviModel == nil {
+			return 0
+		}
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS())
+	}, 10*time.Second).Should(gomega.Equal(0))
+
+	// A Service without source ranges does not restrict the clients of the shared VIP.
+	updateSourceRanges(SHAREDVIPSVC02, nil)
+	g.Eventually(getSourceRanges, 10*time.Second).Should(gomega.Equal([]string{"10.10.0.0/16", "192.168.1.0/24"}))
+
+	TearDownTestForSharedVIPSvcLB(t, g)
+}
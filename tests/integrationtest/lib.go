@@ -49,6 +49,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1034,6 +1035,56 @@ func DelEP(t *testing.T, ns string, Name string) {
 	}
 }
 
+/*
+ConstructEPS constructs an IPv4 EndpointSlice of the Service svcName with the port foo0:8080,
+and a ready endpoint for each of the addresses.
+*/
+func ConstructEPS(ns string, Name string, svcName string, addresses ...string) *discoveryv1.EndpointSlice {
+	var endpoints []discoveryv1.Endpoint
+	for _, address := range addresses {
+		endpoints = append(endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: proto.Bool(true)},
+		})
+	}
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      Name,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   endpoints,
+		Ports: []discoveryv1.EndpointPort{{
+			Name:     proto.String("foo0"),
+			Port:     proto.Int32(8080),
+			Protocol: (*corev1.Protocol)(proto.String(string(corev1.ProtocolTCP))),
+		}},
+	}
+}
+
+func CreateEPS(t *testing.T, epSlice *discoveryv1.EndpointSlice) {
+	_, err := KubeClient.DiscoveryV1().EndpointSlices(epSlice.Namespace).Create(context.TODO(), epSlice, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating EndpointSlice: %v", err)
+	}
+}
+
+func UpdateEPS(t *testing.T, epSlice *discoveryv1.EndpointSlice) {
+	epSlice.ResourceVersion = "2"
+	_, err := KubeClient.DiscoveryV1().EndpointSlices(epSlice.Namespace).Update(context.TODO(), epSlice, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("error in updating EndpointSlice: %v", err)
+	}
+}
+
+func DelEPS(t *testing.T, ns string, Name string) {
+	err := KubeClient.DiscoveryV1().EndpointSlices(ns).Delete(context.TODO(), Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		t.Fatalf("error in deleting EndpointSlice: %v", err)
+	}
+}
+
 func InitializeFakeAKOAPIServer() *api.FakeApiServer {
 	utils.AviLog.Infof("Initializing Fake AKO API server")
 	akoApi := &api.FakeApiServer{
@@ -1076,6 +1127,8 @@ var FakeAviObjects = []string{
 	"tenant",
 	"vsvip",
 	"l4policyset",
+	"networksecuritypolicy",
+	"healthmonitor",
 }
 
 type InjectFault func(w http.ResponseWriter, r *http.Request)
@@ -1645,13 +1698,14 @@ type FakeHTTPRule struct {
 }
 
 type FakeHTTPRulePath struct {
-	Path           string
-	SslProfile     string
-	DestinationCA  string
-	PkiProfile     string
-	HealthMonitors []string
-	LbAlgorithm    string
-	Hash           string
+	Path                   string
+	SslProfile             string
+	DestinationCA          string
+	PkiProfile             string
+	HealthMonitors         []string
+	LbAlgorithm            string
+	Hash                   string
+	GracefulDisableTimeout *int32
 }
 
 func (rr FakeHTTPRule) HTTPRule() *akov1beta1.HTTPRule {
@@ -1668,6 +1722,7 @@ func (rr FakeHTTPRule) HTTPRule() *akov1beta1.HTTPRule {
 				Algorithm: p.LbAlgorithm,
 				Hash:      p.Hash,
 			},
+			GracefulDisableTimeout: p.GracefulDisableTimeout,
 		}
 		if p.DestinationCA != "" {
 			rrForPath.TLS.DestinationCA = p.DestinationCA
@@ -1695,12 +1750,13 @@ func SetupHTTPRule(t *testing.T, rrname, fqdn, path string) {
 		Namespace: "default",
 		Fqdn:      fqdn,
 		PathProperties: []FakeHTTPRulePath{{
-			Path:           path,
-			SslProfile:     "thisisaviref-sslprofile",
-			DestinationCA:  "httprule-destinationCA",
-			LbAlgorithm:    "LB_ALGORITHM_CONSISTENT_HASH",
-			Hash:           "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS",
-			HealthMonitors: []string{"thisisaviref-hm2", "thisisaviref-hm1"},
+			Path:                   path,
+			SslProfile:             "thisisaviref-sslprofile",
+			DestinationCA:          "httprule-destinationCA",
+			LbAlgorithm:            "LB_ALGORITHM_CONSISTENT_HASH",
+			Hash:                   "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS",
+			HealthMonitors:         []string{"thisisaviref-hm2", "thisisaviref-hm1"},
+			GracefulDisableTimeout: proto.Int32(10),
 		}},
 	}
 
@@ -2192,6 +2248,7 @@ func (lr FakeL4Rule) L4Rule() *akov1alpha2.L4Rule {
 			},
 			ApplicationPersistenceProfileRef: proto.String("thisisaviref-applicationpersistenceprofileref"),
 			Enabled:                          proto.Bool(true),
+			GracefulDisableTimeout:           proto.Int32(5),
 			HealthMonitorRefs:                []string{"thisisaviref-hm1", "thisisaviref-hm2"},
 			LbAlgorithm:                      proto.String("LB_ALGORITHM_CONSISTENT_HASH"),
 			LbAlgorithmHash:                  proto.String("LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER"),
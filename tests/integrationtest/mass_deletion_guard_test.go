@@ -0,0 +1,278 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setUpSyncedService creates a Service and waits for its virtualservice, which makes sure that the virtualservices
+// in the cache without a model are deleted during the bootup.
+func setUpSyncedService(t *testing.T, g *gomega.GomegaWithT, svcName string) {
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+	g.Eventually(func() string {
+		vsCache, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return ""
+		}
+		return vsCache.(*cache.AviVsCache).Uuid
+	}, 15*time.Second).ShouldNot(gomega.BeEmpty())
+}
+
+// addStaleVSCaches adds the virtualservices to the cache, for which the Kubernetes objects are not listed.
+func addStaleVSCaches(count int) []cache.NamespaceName {
+	var vsKeys []cache.NamespaceName
+	for i := 0; i < count; i++ {
+		vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-stale-svc-%d", NAMESPACE, i)}
+		cache.SharedAviObjCache().VsCacheMeta.AviCacheAdd(vsKey, &cache.AviVsCache{
+			Name:   vsKey.Name,
+			Tenant: AVINAMESPACE,
+			Uuid:   "virtualservice-" + vsKey.Name + "-random-uuid",
+		})
+		vsKeys = append(vsKeys, vsKey)
+	}
+	return vsKeys
+}
+
+func updateAllowMassDeletion(t *testing.T, value, resourceVersion string) {
+	cm, err := KubeClient.CoreV1().ConfigMaps(utils.GetAKONamespace()).Get(context.TODO(), lib.AviConfigMap, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error in getting the configmap: %v", err)
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[lib.ALLOW_MASS_DELETION] = value
+	cm.ResourceVersion = resourceVersion
+	if _, err = KubeClient.CoreV1().ConfigMaps(utils.GetAKONamespace()).Update(context.TODO(), cm, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating the configmap: %v", err)
+	}
+}
+
+func getStatefulSetDeletionStatus() string {
+	ss, err := KubeClient.AppsV1().StatefulSets(utils.GetAKONamespace()).Get(context.TODO(), lib.AKOStatefulSet, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return ss.GetAnnotations()[status.ObjectDeletionStatus]
+}
+
+func TestMassDeletionGuardHaltAndResume(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+
+	akoStatefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: utils.GetAKONamespace(), Name: lib.AKOStatefulSet}}
+	if _, err := KubeClient.AppsV1().StatefulSets(utils.GetAKONamespace()).Create(context.TODO(), akoStatefulSet, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding the ako StatefulSet: %v", err)
+	}
+	defer KubeClient.AppsV1().StatefulSets(utils.GetAKONamespace()).Delete(context.TODO(), lib.AKOStatefulSet, metav1.DeleteOptions{})
+	svcName := "testsvc-mass-deletion-01"
+	setUpSyncedService(t, g, svcName)
+	os.Setenv("MASS_DELETION_THRESHOLD_COUNT", "1")
+	defer os.Unsetenv("MASS_DELETION_THRESHOLD_COUNT")
+
+	// The deletion of the virtualservices exceeding the threshold is halted.
+	vsKeys := addStaleVSCaches(2)
+	g.Expect(ctrl.FullSyncK8s(true)).To(gomega.Succeed())
+	g.Consistently(func() bool {
+		for _, vsKey := range vsKeys {
+			if _, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey); !found {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second).Should(gomega.BeTrue())
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.ContainElements(
+		lib.GetModelName(AVINAMESPACE, vsKeys[0].Name), lib.GetModelName(AVINAMESPACE, vsKeys[1].Name)))
+	g.Expect(getStatefulSetDeletionStatus()).To(gomega.Equal(lib.ObjectDeletionHaltedStatus))
+
+	// The halted deletions are resumed once the override is set in the configmap.
+	updateAllowMassDeletion(t, "true", "mass-deletion-1")
+	defer updateAllowMassDeletion(t, "false", "mass-deletion-2")
+	g.Eventually(func() int {
+		var found int
+		for _, vsKey := range vsKeys {
+			if _, ok := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey); ok {
+				found++
+			}
+		}
+		return found
+	}, 15*time.Second).Should(gomega.Equal(0))
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.BeEmpty())
+	g.Eventually(getStatefulSetDeletionStatus, 5*time.Second).Should(gomega.BeEmpty())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestMassDeletionGuardBelowThreshold(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	svcName := "testsvc-mass-deletion-02"
+	setUpSyncedService(t, g, svcName)
+	os.Setenv("MASS_DELETION_THRESHOLD_COUNT", "1")
+	defer os.Unsetenv("MASS_DELETION_THRESHOLD_COUNT")
+
+	// The deletion of the virtualservices within the threshold is not halted.
+	vsKeys := addStaleVSCaches(1)
+	g.Expect(ctrl.FullSyncK8s(true)).To(gomega.Succeed())
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKeys[0])
+		return found
+	}, 15*time.Second).Should(gomega.BeFalse())
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.BeEmpty())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestMassDeletionGuardDeleteAviObjects(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	svcName := "testsvc-mass-deletion-03"
+	setUpSyncedService(t, g, svcName)
+	os.Setenv("MASS_DELETION_THRESHOLD_COUNT", "2")
+	defer os.Unsetenv("MASS_DELETION_THRESHOLD_COUNT")
+
+	// The parent virtualservice is within the threshold, but not along with its SNI children.
+	parentKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: "cluster--Shared-L7-mass-deletion"}
+	parentCache := &cache.AviVsCache{
+		Name:   parentKey.Name,
+		Tenant: AVINAMESPACE,
+		Uuid:   "virtualservice-" + parentKey.Name + "-random-uuid",
+	}
+	vsKeys := []cache.NamespaceName{parentKey}
+	for i := 0; i < 2; i++ {
+		childKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--mass-deletion-sni-%d", i)}
+		childUuid := "virtualservice-" + childKey.Name + "-random-uuid"
+		cache.SharedAviObjCache().VsCacheMeta.AviCacheAdd(childKey, &cache.AviVsCache{
+			Name:        childKey.Name,
+			Tenant:      AVINAMESPACE,
+			Uuid:        childUuid,
+			ParentVSRef: parentKey,
+		})
+		parentCache.AddToSNIChildCollection(childUuid)
+		vsKeys = append(vsKeys, childKey)
+	}
+	cache.SharedAviObjCache().VsCacheMeta.AviCacheAdd(parentKey, parentCache)
+
+	k8s.DeleteAviObjects([]cache.NamespaceName{parentKey}, cache.SharedAviObjCache(), cache.SharedAVIClients())
+	for _, vsKey := range vsKeys {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		g.Expect(found).To(gomega.BeTrue())
+	}
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.ContainElement(lib.GetModelName(AVINAMESPACE, parentKey.Name)))
+
+	// The parent virtualservice is deleted along with its SNI children once the override is set in the configmap.
+	updateAllowMassDeletion(t, "true", "mass-deletion-3")
+	defer updateAllowMassDeletion(t, "false", "mass-deletion-4")
+	g.Eventually(func() int {
+		var found int
+		for _, vsKey := range vsKeys {
+			if _, ok := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey); ok {
+				found++
+			}
+		}
+		return found
+	}, 15*time.Second).Should(gomega.Equal(0))
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.BeEmpty())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestMassDeletionGuardShardVSLosingChildren(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	svcName := "testsvc-mass-deletion-04"
+	setUpSyncedService(t, g, svcName)
+	os.Setenv("MASS_DELETION_THRESHOLD_COUNT", "1")
+	defer os.Unsetenv("MASS_DELETION_THRESHOLD_COUNT")
+
+	// The shard virtualservice survives the full sync, but all of its Ingress hosts are gone from its model.
+	parentKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: "cluster--Shared-L7-mass-deletion-children"}
+	modelName := lib.GetModelName(AVINAMESPACE, parentKey.Name)
+	parentCache := &cache.AviVsCache{
+		Name:   parentKey.Name,
+		Tenant: AVINAMESPACE,
+		Uuid:   "virtualservice-" + parentKey.Name + "-random-uuid",
+	}
+	var childKeys []cache.NamespaceName
+	for i := 0; i < 2; i++ {
+		childKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--mass-deletion-children-sni-%d", i)}
+		childUuid := "virtualservice-" + childKey.Name + "-random-uuid"
+		cache.SharedAviObjCache().VsCacheMeta.AviCacheAdd(childKey, &cache.AviVsCache{
+			Name:        childKey.Name,
+			Tenant:      AVINAMESPACE,
+			Uuid:        childUuid,
+			ParentVSRef: parentKey,
+		})
+		parentCache.AddToSNIChildCollection(childUuid)
+		childKeys = append(childKeys, childKey)
+	}
+	cache.SharedAviObjCache().VsCacheMeta.AviCacheAdd(parentKey, parentCache)
+	aviModel := nodes.NewAviObjectGraph()
+	aviModel.AddModelNode(&nodes.AviVsNode{Name: parentKey.Name, Tenant: AVINAMESPACE, SharedVS: true})
+	objects.SharedAviGraphLister().Save(modelName, aviModel)
+	defer objects.SharedAviGraphLister().Delete(modelName)
+	defer cache.SharedAviObjCache().VsCacheMeta.AviCacheDelete(parentKey)
+
+	// The model of the shard virtualservice is held, as its children exceed the threshold.
+	g.Expect(ctrl.FullSyncK8s(true)).To(gomega.Succeed())
+	g.Consistently(func() bool {
+		for _, childKey := range childKeys {
+			if _, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(childKey); !found {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second).Should(gomega.BeTrue())
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.ContainElement(modelName))
+
+	// The children are deleted once the override is set in the configmap.
+	updateAllowMassDeletion(t, "true", "mass-deletion-5")
+	defer updateAllowMassDeletion(t, "false", "mass-deletion-6")
+	g.Eventually(func() int {
+		var found int
+		for _, childKey := range childKeys {
+			if _, ok := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(childKey); ok {
+				found++
+			}
+		}
+		return found
+	}, 15*time.Second).Should(gomega.Equal(0))
+	g.Expect(k8s.SharedMassDeletionGuard().GetHaltedModels()).To(gomega.BeEmpty())
+
+	tearDownSvcWithLBClass(t, g, svcName)
+}
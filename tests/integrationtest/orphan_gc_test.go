@@ -0,0 +1,289 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func markedObject(name, uuid, clusterName string) map[string]interface{} {
+	obj := map[string]interface{}{"name": name, "uuid": uuid}
+	if clusterName != "" {
+		obj["markers"] = []map[string]interface{}{{"key": lib.ClusterNameLabelKey, "values": []string{clusterName}}}
+	}
+	return obj
+}
+
+// setUpOrphanPool creates a Service, and makes the Avi controller list the pool of the Service along with an orphan
+// pool of the cluster, an orphan pool of another cluster and a pool without markers. It returns the number of
+// deletions of the orphan pool received by the Avi controller.
+func setUpOrphanPool(t *testing.T, g *gomega.GomegaWithT, svcName, orphanName, orphanUuid string) *int32 {
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	mcache := cache.SharedAviObjCache()
+
+	objects.SharedAviGraphLister().Delete(fmt.Sprintf("%s/%s", AVINAMESPACE, vsKey.Name))
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	var poolKey cache.NamespaceName
+	g.Eventually(func() int {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found || vsCache.(*cache.AviVsCache).Uuid == "" {
+			return 0
+		}
+		poolKeys := vsCache.(*cache.AviVsCache).PoolKeyCollection
+		if len(poolKeys) == 1 {
+			poolKey = poolKeys[0]
+		}
+		return len(poolKeys)
+	}, 15*time.Second).Should(gomega.Equal(1))
+
+	var orphanDeletes int32
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Contains(r.URL.RawQuery, "fields=name,uuid,markers") {
+			results := []map[string]interface{}{}
+			if strings.Contains(url, "/api/pool/") {
+				results = append(results,
+					markedObject(poolKey.Name, "pool-referred-uuid", lib.GetClusterName()),
+					markedObject("unmarked-pool", "pool-unmarked-uuid", ""),
+					markedObject("other-cluster-pool", "pool-other-cluster-uuid", "other-cluster"))
+				if atomic.LoadInt32(&orphanDeletes) == 0 {
+					results = append(results, markedObject(orphanName, orphanUuid, lib.GetClusterName()))
+				}
+			}
+			collection, _ := json.Marshal(map[string]interface{}{"count": len(results), "results": results})
+			w.WriteHeader(http.StatusOK)
+			w.Write(collection)
+			return
+		}
+		if r.Method == "DELETE" && strings.Contains(url, "/api/pool/") {
+			if strings.Contains(url, "/api/pool/"+orphanUuid) {
+				atomic.AddInt32(&orphanDeletes, 1)
+			} else {
+				t.Errorf("unexpected deletion of the pool %s", url)
+			}
+		}
+		NormalControllerServer(w, r)
+	})
+	return &orphanDeletes
+}
+
+func TestOrphanGCReportWithinGracePeriod(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("ORPHAN_GC_ENABLED", "true")
+	defer os.Unsetenv("ORPHAN_GC_ENABLED")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	models.OrphanReport.InitModel()
+	defer ResetMiddleware()
+
+	svcName := "testsvc-orphan-01"
+	orphanDeletes := setUpOrphanPool(t, g, svcName, "cluster--orphan-pool-01", "pool-orphan-01-uuid")
+
+	// Only the orphan pool of the cluster is reported, and it is retained for the default grace period.
+	k8s.SharedOrphanCollector().CollectOrphanObjects(cache.SharedAVIClients().AviClient[0])
+	orphans := models.OrphanReport.GetOrphans()
+	g.Expect(orphans).To(gomega.HaveLen(1))
+	g.Expect(orphans[0].Type).To(gomega.Equal("pool"))
+	g.Expect(orphans[0].Name).To(gomega.Equal("cluster--orphan-pool-01"))
+	g.Expect(orphans[0].Uuid).To(gomega.Equal("pool-orphan-01-uuid"))
+	g.Expect(orphans[0].DeleteAfter.Sub(orphans[0].FirstSeen)).To(gomega.Equal(time.Duration(lib.DefaultOrphanGCGracePeriod) * time.Second))
+
+	// The first time the pool is found orphan is retained across the runs.
+	ctrl.FullSync()
+	g.Expect(models.OrphanReport.GetOrphans()).To(gomega.Equal(orphans))
+	g.Expect(atomic.LoadInt32(orphanDeletes)).To(gomega.Equal(int32(0)))
+
+	ResetMiddleware()
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestOrphanGCDeleteAfterGracePeriod(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("ORPHAN_GC_ENABLED", "true")
+	defer os.Unsetenv("ORPHAN_GC_ENABLED")
+	os.Setenv("ORPHAN_GC_GRACE_PERIOD", "1")
+	defer os.Unsetenv("ORPHAN_GC_GRACE_PERIOD")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	models.OrphanReport.InitModel()
+	defer ResetMiddleware()
+
+	svcName := "testsvc-orphan-02"
+	orphanDeletes := setUpOrphanPool(t, g, svcName, "cluster--orphan-pool-02", "pool-orphan-02-uuid")
+	aviClient := cache.SharedAVIClients().AviClient[0]
+
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	g.Expect(models.OrphanReport.GetOrphans()).To(gomega.HaveLen(1))
+	g.Expect(atomic.LoadInt32(orphanDeletes)).To(gomega.Equal(int32(0)))
+
+	// The orphan pool is deleted once the grace period expires.
+	time.Sleep(1100 * time.Millisecond)
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	g.Expect(atomic.LoadInt32(orphanDeletes)).To(gomega.Equal(int32(1)))
+	g.Expect(models.OrphanReport.GetOrphans()).To(gomega.BeEmpty())
+
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	g.Expect(atomic.LoadInt32(orphanDeletes)).To(gomega.Equal(int32(1)))
+
+	ResetMiddleware()
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestOrphanGCDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("ORPHAN_GC_ENABLED", "true")
+	defer os.Unsetenv("ORPHAN_GC_ENABLED")
+	os.Setenv("ORPHAN_GC_GRACE_PERIOD", "1")
+	defer os.Unsetenv("ORPHAN_GC_GRACE_PERIOD")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	models.OrphanReport.InitModel()
+	defer ResetMiddleware()
+
+	svcName := "testsvc-orphan-03"
+	orphanDeletes := setUpOrphanPool(t, g, svcName, "cluster--orphan-pool-03", "pool-orphan-03-uuid")
+	aviClient := cache.SharedAVIClients().AviClient[0]
+	os.Setenv("DRY_RUN", "true")
+	defer os.Unsetenv("DRY_RUN")
+
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	g.Expect(models.OrphanReport.GetOrphans()).To(gomega.HaveLen(1))
+
+	// The orphan pool is still only reported once the grace period expires.
+	time.Sleep(1100 * time.Millisecond)
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	orphans := models.OrphanReport.GetOrphans()
+	g.Expect(orphans).To(gomega.HaveLen(1))
+	g.Expect(orphans[0].Name).To(gomega.Equal("cluster--orphan-pool-03"))
+	g.Expect(atomic.LoadInt32(orphanDeletes)).To(gomega.Equal(int32(0)))
+
+	// The orphan pool is deleted once the dry-run mode is disabled.
+	os.Unsetenv("DRY_RUN")
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	g.Expect(atomic.LoadInt32(orphanDeletes)).To(gomega.Equal(int32(1)))
+	g.Expect(models.OrphanReport.GetOrphans()).To(gomega.BeEmpty())
+
+	ResetMiddleware()
+	tearDownSvcWithLBClass(t, g, svcName)
+}
+
+func TestOrphanGCDeletionOrder(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("ORPHAN_GC_ENABLED", "true")
+	defer os.Unsetenv("ORPHAN_GC_ENABLED")
+	os.Setenv("ORPHAN_GC_GRACE_PERIOD", "1")
+	defer os.Unsetenv("ORPHAN_GC_GRACE_PERIOD")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	models.OrphanReport.InitModel()
+	defer ResetMiddleware()
+
+	svcName := "testsvc-orphan-04"
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+
+	var vsvipKey cache.NamespaceName
+	g.Eventually(func() int {
+		vsCache, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return 0
+		}
+		vsvipKeys := vsCache.(*cache.AviVsCache).VSVipKeyCollection
+		if len(vsvipKeys) == 1 {
+			vsvipKey = vsvipKeys[0]
+		}
+		return len(vsvipKeys)
+	}, 15*time.Second).Should(gomega.Equal(1))
+
+	// The orphans of the types referring other objects are listed along with the referred vsvip of the Service.
+	listed := map[string][]map[string]interface{}{
+		"networksecuritypolicy": {markedObject("cluster--orphan-nsp-04", "networksecuritypolicy-orphan-04-uuid", lib.GetClusterName())},
+		"vsvip": {
+			markedObject(vsvipKey.Name, "vsvip-referred-uuid", lib.GetClusterName()),
+			markedObject("cluster--orphan-vsvip-04", "vsvip-orphan-04-uuid", lib.GetClusterName()),
+		},
+		"pool":          {markedObject("cluster--orphan-pool-04", "pool-orphan-04-uuid", lib.GetClusterName())},
+		"healthmonitor": {markedObject("cluster--orphan-hm-04", "healthmonitor-orphan-04-uuid", lib.GetClusterName())},
+	}
+	var lock sync.Mutex
+	var deletedTypes []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		objType := strings.Split(strings.SplitN(url, "/api/", 2)[1], "/")[0]
+		if r.Method == "GET" && strings.Contains(r.URL.RawQuery, "fields=name,uuid,markers") {
+			lock.Lock()
+			results := listed[objType]
+			lock.Unlock()
+			if results == nil {
+				results = []map[string]interface{}{}
+			}
+			collection, _ := json.Marshal(map[string]interface{}{"count": len(results), "results": results})
+			w.WriteHeader(http.StatusOK)
+			w.Write(collection)
+			return
+		}
+		if r.Method == "DELETE" && strings.Contains(url, "-orphan-04-uuid") {
+			lock.Lock()
+			deletedTypes = append(deletedTypes, objType)
+			listed[objType] = listed[objType][:len(listed[objType])-1]
+			lock.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	aviClient := cache.SharedAVIClients().AviClient[0]
+
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	var orphanNames []string
+	for _, orphan := range models.OrphanReport.GetOrphans() {
+		orphanNames = append(orphanNames, orphan.Type+"/"+orphan.Name)
+	}
+	g.Expect(orphanNames).To(gomega.ConsistOf("networksecuritypolicy/cluster--orphan-nsp-04",
+		"vsvip/cluster--orphan-vsvip-04", "pool/cluster--orphan-pool-04", "healthmonitor/cluster--orphan-hm-04"))
+
+	// The objects are deleted before the objects they refer.
+	time.Sleep(1100 * time.Millisecond)
+	k8s.SharedOrphanCollector().CollectOrphanObjects(aviClient)
+	lock.Lock()
+	g.Expect(deletedTypes).To(gomega.Equal([]string{"networksecuritypolicy", "vsvip", "pool", "healthmonitor"}))
+	lock.Unlock()
+	g.Expect(models.OrphanReport.GetOrphans()).To(gomega.BeEmpty())
+
+	ResetMiddleware()
+	tearDownSvcWithLBClass(t, g, svcName)
+}
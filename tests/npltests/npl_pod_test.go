@@ -726,6 +726,60 @@ func TestNPLLBSvcDelPod(t *testing.T) {
 	tearDownTestForSvcLB(t, g)
 }
 
+// TestNPLLBSvcTerminatingPod creates a Service type LB and a Pod with matching label and the model is verified.
+// Then the Pod is marked for deletion, and it is verified that the Server is disabled in the model.
+func TestNPLLBSvcTerminatingPod(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	selectors := make(map[string]string)
+	selectors["app"] = "npl"
+	objects.SharedAviGraphLister().Delete(defaultLBModel)
+	createPodWithNPLAnnotation(selectors)
+	setUpTestForSvcLB(t)
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(defaultLBModel)
+		if aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].PoolRefs[0].Servers)
+	}, 40*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(defaultLBModel)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs[0].Servers[0].Disabled).To(gomega.BeFalse())
+
+	// If the Pod is terminating, the server should be disabled in the model
+	testPod := getTestPod(selectors)
+	testPod.Annotations = map[string]string{lib.NPLPodAnnotation: "[{\"podPort\":8080,\"nodeIP\":\"10.10.10.10\",\"nodePort\":40001}]"}
+	testPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	testPod.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Pods(defaultNS).Update(context.TODO(), &testPod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Pod: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, aviModel = objects.SharedAviGraphLister().Get(defaultLBModel)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].PoolRefs[0].Servers) != 1 {
+			return false
+		}
+		return nodes[0].PoolRefs[0].Servers[0].Disabled
+	}, 40*time.Second).Should(gomega.Equal(true))
+
+	// Once the Pod is deleted, the server should get deleted from model
+	if err := KubeClient.CoreV1().Pods(defaultNS).Delete(context.TODO(), defaultPodName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Pod: %v", err)
+	}
+	g.Eventually(func() int {
+		_, aviModel = objects.SharedAviGraphLister().Get(defaultLBModel)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].PoolRefs[0].Servers)
+	}, 40*time.Second).Should(gomega.Equal(0))
+	tearDownTestForSvcLB(t, g)
+}
+
 // TestNPLLBSvcNoLabel creates a Service of type LB with no Label and a Pod with NPL annotation.
 // Then it is verified that no server is getting added in the model.
 func TestNPLLBSvcNoLabel(t *testing.T) {
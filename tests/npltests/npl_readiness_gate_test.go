@@ -0,0 +1,169 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package npltests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createPodWithReadinessGate(labels map[string]string) {
+	testPod := getTestPod(labels)
+	testPod.Annotations = map[string]string{lib.NPLPodAnnotation: "[{\"podPort\":8080,\"nodeIP\":\"10.10.10.10\",\"nodePort\":40001}]"}
+	testPod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: lib.PoolMemberReadyConditionType}}
+	testPod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+	}
+	KubeClient.CoreV1().Pods(defaultNS).Create(context.TODO(), &testPod, metav1.CreateOptions{})
+}
+
+func isPoolMemberReadyConditionSet() bool {
+	pod, err := KubeClient.CoreV1().Pods(defaultNS).Get(context.TODO(), defaultPodName, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return lib.IsPoolMemberReadyConditionTrue(pod)
+}
+
+func setPoolServerOperState(state string) {
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.Contains(r.URL.EscapedPath(), "/runtime/server") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"ip_addr": {"addr": "10.10.10.10", "type": "V4"}, "port": 40001, "oper_status": {"state": "` + state + `"}}]`))
+			return
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+}
+
+// TestNPLLBSvcPodReadinessGate creates a Pod carrying the pool member readiness gate, and verifies that the
+// readiness condition is set only once the Avi controller reports the pool server of the Pod up.
+func TestNPLLBSvcPodReadinessGate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("POD_READINESS_GATE_ENABLED", "true")
+	defer os.Unsetenv("POD_READINESS_GATE_ENABLED")
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	defer integrationtest.ResetMiddleware()
+
+	selectors := map[string]string{"app": "npl"}
+	objects.SharedAviGraphLister().Delete(defaultLBModel)
+	createPodWithReadinessGate(selectors)
+	setUpTestForSvcLB(t)
+
+	var poolNode *avinodes.AviPoolNode
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(defaultLBModel)
+		if aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return -1
+		}
+		poolNode = nodes[0].PoolRefs[0]
+		return len(poolNode.Servers)
+	}, 40*time.Second).Should(gomega.Equal(1))
+
+	poolKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: poolNode.Name}
+	found, members := objects.SharedPoolMemberLister().Get(poolKey.Namespace + "/" + poolKey.Name)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(members).To(gomega.Equal([]objects.PoolMember{{Pod: defaultNS + "/" + defaultPodName, IP: defaultHostIP, Port: defaultNodePort}}))
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().PoolCache.AviCacheGet(poolKey)
+		return found
+	}, 40*time.Second).Should(gomega.BeTrue())
+
+	// The condition is not set while the pool server is down.
+	setPoolServerOperState("OPER_DOWN")
+	status.UpdatePoolMemberReadiness()
+	g.Expect(isPoolMemberReadyConditionSet()).To(gomega.BeFalse())
+
+	setPoolServerOperState(status.PoolMemberOperUp)
+	status.UpdatePoolMemberReadiness()
+	g.Eventually(isPoolMemberReadyConditionSet, 10*time.Second).Should(gomega.BeTrue())
+
+	tearDownTestForSvcLB(t, g)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedPoolMemberLister().Get(poolKey.Namespace + "/" + poolKey.Name)
+		return found
+	}, 40*time.Second).Should(gomega.BeFalse())
+}
+
+// TestLBSvcPodReadinessGateNotReadyEndpoint verifies that in ClusterIP mode, the not ready endpoint of a Pod waiting
+// only for its pool member readiness condition is added as a pool server, unlike the other not ready endpoints.
+func TestLBSvcPodReadinessGateNotReadyEndpoint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv("POD_READINESS_GATE_ENABLED", "true")
+	defer os.Unsetenv("POD_READINESS_GATE_ENABLED")
+	integrationtest.SetClusterIPMode()
+	defer os.Setenv("SERVICE_TYPE", "NodePortLocal")
+
+	selectors := map[string]string{"app": "npl"}
+	objects.SharedAviGraphLister().Delete(defaultLBModel)
+	createPodWithReadinessGate(selectors)
+	setUpTestForSvcLB(t)
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultNS, Name: integrationtest.SINGLEPORTSVC},
+		Subsets: []corev1.EndpointSubset{{
+			NotReadyAddresses: []corev1.EndpointAddress{
+				{IP: defaultPodIP, TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: defaultNS, Name: defaultPodName}},
+				{IP: "192.168.32.11", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: defaultNS, Name: "test-pod-without-gate"}},
+			},
+			Ports: []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(defaultNS).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+
+	var poolNode *avinodes.AviPoolNode
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(defaultLBModel)
+		if aviModel == nil {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return -1
+		}
+		poolNode = nodes[0].PoolRefs[0]
+		return len(poolNode.Servers)
+	}, 40*time.Second).Should(gomega.Equal(1))
+	g.Expect(*poolNode.Servers[0].Ip.Addr).To(gomega.Equal(defaultPodIP))
+
+	// The server uses the port of the pool, which is recorded for the member.
+	found, members := objects.SharedPoolMemberLister().Get(integrationtest.AVINAMESPACE + "/" + poolNode.Name)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(members).To(gomega.Equal([]objects.PoolMember{{Pod: defaultNS + "/" + defaultPodName, IP: defaultPodIP, Port: 8080}}))
+
+	KubeClient.CoreV1().Endpoints(defaultNS).Delete(context.TODO(), integrationtest.SINGLEPORTSVC, metav1.DeleteOptions{})
+	tearDownTestForSvcLB(t, g)
+}